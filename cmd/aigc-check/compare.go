@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/leoobai/aigc-check/internal/analyzer"
+	"github.com/leoobai/aigc-check/internal/compare"
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// dimensionNames 评分维度显示名称
+var dimensionNames = map[string]string{
+	"vocabulary_diversity":   "词汇多样性",
+	"sentence_complexity":    "句式复杂度",
+	"personalization":        "个人化表达",
+	"logical_coherence":      "逻辑连贯性",
+	"emotional_authenticity": "情感真实度",
+}
+
+// runCompare 执行 compare 子命令：比较同一文档两个版本的检测结果
+func runCompare(args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+
+	var (
+		configFile string
+		format     string
+	)
+	fs.StringVar(&configFile, "c", "", "配置文件路径（可选）")
+	fs.StringVar(&configFile, "config", "", "配置文件路径（可选）")
+	fs.StringVar(&format, "format", "text", "输出格式: text, json")
+	fs.Usage = printCompareHelp
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("必须指定两个输入文件（旧版本和新版本）")
+	}
+	if format != "text" && format != "json" {
+		return fmt.Errorf("不支持的输出格式: %s", format)
+	}
+
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return err
+	}
+	a := analyzer.NewAnalyzer(cfg)
+
+	var results [2]*models.DetectionResult
+	for i, path := range fs.Args() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("读取输入文件失败: %w", err)
+		}
+		if len(content) == 0 {
+			return fmt.Errorf("输入文件为空: %s", path)
+		}
+		results[i], err = a.Analyze(models.DetectionRequest{Text: string(content)})
+		if err != nil {
+			return fmt.Errorf("分析 %s 失败: %w", path, err)
+		}
+	}
+
+	comparison := compare.Compare(results[0], results[1])
+
+	if format == "json" {
+		data, err := json.MarshalIndent(comparison, "", "  ")
+		if err != nil {
+			return fmt.Errorf("生成报告失败: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printComparison(fs.Arg(0), fs.Arg(1), comparison)
+	return nil
+}
+
+// printComparison 以文本形式输出比较结果
+func printComparison(fromName, toName string, c *compare.Comparison) {
+	fmt.Printf("比较: %s → %s\n", fromName, toName)
+	fmt.Printf("总分: %.1f → %.1f (%s)\n", c.FromScore, c.ToScore, signed(c.ScoreDelta))
+	if c.FromRiskLevel != c.ToRiskLevel {
+		fmt.Printf("风险等级: %s → %s\n", c.FromRiskLevel.Description(), c.ToRiskLevel.Description())
+	} else {
+		fmt.Printf("风险等级: %s（未变化）\n", c.ToRiskLevel.Description())
+	}
+
+	fmt.Println()
+	fmt.Println("维度变化:")
+	for _, d := range c.Dimensions {
+		fmt.Printf("  %s: %.1f → %.1f (%s)\n", dimensionNames[d.Name], d.From, d.To, signed(d.Delta))
+	}
+
+	fmt.Println()
+	fmt.Println("规则变化:")
+	changed := 0
+	for _, r := range c.Rules {
+		if r.ScoreDelta == 0 && r.Resolved == 0 && r.Introduced == 0 {
+			continue
+		}
+		changed++
+		fmt.Printf("  %s: %.1f → %.1f (%s)，匹配 %d → %d（消除 %d，新增 %d）\n",
+			models.GetRuleTypeName(r.RuleType), r.FromScore, r.ToScore, signed(r.ScoreDelta),
+			r.FromCount, r.ToCount, r.Resolved, r.Introduced)
+	}
+	if changed == 0 {
+		fmt.Println("  无")
+	}
+
+	fmt.Println()
+	fmt.Println("文本变更:")
+	if len(c.Edits) == 0 {
+		fmt.Println("  无")
+	}
+	for _, edit := range c.Edits {
+		header := fmt.Sprintf("@@ 第 %d 行 → 第 %d 行 @@", edit.OldLine, edit.NewLine)
+		if edit.MovedScore() {
+			names := make([]string, len(edit.Rules))
+			for i, ruleType := range edit.Rules {
+				names[i] = models.GetRuleTypeName(ruleType)
+			}
+			header += "  影响分数: " + strings.Join(names, "、")
+		}
+		fmt.Println(header)
+		printLines("-", edit.Removed)
+		printLines("+", edit.Added)
+		printMatchChanges("消除", edit.Resolved)
+		printMatchChanges("新增", edit.Introduced)
+	}
+
+	var unattributed [2][]compare.MatchChange
+	for _, m := range c.Resolved {
+		if m.Edit < 0 {
+			unattributed[0] = append(unattributed[0], m)
+		}
+	}
+	for _, m := range c.Introduced {
+		if m.Edit < 0 {
+			unattributed[1] = append(unattributed[1], m)
+		}
+	}
+	if len(unattributed[0]) > 0 || len(unattributed[1]) > 0 {
+		fmt.Println()
+		fmt.Println("未对应具体文本变更的变化（如阈值或整体统计变化）:")
+		printMatchChanges("消除", unattributed[0])
+		printMatchChanges("新增", unattributed[1])
+	}
+}
+
+// printLines 按行输出变更文本
+func printLines(prefix, text string) {
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if line == "" {
+			continue
+		}
+		fmt.Print(prefix + line)
+		if !strings.HasSuffix(line, "\n") {
+			fmt.Println()
+		}
+	}
+}
+
+// printMatchChanges 输出匹配变化
+func printMatchChanges(label string, changes []compare.MatchChange) {
+	for _, m := range changes {
+		fmt.Printf("  %s: [%s] %q\n", label, models.GetRuleTypeName(m.RuleType), m.Text)
+	}
+}
+
+// signed 格式化带符号的分数变化
+func signed(delta float64) string {
+	return fmt.Sprintf("%+.1f", delta)
+}
+
+// printCompareHelp 打印 compare 子命令帮助信息
+func printCompareHelp() {
+	fmt.Println("用法:")
+	fmt.Println("  aigc-check compare [选项] <旧版本文件> <新版本文件>")
+	fmt.Println()
+	fmt.Println("分别检测两个版本，输出分数变化、各规则变化、消除和新出现的匹配，")
+	fmt.Println("以及标注了哪些修改影响了分数的文本差异。")
+	fmt.Println()
+	fmt.Println("选项:")
+	fmt.Println("  -c, --config <路径>    配置文件路径（可选）")
+	fmt.Println("  -format <格式>         输出格式: text, json（默认: text）")
+	fmt.Println()
+	fmt.Println("示例:")
+	fmt.Println("  aigc-check compare draft-v1.txt draft-v2.txt")
+	fmt.Println("  aigc-check compare -format json draft-v1.txt draft-v2.txt")
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/leoobai/aigc-check/internal/analyzer"
+	"github.com/leoobai/aigc-check/internal/corpus"
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// riskLevelOrder 汇总中风险等级的输出顺序
+var riskLevelOrder = []models.RiskLevel{
+	models.RiskLevelVeryHigh,
+	models.RiskLevelHigh,
+	models.RiskLevelMedium,
+	models.RiskLevelLow,
+}
+
+// runCorpus 执行 corpus 子命令：流式检测 JSONL/CSV 语料中的每一行，并把结果写回各行
+func runCorpus(args []string) error {
+	fs := flag.NewFlagSet("corpus", flag.ContinueOnError)
+
+	var (
+		configFile  string
+		outputFile  string
+		inputFormat string
+		textField   string
+		idField     string
+		profile     string
+		workers     int
+		resume      bool
+	)
+	fs.StringVar(&configFile, "c", "", "配置文件路径（可选）")
+	fs.StringVar(&configFile, "config", "", "配置文件路径（可选）")
+	fs.StringVar(&outputFile, "o", "", "输出文件路径")
+	fs.StringVar(&outputFile, "output", "", "输出文件路径")
+	fs.StringVar(&inputFormat, "input-format", "auto", "输入格式: auto, jsonl, csv, tsv")
+	fs.StringVar(&textField, "text-field", "text", "待检测文本所在的字段或列名")
+	fs.StringVar(&idField, "id-field", "", "行 ID 所在的字段或列名（可选）")
+	fs.StringVar(&profile, "profile", "", "规则配置档（可选）")
+	fs.IntVar(&workers, "workers", 0, "并发检测的 worker 数量（默认使用配置中的 batch.concurrency）")
+	fs.BoolVar(&resume, "resume", false, "从检查点继续上次中断的运行")
+	fs.Usage = printCorpusHelp
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("必须指定一个输入文件")
+	}
+	input := fs.Arg(0)
+
+	opts := corpus.Options{
+		TextField: textField,
+		IDField:   idField,
+		Workers:   workers,
+		Resume:    resume,
+	}
+	if inputFormat != "" && inputFormat != "auto" {
+		format, err := corpus.ParseFormat(inputFormat)
+		if err != nil {
+			return err
+		}
+		opts.Format = format
+	}
+	if outputFile == "" {
+		ext := filepath.Ext(input)
+		outputFile = strings.TrimSuffix(input, ext) + ".aigc" + ext
+	}
+
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return err
+	}
+	cfg, err = cfg.WithProfile(profile)
+	if err != nil {
+		return fmt.Errorf("加载规则配置档失败: %w", err)
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = cfg.Batch.Concurrency
+	}
+
+	a := analyzer.NewAnalyzer(cfg)
+	detect := func(text string) (*models.DetectionResult, error) {
+		return a.Analyze(models.DetectionRequest{
+			Text:    text,
+			Options: models.DetectionOptions{Language: cfg.Output.Language},
+		})
+	}
+
+	// Ctrl+C 或 SIGTERM 时等待进行中的行完成并保存检查点
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	progress := func(p corpus.Progress) {
+		fmt.Fprintf(os.Stderr, "\r已处理 %d 行（失败 %d）", p.Rows, p.Failed)
+	}
+	summary, err := corpus.Run(ctx, input, outputFile, opts, detect, progress)
+	if summary != nil {
+		fmt.Fprintln(os.Stderr)
+	}
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("检测已中断（已写出 %d 行），使用 --resume 从检查点继续", summary.Rows)
+	}
+	if errors.Is(err, corpus.ErrCheckpointMismatch) {
+		return fmt.Errorf("无法从检查点继续: %w（去掉 --resume 重新开始）", err)
+	}
+	if err != nil {
+		return fmt.Errorf("语料检测失败: %w", err)
+	}
+
+	printCorpusSummary(summary, outputFile)
+	return nil
+}
+
+// printCorpusSummary 输出语料检测汇总
+func printCorpusSummary(s *corpus.Summary, output string) {
+	if s.Resumed {
+		fmt.Printf("从检查点继续，跳过已处理的 %d 行\n", s.Skipped)
+	}
+	fmt.Printf("共 %d 行：成功 %d，失败 %d\n", s.Rows, s.Succeeded, s.Failed)
+	for _, level := range riskLevelOrder {
+		if n := s.RiskLevels[level]; n > 0 {
+			fmt.Printf("  %s: %d\n", level.Description(), n)
+		}
+	}
+	if len(s.Failures) > 0 {
+		fmt.Printf("失败的行（前 %d 个）:\n", len(s.Failures))
+		for _, f := range s.Failures {
+			if f.ID != "" {
+				fmt.Printf("  第 %d 行（%s）: %s\n", f.Row, f.ID, f.Err)
+			} else {
+				fmt.Printf("  第 %d 行: %s\n", f.Row, f.Err)
+			}
+		}
+	}
+	fmt.Printf("结果已写入: %s\n", output)
+}
+
+// printCorpusHelp 打印 corpus 子命令帮助信息
+func printCorpusHelp() {
+	fmt.Println("用法:")
+	fmt.Println("  aigc-check corpus [选项] <输入文件>")
+	fmt.Println()
+	fmt.Println("流式检测 JSONL 或 CSV/TSV 语料（如微调数据、问卷回答）中的每一行，以 worker 池并发检测，")
+	fmt.Println("按输入顺序把原有的行连同 aigc_score、aigc_risk_level、aigc_rules、aigc_error 写入输出文件。")
+	fmt.Println("内存占用与文件大小无关；定期在输出文件旁保存检查点（.checkpoint），中断后可用 --resume 继续。")
+	fmt.Println()
+	fmt.Println("选项:")
+	fmt.Println("  -o, --output <路径>       输出文件路径（默认: 输入文件名加 .aigc，如 data.aigc.jsonl）")
+	fmt.Println("  --input-format <格式>     输入格式: auto, jsonl, csv, tsv（默认: auto，根据扩展名判断）")
+	fmt.Println("  --text-field <名称>       待检测文本所在的字段或列名（默认: text）")
+	fmt.Println("  --id-field <名称>         行 ID 所在的字段或列名，用于汇总中的失败行（可选）")
+	fmt.Println("  --workers <数量>          并发检测的 worker 数量（默认: 配置中的 batch.concurrency）")
+	fmt.Println("  --resume                  从检查点继续上次中断的运行")
+	fmt.Println("  --profile <名称>          规则配置档（可选）")
+	fmt.Println("  -c, --config <路径>       配置文件路径（可选）")
+	fmt.Println()
+	fmt.Println("示例:")
+	fmt.Println("  aigc-check corpus --text-field completion --id-field id train.jsonl")
+	fmt.Println("  aigc-check corpus --text-field answer -o screened.csv survey.csv")
+	fmt.Println("  aigc-check corpus --resume --text-field completion --id-field id train.jsonl")
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/database"
+	"github.com/leoobai/aigc-check/internal/database/migrations"
+	"github.com/leoobai/aigc-check/internal/repository"
+	"github.com/leoobai/aigc-check/internal/service"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// runDB 执行 db 子命令：管理数据库 schema 迁移
+func runDB(args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		printDBHelp()
+		return nil
+	}
+	action := args[0]
+	switch action {
+	case "migrate", "rollback", "status", "purge":
+	default:
+		printDBHelp()
+		return fmt.Errorf("未知的 db 子命令: %s", action)
+	}
+
+	fs := flag.NewFlagSet("db "+action, flag.ContinueOnError)
+	var (
+		configFile string
+		steps      int
+		dryRun     bool
+		days       int
+	)
+	fs.StringVar(&configFile, "c", "", "配置文件路径（可选）")
+	fs.StringVar(&configFile, "config", "", "配置文件路径（可选）")
+	fs.IntVar(&steps, "steps", 1, "回滚的迁移数量（仅 rollback）")
+	fs.BoolVar(&dryRun, "dry-run", false, "只列出将被清除的记录，不修改数据（仅 purge）")
+	fs.IntVar(&days, "days", 0, "覆盖配置中的原文保留天数（仅 purge）")
+	fs.Usage = printDBHelp
+
+	if err := fs.Parse(args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+
+	if days < 0 {
+		return fmt.Errorf("--days 不能为负数")
+	}
+
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return err
+	}
+
+	dbConfig, err := database.NewConfig(cfg.Database, logger.Silent)
+	if err != nil {
+		return fmt.Errorf("数据库配置错误: %w", err)
+	}
+	db, err := database.Open(dbConfig)
+	if err != nil {
+		return err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	// 迁移进度由本命令输出，屏蔽迁移包的日志
+	log.SetOutput(io.Discard)
+
+	switch action {
+	case "migrate":
+		applied, err := migrations.Migrate(db)
+		for _, m := range applied {
+			fmt.Printf("已应用 %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("数据库已是最新版本")
+		}
+		return nil
+
+	case "rollback":
+		if steps < 1 {
+			return fmt.Errorf("--steps 必须大于 0")
+		}
+		rolledBack, err := migrations.Rollback(db, steps)
+		for _, m := range rolledBack {
+			fmt.Printf("已回滚 %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(rolledBack) == 0 {
+			fmt.Println("没有可回滚的迁移")
+		}
+		return nil
+
+	case "purge":
+		retention := cfg.Database.Retention
+		if days > 0 {
+			retention.TextDays = days
+		}
+		return runPurge(db, retention, dryRun)
+
+	default:
+		return printMigrationStatus(db)
+	}
+}
+
+// runPurge 清除超过保留期限的原文（purge）
+func runPurge(db *gorm.DB, retention config.RetentionConfig, dryRun bool) error {
+	if retention.TextDays <= 0 {
+		return fmt.Errorf("未配置原文保留天数，请设置 database.retention.text_days 或使用 --days")
+	}
+	if err := migrations.CheckVersion(db); err != nil {
+		return err
+	}
+	if current, err := migrations.CurrentVersion(db); err != nil {
+		return err
+	} else if current < migrations.LatestVersion() {
+		return fmt.Errorf("数据库存在未执行的迁移，请先运行 aigc-check db migrate")
+	}
+
+	retentionService := service.NewRetentionService(retention,
+		repository.NewDetectionRepository(db), repository.NewAuditRepository(db))
+	report, err := retentionService.Purge(service.PurgeOptions{DryRun: dryRun, Actor: "cli"})
+	if report != nil {
+		fmt.Printf("保留期限: %d 天（清除 %s 之前创建的记录的原文）\n", report.TextDays, report.Cutoff.Format("2006-01-02 15:04:05"))
+		if report.DryRun {
+			fmt.Printf("将清除 %d 条记录的原文（dry-run，未修改数据）\n", report.Matched)
+		} else {
+			fmt.Printf("已清除 %d/%d 条记录的原文\n", report.Purged, report.Matched)
+		}
+		for _, id := range report.RecordIDs {
+			fmt.Printf("  %s\n", id)
+		}
+		if int64(len(report.RecordIDs)) < report.Matched {
+			fmt.Printf("  ……（仅列出前 %d 条）\n", len(report.RecordIDs))
+		}
+	}
+	return err
+}
+
+// printMigrationStatus 打印迁移状态（status）
+func printMigrationStatus(db *gorm.DB) error {
+	statuses, err := migrations.Status(db)
+	if err != nil {
+		return err
+	}
+	current, err := migrations.CurrentVersion(db)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("当前版本: %d，程序支持的最高版本: %d\n\n", current, migrations.LatestVersion())
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "版本\t名称\t状态\t执行时间")
+	for _, status := range statuses {
+		state, appliedAt := "未执行", "-"
+		if status.Applied {
+			state = "已执行"
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if status.Unknown {
+			state = "未知（由更新版本的程序执行）"
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if current > migrations.LatestVersion() {
+		return fmt.Errorf("%w，请升级程序", migrations.ErrSchemaTooNew)
+	}
+	return nil
+}
+
+// printDBHelp 打印 db 子命令帮助信息
+func printDBHelp() {
+	fmt.Println("用法:")
+	fmt.Println("  aigc-check db <migrate|rollback|status|purge> [选项]")
+	fmt.Println()
+	fmt.Println("管理检测历史数据库的 schema 版本，数据库连接读取配置文件中的 database 配置块。")
+	fmt.Println()
+	fmt.Println("子命令:")
+	fmt.Println("  migrate                应用所有未执行的迁移")
+	fmt.Println("  rollback               回滚最近执行的迁移")
+	fmt.Println("  status                 显示每个迁移的执行状态")
+	fmt.Println("  purge                  清除超过保留期限的原文，只保留分数和元数据")
+	fmt.Println()
+	fmt.Println("选项:")
+	fmt.Println("  -c, --config <路径>    配置文件路径（可选）")
+	fmt.Println("  --steps <数量>         回滚的迁移数量（默认: 1）")
+	fmt.Println("  --dry-run              只列出将被清除的记录，不修改数据")
+	fmt.Println("  --days <天数>          覆盖配置中的 database.retention.text_days")
+	fmt.Println()
+	fmt.Println("示例:")
+	fmt.Println("  aigc-check db status")
+	fmt.Println("  aigc-check db migrate -c configs/aigc-check.yaml")
+	fmt.Println("  aigc-check db rollback --steps 2")
+	fmt.Println("  aigc-check db purge --dry-run --days 30")
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/leoobai/aigc-check/internal/analyzer"
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/extract"
+	"github.com/leoobai/aigc-check/internal/gitdiff"
+	"github.com/leoobai/aigc-check/internal/models"
+	"github.com/leoobai/aigc-check/internal/reporter"
+)
+
+// diffReport diff 子命令的 JSON 输出
+type diffReport struct {
+	Files  int                     `json:"files"` // 包含待检测新增行的文件数
+	Lines  int                     `json:"lines"` // 参与检测的新增行数
+	Blocks []gitdiff.Block         `json:"blocks"`
+	Result *models.DetectionResult `json:"result"`
+}
+
+// reviewComment 可直接提交为代码评审行内评论的检测发现（字段与 GitHub 评审评论一致）
+type reviewComment struct {
+	Path string `json:"path"`
+	Line int    `json:"line"` // 新文件中的行号
+	Side string `json:"side"`
+	Body string `json:"body"`
+}
+
+// runDiff 执行 diff 子命令：只检测 diff 中新增的文本
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+
+	var (
+		configFile string
+		format     string
+		profile    string
+		unified    int
+	)
+	fs.StringVar(&configFile, "c", "", "配置文件路径（可选）")
+	fs.StringVar(&configFile, "config", "", "配置文件路径（可选）")
+	fs.StringVar(&format, "format", "text", "输出格式: text, json, review")
+	fs.StringVar(&profile, "profile", "", "规则配置档（可选）")
+	fs.IntVar(&unified, "U", 3, "调用 git diff 时每个变更块保留的上下文行数")
+	fs.IntVar(&unified, "unified", 3, "调用 git diff 时每个变更块保留的上下文行数")
+	fs.Usage = printDiffHelp
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+	if format != "text" && format != "json" && format != "review" {
+		return fmt.Errorf("不支持的输出格式: %s", format)
+	}
+	if unified < 0 {
+		return fmt.Errorf("-U 不能为负数")
+	}
+
+	input, err := readDiff(fs.Args(), unified)
+	if err != nil {
+		return err
+	}
+	files, err := gitdiff.Parse(bytes.NewReader(input))
+	if err != nil {
+		return fmt.Errorf("解析 diff 失败: %w", err)
+	}
+	changes := gitdiff.Build(files)
+	if changes.Lines == 0 {
+		fmt.Fprintln(os.Stderr, "未发现新增文本")
+		return nil
+	}
+
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return err
+	}
+	// 新增文本全部来自源代码时只检测了注释，未指定规则配置档时使用 code 配置档
+	if profile == "" && extract.IsSourceCode(changes.Format()) {
+		profile = config.ProfileCode
+	}
+	cfg, err = cfg.WithProfile(profile)
+	if err != nil {
+		return fmt.Errorf("加载规则配置档失败: %w", err)
+	}
+
+	request := models.DetectionRequest{
+		Text: changes.Text,
+		Options: models.DetectionOptions{
+			Language: cfg.Output.Language,
+		},
+	}
+	if changes.Format() == extract.FormatMarkdown {
+		request.Options.InputFormat = models.InputFormatMarkdown
+	}
+	result, err := analyzer.NewAnalyzer(cfg).Analyze(request)
+	if err != nil {
+		return fmt.Errorf("分析失败: %w", err)
+	}
+	changes.MapResult(result)
+
+	switch format {
+	case "json":
+		return printJSON(diffReport{Files: changes.Files, Lines: changes.Lines, Blocks: changes.Blocks, Result: result})
+	case "review":
+		return printJSON(reviewComments(result))
+	}
+
+	report, err := reporter.NewTextReporter(cfg.Output.ColorEnabled).Generate(result)
+	if err != nil {
+		return fmt.Errorf("生成报告失败: %w", err)
+	}
+	fmt.Printf("检测范围: %d 个文件中新增的 %d 行（未修改的内容不参与检测）\n\n", changes.Files, changes.Lines)
+	fmt.Println(report)
+	return nil
+}
+
+// readDiff 读取统一 diff：参数为 "-" 或标准输入不是终端时从标准输入读取，否则调用 git diff，参数原样传给 git
+func readDiff(args []string, unified int) ([]byte, error) {
+	if len(args) == 1 && args[0] == "-" || len(args) == 0 && !isTerminal(os.Stdin) {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("读取标准输入失败: %w", err)
+		}
+		return data, nil
+	}
+
+	gitArgs := append([]string{"diff", "--no-color", "--no-ext-diff", "--unified=" + strconv.Itoa(unified)}, args...)
+	cmd := exec.Command("git", gitArgs...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("执行 git diff 失败: %s", msg)
+		}
+		return nil, fmt.Errorf("执行 git diff 失败: %w", err)
+	}
+	return out, nil
+}
+
+// isTerminal 判断文件是否为终端（字符设备）
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// reviewComments 将检出规则的匹配项按文件和行合并为评审评论
+func reviewComments(result *models.DetectionResult) []reviewComment {
+	type key struct {
+		path string
+		line int
+	}
+	bodies := make(map[key][]string)
+	var keys []key
+	for _, ruleResult := range result.RuleResults {
+		if !ruleResult.Detected {
+			continue
+		}
+		for _, match := range ruleResult.Matches {
+			if match.File == "" {
+				continue
+			}
+			k := key{match.File, match.Position.Line}
+			if _, ok := bodies[k]; !ok {
+				keys = append(keys, k)
+			}
+			finding := fmt.Sprintf("**%s**: %q", ruleResult.RuleName, match.Text)
+			if match.Reason != "" {
+				finding += " — " + match.Reason
+			}
+			bodies[k] = append(bodies[k], finding)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].path != keys[j].path {
+			return keys[i].path < keys[j].path
+		}
+		return keys[i].line < keys[j].line
+	})
+
+	comments := make([]reviewComment, 0, len(keys))
+	for _, k := range keys {
+		comments = append(comments, reviewComment{
+			Path: k.path,
+			Line: k.line,
+			Side: "RIGHT",
+			Body: strings.Join(bodies[k], "\n"),
+		})
+	}
+	return comments
+}
+
+// printJSON 以缩进的 JSON 输出
+func printJSON(v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("生成报告失败: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// printDiffHelp 打印 diff 子命令帮助信息
+func printDiffHelp() {
+	fmt.Println("用法:")
+	fmt.Println("  aigc-check diff [选项] [<提交范围>] [-- <路径>...]")
+	fmt.Println("  git diff main...HEAD | aigc-check diff [选项]")
+	fmt.Println()
+	fmt.Println("只检测统一 diff 中新增的行，未修改的内容不参与检测；Markdown、LaTeX 去除格式标记，源代码只检测注释。")
+	fmt.Println("检测发现标注为 文件:行号（新文件中的行号），可直接作为代码评审的行内评论。")
+	fmt.Println("指定提交范围时调用 git diff，参数原样传给 git；")
+	fmt.Println("不指定时从标准输入读取 diff，标准输入为终端时检测工作区中尚未暂存的修改。")
+	fmt.Println()
+	fmt.Println("选项:")
+	fmt.Println("  -c, --config <路径>    配置文件路径（可选）")
+	fmt.Println("  -format <格式>         输出格式: text, json, review（默认: text；review 输出按行合并的评审评论）")
+	fmt.Println("  --profile <名称>       规则配置档（可选，新增内容全部来自源代码时默认: code）")
+	fmt.Println("  -U, --unified <行数>   调用 git diff 时每个变更块保留的上下文行数（默认: 3）")
+	fmt.Println()
+	fmt.Println("示例:")
+	fmt.Println("  aigc-check diff main..HEAD")
+	fmt.Println("  aigc-check diff main...feature -- docs/")
+	fmt.Println("  git diff --cached | aigc-check diff -format review")
+}
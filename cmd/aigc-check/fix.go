@@ -0,0 +1,128 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/leoobai/aigc-check/internal/fixer"
+)
+
+// runFix 执行 fix 子命令：基于规则离线修复文本
+func runFix(args []string) error {
+	fs := flag.NewFlagSet("fix", flag.ContinueOnError)
+
+	var (
+		inputFile  string
+		configFile string
+		write      bool
+		dryRun     bool
+	)
+	fs.StringVar(&inputFile, "f", "", "输入文件路径")
+	fs.StringVar(&inputFile, "file", "", "输入文件路径")
+	fs.StringVar(&configFile, "c", "", "配置文件路径（可选）")
+	fs.StringVar(&configFile, "config", "", "配置文件路径（可选）")
+	fs.BoolVar(&write, "w", false, "直接修改输入文件（默认输出统一diff）")
+	fs.BoolVar(&write, "write", false, "直接修改输入文件（默认输出统一diff）")
+	fs.BoolVar(&dryRun, "dry-run", false, "仅列出将要执行的修改，不写入文件")
+	fs.Usage = printFixHelp
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+	if inputFile == "" && fs.NArg() > 0 {
+		inputFile = fs.Arg(0)
+	}
+	if inputFile == "" {
+		return fmt.Errorf("必须指定输入文件")
+	}
+
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("读取输入文件失败: %w", err)
+	}
+
+	result := fixer.NewFixer(cfg).Fix(string(content))
+
+	if dryRun {
+		printFixPlan(result)
+		return nil
+	}
+
+	if !result.Changed() {
+		fmt.Fprintln(os.Stderr, "未发现可自动修复的问题")
+		return nil
+	}
+
+	if write {
+		info, err := os.Stat(inputFile)
+		if err != nil {
+			return fmt.Errorf("读取文件信息失败: %w", err)
+		}
+		if err := os.WriteFile(inputFile, []byte(result.Fixed), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("写入文件失败: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "已修复 %s: 应用 %d 处修改", inputFile, len(result.Applied))
+		if len(result.Conflicts) > 0 {
+			fmt.Fprintf(os.Stderr, "，%d 处因重叠被跳过（可再次运行 fix）", len(result.Conflicts))
+		}
+		fmt.Fprintln(os.Stderr)
+		return nil
+	}
+
+	oldName, newName := inputFile, inputFile
+	if !filepath.IsAbs(inputFile) {
+		oldName, newName = "a/"+filepath.ToSlash(inputFile), "b/"+filepath.ToSlash(inputFile)
+	}
+	fmt.Print(fixer.UnifiedDiff(oldName, newName, result.Original, result.Fixed))
+	return nil
+}
+
+// printFixPlan 打印修改计划（--dry-run）
+func printFixPlan(result *fixer.Result) {
+	if !result.Changed() {
+		fmt.Println("未发现可自动修复的问题")
+		return
+	}
+
+	fmt.Printf("将应用 %d 处修改:\n", len(result.Applied))
+	for _, edit := range result.Applied {
+		fmt.Printf("  [%s] 偏移 %d: %q → %q (%s)\n", edit.RuleType, edit.Offset, edit.Original, edit.Replacement, edit.Reason)
+	}
+
+	if len(result.Conflicts) > 0 {
+		fmt.Printf("\n%d 处修改因范围重叠被跳过:\n", len(result.Conflicts))
+		for _, edit := range result.Conflicts {
+			fmt.Printf("  [%s] 偏移 %d: %q → %q\n", edit.RuleType, edit.Offset, edit.Original, edit.Replacement)
+		}
+	}
+}
+
+// printFixHelp 打印 fix 子命令帮助信息
+func printFixHelp() {
+	fmt.Println("用法:")
+	fmt.Println("  aigc-check fix [选项] <文件路径>")
+	fmt.Println()
+	fmt.Println("基于规则离线修复可机械处理的问题：AI引用标记、Markdown残留、破折号、")
+	fmt.Println("协作式结束语、高频词（使用配置中的同义词替换表）以及隐形字符和同形字母。")
+	fmt.Println()
+	fmt.Println("选项:")
+	fmt.Println("  -f, --file <路径>      输入文件路径")
+	fmt.Println("  -c, --config <路径>    配置文件路径（可选）")
+	fmt.Println("  -w, --write            直接修改输入文件（默认输出统一diff到标准输出）")
+	fmt.Println("  --dry-run              仅列出将要执行的修改和冲突，不写入文件")
+	fmt.Println()
+	fmt.Println("示例:")
+	fmt.Println("  aigc-check fix sample.txt")
+	fmt.Println("  aigc-check fix -w sample.txt")
+	fmt.Println("  aigc-check fix --dry-run sample.txt")
+}
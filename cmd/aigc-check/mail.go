@@ -0,0 +1,321 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leoobai/aigc-check/internal/analyzer"
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/email"
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// mailExtensions 检测目录时读取的邮件文件扩展名
+var mailExtensions = map[string]bool{".eml": true, ".mbox": true, ".mbx": true}
+
+// mailRecord 一封邮件的检测结果，以 Message-ID、发件人和日期标识
+type mailRecord struct {
+	File  string `json:"file"`
+	Index int    `json:"index"` // 在文件中的序号，从 1 开始
+	*email.Message
+	Result *models.DetectionResult `json:"result,omitempty"`
+	Error  string                  `json:"error,omitempty"`
+}
+
+// mailSummary 邮件检测汇总
+type mailSummary struct {
+	Messages   int                      `json:"messages"`
+	Analyzed   int                      `json:"analyzed"`
+	Skipped    int                      `json:"skipped"` // 没有正文的邮件
+	Filtered   int                      `json:"filtered"`
+	Failed     int                      `json:"failed"`
+	RiskLevels map[models.RiskLevel]int `json:"risk_levels"`
+}
+
+// mailScanner 逐封检测邮件并输出结果
+type mailScanner struct {
+	analyzer *analyzer.Analyzer
+	cfg      *config.Config
+	from     string
+	format   string
+	out      *bufio.Writer
+	summary  mailSummary
+}
+
+// runMail 执行 mail 子命令：逐封检测 EML 文件或 mbox 邮箱中去除引用和签名后的正文
+func runMail(args []string) error {
+	fs := flag.NewFlagSet("mail", flag.ContinueOnError)
+
+	var (
+		configFile string
+		format     string
+		profile    string
+		from       string
+	)
+	fs.StringVar(&configFile, "c", "", "配置文件路径（可选）")
+	fs.StringVar(&configFile, "config", "", "配置文件路径（可选）")
+	fs.StringVar(&format, "format", "text", "输出格式: text, jsonl")
+	fs.StringVar(&profile, "profile", "", "规则配置档（可选）")
+	fs.StringVar(&from, "from", "", "只检测发件人地址或名称包含该字符串的邮件（忽略大小写）")
+	fs.Usage = printMailHelp
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+	if format != "text" && format != "jsonl" {
+		return fmt.Errorf("不支持的输出格式: %s", format)
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("必须指定至少一个邮件文件或目录")
+	}
+
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return err
+	}
+	cfg, err = cfg.WithProfile(profile)
+	if err != nil {
+		return fmt.Errorf("加载规则配置档失败: %w", err)
+	}
+
+	s := &mailScanner{
+		analyzer: analyzer.NewAnalyzer(cfg),
+		cfg:      cfg,
+		from:     strings.ToLower(from),
+		format:   format,
+		out:      bufio.NewWriter(os.Stdout),
+		summary:  mailSummary{RiskLevels: make(map[models.RiskLevel]int)},
+	}
+	defer s.out.Flush()
+
+	for _, arg := range fs.Args() {
+		files, err := mailFiles(arg)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			if err := s.scanFile(file); err != nil {
+				return err
+			}
+		}
+	}
+
+	if format == "jsonl" {
+		s.out.Flush()
+		data, _ := json.Marshal(s.summary)
+		fmt.Fprintf(os.Stderr, "%s\n", data)
+		return nil
+	}
+	s.printSummary()
+	return nil
+}
+
+// mailFiles 展开参数：文件原样返回，目录递归查找 .eml、.mbox 和 .mbx 文件
+func mailFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取 %s 失败: %w", path, err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && mailExtensions[strings.ToLower(filepath.Ext(p))] {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("遍历目录 %s 失败: %w", path, err)
+	}
+	return files, nil
+}
+
+// scanFile 检测一个邮件文件：mbox 邮箱逐封读取，其他文件作为单封邮件
+func (s *mailScanner) scanFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("打开 %s 失败: %w", path, err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	head, _ := r.Peek(512)
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".mbox" || ext == ".mbx" || email.IsMbox(head) {
+		index := 0
+		err := email.ReadMbox(r, func(raw []byte) error {
+			index++
+			return s.scan(path, index, raw)
+		})
+		if err != nil {
+			return fmt.Errorf("读取 %s 失败: %w", path, err)
+		}
+		return nil
+	}
+
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("读取 %s 失败: %w", path, err)
+	}
+	return s.scan(path, 1, raw)
+}
+
+// scan 检测一封邮件并输出结果，单封邮件的错误只记录在结果中
+func (s *mailScanner) scan(file string, index int, raw []byte) error {
+	s.summary.Messages++
+	record := &mailRecord{File: file, Index: index}
+
+	m, err := email.Parse(raw)
+	record.Message = m
+	if m != nil && s.from != "" &&
+		!strings.Contains(strings.ToLower(m.From), s.from) && !strings.Contains(strings.ToLower(m.FromName), s.from) {
+		s.summary.Filtered++
+		return nil
+	}
+	switch {
+	case errors.Is(err, email.ErrNoBody):
+		s.summary.Skipped++
+		record.Error = "去除引用和签名后没有正文"
+	case err != nil:
+		s.summary.Failed++
+		record.Error = err.Error()
+	default:
+		if err := s.analyze(record); err != nil {
+			s.summary.Failed++
+			record.Error = err.Error()
+		}
+	}
+	return s.write(record)
+}
+
+// analyze 检测邮件正文，检测结果标注段落位置
+func (s *mailScanner) analyze(record *mailRecord) error {
+	doc, err := record.Document()
+	if err != nil {
+		return fmt.Errorf("提取正文失败: %w", err)
+	}
+	result, err := s.analyzer.Analyze(models.DetectionRequest{
+		Text:    doc.Text,
+		Options: models.DetectionOptions{Language: s.cfg.Output.Language},
+	})
+	if err != nil {
+		return fmt.Errorf("分析失败: %w", err)
+	}
+	doc.MapResult(result)
+	record.Result = result
+	s.summary.Analyzed++
+	s.summary.RiskLevels[result.RiskLevel]++
+	return nil
+}
+
+// write 输出一封邮件的检测结果
+func (s *mailScanner) write(record *mailRecord) error {
+	if s.format == "jsonl" {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("序列化结果失败: %w", err)
+		}
+		s.out.Write(data)
+		s.out.WriteByte('\n')
+		return nil
+	}
+
+	w := s.out
+	fmt.Fprintf(w, "[%s #%d]", record.File, record.Index)
+	if record.Message != nil && record.ID != "" {
+		fmt.Fprintf(w, " <%s>", record.ID)
+	}
+	fmt.Fprintln(w)
+	if m := record.Message; m != nil {
+		if m.FromName != "" {
+			fmt.Fprintf(w, "  发件人: %s <%s>\n", m.FromName, m.From)
+		} else {
+			fmt.Fprintf(w, "  发件人: %s\n", m.From)
+		}
+		if m.Date != "" {
+			fmt.Fprintf(w, "  日期: %s\n", m.Date)
+		}
+		if m.Subject != "" {
+			fmt.Fprintf(w, "  主题: %s\n", m.Subject)
+		}
+	}
+	if record.Error != "" {
+		fmt.Fprintf(w, "  错误: %s\n\n", record.Error)
+		return nil
+	}
+
+	result := record.Result
+	fmt.Fprintf(w, "  总分: %.1f（%s）\n", result.Score.Total, result.RiskLevel.Description())
+	for _, rule := range result.RuleResults {
+		if !rule.Detected {
+			continue
+		}
+		fmt.Fprintf(w, "  - %s\n", rule.RuleName)
+		for _, match := range rule.Matches[:min(len(rule.Matches), 3)] {
+			location := match.Location
+			if location == "" {
+				location = fmt.Sprintf("行%d", match.Position.Line)
+			}
+			fmt.Fprintf(w, "      %s: %s\n", location, match.Text)
+		}
+		if len(rule.Matches) > 3 {
+			fmt.Fprintf(w, "      ... 还有 %d 处\n", len(rule.Matches)-3)
+		}
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+// printSummary 输出邮件检测汇总
+func (s *mailScanner) printSummary() {
+	w := s.out
+	sum := s.summary
+	fmt.Fprintf(w, "共 %d 封邮件：检测 %d，无正文 %d，失败 %d", sum.Messages, sum.Analyzed, sum.Skipped, sum.Failed)
+	if sum.Filtered > 0 {
+		fmt.Fprintf(w, "，发件人不匹配 %d", sum.Filtered)
+	}
+	fmt.Fprintln(w)
+	for _, level := range riskLevelOrder {
+		if n := sum.RiskLevels[level]; n > 0 {
+			fmt.Fprintf(w, "  %s: %d\n", level.Description(), n)
+		}
+	}
+}
+
+// printMailHelp 打印 mail 子命令帮助信息
+func printMailHelp() {
+	fmt.Println("用法:")
+	fmt.Println("  aigc-check mail [选项] <EML 文件、mbox 邮箱或目录>...")
+	fmt.Println()
+	fmt.Println("逐封检测邮件：解析 MIME 结构，优先取 text/plain 正文（没有时把 text/html 转为纯文本），")
+	fmt.Println("去除回复中引用的历史邮件和签名后单独检测每封邮件，结果以 Message-ID、发件人和日期标识。")
+	fmt.Println("mbox 邮箱逐封流式读取；目录中的 .eml、.mbox、.mbx 文件被递归检测。")
+	fmt.Println()
+	fmt.Println("选项:")
+	fmt.Println("  -format <格式>            输出格式: text, jsonl（每封邮件一行 JSON，汇总输出到标准错误；默认: text）")
+	fmt.Println("  --from <字符串>           只检测发件人地址或名称包含该字符串的邮件，如客服团队的域名")
+	fmt.Println("  --profile <名称>          规则配置档（可选）")
+	fmt.Println("  -c, --config <路径>       配置文件路径（可选）")
+	fmt.Println()
+	fmt.Println("示例:")
+	fmt.Println("  aigc-check mail reply.eml")
+	fmt.Println("  aigc-check mail --from @support.example.com export.mbox")
+	fmt.Println("  aigc-check mail -format jsonl tickets/ > results.jsonl")
+}
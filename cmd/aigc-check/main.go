@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -8,15 +9,20 @@ import (
 
 	"github.com/leoobai/aigc-check/internal/analyzer"
 	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/extract"
 	"github.com/leoobai/aigc-check/internal/models"
 	"github.com/leoobai/aigc-check/internal/reporter"
 )
 
-const version = "2.0.0"
+const version = analyzer.Version
 
 // runOptions 运行选项
 type runOptions struct {
 	inputFile        string
+	inputFormat      string
+	footnotes        bool
+	comments         bool
+	profile          string
 	outputFile       string
 	format           string
 	configFile       string
@@ -28,9 +34,55 @@ type runOptions struct {
 }
 
 func main() {
+	// 子命令
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "fix":
+			if err := runFix(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "db":
+			if err := runDB(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "compare":
+			if err := runCompare(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "diff":
+			if err := runDiff(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "corpus":
+			if err := runCorpus(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "mail":
+			if err := runMail(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	// 定义命令行参数
 	var (
 		inputFile       string
+		inputFormat     string
+		footnotes       bool
+		comments        bool
+		profile         string
 		outputFile      string
 		format          string
 		configFile      string
@@ -45,6 +97,10 @@ func main() {
 
 	flag.StringVar(&inputFile, "f", "", "输入文件路径")
 	flag.StringVar(&inputFile, "file", "", "输入文件路径")
+	flag.StringVar(&inputFormat, "input-format", "auto", "输入格式: auto, text, markdown, html, docx, odt, pdf, latex, srt, vtt, ipynb, epub, go, python, javascript, typescript, java, c")
+	flag.BoolVar(&footnotes, "footnotes", false, "同时检测脚注和尾注（DOCX、ODT、LaTeX）")
+	flag.BoolVar(&comments, "comments", false, "同时检测批注（DOCX、ODT）")
+	flag.StringVar(&profile, "profile", "", "规则配置档，源代码默认使用 code")
 	flag.StringVar(&outputFile, "o", "", "输出文件路径（可选）")
 	flag.StringVar(&outputFile, "output", "", "输出文件路径（可选）")
 	flag.StringVar(&format, "format", "text", "输出格式: text, json")
@@ -89,6 +145,10 @@ func main() {
 	// 运行检测
 	opts := runOptions{
 		inputFile:        inputFile,
+		inputFormat:      inputFormat,
+		footnotes:        footnotes,
+		comments:         comments,
+		profile:          profile,
 		outputFile:       outputFile,
 		format:           format,
 		configFile:       configFile,
@@ -107,23 +167,9 @@ func main() {
 // run 执行检测流程
 func run(opts runOptions) error {
 	// 加载配置
-	var cfg *config.Config
-	var err error
-
-	if opts.configFile != "" {
-		cfg, err = config.LoadConfig(opts.configFile)
-		if err != nil {
-			return fmt.Errorf("加载配置文件失败: %w", err)
-		}
-	} else {
-		// 尝试加载默认配置
-		defaultConfigPath := filepath.Join("configs", "aigc-check.yaml")
-		cfg, err = config.LoadConfig(defaultConfigPath)
-		if err != nil {
-			// 使用默认配置
-			defaultCfg := config.DefaultConfig
-			cfg = &defaultCfg
-		}
+	cfg, err := loadConfig(opts.configFile)
+	if err != nil {
+		return err
 	}
 
 	// 如果命令行指定了格式，覆盖配置
@@ -155,10 +201,28 @@ func run(opts runOptions) error {
 		return fmt.Errorf("读取输入文件失败: %w", err)
 	}
 
-	text := string(content)
-	if text == "" {
+	// 提取纯文本，未指定输入格式时根据文件内容和扩展名识别
+	doc, err := extractInput(opts.inputFile, opts.inputFormat, content, extract.Options{
+		Footnotes: opts.footnotes,
+		Comments:  opts.comments,
+	})
+	if errors.Is(err, extract.ErrNoText) {
 		return fmt.Errorf("输入文件为空")
 	}
+	if err != nil {
+		return fmt.Errorf("提取文本失败: %w", err)
+	}
+	text := doc.Text
+
+	// 源代码只检测注释和文档字符串，未指定规则配置档时使用 code 配置档
+	profile := opts.profile
+	if profile == "" && extract.IsSourceCode(doc.Format) {
+		profile = config.ProfileCode
+	}
+	cfg, err = cfg.WithProfile(profile)
+	if err != nil {
+		return fmt.Errorf("加载规则配置档失败: %w", err)
+	}
 
 	// 创建分析器
 	a := analyzer.NewAnalyzer(cfg)
@@ -171,12 +235,18 @@ func run(opts runOptions) error {
 			OutputFormat: cfg.Output.DefaultFormat,
 		},
 	}
+	if doc.Format == extract.FormatMarkdown {
+		request.Options.InputFormat = models.InputFormatMarkdown
+	}
 
 	result, err := a.Analyze(request)
 	if err != nil {
 		return fmt.Errorf("分析失败: %w", err)
 	}
 
+	// 报告中的位置指向原文件（Markdown、源代码只检测了正文或注释，需要映射回原文件；DOCX 等没有行号的格式标注段落位置）
+	doc.MapResult(result)
+
 	// 生成报告
 	var rep reporter.Reporter
 	switch cfg.Output.DefaultFormat {
@@ -208,15 +278,71 @@ func run(opts runOptions) error {
 	return nil
 }
 
+// loadConfig 加载配置文件，未指定时尝试默认路径，失败则使用默认配置
+func loadConfig(configFile string) (*config.Config, error) {
+	if configFile != "" {
+		cfg, err := config.LoadConfig(configFile)
+		if err != nil {
+			return nil, fmt.Errorf("加载配置文件失败: %w", err)
+		}
+		return cfg, nil
+	}
+
+	// 尝试加载默认配置
+	defaultConfigPath := filepath.Join("configs", "aigc-check.yaml")
+	cfg, err := config.LoadConfig(defaultConfigPath)
+	if err != nil {
+		// 使用默认配置
+		defaultCfg := config.DefaultConfig
+		cfg = &defaultCfg
+	}
+	return cfg, nil
+}
+
+// extractInput 按指定格式提取输入文件的文本，format 为 auto 或空时根据文件内容和扩展名识别
+func extractInput(filename, format string, content []byte, opts extract.Options) (*extract.Document, error) {
+	if format == "" || format == "auto" {
+		return extract.Extract(filename, content, opts)
+	}
+	f, err := extract.ParseFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := extract.ExtractAs(f, content, opts)
+	if err != nil {
+		return nil, err
+	}
+	doc.Name = filename
+	return doc, nil
+}
+
 // printHelp 打印帮助信息
 func printHelp() {
 	fmt.Println("AIGC-Check - AI生成内容检测工具")
 	fmt.Println()
 	fmt.Println("用法:")
 	fmt.Println("  aigc-check -f <文件路径> [选项]")
+	fmt.Println("  aigc-check fix [选项] <文件路径>")
+	fmt.Println("  aigc-check db <migrate|rollback|status> [选项]")
+	fmt.Println("  aigc-check compare [选项] <旧版本文件> <新版本文件>")
+	fmt.Println("  aigc-check diff [选项] [<提交范围>] [-- <路径>...]")
+	fmt.Println("  aigc-check corpus [选项] <JSONL 或 CSV 文件>")
+	fmt.Println("  aigc-check mail [选项] <EML 文件、mbox 邮箱或目录>...")
+	fmt.Println()
+	fmt.Println("子命令:")
+	fmt.Println("  fix                    基于规则离线自动修复文本（使用 aigc-check fix -h 查看选项）")
+	fmt.Println("  db                     管理检测历史数据库的 schema 迁移")
+	fmt.Println("  compare                比较同一文档两个版本的检测结果")
+	fmt.Println("  diff                   只检测 git diff 或标准输入中统一 diff 新增的行")
+	fmt.Println("  corpus                 流式检测 JSONL/CSV 语料的每一行并写回结果，支持中断后继续")
+	fmt.Println("  mail                   逐封检测 EML/mbox 邮件去除引用和签名后的正文")
 	fmt.Println()
 	fmt.Println("选项:")
 	fmt.Println("  -f, --file <路径>      输入文件路径（必需）")
+	fmt.Println("  --input-format <格式>  输入格式: auto, text, markdown, html, docx, odt, pdf, latex, srt, vtt, ipynb, epub, go, python, javascript, typescript, java, c（默认: auto，根据文件内容和扩展名判断）")
+	fmt.Println("  --footnotes            同时检测脚注和尾注（DOCX、ODT、LaTeX，默认: false）")
+	fmt.Println("  --comments             同时检测批注（DOCX、ODT，默认: false）")
+	fmt.Println("  --profile <名称>       规则配置档（配置文件 profiles 中定义，源代码默认: code）")
 	fmt.Println("  -o, --output <路径>    输出文件路径（可选，默认输出到标准输出）")
 	fmt.Println("  -format <格式>         输出格式: text, json（默认: text）")
 	fmt.Println("  -c, --config <路径>    配置文件路径（可选）")
@@ -234,6 +360,15 @@ func printHelp() {
 	fmt.Println("  # 检测文本文件")
 	fmt.Println("  aigc-check -f sample.txt")
 	fmt.Println()
+	fmt.Println("  # 将 Markdown 文档作为纯文本检测（格式标记计入 Markdown 残留）")
+	fmt.Println("  aigc-check -f README.md --input-format text")
+	fmt.Println()
+	fmt.Println("  # 检测 Word 文档，包括脚注和批注")
+	fmt.Println("  aigc-check -f thesis.docx --footnotes --comments")
+	fmt.Println()
+	fmt.Println("  # 检测源代码中的注释和文档字符串，位置标注为 文件名:行号")
+	fmt.Println("  aigc-check -f internal/server/handler.go")
+	fmt.Println()
 	fmt.Println("  # 使用JSON格式输出")
 	fmt.Println("  aigc-check -f sample.txt -format json")
 	fmt.Println()
@@ -3,7 +3,10 @@ package analyzer
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/leoobai/aigc-check/internal/config"
 	"github.com/leoobai/aigc-check/internal/detector"
@@ -15,6 +18,9 @@ import (
 	"github.com/leoobai/aigc-check/internal/text"
 )
 
+// Version 分析器版本，随检测结果一起保存
+const Version = "2.0.0"
+
 // Analyzer 主分析器
 type Analyzer struct {
 	config           *config.Config
@@ -25,6 +31,7 @@ type Analyzer struct {
 	geminiAnalyzer   *gemini.Analyzer
 	geminiSuggester  *gemini.Suggester
 	multimodalConfig models.MultimodalConfig
+	fingerprint      string
 }
 
 // NewAnalyzer 创建分析器
@@ -33,16 +40,9 @@ func NewAnalyzer(cfg *config.Config) *Analyzer {
 	ruleEngine := detector.NewRuleEngine(cfg)
 
 	// 注册所有规则
-	ruleEngine.RegisterRule(rules.NewHighFreqWordsRule(cfg))
-	ruleEngine.RegisterRule(rules.NewSentenceStartersRule(cfg))
-	ruleEngine.RegisterRule(rules.NewFalseRangeRule(cfg))
-	ruleEngine.RegisterRule(rules.NewCitationAnomalyRule(cfg))
-	ruleEngine.RegisterRule(rules.NewEmDashRule(cfg))
-	ruleEngine.RegisterRule(rules.NewMarkdownRule(cfg))
-	ruleEngine.RegisterRule(rules.NewEmojiRule(cfg))
-	ruleEngine.RegisterRule(rules.NewKnowledgeCutoffRule(cfg))
-	ruleEngine.RegisterRule(rules.NewCollaborativeRule(cfg))
-	ruleEngine.RegisterRule(rules.NewPerfectionismRule(cfg))
+	for _, rule := range rules.All(cfg) {
+		ruleEngine.RegisterRule(rule)
+	}
 
 	// 创建统计分析器
 	statsAnalyzer := statistics.NewAnalyzer()
@@ -60,6 +60,7 @@ func NewAnalyzer(cfg *config.Config) *Analyzer {
 
 	// 多模态配置
 	multimodalConfig := models.DefaultMultimodalConfig
+	multimodalConfig.Enabled = cfg.Multimodal.Enabled
 	multimodalConfig.EnableStatistics = true
 	multimodalConfig.EnableSemantic = cfg.Gemini.Enabled
 
@@ -72,9 +73,15 @@ func NewAnalyzer(cfg *config.Config) *Analyzer {
 		geminiAnalyzer:   geminiAnalyzer,
 		geminiSuggester:  geminiSuggester,
 		multimodalConfig: multimodalConfig,
+		fingerprint:      cfg.Fingerprint(),
 	}
 }
 
+// Fingerprint 返回分析器所用配置的指纹
+func (a *Analyzer) Fingerprint() string {
+	return a.fingerprint
+}
+
 // Analyze 执行完整分析（支持多模态检测）
 func (a *Analyzer) Analyze(request models.DetectionRequest) (*models.DetectionResult, error) {
 	startTime := time.Now()
@@ -92,31 +99,117 @@ func (a *Analyzer) Analyze(request models.DetectionRequest) (*models.DetectionRe
 // analyzeSingleLayer 单层检测（传统模式）
 func (a *Analyzer) analyzeSingleLayer(request models.DetectionRequest, startTime time.Time) (*models.DetectionResult, error) {
 	// 执行规则检测
-	ruleResults := a.ruleEngine.Check(request.Text)
+	ruleResults := a.checkRules(request)
 
 	// 计算评分
 	score := a.scorer.Calculate(ruleResults)
+	reportLayer(request, models.LayerRule, score.Total)
 
 	// 生成建议
-	suggestions := a.generateSuggestions(ruleResults)
+	suggestions := a.generateSuggestions(request.Text, ruleResults)
 
 	// 构建结果
 	result := &models.DetectionResult{
-		RequestID:   generateRequestID(),
-		Text:        request.Text,
-		Score:       score,
-		RuleResults: ruleResults,
-		Suggestions: suggestions,
-		RiskLevel:   models.GetRiskLevel(score.Total),
-		ProcessTime: time.Since(startTime),
-		DetectedAt:  time.Now(),
+		RequestID:         generateRequestID(),
+		Text:              request.Text,
+		Score:             score,
+		RuleResults:       ruleResults,
+		Suggestions:       suggestions,
+		RiskLevel:         models.GetRiskLevel(score.Total),
+		ProcessTime:       time.Since(startTime),
+		DetectedAt:        time.Now(),
+		AnalyzerVersion:   Version,
+		ConfigFingerprint: a.fingerprint,
 	}
 
 	return result, nil
 }
 
+// checkRules 执行规则检测
+// Markdown 输入的格式标记在提取正文时已经去除，此时 MarkdownRule 只检测渲染后仍残留在正文中的标记，配置为 off 时不执行
+func (a *Analyzer) checkRules(request models.DetectionRequest) []models.RuleResult {
+	if request.Options.InputFormat != models.InputFormatMarkdown ||
+		a.config.Thresholds.MarkdownResidue.MarkdownInput != config.MarkdownInputOff {
+		return a.ruleEngine.Check(request.Text)
+	}
+
+	var ruleTypes []models.RuleType
+	for _, rule := range a.ruleEngine.GetAllRules() {
+		if rule.GetType() != models.RuleTypeMarkdown {
+			ruleTypes = append(ruleTypes, rule.GetType())
+		}
+	}
+	return a.ruleEngine.CheckWithRules(request.Text, ruleTypes)
+}
+
+// suggestionTemplate 规则建议模板
+type suggestionTemplate struct {
+	category    models.SuggestionCategory
+	priority    models.Priority
+	title       string
+	description string
+}
+
+// suggestionTemplates 各规则对应的建议模板
+var suggestionTemplates = map[models.RuleType]suggestionTemplate{
+	models.RuleTypeHighFreqWords: {
+		models.CategoryVocabulary, models.PriorityHigh,
+		"减少AI常用高频词汇",
+		"避免过度使用 crucial, pivotal, vital 等AI常用词汇，使用更自然、多样化的表达方式。",
+	},
+	models.RuleTypeSentenceStarters: {
+		models.CategorySentence, models.PriorityHigh,
+		"增加句式开头的多样性",
+		"避免重复使用 Additionally, Furthermore, Moreover 等连接词开头，尝试使用更多样化的句式结构。",
+	},
+	models.RuleTypeCitationAnomaly: {
+		models.CategoryFormatting, models.PriorityHigh,
+		"清理AI生成的引用标记",
+		"移除所有AI生成的UTM参数、幽灵标记和占位符日期。",
+	},
+	models.RuleTypeEmDash: {
+		models.CategorySentence, models.PriorityMedium,
+		"减少破折号的使用",
+		"适度使用破折号（—），过度使用会显得不自然。考虑使用其他标点符号或句式结构。",
+	},
+	models.RuleTypeMarkdown: {
+		models.CategoryFormatting, models.PriorityHigh,
+		"清理Markdown格式残留",
+		"移除所有Markdown格式标记，如 ##, **, []() 等，确保文本格式干净。",
+	},
+	models.RuleTypeKnowledgeCutoff: {
+		models.CategoryAuthenticity, models.PriorityHigh,
+		"移除AI知识截止短语",
+		"删除'截至我的知识更新'等明显的AI特征短语。",
+	},
+	models.RuleTypeCollaborative: {
+		models.CategoryTone, models.PriorityHigh,
+		"调整协作式语气",
+		"移除'希望这能帮到你'等AI助手特有的协作式语气，使用更自然的表达方式。",
+	},
+	models.RuleTypePerfectionism: {
+		models.CategoryAuthenticity, models.PriorityHigh,
+		"增加个人化表达",
+		"适当使用第一人称、情感词汇和不确定性表达，使文本更具人类特征。",
+	},
+	models.RuleTypeHiddenChars: {
+		models.CategoryFormatting, models.PriorityHigh,
+		"清除隐形字符和同形字母",
+		"删除零宽空格、零宽连接符、窄不换行空格等隐形字符，并将混入单词的西里尔、希腊字母换回拉丁字母。",
+	},
+	models.RuleTypeParallelStructure: {
+		models.CategorySentence, models.PriorityMedium,
+		"减少三段式列举和排比句式",
+		"避免反复使用'快速、可靠且可扩展'式的三项列举和'既…又…还…'等工整结构，列举项数随内容而定，相邻句子的句式长短交错。",
+	},
+}
+
+// maxSuggestionExamples 每条建议从原文生成的最大示例数
+const maxSuggestionExamples = 3
+
 // generateSuggestions 生成改进建议
-func (a *Analyzer) generateSuggestions(results []models.RuleResult) []models.Suggestion {
+// 每条建议锚定到规则匹配的具体位置；实现了 models.Fixer 的规则提供可直接应用的替换候选
+func (a *Analyzer) generateSuggestions(text string, results []models.RuleResult) []models.Suggestion {
 	var suggestions []models.Suggestion
 
 	for _, result := range results {
@@ -124,114 +217,105 @@ func (a *Analyzer) generateSuggestions(results []models.RuleResult) []models.Sug
 			continue
 		}
 
-		// 根据规则类型生成建议
-		switch result.RuleType {
-		case models.RuleTypeHighFreqWords:
-			suggestion := models.NewSuggestion(
-				models.CategoryVocabulary,
-				models.PriorityHigh,
-				"减少AI常用高频词汇",
-				"避免过度使用 crucial, pivotal, vital 等AI常用词汇，使用更自然、多样化的表达方式。",
-				result.RuleType,
-			)
-			suggestion.AddExample(
-				"This is a crucial step in the process.",
-				"This is an important step in the process.",
-				"使用更自然的词汇替代AI高频词",
-			)
-			suggestions = append(suggestions, suggestion)
-
-		case models.RuleTypeSentenceStarters:
-			suggestion := models.NewSuggestion(
-				models.CategorySentence,
-				models.PriorityHigh,
-				"增加句式开头的多样性",
-				"避免重复使用 Additionally, Furthermore, Moreover 等连接词开头，尝试使用更多样化的句式结构。",
-				result.RuleType,
-			)
-			suggestion.AddExample(
-				"Additionally, we need to consider the cost. Furthermore, the timeline is important.",
-				"We also need to consider the cost. The timeline matters too.",
-				"使用更自然的连接方式",
-			)
-			suggestions = append(suggestions, suggestion)
-
-		case models.RuleTypeCitationAnomaly:
-			suggestion := models.NewSuggestion(
-				models.CategoryFormatting,
-				models.PriorityHigh,
-				"清理AI生成的引用标记",
-				"移除所有AI生成的UTM参数、幽灵标记和占位符日期。",
-				result.RuleType,
-			)
-			suggestions = append(suggestions, suggestion)
-
-		case models.RuleTypeEmDash:
-			suggestion := models.NewSuggestion(
-				models.CategorySentence,
-				models.PriorityMedium,
-				"减少破折号的使用",
-				"适度使用破折号（—），过度使用会显得不自然。考虑使用其他标点符号或句式结构。",
-				result.RuleType,
-			)
-			suggestions = append(suggestions, suggestion)
-
-		case models.RuleTypeMarkdown:
-			suggestion := models.NewSuggestion(
-				models.CategoryFormatting,
-				models.PriorityHigh,
-				"清理Markdown格式残留",
-				"移除所有Markdown格式标记，如 ##, **, []() 等，确保文本格式干净。",
-				result.RuleType,
-			)
-			suggestions = append(suggestions, suggestion)
-
-		case models.RuleTypeKnowledgeCutoff:
-			suggestion := models.NewSuggestion(
-				models.CategoryAuthenticity,
-				models.PriorityHigh,
-				"移除AI知识截止短语",
-				"删除'截至我的知识更新'等明显的AI特征短语。",
-				result.RuleType,
-			)
-			suggestions = append(suggestions, suggestion)
-
-		case models.RuleTypeCollaborative:
-			suggestion := models.NewSuggestion(
-				models.CategoryTone,
-				models.PriorityHigh,
-				"调整协作式语气",
-				"移除'希望这能帮到你'等AI助手特有的协作式语气，使用更自然的表达方式。",
-				result.RuleType,
-			)
-			suggestions = append(suggestions, suggestion)
-
-		case models.RuleTypePerfectionism:
-			suggestion := models.NewSuggestion(
-				models.CategoryAuthenticity,
-				models.PriorityHigh,
-				"增加个人化表达",
-				"适当使用第一人称、情感词汇和不确定性表达，使文本更具人类特征。",
-				result.RuleType,
-			)
-			suggestion.AddExample(
-				"The solution is optimal and will work perfectly.",
-				"I think this solution should work well, though we might need to adjust it.",
-				"增加个人观点和适度的不确定性",
-			)
-			suggestions = append(suggestions, suggestion)
+		tmpl, ok := suggestionTemplates[result.RuleType]
+		if !ok {
+			continue
 		}
+
+		suggestion := models.NewSuggestion(tmpl.category, tmpl.priority, tmpl.title, tmpl.description, result.RuleType)
+		a.addAnchors(&suggestion, text, result)
+		suggestions = append(suggestions, suggestion)
 	}
 
+	// 按规则类型排序，保证输出稳定
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].RelatedRule < suggestions[j].RelatedRule
+	})
+
 	return suggestions
 }
 
+// addAnchors 为建议添加锚点和基于原文的示例
+func (a *Analyzer) addAnchors(suggestion *models.Suggestion, text string, result models.RuleResult) {
+	var edits []models.Edit
+	if rule, ok := a.ruleEngine.GetRule(result.RuleType); ok {
+		if fixable, ok := rule.(models.Fixer); ok {
+			edits = fixable.Fix(text, result)
+		}
+	}
+
+	// 有修复操作时以修改范围为锚点，否则以匹配项为锚点
+	if len(edits) > 0 {
+		sort.SliceStable(edits, func(i, j int) bool { return edits[i].Offset < edits[j].Offset })
+		exampled := make(map[string]bool)
+		for _, edit := range edits {
+			line, column := a.processor.GetLineColumn(text, edit.Offset)
+			suggestion.AddAnchor(edit.Original, models.Position{
+				Line:   line,
+				Column: column,
+				Offset: edit.Offset,
+				Length: edit.Length,
+			}, edit.Replacement)
+
+			// 相同的修改只生成一个示例
+			key := edit.Original + "\x00" + edit.Replacement
+			if len(suggestion.Examples) < maxSuggestionExamples && !exampled[key] {
+				exampled[key] = true
+				before, after := exampleFromEdit(text, edit)
+				suggestion.AddExample(before, after, edit.Reason)
+			}
+		}
+		return
+	}
+
+	for _, match := range result.Matches {
+		suggestion.AddAnchor(match.Text, match.Position)
+	}
+}
+
+// exampleContextRunes 示例中修改位置前后保留的字符数
+const exampleContextRunes = 30
+
+// exampleFromEdit 截取修改位置附近的原文作为修改前后的示例
+// 截取范围不超过所在行，前后各保留 exampleContextRunes 个字符
+func exampleFromEdit(text string, edit models.Edit) (string, string) {
+	start := edit.Offset
+	for n := 0; start > 0 && n < exampleContextRunes; n++ {
+		r, size := utf8.DecodeLastRuneInString(text[:start])
+		if r == '\n' {
+			break
+		}
+		start -= size
+	}
+
+	end := edit.End()
+	for n := 0; end < len(text) && n < exampleContextRunes; n++ {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if r == '\n' {
+			break
+		}
+		end += size
+	}
+
+	before := text[start:end]
+	after := text[start:edit.Offset] + edit.Replacement + text[edit.End():end]
+	return strings.TrimSpace(before), strings.TrimSpace(after)
+}
+
+// reportLayer 通知调用方某个检测层已完成
+func reportLayer(request models.DetectionRequest, layer string, score float64) {
+	if request.OnLayer != nil {
+		request.OnLayer(models.LayerResult{Layer: layer, Score: score, CompletedAt: time.Now()})
+	}
+}
+
 // analyzeMultimodal 多模态检测（分层触发策略）
 func (a *Analyzer) analyzeMultimodal(ctx context.Context, request models.DetectionRequest, startTime time.Time) (*models.DetectionResult, error) {
 	// Layer 1: 规则检测
-	ruleResults := a.ruleEngine.Check(request.Text)
+	ruleResults := a.checkRules(request)
 	ruleScore := a.scorer.Calculate(ruleResults)
 	ruleConfidence := a.calculateRuleConfidence(ruleResults, ruleScore)
+	reportLayer(request, models.LayerRule, ruleScore.Total)
 
 	// 初始化多模态结果
 	multimodal := &models.MultimodalResult{
@@ -274,6 +358,7 @@ func (a *Analyzer) continueMultimodalAnalysis(
 			AIProbability:          statsResult.AIProbability,
 			Details:                statsResult.Details,
 		}
+		reportLayer(request, models.LayerStatistics, statsResult.HumanScore)
 	}
 
 	return a.finalizeMultimodalResult(ctx, request, ruleResults, ruleScore, ruleConfidence, multimodal, startTime)
@@ -320,6 +405,7 @@ func (a *Analyzer) finalizeMultimodalResult(
 				Explanation:          analysisResult.Explanation,
 				FromCache:            false,
 			}
+			reportLayer(request, models.LayerSemantic, humanScore)
 		}
 	}
 
@@ -343,27 +429,35 @@ func (a *Analyzer) finalizeMultimodalResult(
 	multimodal.FusionExplanation = a.generateFusionExplanation(multimodal)
 
 	// 生成建议
-	suggestions := a.generateSuggestions(ruleResults)
+	suggestions := a.generateSuggestions(request.Text, ruleResults)
 
 	// 如果启用了智能建议，添加 Gemini 建议
 	if a.geminiSuggester != nil && multimodal.SemanticLayerDetails != nil {
 		issues := extractIssuesFromResults(ruleResults)
 		geminiSuggestions, err := a.geminiSuggester.GenerateSuggestions(ctx, request.Text, issues)
 		if err == nil {
-			suggestions = append(suggestions, convertGeminiSuggestions(geminiSuggestions)...)
+			suggestions = append(suggestions, a.convertGeminiSuggestions(request.Text, geminiSuggestions)...)
+			suggestions = models.DeduplicateSuggestions(suggestions)
 		}
 	}
 
 	// 构建最终结果
 	result := &models.DetectionResult{
-		RequestID:   generateRequestID(),
-		Text:        request.Text,
-		Score:       models.Score{Total: multimodal.FinalScore, Dimensions: ruleScore.Dimensions},
-		RuleResults: ruleResults,
-		Suggestions: suggestions,
-		RiskLevel:   models.GetRiskLevel(multimodal.FinalScore),
-		ProcessTime: time.Since(startTime),
-		DetectedAt:  time.Now(),
+		RequestID: generateRequestID(),
+		Text:      request.Text,
+		Score: models.Score{
+			Total:      multimodal.FinalScore,
+			Dimensions: ruleScore.Dimensions,
+			Breakdown:  ruleScore.Breakdown,
+		},
+		RuleResults:       ruleResults,
+		Suggestions:       suggestions,
+		RiskLevel:         models.GetRiskLevel(multimodal.FinalScore),
+		ProcessTime:       time.Since(startTime),
+		DetectedAt:        time.Now(),
+		Multimodal:        multimodal,
+		AnalyzerVersion:   Version,
+		ConfigFingerprint: a.fingerprint,
 	}
 
 	return result, nil
@@ -419,6 +513,7 @@ func countRedFlags(results []models.RuleResult) int {
 		models.RuleTypeKnowledgeCutoff:  true,
 		models.RuleTypeMarkdown:         true,
 		models.RuleTypeEmoji:            true,
+		models.RuleTypeHiddenChars:      true,
 	}
 
 	for _, r := range results {
@@ -470,7 +565,13 @@ func extractIssuesFromResults(results []models.RuleResult) []string {
 }
 
 // convertGeminiSuggestions 转换 Gemini 建议为标准建议格式
-func convertGeminiSuggestions(geminiSuggestions []gemini.Suggestion) []models.Suggestion {
+// 建议类型与规则类型一致时关联到对应规则，原文片段在文本中定位为锚点
+func (a *Analyzer) convertGeminiSuggestions(text string, geminiSuggestions []gemini.Suggestion) []models.Suggestion {
+	ruleTypes := make(map[models.RuleType]bool)
+	for _, ruleType := range models.GetAllRuleTypes() {
+		ruleTypes[ruleType] = true
+	}
+
 	suggestions := make([]models.Suggestion, 0, len(geminiSuggestions))
 	for _, gs := range geminiSuggestions {
 		// 根据优先级映射
@@ -481,18 +582,34 @@ func convertGeminiSuggestions(geminiSuggestions []gemini.Suggestion) []models.Su
 			priority = models.PriorityLow
 		}
 
-		suggestion := models.NewSuggestion(
-			models.CategoryAuthenticity,
-			priority,
-			gs.Title,
-			gs.Description,
-			"", // 不关联特定规则
-		)
+		// 关联规则和类别
+		var relatedRule models.RuleType
+		category := models.CategoryAuthenticity
+		if ruleType := models.RuleType(gs.Type); ruleTypes[ruleType] {
+			relatedRule = ruleType
+			if tmpl, ok := suggestionTemplates[ruleType]; ok {
+				category = tmpl.category
+			}
+		}
+
+		suggestion := models.NewSuggestion(category, priority, gs.Title, gs.Description, relatedRule)
+		suggestion.Source = models.SourceLLM
 
-		// 如果有原文和建议文本，添加为示例
+		// 如果有原文和建议文本，添加为示例并定位锚点
 		if gs.OriginalText != "" && gs.SuggestedText != "" {
 			suggestion.AddExample(gs.OriginalText, gs.SuggestedText, gs.Reason)
 		}
+		if gs.OriginalText != "" {
+			if offset := strings.Index(text, gs.OriginalText); offset != -1 {
+				line, column := a.processor.GetLineColumn(text, offset)
+				position := models.Position{Line: line, Column: column, Offset: offset, Length: len(gs.OriginalText)}
+				if gs.SuggestedText != "" {
+					suggestion.AddAnchor(gs.OriginalText, position, gs.SuggestedText)
+				} else {
+					suggestion.AddAnchor(gs.OriginalText, position)
+				}
+			}
+		}
 
 		suggestions = append(suggestions, suggestion)
 	}
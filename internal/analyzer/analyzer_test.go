@@ -122,6 +122,72 @@ func TestAnalyzer_Analyze_EmptyText(t *testing.T) {
 	}
 }
 
+func TestAnalyzer_Analyze_OnLayer(t *testing.T) {
+	for _, multimodal := range []bool{false, true} {
+		cfg := config.DefaultConfig
+		cfg.Multimodal.Enabled = multimodal
+		analyzer := NewAnalyzer(&cfg)
+
+		var layers []models.LayerResult
+		result, err := analyzer.Analyze(models.DetectionRequest{
+			Text:    "Additionally, it is crucial to delve into this. I hope this helps!",
+			OnLayer: func(layer models.LayerResult) { layers = append(layers, layer) },
+		})
+		if err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+
+		if len(layers) == 0 || layers[0].Layer != models.LayerRule {
+			t.Fatalf("multimodal=%v: layers = %+v, want rule layer first", multimodal, layers)
+		}
+		if !multimodal && (len(layers) != 1 || layers[0].Score != result.Score.Total) {
+			t.Errorf("single layer: layers = %+v, want one rule layer scoring %v", layers, result.Score.Total)
+		}
+		if multimodal && layers[0].Score != result.Multimodal.RuleLayerScore {
+			t.Errorf("multimodal: rule layer score = %v, want %v", layers[0].Score, result.Multimodal.RuleLayerScore)
+		}
+	}
+}
+
+func TestAnalyzer_Analyze_MarkdownInput(t *testing.T) {
+	text := "It has **leftover** marks and ## headings and **more** of them and **again** here."
+
+	tests := []struct {
+		name        string
+		inputFormat string
+		mode        string
+		wantRule    bool
+	}{
+		{"text input", models.InputFormatText, config.MarkdownInputOff, true},
+		{"markdown leak mode", models.InputFormatMarkdown, config.MarkdownInputLeak, true},
+		{"markdown off mode", models.InputFormatMarkdown, config.MarkdownInputOff, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig
+			cfg.Thresholds.MarkdownResidue.MarkdownInput = tt.mode
+			result, err := NewAnalyzer(&cfg).Analyze(models.DetectionRequest{
+				Text:    text,
+				Options: models.DetectionOptions{InputFormat: tt.inputFormat},
+			})
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+
+			found := false
+			for _, rr := range result.RuleResults {
+				if rr.RuleType == models.RuleTypeMarkdown {
+					found = true
+				}
+			}
+			if found != tt.wantRule {
+				t.Errorf("markdown rule ran = %v, want %v", found, tt.wantRule)
+			}
+		})
+	}
+}
+
 func TestAnalyzer_GenerateSuggestions(t *testing.T) {
 	cfg := &config.Config{
 		Thresholds: config.DefaultThresholds,
@@ -259,3 +325,41 @@ func TestAnalyzer_RedFlagDetection(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalyzer_SuggestionAnchors(t *testing.T) {
+	cfg := &config.Config{
+		Thresholds: config.DefaultThresholds,
+		Rules:      config.DefaultConfig.Rules,
+	}
+	analyzer := NewAnalyzer(cfg)
+
+	text := "This is crucial. That is crucial. Another crucial point. A crucial idea. It is pivotal and vital."
+
+	result, err := analyzer.Analyze(models.DetectionRequest{Text: text})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	var found bool
+	for _, suggestion := range result.Suggestions {
+		if suggestion.RelatedRule != models.RuleTypeHighFreqWords {
+			continue
+		}
+		found = true
+		if len(suggestion.Anchors) == 0 {
+			t.Fatal("high frequency words suggestion has no anchors")
+		}
+		for _, anchor := range suggestion.Anchors {
+			end := anchor.Position.Offset + anchor.Position.Length
+			if end > len(text) || text[anchor.Position.Offset:end] != anchor.Text {
+				t.Errorf("anchor %q does not match text span [%d:%d]", anchor.Text, anchor.Position.Offset, end)
+			}
+			if len(anchor.Replacements) == 0 {
+				t.Errorf("anchor %q has no replacement candidates", anchor.Text)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected a high frequency words suggestion")
+	}
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoobai/aigc-check/internal/service"
+)
+
+// ndjsonContentType NDJSON 流式输出的 Content-Type
+const ndjsonContentType = "application/x-ndjson"
+
+// BatchHandler 批量检测处理器
+type BatchHandler struct {
+	batchService service.BatchService
+	maxItems     int
+}
+
+// NewBatchHandler 创建批量检测处理器
+func NewBatchHandler(batchService service.BatchService, maxItems int) *BatchHandler {
+	return &BatchHandler{
+		batchService: batchService,
+		maxItems:     maxItems,
+	}
+}
+
+// BatchDetectRequest 批量检测请求
+// @Description 批量检测请求参数，options、tags、force、store 对所有条目生效
+type BatchDetectRequest struct {
+	Items   []service.BatchItem `json:"items" binding:"required,min=1"`
+	Options DetectOptions       `json:"options"`
+	Tags    []string            `json:"tags" example:"product-descriptions"`
+	Force   bool                `json:"force" example:"false"`
+	Store   *bool               `json:"store" example:"true"`
+}
+
+// BatchDetectResponse 批量检测响应
+// @Description 各条目的检测结果或错误，按请求中的顺序排列
+type BatchDetectResponse struct {
+	Summary service.BatchSummary       `json:"summary"`
+	Items   []*service.BatchItemResult `json:"items"`
+}
+
+// batchStreamSummary NDJSON 输出的最后一行
+type batchStreamSummary struct {
+	Summary *service.BatchSummary `json:"summary"`
+}
+
+// Detect 批量检测
+// @Summary      批量检测
+// @Description  以有限并发检测多个条目，单个条目失败只在该条目中返回 error；Accept 为 application/x-ndjson 或 stream=true 时按完成顺序逐行输出各条目结果，最后一行为 {"summary": {...}}
+// @Tags         detection
+// @Accept       json
+// @Produce      json,application/x-ndjson
+// @Param        request body BatchDetectRequest true "批量检测请求参数"
+// @Param        stream query bool false "以 NDJSON 流式输出"
+// @Success      200 {object} Response{data=BatchDetectResponse} "检测完成"
+// @Failure      400 {object} Response "请求参数错误"
+// @Router       /api/v1/detect/batch [post]
+func (h *BatchHandler) Detect(c *gin.Context) {
+	var req BatchDetectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+	if h.maxItems > 0 && len(req.Items) > h.maxItems {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: fmt.Sprintf("Invalid request: at most %d items per batch", h.maxItems),
+		})
+		return
+	}
+
+	single := DetectRequest{Options: req.Options, Tags: req.Tags, Force: req.Force, Store: req.Store}
+	options, err := single.toOptions()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	if wantsStream(c) {
+		h.stream(c, req.Items, options)
+		return
+	}
+
+	results := make([]*service.BatchItemResult, len(req.Items))
+	summary := h.batchService.Detect(c.Request.Context(), req.Items, options, func(result *service.BatchItemResult) {
+		results[result.Index] = result
+	})
+
+	c.JSON(http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: BatchDetectResponse{
+			Summary: *summary,
+			Items:   results,
+		},
+	})
+}
+
+// stream 按完成顺序以 NDJSON 逐行输出各条目结果
+func (h *BatchHandler) stream(c *gin.Context, items []service.BatchItem, options service.DetectionOptions) {
+	c.Header("Content-Type", ndjsonContentType)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+	summary := h.batchService.Detect(c.Request.Context(), items, options, func(result *service.BatchItemResult) {
+		if err := encoder.Encode(result); err == nil {
+			c.Writer.Flush()
+		}
+	})
+	if err := encoder.Encode(batchStreamSummary{Summary: summary}); err == nil {
+		c.Writer.Flush()
+	}
+}
+
+// wantsStream 判断客户端是否要求 NDJSON 流式输出
+func wantsStream(c *gin.Context) bool {
+	if c.Query("stream") == "true" || c.Query("stream") == "1" {
+		return true
+	}
+	return strings.Contains(c.GetHeader("Accept"), ndjsonContentType)
+}
@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
+	"github.com/leoobai/aigc-check/internal/config"
 	"github.com/leoobai/aigc-check/internal/service"
 )
 
@@ -24,8 +29,17 @@ func NewDetectionHandler(detectionService service.DetectionService) *DetectionHa
 type DetectRequest struct {
 	Text    string        `json:"text" binding:"required" example:"这是一段需要检测的文本"`
 	Options DetectOptions `json:"options"`
+	Tags    []string      `json:"tags" example:"finance,q3-report"`
+	Force   bool          `json:"force" example:"false"` // 忽略内容相同的已有结果，强制重新检测
+	Store   *bool         `json:"store" example:"true"`  // 为 false 时只保存分数和元数据，不保存原文
 }
 
+// 标签限制
+const (
+	maxTags      = 20
+	maxTagLength = 64
+)
+
 // DetectOptions 检测选项
 // @Description 检测选项配置
 type DetectOptions struct {
@@ -33,6 +47,7 @@ type DetectOptions struct {
 	EnableStatistics bool   `json:"enable_statistics" example:"false"`
 	EnableSemantic   bool   `json:"enable_semantic" example:"false"`
 	Language         string `json:"language" example:"zh"`
+	Profile          string `json:"profile" example:"code"` // 规则配置档，为空时使用全局配置
 }
 
 // Response 通用响应
@@ -53,6 +68,9 @@ type DetectionResultResponse struct {
 	RiskLevel   string  `json:"risk_level" example:"medium"`
 	ProcessTime string  `json:"process_time" example:"150ms"`
 	DetectedAt  string  `json:"detected_at" example:"2024-01-15T10:30:00Z"`
+	Cached      bool    `json:"cached" example:"false"`
+	DuplicateOf string  `json:"duplicate_of,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	SubmitCount int     `json:"submit_count" example:"1"`
 }
 
 // Detect 执行检测
@@ -77,15 +95,24 @@ func (h *DetectionHandler) Detect(c *gin.Context) {
 	}
 
 	// 转换选项
-	options := service.DetectionOptions{
-		EnableMultimodal: req.Options.EnableMultimodal,
-		EnableStatistics: req.Options.EnableStatistics,
-		EnableSemantic:   req.Options.EnableSemantic,
-		Language:         req.Options.Language,
+	options, err := req.toOptions()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
 	}
 
 	// 执行检测
 	result, err := h.detectionService.Detect(req.Text, options)
+	if errors.Is(err, config.ErrUnknownProfile) {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, Response{
 			Code:    500,
@@ -101,6 +128,48 @@ func (h *DetectionHandler) Detect(c *gin.Context) {
 	})
 }
 
+// toOptions 将请求参数转换为检测选项
+func (req *DetectRequest) toOptions() (service.DetectionOptions, error) {
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return service.DetectionOptions{}, err
+	}
+	return service.DetectionOptions{
+		EnableMultimodal: req.Options.EnableMultimodal,
+		EnableStatistics: req.Options.EnableStatistics,
+		EnableSemantic:   req.Options.EnableSemantic,
+		Language:         req.Options.Language,
+		Profile:          req.Options.Profile,
+		Tags:             tags,
+		Force:            req.Force,
+		Anonymous:        req.Store != nil && !*req.Store,
+	}, nil
+}
+
+// normalizeTags 清理并校验标签：去除空白和重复项，标签中不能包含逗号
+func normalizeTags(tags []string) ([]string, error) {
+	var result []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if strings.Contains(tag, ",") {
+			return nil, fmt.Errorf("tag must not contain comma: %q", tag)
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, fmt.Errorf("tag exceeds %d characters: %q", maxTagLength, tag)
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	if len(result) > maxTags {
+		return nil, fmt.Errorf("at most %d tags are allowed", maxTags)
+	}
+	return result, nil
+}
+
 // GetByID 根据 ID 获取检测结果
 // @Summary      获取检测结果详情
 // @Description  根据检测ID获取详细的检测结果
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoobai/aigc-check/internal/service"
+)
+
+// DocumentHandler 文档修订处理器
+type DocumentHandler struct {
+	documentService service.DocumentService
+}
+
+// NewDocumentHandler 创建文档修订处理器
+func NewDocumentHandler(documentService service.DocumentService) *DocumentHandler {
+	return &DocumentHandler{
+		documentService: documentService,
+	}
+}
+
+// CreateDocumentRequest 创建文档请求
+// @Description 创建文档请求参数
+type CreateDocumentRequest struct {
+	Title string `json:"title" binding:"max=255" example:"季度报告"`
+}
+
+// DocumentResponse 文档响应
+// @Description 文档及各修订版本的分数走势
+type DocumentResponse struct {
+	ID        string                    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Title     string                    `json:"title" example:"季度报告"`
+	Revisions []RevisionSummaryResponse `json:"revisions"`
+	CreatedAt string                    `json:"created_at" example:"2024-01-15T10:30:00Z"`
+	UpdatedAt string                    `json:"updated_at" example:"2024-01-15T10:30:00Z"`
+}
+
+// RevisionSummaryResponse 修订版本摘要
+// @Description 修订版本摘要
+type RevisionSummaryResponse struct {
+	Revision   int     `json:"revision" example:"2"`
+	RecordID   string  `json:"record_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Score      float64 `json:"score" example:"68.5"`
+	RiskLevel  string  `json:"risk_level" example:"medium"`
+	ScoreDelta float64 `json:"score_delta" example:"12.5"`
+	CreatedAt  string  `json:"created_at" example:"2024-01-15T10:30:00Z"`
+}
+
+// RevisionResponse 提交修订版本响应
+// @Description 新修订版本的修订号和检测结果
+type RevisionResponse struct {
+	DocumentID string                  `json:"document_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Revision   int                     `json:"revision" example:"2"`
+	Result     DetectionResultResponse `json:"result"`
+}
+
+// ComparisonResponse 修订版本比较响应
+// @Description 两个修订版本之间的分数变化、规则变化和标注了影响的文本变更
+type ComparisonResponse struct {
+	DocumentID   string  `json:"document_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	FromRevision int     `json:"from_revision" example:"1"`
+	ToRevision   int     `json:"to_revision" example:"2"`
+	FromScore    float64 `json:"from_score" example:"56"`
+	ToScore      float64 `json:"to_score" example:"68.5"`
+	ScoreDelta   float64 `json:"score_delta" example:"12.5"`
+}
+
+// Create 创建文档
+// @Summary      创建文档
+// @Description  创建用于跟踪多次修订的文档
+// @Tags         documents
+// @Accept       json
+// @Produce      json
+// @Param        request body CreateDocumentRequest true "文档信息"
+// @Success      200 {object} Response{data=DocumentResponse} "创建成功"
+// @Failure      400 {object} Response "请求参数错误"
+// @Failure      500 {object} Response "服务器内部错误"
+// @Router       /api/v1/documents [post]
+func (h *DocumentHandler) Create(c *gin.Context) {
+	var req CreateDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	document, err := h.documentService.Create(req.Title)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: "Failed to create document: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    document,
+	})
+}
+
+// GetByID 获取文档
+// @Summary      获取文档
+// @Description  获取文档及各修订版本的分数走势
+// @Tags         documents
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "文档ID"
+// @Success      200 {object} Response{data=DocumentResponse} "获取成功"
+// @Failure      404 {object} Response "文档不存在"
+// @Router       /api/v1/documents/{id} [get]
+func (h *DocumentHandler) GetByID(c *gin.Context) {
+	document, err := h.documentService.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, Response{
+			Code:    404,
+			Message: "Document not found: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    document,
+	})
+}
+
+// AddRevision 提交修订版本
+// @Summary      提交文档修订版本
+// @Description  检测新版本文本并追加为文档的下一个修订版本
+// @Tags         documents
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "文档ID"
+// @Param        request body DetectRequest true "检测请求参数"
+// @Success      200 {object} Response{data=RevisionResponse} "检测成功"
+// @Failure      400 {object} Response "请求参数错误"
+// @Failure      404 {object} Response "文档不存在"
+// @Failure      500 {object} Response "服务器内部错误"
+// @Router       /api/v1/documents/{id}/revisions [post]
+func (h *DocumentHandler) AddRevision(c *gin.Context) {
+	var req DetectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	options, err := req.toOptions()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	id := c.Param("id")
+	if _, err := h.documentService.Get(id); err != nil {
+		c.JSON(http.StatusNotFound, Response{
+			Code:    404,
+			Message: "Document not found: " + err.Error(),
+		})
+		return
+	}
+
+	result, err := h.documentService.AddRevision(id, req.Text, options)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: "Failed to add revision: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    result,
+	})
+}
+
+// Compare 比较修订版本
+// @Summary      比较文档修订版本
+// @Description  返回两个修订版本之间的分数变化、各规则变化、消除和新出现的匹配，以及标注了影响的文本变更
+// @Tags         documents
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "文档ID"
+// @Param        from query int false "旧修订号，默认为 to 的上一个版本"
+// @Param        to query int false "新修订号，默认为最新版本"
+// @Success      200 {object} Response{data=ComparisonResponse} "比较成功"
+// @Failure      400 {object} Response "请求参数错误"
+// @Failure      404 {object} Response "文档或修订版本不存在"
+// @Router       /api/v1/documents/{id}/compare [get]
+func (h *DocumentHandler) Compare(c *gin.Context) {
+	from, err := strconv.Atoi(c.DefaultQuery("from", "0"))
+	if err != nil || from < 0 {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: from must be a revision number",
+		})
+		return
+	}
+	to, err := strconv.Atoi(c.DefaultQuery("to", "0"))
+	if err != nil || to < 0 {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: to must be a revision number",
+		})
+		return
+	}
+
+	comparison, err := h.documentService.Compare(c.Param("id"), from, to)
+	if err != nil {
+		c.JSON(http.StatusNotFound, Response{
+			Code:    404,
+			Message: "Comparison failed: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    comparison,
+	})
+}
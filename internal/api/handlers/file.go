@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/extract"
+	"github.com/leoobai/aigc-check/internal/models"
+	"github.com/leoobai/aigc-check/internal/service"
+)
+
+// FileHandler 文件上传检测处理器
+type FileHandler struct {
+	detectionService service.DetectionService
+	config           config.UploadConfig
+}
+
+// NewFileHandler 创建文件上传检测处理器
+func NewFileHandler(detectionService service.DetectionService, cfg config.UploadConfig) *FileHandler {
+	return &FileHandler{
+		detectionService: detectionService,
+		config:           cfg,
+	}
+}
+
+// FileDetectionResponse 文件检测响应
+// @Description 提取出的文本的段落结构映射和检测结果，检测结果中的偏移量指向提取出的文本（result.text）；
+// @Description 没有行号的格式（DOCX、ODT 等）的匹配项带有段落位置描述，带修订记录的文档附修订作者汇总；
+// @Description 字幕（SRT、WebVTT）的匹配项带有时间范围，并附各句的片段得分
+type FileDetectionResponse struct {
+	File            FileInfo                `json:"file"`
+	Paragraphs      []extract.Paragraph     `json:"paragraphs"`
+	Revisions       []extract.Revision      `json:"revisions,omitempty"`
+	RevisionAuthors []models.RevisionAuthor `json:"revision_authors,omitempty"`
+	Segments        []models.SegmentScore   `json:"segments,omitempty"`
+	Result          DetectionResultResponse `json:"result"`
+}
+
+// FileInfo 上传文件信息
+// @Description 上传文件信息
+type FileInfo struct {
+	Name   string `json:"name" example:"report.docx"`
+	Format string `json:"format" example:"docx"`
+	Size   int64  `json:"size" example:"18432"`
+}
+
+// fileDetectionResult 文件检测结果
+type fileDetectionResult struct {
+	File            FileInfo                 `json:"file"`
+	Paragraphs      []extract.Paragraph      `json:"paragraphs"`
+	Revisions       []extract.Revision       `json:"revisions,omitempty"`
+	RevisionAuthors []models.RevisionAuthor  `json:"revision_authors,omitempty"`
+	Segments        []models.SegmentScore    `json:"segments,omitempty"`
+	Result          *service.DetectionResult `json:"result"`
+}
+
+// multipartOverhead multipart 请求中文件以外部分的大小余量
+const multipartOverhead = 1 << 20
+
+// Detect 上传文件检测
+// @Summary      上传文件检测
+// @Description  上传 txt、md、html、docx、odt、pdf、tex、srt、vtt 或源代码（go、py、js、ts、java、c）文件，自动识别格式并提取纯文本后检测（Markdown、LaTeX 只检测正文，不检测格式标记；字幕连句后检测，匹配项带时间范围；源代码只检测注释和文档字符串，默认使用 code 规则配置档，匹配项标注 "文件名:行号"）；返回段落结构映射，检测结果中的偏移量指向提取出的文本
+// @Tags         detection
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file formData file true "待检测文件"
+// @Param        format formData string false "指定文档格式，不指定时根据文件内容和扩展名判断"
+// @Param        footnotes formData bool false "同时检测脚注和尾注（DOCX、ODT、LaTeX）"
+// @Param        comments formData bool false "同时检测批注（DOCX、ODT）"
+// @Param        options formData string false "检测选项（JSON，同 /api/v1/detect 的 options，其中 profile 指定规则配置档）"
+// @Param        tags formData string false "标签，逗号分隔"
+// @Param        force formData bool false "忽略内容相同的已有结果，强制重新检测"
+// @Param        store formData bool false "为 false 时只保存分数和元数据，不保存原文"
+// @Success      200 {object} Response{data=FileDetectionResponse} "检测成功"
+// @Failure      400 {object} Response "请求参数错误或无法提取文本"
+// @Failure      413 {object} Response "文件过大"
+// @Failure      415 {object} Response "不支持的文件格式"
+// @Failure      500 {object} Response "服务器内部错误"
+// @Router       /api/v1/detect/file [post]
+func (h *FileHandler) Detect(c *gin.Context) {
+	maxSize := h.config.MaxSize()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.tooLarge(c)
+			return
+		}
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: file is required",
+		})
+		return
+	}
+	if header.Size > maxSize {
+		h.tooLarge(c)
+		return
+	}
+
+	req, extractOptions, err := fileDetectRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+	options, err := req.toOptions()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	format, err := h.format(c.PostForm("format"), header.Filename, data)
+	if err != nil {
+		c.JSON(http.StatusUnsupportedMediaType, Response{
+			Code:    415,
+			Message: "Unsupported file: " + err.Error(),
+		})
+		return
+	}
+
+	doc, err := extract.ExtractAs(format, data, extractOptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Failed to extract text: " + err.Error(),
+		})
+		return
+	}
+
+	if format == extract.FormatMarkdown {
+		options.InputFormat = models.InputFormatMarkdown
+	}
+	// 源代码只检测注释，未指定规则配置档时使用 code 配置档，位置标注为 "文件名:行号"
+	if extract.IsSourceCode(format) {
+		doc.Name = header.Filename
+		if options.Profile == "" {
+			options.Profile = config.ProfileCode
+		}
+	}
+
+	result, err := h.detectionService.Detect(doc.Text, options)
+	if errors.Is(err, config.ErrUnknownProfile) {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: "Detection failed: " + err.Error(),
+		})
+		return
+	}
+	locateFindings(doc, result)
+
+	c.JSON(http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: fileDetectionResult{
+			File: FileInfo{
+				Name:   header.Filename,
+				Format: string(format),
+				Size:   header.Size,
+			},
+			Paragraphs:      doc.Paragraphs,
+			Revisions:       doc.Revisions,
+			RevisionAuthors: doc.RevisionAuthors(),
+			Segments:        segmentScores(doc, result),
+			Result:          result,
+		},
+	})
+}
+
+// format 确定文档格式并检查是否在允许的格式之内
+func (h *FileHandler) format(name, filename string, data []byte) (extract.Format, error) {
+	var (
+		format extract.Format
+		err    error
+	)
+	if name != "" {
+		format, err = extract.ParseFormat(name)
+	} else {
+		format, err = extract.Detect(filename, data)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if len(h.config.AllowedTypes) == 0 {
+		return format, nil
+	}
+	for _, allowed := range h.config.AllowedTypes {
+		if extract.Format(strings.ToLower(allowed)) == format {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("%s files are not allowed", format)
+}
+
+// tooLarge 返回文件过大的响应
+func (h *FileHandler) tooLarge(c *gin.Context) {
+	c.JSON(http.StatusRequestEntityTooLarge, Response{
+		Code:    413,
+		Message: fmt.Sprintf("File too large: at most %d MB", h.config.MaxSizeMB),
+	})
+}
+
+// locateFindings 为没有行号的格式的匹配项和建议锚点设置段落位置描述，字幕设置时间范围
+func locateFindings(doc *extract.Document, result *service.DetectionResult) {
+	for _, ruleResult := range result.RuleResults {
+		for i := range ruleResult.Matches {
+			match := &ruleResult.Matches[i]
+			match.Location, match.Time = doc.Describe(match.Position)
+		}
+	}
+	for _, suggestion := range result.Suggestions {
+		for i := range suggestion.Anchors {
+			anchor := &suggestion.Anchors[i]
+			anchor.Location, anchor.Time = doc.Describe(anchor.Position)
+		}
+	}
+}
+
+// segmentScores 计算字幕、笔记本、EPUB 等文档中各片段的得分
+func segmentScores(doc *extract.Document, result *service.DetectionResult) []models.SegmentScore {
+	ruleResults := make([]models.RuleResult, 0, len(result.RuleResults))
+	for _, ruleResult := range result.RuleResults {
+		ruleResults = append(ruleResults, *ruleResult)
+	}
+	return doc.SegmentScores(ruleResults)
+}
+
+// fileDetectRequest 从表单字段读取检测参数和文本提取选项
+func fileDetectRequest(c *gin.Context) (*DetectRequest, extract.Options, error) {
+	req := &DetectRequest{}
+	var opts extract.Options
+	if options := c.PostForm("options"); options != "" {
+		if err := json.Unmarshal([]byte(options), &req.Options); err != nil {
+			return nil, opts, fmt.Errorf("options must be a JSON object: %w", err)
+		}
+	}
+	if tags := c.PostForm("tags"); tags != "" {
+		req.Tags = strings.Split(tags, ",")
+	}
+	if force := c.PostForm("force"); force != "" {
+		value, err := strconv.ParseBool(force)
+		if err != nil {
+			return nil, opts, fmt.Errorf("force must be a boolean")
+		}
+		req.Force = value
+	}
+	if store := c.PostForm("store"); store != "" {
+		value, err := strconv.ParseBool(store)
+		if err != nil {
+			return nil, opts, fmt.Errorf("store must be a boolean")
+		}
+		req.Store = &value
+	}
+	if footnotes := c.PostForm("footnotes"); footnotes != "" {
+		value, err := strconv.ParseBool(footnotes)
+		if err != nil {
+			return nil, opts, fmt.Errorf("footnotes must be a boolean")
+		}
+		opts.Footnotes = value
+	}
+	if comments := c.PostForm("comments"); comments != "" {
+		value, err := strconv.ParseBool(comments)
+		if err != nil {
+			return nil, opts, fmt.Errorf("comments must be a boolean")
+		}
+		opts.Comments = value
+	}
+	return req, opts, nil
+}
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/leoobai/aigc-check/internal/models"
 	"github.com/leoobai/aigc-check/internal/service"
 )
 
@@ -32,12 +36,19 @@ type HistoryListResponse struct {
 // HistoryItemResult 历史记录项
 // @Description 历史记录条目
 type HistoryItemResult struct {
-	ID          string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	RequestID   string  `json:"request_id" example:"req-12345"`
-	TextPreview string  `json:"text_preview" example:"这是检测文本的预览..."`
-	Score       float64 `json:"score" example:"75.5"`
-	RiskLevel   string  `json:"risk_level" example:"medium"`
-	CreatedAt   string  `json:"created_at" example:"2024-01-15 10:30:00"`
+	ID            string   `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	RequestID     string   `json:"request_id" example:"req-12345"`
+	TextPreview   string   `json:"text_preview" example:"这是检测文本的预览..."`
+	Score         float64  `json:"score" example:"75.5"`
+	RiskLevel     string   `json:"risk_level" example:"medium"`
+	DetectedRules []string `json:"detected_rules" example:"knowledge_cutoff,markdown_residue"`
+	Tags          []string `json:"tags" example:"finance"`
+	Snippet       string   `json:"snippet,omitempty" example:"…本季度的<mark>季度报告</mark>显示…"`
+	DuplicateOf   string   `json:"duplicate_of,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	SubmitCount   int      `json:"submit_count" example:"3"`
+	Duplicates    int64    `json:"duplicates" example:"1"`
+	TextPurged    bool     `json:"text_purged" example:"false"`
+	CreatedAt     string   `json:"created_at" example:"2024-01-15 10:30:00"`
 }
 
 // allowedSortColumns 允许的排序字段白名单
@@ -47,6 +58,23 @@ var allowedSortColumns = map[string]bool{
 	"risk_level": true,
 }
 
+// allowedRiskLevels 允许的风险等级过滤值
+var allowedRiskLevels = map[string]bool{
+	string(models.RiskLevelVeryHigh): true,
+	string(models.RiskLevelHigh):     true,
+	string(models.RiskLevelMedium):   true,
+	string(models.RiskLevelLow):      true,
+}
+
+// allowedRuleTypes 允许的规则类型过滤值
+var allowedRuleTypes = func() map[string]bool {
+	ruleTypes := make(map[string]bool)
+	for _, ruleType := range models.GetAllRuleTypes() {
+		ruleTypes[string(ruleType)] = true
+	}
+	return ruleTypes
+}()
+
 // allowedOrders 允许的排序方向
 var allowedOrders = map[string]bool{
 	"asc":  true,
@@ -63,6 +91,17 @@ var allowedOrders = map[string]bool{
 // @Param        page_size query int false "每页数量" default(20) minimum(1) maximum(100)
 // @Param        sort query string false "排序字段" Enums(created_at,score,risk_level) default(created_at)
 // @Param        order query string false "排序方向" Enums(asc,desc) default(desc)
+// @Param        q query string false "全文检索关键词，空格分隔的多个词必须全部出现"
+// @Param        min_score query number false "最低分数（含）"
+// @Param        max_score query number false "最高分数（含）"
+// @Param        risk_level query string false "风险等级，逗号分隔" Enums(very_high,high,medium,low)
+// @Param        from query string false "开始时间（RFC3339 或 YYYY-MM-DD）"
+// @Param        to query string false "结束时间（RFC3339 或 YYYY-MM-DD，日期包含当天）"
+// @Param        days query int false "最近 N 天，与 from 同时指定时以较晚者为准"
+// @Param        rule_type query string false "触发的规则类型，逗号分隔，必须全部触发"
+// @Param        tag query string false "元数据标签，逗号分隔，必须全部包含"
+// @Param        group query bool false "按文档分组，只返回首次提交的记录（重新检测次数见 duplicates）"
+// @Param        duplicate_of query string false "只返回关联到该记录的重新检测记录"
 // @Success      200 {object} Response{data=HistoryListResponse} "获取成功"
 // @Failure      400 {object} Response "请求参数错误"
 // @Failure      500 {object} Response "服务器内部错误"
@@ -94,8 +133,18 @@ func (h *HistoryHandler) List(c *gin.Context) {
 		order = "desc"
 	}
 
+	// 解析过滤条件
+	filter, err := parseHistoryFilter(c, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid filter: " + err.Error(),
+		})
+		return
+	}
+
 	// 获取历史记录
-	result, err := h.historyService.List(page, pageSize, sortBy, order)
+	result, err := h.historyService.List(page, pageSize, sortBy, order, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, Response{
 			Code:    500,
@@ -111,6 +160,112 @@ func (h *HistoryHandler) List(c *gin.Context) {
 	})
 }
 
+// parseHistoryFilter 解析历史记录过滤参数
+func parseHistoryFilter(c *gin.Context, now time.Time) (service.HistoryFilter, error) {
+	filter := service.HistoryFilter{
+		Query: strings.TrimSpace(c.Query("q")),
+	}
+
+	var err error
+	if filter.MinScore, err = parseScoreParam(c, "min_score"); err != nil {
+		return filter, err
+	}
+	if filter.MaxScore, err = parseScoreParam(c, "max_score"); err != nil {
+		return filter, err
+	}
+	if filter.MinScore != nil && filter.MaxScore != nil && *filter.MinScore > *filter.MaxScore {
+		return filter, fmt.Errorf("min_score must not exceed max_score")
+	}
+
+	for _, level := range queryList(c, "risk_level") {
+		if !allowedRiskLevels[level] {
+			return filter, fmt.Errorf("unknown risk_level: %s", level)
+		}
+		filter.RiskLevels = append(filter.RiskLevels, level)
+	}
+
+	if value := c.Query("from"); value != "" {
+		if filter.From, _, err = parseTimeParam(value); err != nil {
+			return filter, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		to, dateOnly, err := parseTimeParam(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid to: %w", err)
+		}
+		// 只给出日期时包含当天
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = to
+	}
+	if value := c.Query("days"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 {
+			return filter, fmt.Errorf("days must be a positive integer")
+		}
+		if since := now.AddDate(0, 0, -days); since.After(filter.From) {
+			filter.From = since
+		}
+	}
+
+	for _, ruleType := range queryList(c, "rule_type") {
+		if !allowedRuleTypes[ruleType] {
+			return filter, fmt.Errorf("unknown rule_type: %s", ruleType)
+		}
+		filter.RuleTypes = append(filter.RuleTypes, ruleType)
+	}
+	filter.Tags = queryList(c, "tag")
+
+	filter.DuplicateOf = strings.TrimSpace(c.Query("duplicate_of"))
+	if value := c.Query("group"); value != "" {
+		if filter.OriginalsOnly, err = strconv.ParseBool(value); err != nil {
+			return filter, fmt.Errorf("group must be a boolean")
+		}
+	}
+
+	return filter, nil
+}
+
+// parseScoreParam 解析分数参数
+func parseScoreParam(c *gin.Context, key string) (*float64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	score, err := strconv.ParseFloat(value, 64)
+	if err != nil || score < 0 || score > 100 {
+		return nil, fmt.Errorf("%s must be a number between 0 and 100", key)
+	}
+	return &score, nil
+}
+
+// parseTimeParam 解析时间参数，支持 RFC3339 和 YYYY-MM-DD（按本地时区）
+func parseTimeParam(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("expected RFC3339 or YYYY-MM-DD, got %q", value)
+	}
+	return t, true, nil
+}
+
+// queryList 读取列表参数，支持重复参数和逗号分隔两种形式
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
 // GetByID 根据 ID 获取历史记录
 // @Summary      获取历史记录详情
 // @Description  根据ID获取单条历史记录的详细信息
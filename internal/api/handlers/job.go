@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoobai/aigc-check/internal/service"
+	"gorm.io/gorm"
+)
+
+// JobHandler 异步检测任务处理器
+type JobHandler struct {
+	jobService service.JobService
+}
+
+// NewJobHandler 创建异步检测任务处理器
+func NewJobHandler(jobService service.JobService) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
+
+// SubmitJobRequest 提交异步检测任务请求
+// @Description 检测请求参数，以及任务结束时的回调地址
+type SubmitJobRequest struct {
+	DetectRequest
+	WebhookURL string `json:"webhook_url" binding:"max=2048" example:"https://example.com/hooks/aigc"`
+}
+
+// Submit 提交异步检测任务
+// @Summary      提交异步检测任务
+// @Description  立即返回任务 ID，通过 GET /api/v1/jobs/{id} 轮询状态；设置 webhook_url 时任务结束后回调，请求头 X-AIGC-Signature 为 HMAC-SHA256 签名；服务端未配置签名密钥或回调地址指向内网时返回 400
+// @Tags         jobs
+// @Accept       json
+// @Produce      json
+// @Param        request body SubmitJobRequest true "检测请求参数"
+// @Success      202 {object} Response{data=service.JobStatus} "已提交"
+// @Failure      400 {object} Response "请求参数错误"
+// @Failure      503 {object} Response "任务队列已满"
+// @Failure      500 {object} Response "服务器内部错误"
+// @Router       /api/v1/jobs [post]
+func (h *JobHandler) Submit(c *gin.Context) {
+	var req SubmitJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	options, err := req.toOptions()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	job, err := h.jobService.Submit(req.Text, options, req.WebhookURL)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidWebhookURL), errors.Is(err, service.ErrWebhookDisabled):
+			c.JSON(http.StatusBadRequest, Response{
+				Code:    400,
+				Message: "Invalid request: " + err.Error(),
+			})
+		case errors.Is(err, service.ErrQueueFull):
+			c.JSON(http.StatusServiceUnavailable, Response{
+				Code:    503,
+				Message: "Job queue is full, please retry later",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, Response{
+				Code:    500,
+				Message: "Failed to submit job: " + err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, Response{
+		Code:    0,
+		Message: "success",
+		Data:    job,
+	})
+}
+
+// GetByID 获取异步任务状态
+// @Summary      获取异步任务状态
+// @Description  返回任务状态（queued/running/done/failed）、已完成检测层的阶段性结果，任务完成后附带检测结果
+// @Tags         jobs
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "任务ID"
+// @Success      200 {object} Response{data=service.JobStatus} "获取成功"
+// @Failure      404 {object} Response "任务不存在"
+// @Failure      500 {object} Response "服务器内部错误"
+// @Router       /api/v1/jobs/{id} [get]
+func (h *JobHandler) GetByID(c *gin.Context) {
+	job, err := h.jobService.Get(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, Response{
+				Code:    404,
+				Message: "Job not found: " + err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: "Failed to get job: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    job,
+	})
+}
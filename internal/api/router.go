@@ -18,6 +18,10 @@ import (
 func SetupRouter(
 	detectionHandler *handlers.DetectionHandler,
 	historyHandler *handlers.HistoryHandler,
+	documentHandler *handlers.DocumentHandler,
+	jobHandler *handlers.JobHandler,
+	batchHandler *handlers.BatchHandler,
+	fileHandler *handlers.FileHandler,
 ) *gin.Engine {
 	router := gin.New()
 
@@ -70,6 +74,8 @@ func SetupRouter(
 	{
 		// 检测相关 API
 		v1.POST("/detect", detectionHandler.Detect)
+		v1.POST("/detect/batch", batchHandler.Detect)
+		v1.POST("/detect/file", fileHandler.Detect)
 		v1.GET("/detect/:id", detectionHandler.GetByID)
 
 		// 历史记录相关 API
@@ -77,6 +83,16 @@ func SetupRouter(
 		v1.GET("/history/:id", historyHandler.GetByID)
 		v1.DELETE("/history/:id", historyHandler.Delete)
 		v1.DELETE("/history", historyHandler.DeleteAll)
+
+		// 文档修订相关 API
+		v1.POST("/documents", documentHandler.Create)
+		v1.GET("/documents/:id", documentHandler.GetByID)
+		v1.POST("/documents/:id/revisions", documentHandler.AddRevision)
+		v1.GET("/documents/:id/compare", documentHandler.Compare)
+
+		// 异步检测任务相关 API
+		v1.POST("/jobs", jobHandler.Submit)
+		v1.GET("/jobs/:id", jobHandler.GetByID)
 	}
 
 	return router
@@ -0,0 +1,321 @@
+package compare
+
+import (
+	"github.com/leoobai/aigc-check/internal/fixer"
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// Comparison 两次检测结果（通常是同一文档的两个修订版本）之间的比较
+type Comparison struct {
+	FromScore     float64          `json:"from_score"`
+	ToScore       float64          `json:"to_score"`
+	ScoreDelta    float64          `json:"score_delta"` // 正值表示新版本更接近人类写作
+	FromRiskLevel models.RiskLevel `json:"from_risk_level"`
+	ToRiskLevel   models.RiskLevel `json:"to_risk_level"`
+	Dimensions    []DimensionDelta `json:"dimensions"`
+	Rules         []RuleDelta      `json:"rules"`
+	Resolved      []MatchChange    `json:"resolved"`   // 旧版本中存在、新版本中消失的匹配
+	Introduced    []MatchChange    `json:"introduced"` // 新版本中新出现的匹配
+	Edits         []Edit           `json:"edits"`      // 文本变更，标注了各自消除和引入的匹配
+}
+
+// DimensionDelta 单个评分维度的变化
+type DimensionDelta struct {
+	Name  string  `json:"name"`
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Delta float64 `json:"delta"`
+}
+
+// RuleDelta 单条规则的变化
+type RuleDelta struct {
+	RuleType   models.RuleType `json:"rule_type"`
+	RuleName   string          `json:"rule_name"`
+	FromScore  float64         `json:"from_score"`
+	ToScore    float64         `json:"to_score"`
+	ScoreDelta float64         `json:"score_delta"`
+	FromCount  int             `json:"from_count"`
+	ToCount    int             `json:"to_count"`
+	Resolved   int             `json:"resolved"`
+	Introduced int             `json:"introduced"`
+}
+
+// MatchChange 消除或新出现的匹配
+type MatchChange struct {
+	RuleType models.RuleType `json:"rule_type"`
+	Text     string          `json:"text"`
+	Position models.Position `json:"position"` // 消除的匹配为旧版本中的位置，新匹配为新版本中的位置
+	Edit     int             `json:"edit"`     // 引起该变化的文本变更下标，无法归因于具体变更时为 -1
+}
+
+// Edit 一处文本变更及其对检测结果的影响
+type Edit struct {
+	fixer.Change
+	Resolved   []MatchChange     `json:"resolved"`
+	Introduced []MatchChange     `json:"introduced"`
+	Rules      []models.RuleType `json:"rules"` // 该变更涉及且评分或匹配数量发生变化的规则，总分不变时为空
+}
+
+// MovedScore 该变更是否影响了分数
+func (e *Edit) MovedScore() bool {
+	return len(e.Rules) > 0
+}
+
+// Compare 比较两次检测结果
+func Compare(from, to *models.DetectionResult) *Comparison {
+	c := &Comparison{
+		FromScore:     from.Score.Total,
+		ToScore:       to.Score.Total,
+		ScoreDelta:    to.Score.Total - from.Score.Total,
+		FromRiskLevel: from.RiskLevel,
+		ToRiskLevel:   to.RiskLevel,
+		Dimensions:    compareDimensions(from.Score.Dimensions, to.Score.Dimensions),
+	}
+
+	changes := fixer.Changes(from.Text, to.Text)
+	c.Edits = make([]Edit, len(changes))
+	for i, change := range changes {
+		c.Edits[i] = Edit{Change: change}
+	}
+
+	c.Resolved, c.Introduced = diffMatches(from.RuleResults, to.RuleResults, changes)
+	c.Rules = compareRules(from.RuleResults, to.RuleResults, c.Resolved, c.Introduced)
+
+	// 将匹配变化归入对应的文本变更，并标注影响分数的规则
+	// 维度评分由各规则的匹配数量计算，规则评分不变时匹配数量的变化同样会影响总分
+	moved := make(map[models.RuleType]bool)
+	for _, rule := range c.Rules {
+		if c.ScoreDelta != 0 && (rule.ScoreDelta != 0 || rule.FromCount != rule.ToCount) {
+			moved[rule.RuleType] = true
+		}
+	}
+	for _, m := range c.Resolved {
+		if m.Edit >= 0 {
+			c.Edits[m.Edit].Resolved = append(c.Edits[m.Edit].Resolved, m)
+		}
+	}
+	for _, m := range c.Introduced {
+		if m.Edit >= 0 {
+			c.Edits[m.Edit].Introduced = append(c.Edits[m.Edit].Introduced, m)
+		}
+	}
+	for i := range c.Edits {
+		seen := make(map[models.RuleType]bool)
+		for _, m := range append(append([]MatchChange{}, c.Edits[i].Resolved...), c.Edits[i].Introduced...) {
+			if moved[m.RuleType] && !seen[m.RuleType] {
+				seen[m.RuleType] = true
+				c.Edits[i].Rules = append(c.Edits[i].Rules, m.RuleType)
+			}
+		}
+	}
+
+	return c
+}
+
+// compareDimensions 比较五个评分维度
+func compareDimensions(from, to models.DimensionScores) []DimensionDelta {
+	pairs := []struct {
+		name     string
+		from, to models.DimensionScore
+	}{
+		{"vocabulary_diversity", from.VocabularyDiversity, to.VocabularyDiversity},
+		{"sentence_complexity", from.SentenceComplexity, to.SentenceComplexity},
+		{"personalization", from.Personalization, to.Personalization},
+		{"logical_coherence", from.LogicalCoherence, to.LogicalCoherence},
+		{"emotional_authenticity", from.EmotionalAuthenticity, to.EmotionalAuthenticity},
+	}
+
+	deltas := make([]DimensionDelta, len(pairs))
+	for i, p := range pairs {
+		deltas[i] = DimensionDelta{
+			Name:  p.name,
+			From:  p.from.Score,
+			To:    p.to.Score,
+			Delta: p.to.Score - p.from.Score,
+		}
+	}
+	return deltas
+}
+
+// compareRules 按规则汇总评分和匹配数量的变化，规则顺序与 models.GetAllRuleTypes 一致
+func compareRules(from, to []models.RuleResult, resolved, introduced []MatchChange) []RuleDelta {
+	index := make(map[models.RuleType]*RuleDelta)
+	var order []models.RuleType
+	get := func(result models.RuleResult) *RuleDelta {
+		delta, ok := index[result.RuleType]
+		if !ok {
+			delta = &RuleDelta{RuleType: result.RuleType, RuleName: result.RuleName}
+			index[result.RuleType] = delta
+			order = append(order, result.RuleType)
+		}
+		return delta
+	}
+
+	for _, result := range from {
+		delta := get(result)
+		delta.FromScore = result.Score
+		delta.FromCount = len(result.Matches)
+	}
+	for _, result := range to {
+		delta := get(result)
+		delta.ToScore = result.Score
+		delta.ToCount = len(result.Matches)
+	}
+	for _, m := range resolved {
+		if delta, ok := index[m.RuleType]; ok {
+			delta.Resolved++
+		}
+	}
+	for _, m := range introduced {
+		if delta, ok := index[m.RuleType]; ok {
+			delta.Introduced++
+		}
+	}
+
+	deltas := make([]RuleDelta, 0, len(order))
+	known := make(map[models.RuleType]bool)
+	for _, ruleType := range models.GetAllRuleTypes() {
+		known[ruleType] = true
+		if delta, ok := index[ruleType]; ok {
+			deltas = append(deltas, *delta)
+		}
+	}
+	for _, ruleType := range order {
+		if !known[ruleType] {
+			deltas = append(deltas, *index[ruleType])
+		}
+	}
+	for i := range deltas {
+		deltas[i].ScoreDelta = deltas[i].ToScore - deltas[i].FromScore
+	}
+	return deltas
+}
+
+// located 带规则类型的匹配
+type located struct {
+	ruleType models.RuleType
+	match    models.Match
+	used     bool
+}
+
+// diffMatches 找出消除和新出现的匹配
+// 位于未修改文本中的匹配按偏移映射到新版本后比较；位于修改范围内的匹配在对应变更的新文本中按规则和内容比较；
+// 没有位置信息的整体性匹配（长度为 0）只按规则和内容比较
+func diffMatches(from, to []models.RuleResult, changes []fixer.Change) (resolved, introduced []MatchChange) {
+	fromMatches := collectMatches(from)
+	toMatches := collectMatches(to)
+
+	for _, fm := range fromMatches {
+		start, end := fm.match.Position.Offset, fm.match.Position.Offset+fm.match.Position.Length
+		edit := -1
+		var found *located
+
+		switch {
+		case fm.match.Position.Length == 0:
+			found = findMatch(toMatches, fm, func(tm *located) bool {
+				return tm.match.Position.Length == 0
+			})
+		default:
+			edit = changeAt(changes, start, end, true)
+			if edit < 0 {
+				mapped := mapOffset(changes, start)
+				found = findMatch(toMatches, fm, func(tm *located) bool {
+					return tm.match.Position.Offset == mapped
+				})
+			} else {
+				change := changes[edit]
+				found = findMatch(toMatches, fm, func(tm *located) bool {
+					return changeAt([]fixer.Change{change}, tm.match.Position.Offset, tm.match.Position.Offset+tm.match.Position.Length, false) == 0
+				})
+			}
+		}
+
+		if found != nil {
+			found.used = true
+			continue
+		}
+		resolved = append(resolved, MatchChange{
+			RuleType: fm.ruleType,
+			Text:     fm.match.Text,
+			Position: fm.match.Position,
+			Edit:     edit,
+		})
+	}
+
+	for _, tm := range toMatches {
+		if tm.used {
+			continue
+		}
+		edit := -1
+		if tm.match.Position.Length > 0 {
+			start := tm.match.Position.Offset
+			edit = changeAt(changes, start, start+tm.match.Position.Length, false)
+		}
+		introduced = append(introduced, MatchChange{
+			RuleType: tm.ruleType,
+			Text:     tm.match.Text,
+			Position: tm.match.Position,
+			Edit:     edit,
+		})
+	}
+
+	return resolved, introduced
+}
+
+// collectMatches 收集所有规则的匹配
+func collectMatches(results []models.RuleResult) []*located {
+	var matches []*located
+	for _, result := range results {
+		for _, match := range result.Matches {
+			matches = append(matches, &located{ruleType: result.RuleType, match: match})
+		}
+	}
+	return matches
+}
+
+// findMatch 查找规则和内容相同、满足位置条件且尚未被配对的匹配
+func findMatch(candidates []*located, target *located, accept func(*located) bool) *located {
+	for _, candidate := range candidates {
+		if candidate.used || candidate.ruleType != target.ruleType || candidate.match.Text != target.match.Text {
+			continue
+		}
+		if accept(candidate) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// changeAt 返回与 [start, end) 相交的变更下标，没有时返回 -1
+// old 为 true 时按变更在旧文本中的范围判断，否则按新文本中的范围判断
+func changeAt(changes []fixer.Change, start, end int, old bool) int {
+	for i, change := range changes {
+		offset, length := change.NewOffset, len(change.Added)
+		if old {
+			offset, length = change.OldOffset, len(change.Removed)
+		}
+		if length == 0 {
+			// 纯插入只影响跨越插入点的匹配
+			if start < offset && offset < end {
+				return i
+			}
+			continue
+		}
+		if start < offset+length && offset < end {
+			return i
+		}
+	}
+	return -1
+}
+
+// mapOffset 将旧文本中未被修改的偏移映射到新文本
+func mapOffset(changes []fixer.Change, offset int) int {
+	shift := 0
+	for _, change := range changes {
+		if change.OldOffset+len(change.Removed) > offset {
+			break
+		}
+		shift += len(change.Added) - len(change.Removed)
+	}
+	return offset + shift
+}
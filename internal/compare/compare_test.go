@@ -0,0 +1,102 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// match 构造指定偏移的匹配
+func match(text string, offset int) models.Match {
+	return models.Match{Text: text, Position: models.Position{Offset: offset, Length: len(text)}}
+}
+
+func TestCompare(t *testing.T) {
+	fromText := "It is crucial.\nWe delve into it.\nMiddle.\nThe end.\n"
+	toText := "It is crucial.\nWe look into it.\nMiddle.\nAdditionally, the end.\n"
+
+	from := &models.DetectionResult{
+		Text:      fromText,
+		Score:     models.Score{Total: 50},
+		RiskLevel: models.RiskLevelHigh,
+		RuleResults: []models.RuleResult{
+			{
+				RuleType: models.RuleTypeHighFreqWords,
+				Score:    40,
+				Matches:  []models.Match{match("crucial", 6), match("delve", 18)},
+			},
+			{RuleType: models.RuleTypeSentenceStarters, Score: 100},
+		},
+	}
+	to := &models.DetectionResult{
+		Text:      toText,
+		Score:     models.Score{Total: 58},
+		RiskLevel: models.RiskLevelMedium,
+		RuleResults: []models.RuleResult{
+			{
+				RuleType: models.RuleTypeHighFreqWords,
+				Score:    70,
+				Matches:  []models.Match{match("crucial", 6)},
+			},
+			{
+				RuleType: models.RuleTypeSentenceStarters,
+				Score:    100,
+				Matches:  []models.Match{match("Additionally", 40)},
+			},
+		},
+	}
+
+	c := Compare(from, to)
+
+	if c.ScoreDelta != 8 {
+		t.Errorf("ScoreDelta = %v, want 8", c.ScoreDelta)
+	}
+	if len(c.Edits) != 2 {
+		t.Fatalf("len(Edits) = %d, want 2", len(c.Edits))
+	}
+
+	// 未修改行中的匹配不应视为变化
+	if len(c.Resolved) != 1 || c.Resolved[0].Text != "delve" || c.Resolved[0].Edit != 0 {
+		t.Errorf("Resolved = %+v, want only \"delve\" attributed to edit 0", c.Resolved)
+	}
+	if len(c.Introduced) != 1 || c.Introduced[0].Text != "Additionally" || c.Introduced[0].Edit != 1 {
+		t.Errorf("Introduced = %+v, want only \"Additionally\" attributed to edit 1", c.Introduced)
+	}
+
+	// 规则评分不变但匹配数量变化同样影响分数
+	if !c.Edits[0].MovedScore() || c.Edits[0].Rules[0] != models.RuleTypeHighFreqWords {
+		t.Errorf("Edits[0].Rules = %v, want [%s]", c.Edits[0].Rules, models.RuleTypeHighFreqWords)
+	}
+	if !c.Edits[1].MovedScore() || c.Edits[1].Rules[0] != models.RuleTypeSentenceStarters {
+		t.Errorf("Edits[1].Rules = %v, want [%s]", c.Edits[1].Rules, models.RuleTypeSentenceStarters)
+	}
+
+	// 总分不变时没有变更影响分数
+	to.Score.Total = from.Score.Total
+	for i, edit := range Compare(from, to).Edits {
+		if edit.MovedScore() {
+			t.Errorf("Edits[%d].Rules = %v with unchanged total, want none", i, edit.Rules)
+		}
+	}
+
+	rule := c.Rules[0]
+	if rule.RuleType != models.RuleTypeHighFreqWords || rule.ScoreDelta != 30 ||
+		rule.FromCount != 2 || rule.ToCount != 1 || rule.Resolved != 1 {
+		t.Errorf("Rules[0] = %+v, want high frequency words delta 30, 2 -> 1 matches", rule)
+	}
+}
+
+func TestCompare_IdenticalText(t *testing.T) {
+	result := &models.DetectionResult{
+		Text: "Same text.\n",
+		RuleResults: []models.RuleResult{
+			{RuleType: models.RuleTypePerfectionism, Matches: []models.Match{{Text: "no first person"}}},
+		},
+	}
+
+	c := Compare(result, result)
+	if len(c.Edits) != 0 || len(c.Resolved) != 0 || len(c.Introduced) != 0 {
+		t.Errorf("Compare() of identical results = %d edits, %d resolved, %d introduced, want none",
+			len(c.Edits), len(c.Resolved), len(c.Introduced))
+	}
+}
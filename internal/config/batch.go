@@ -0,0 +1,23 @@
+package config
+
+// BatchConfig 批量检测配置
+type BatchConfig struct {
+	MaxItems    int `yaml:"max_items"`   // 单次请求最多包含的条目数
+	Concurrency int `yaml:"concurrency"` // 同一批次内并发检测的条目数
+}
+
+// DefaultBatchConfig 默认批量检测配置
+var DefaultBatchConfig = BatchConfig{
+	MaxItems:    1000,
+	Concurrency: 8,
+}
+
+// mergeBatchDefaults 补充缺失的批量检测配置项
+func mergeBatchDefaults(batch *BatchConfig) {
+	if batch.MaxItems <= 0 {
+		batch.MaxItems = DefaultBatchConfig.MaxItems
+	}
+	if batch.Concurrency <= 0 {
+		batch.Concurrency = DefaultBatchConfig.Concurrency
+	}
+}
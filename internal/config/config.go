@@ -16,6 +16,11 @@ type Config struct {
 	Rules       map[string]RuleConfig    `yaml:"rules"`       // 规则配置
 	Multimodal  models.MultimodalConfig  `yaml:"multimodal"`  // 多模态配置
 	Gemini      gemini.Config            `yaml:"gemini"`      // Gemini API 配置
+	Database    DatabaseConfig           `yaml:"database"`    // 数据库配置
+	Jobs        JobsConfig               `yaml:"jobs"`        // 异步任务配置
+	Batch       BatchConfig              `yaml:"batch"`       // 批量检测配置
+	Upload      UploadConfig             `yaml:"upload"`      // 文件上传检测配置
+	Profiles    map[string]Profile       `yaml:"profiles"`    // 规则配置档，如代码注释模式使用的 code
 }
 
 // ScoringConfig 评分配置
@@ -53,6 +58,11 @@ var DefaultConfig = Config{
 	},
 	Multimodal: models.DefaultMultimodalConfig,
 	Gemini:     gemini.DefaultConfig(),
+	Database:   DefaultDatabaseConfig,
+	Jobs:       DefaultJobsConfig,
+	Batch:      DefaultBatchConfig,
+	Upload:     DefaultUploadConfig,
+	Profiles:   DefaultProfiles,
 	Rules: map[string]RuleConfig{
 		string(models.RuleTypeHighFreqWords): {
 			Enabled:   true,
@@ -104,6 +114,16 @@ var DefaultConfig = Config{
 			Threshold: 5,
 			Severity:  models.SeverityHigh,
 		},
+		string(models.RuleTypeHiddenChars): {
+			Enabled:   true,
+			Threshold: 1,
+			Severity:  models.SeverityCritical,
+		},
+		string(models.RuleTypeParallelStructure): {
+			Enabled:   true,
+			Threshold: 3,
+			Severity:  models.SeverityMedium,
+		},
 	},
 }
 
@@ -163,10 +183,85 @@ func mergeWithDefaults(config *Config) {
 		config.Output.Language = DefaultConfig.Output.Language
 	}
 
+	// 如果未配置同义词替换表，使用默认值
+	if config.Thresholds.HighFrequencyWords.Synonyms == nil {
+		config.Thresholds.HighFrequencyWords.Synonyms = DefaultThresholds.HighFrequencyWords.Synonyms
+	}
+
+	if config.Thresholds.MarkdownResidue.MarkdownInput == "" {
+		config.Thresholds.MarkdownResidue.MarkdownInput = DefaultThresholds.MarkdownResidue.MarkdownInput
+	}
+
+	// 旧配置文件没有 Signal 11 的阈值
+	if config.Thresholds.HiddenCharacters.Threshold == 0 {
+		config.Thresholds.HiddenCharacters.Threshold = DefaultThresholds.HiddenCharacters.Threshold
+	}
+	mergeParallelStructureDefaults(&config.Thresholds.ParallelStructure)
+
+	mergeDatabaseDefaults(&config.Database)
+	mergeJobsDefaults(&config.Jobs)
+	mergeBatchDefaults(&config.Batch)
+	mergeUploadDefaults(&config.Upload)
+	mergeProfileDefaults(config)
+
 	// 检查环境变量覆盖 Gemini API Key
 	if apiKey := os.Getenv("GEMINI_API_KEY"); apiKey != "" {
 		config.Gemini.APIKey = apiKey
 	}
+	if secret := os.Getenv("AIGC_WEBHOOK_SECRET"); secret != "" {
+		config.Jobs.WebhookSecret = secret
+	}
+}
+
+// mergeParallelStructureDefaults 补充缺失的 Signal 12 阈值
+func mergeParallelStructureDefaults(t *ParallelStructureThresholds) {
+	defaults := DefaultThresholds.ParallelStructure
+
+	if t.Density == 0 {
+		t.Density = defaults.Density
+	}
+	if t.MinRepeats == 0 {
+		t.MinRepeats = defaults.MinRepeats
+	}
+	if t.Threshold == 0 {
+		t.Threshold = defaults.Threshold
+	}
+}
+
+// mergeDatabaseDefaults 补充缺失的数据库配置项
+func mergeDatabaseDefaults(db *DatabaseConfig) {
+	defaults := DefaultDatabaseConfig
+
+	if db.Type == "" {
+		db.Type = defaults.Type
+	}
+	if db.SQLite.Path == "" {
+		db.SQLite = defaults.SQLite
+	}
+	if db.PostgreSQL.Host == "" {
+		db.PostgreSQL.Host = defaults.PostgreSQL.Host
+	}
+	if db.PostgreSQL.Port == 0 {
+		db.PostgreSQL.Port = defaults.PostgreSQL.Port
+	}
+	if db.PostgreSQL.SSLMode == "" {
+		db.PostgreSQL.SSLMode = defaults.PostgreSQL.SSLMode
+	}
+	if db.MySQL.Host == "" {
+		db.MySQL.Host = defaults.MySQL.Host
+	}
+	if db.MySQL.Port == 0 {
+		db.MySQL.Port = defaults.MySQL.Port
+	}
+	if db.MySQL.Charset == "" {
+		db.MySQL.Charset = defaults.MySQL.Charset
+	}
+	if db.Pool == (PoolConfig{}) {
+		db.Pool = defaults.Pool
+	}
+	if db.Retention.PurgeInterval <= 0 {
+		db.Retention.PurgeInterval = defaults.Retention.PurgeInterval
+	}
 }
 
 // GetRuleConfig 获取规则配置
@@ -190,6 +190,16 @@ func TestMergeWithDefaults(t *testing.T) {
 	if cfg.Output.Language != "zh" {
 		t.Errorf("mergeWithDefaults() Language = %s, want zh", cfg.Output.Language)
 	}
+
+	// 验证旧配置缺少的 Signal 11 阈值被补充
+	if cfg.Thresholds.HiddenCharacters.Threshold != DefaultThresholds.HiddenCharacters.Threshold {
+		t.Errorf("mergeWithDefaults() HiddenCharacters.Threshold = %d, want %d",
+			cfg.Thresholds.HiddenCharacters.Threshold, DefaultThresholds.HiddenCharacters.Threshold)
+	}
+	if cfg.Thresholds.ParallelStructure != DefaultThresholds.ParallelStructure {
+		t.Errorf("mergeWithDefaults() ParallelStructure = %+v, want %+v",
+			cfg.Thresholds.ParallelStructure, DefaultThresholds.ParallelStructure)
+	}
 }
 
 func TestDefaultConfig_AllRulesPresent(t *testing.T) {
@@ -204,6 +214,8 @@ func TestDefaultConfig_AllRulesPresent(t *testing.T) {
 		models.RuleTypeKnowledgeCutoff,
 		models.RuleTypeCollaborative,
 		models.RuleTypePerfectionism,
+		models.RuleTypeHiddenChars,
+		models.RuleTypeParallelStructure,
 	}
 
 	for _, ruleType := range expectedRules {
@@ -212,3 +224,152 @@ func TestDefaultConfig_AllRulesPresent(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadConfig_Database(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+
+	configContent := `
+database:
+  type: postgresql
+  postgresql:
+    host: "db.internal"
+    user: "aigc"
+    database: "aigc_check"
+  retention:
+    text_days: 30
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	db := cfg.Database
+	if db.Type != "postgresql" {
+		t.Errorf("Database.Type = %s, want postgresql", db.Type)
+	}
+	if db.PostgreSQL.Host != "db.internal" || db.PostgreSQL.Database != "aigc_check" {
+		t.Errorf("Database.PostgreSQL = %+v, want values from file", db.PostgreSQL)
+	}
+	// 未配置的项使用默认值
+	if db.PostgreSQL.Port != 5432 {
+		t.Errorf("Database.PostgreSQL.Port = %d, want 5432", db.PostgreSQL.Port)
+	}
+	if db.PostgreSQL.SSLMode != "disable" {
+		t.Errorf("Database.PostgreSQL.SSLMode = %s, want disable", db.PostgreSQL.SSLMode)
+	}
+	if db.Pool != DefaultDatabaseConfig.Pool {
+		t.Errorf("Database.Pool = %+v, want defaults", db.Pool)
+	}
+	if db.Retention.TextDays != 30 || db.Retention.PurgeInterval != DefaultDatabaseConfig.Retention.PurgeInterval {
+		t.Errorf("Database.Retention = %+v, want text_days from file and default purge interval", db.Retention)
+	}
+}
+
+func TestLoadConfig_Jobs(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+
+	configContent := `
+jobs:
+  workers: 8
+  webhook_secret: "from-file"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Jobs.Workers != 8 || cfg.Jobs.WebhookSecret != "from-file" {
+		t.Errorf("Jobs = %+v, want workers and secret from file", cfg.Jobs)
+	}
+	if cfg.Jobs.QueueSize != DefaultJobsConfig.QueueSize || cfg.Jobs.WebhookTimeout != DefaultJobsConfig.WebhookTimeout ||
+		cfg.Jobs.LeaseTimeout != DefaultJobsConfig.LeaseTimeout {
+		t.Errorf("Jobs = %+v, want default queue size, webhook timeout and lease timeout", cfg.Jobs)
+	}
+	if cfg.Batch != DefaultBatchConfig {
+		t.Errorf("Batch = %+v, want defaults", cfg.Batch)
+	}
+	if cfg.Upload.MaxSize() != 10<<20 || len(cfg.Upload.AllowedTypes) != len(DefaultUploadConfig.AllowedTypes) {
+		t.Errorf("Upload = %+v, want defaults", cfg.Upload)
+	}
+
+	// 环境变量覆盖配置文件中的密钥
+	t.Setenv("AIGC_WEBHOOK_SECRET", "from-env")
+	cfg, err = LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Jobs.WebhookSecret != "from-env" {
+		t.Errorf("Jobs.WebhookSecret = %q, want from-env", cfg.Jobs.WebhookSecret)
+	}
+}
+
+func TestConfig_WithProfile(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+
+	configContent := `
+thresholds:
+  perfectionism:
+    first_person_pronouns: [I, my]
+    threshold: 5
+profiles:
+  docs:
+    disabled_rules: [emoji_anomaly]
+    perfectionism:
+      threshold: 1
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if _, ok := cfg.Profiles[ProfileCode]; !ok {
+		t.Fatalf("Profiles = %v, want built-in %q merged in", cfg.ProfileNames(), ProfileCode)
+	}
+
+	// 只覆盖配置档中设置的项，原配置不受影响
+	docs, err := cfg.WithProfile("docs")
+	if err != nil {
+		t.Fatalf("WithProfile(docs) error = %v", err)
+	}
+	if docs.IsRuleEnabled(models.RuleTypeEmoji) || !cfg.IsRuleEnabled(models.RuleTypeEmoji) {
+		t.Error("emoji_anomaly should be disabled only in the derived config")
+	}
+	perfectionism := docs.Thresholds.Perfectionism
+	if perfectionism.Threshold != 1 || len(perfectionism.FirstPersonPronouns) != 2 {
+		t.Errorf("Perfectionism = %+v, want threshold 1 and pronouns from thresholds", perfectionism)
+	}
+	if cfg.Thresholds.Perfectionism.Threshold != 5 {
+		t.Errorf("base Perfectionism.Threshold = %d, want unchanged", cfg.Thresholds.Perfectionism.Threshold)
+	}
+	if docs.Fingerprint() == cfg.Fingerprint() {
+		t.Error("derived config should have a different fingerprint")
+	}
+
+	code, err := cfg.WithProfile(ProfileCode)
+	if err != nil {
+		t.Fatalf("WithProfile(code) error = %v", err)
+	}
+	if code.IsRuleEnabled(models.RuleTypeMarkdown) || code.Thresholds.CollaborativeTone.Threshold != 2 {
+		t.Errorf("code profile: markdown enabled = %v, collaborative threshold = %d", code.IsRuleEnabled(models.RuleTypeMarkdown), code.Thresholds.CollaborativeTone.Threshold)
+	}
+
+	if same, err := cfg.WithProfile(""); err != nil || same != cfg {
+		t.Errorf("WithProfile(\"\") = %p, %v; want the same config", same, err)
+	}
+	if _, err := cfg.WithProfile("missing"); err == nil {
+		t.Error("WithProfile(missing) error = nil, want unknown profile")
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+// DatabaseConfig 数据库配置
+type DatabaseConfig struct {
+	Type       string           `yaml:"type"`       // 数据库类型: sqlite, postgresql, mysql
+	SQLite     SQLiteConfig     `yaml:"sqlite"`     // SQLite 配置
+	PostgreSQL PostgreSQLConfig `yaml:"postgresql"` // PostgreSQL 配置
+	MySQL      MySQLConfig      `yaml:"mysql"`      // MySQL 配置
+	Pool       PoolConfig       `yaml:"pool"`       // 连接池配置
+	Retention  RetentionConfig  `yaml:"retention"`  // 数据保留策略
+}
+
+// SQLiteConfig SQLite 配置
+type SQLiteConfig struct {
+	Path       string `yaml:"path"`        // 数据库文件路径
+	WALEnabled bool   `yaml:"wal_enabled"` // 启用 WAL 日志模式
+}
+
+// PostgreSQLConfig PostgreSQL 配置
+type PostgreSQLConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Database string `yaml:"database"`
+	SSLMode  string `yaml:"ssl_mode"` // disable, require, verify-ca, verify-full
+}
+
+// MySQLConfig MySQL 配置
+type MySQLConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Database string `yaml:"database"`
+	Charset  string `yaml:"charset"` // 默认 utf8mb4
+}
+
+// PoolConfig 连接池配置
+type PoolConfig struct {
+	MaxConnections int `yaml:"max_connections"` // 最大打开连接数
+	MinConnections int `yaml:"min_connections"` // 最大空闲连接数
+	MaxIdleTime    int `yaml:"max_idle_time"`   // 连接最大空闲时间（秒）
+	MaxLifetime    int `yaml:"max_lifetime"`    // 连接最大存活时间（秒）
+}
+
+// RetentionConfig 数据保留策略
+type RetentionConfig struct {
+	TextDays      int  `yaml:"text_days"`      // 原文保留天数，超期后只保留分数和元数据；0 表示永久保留
+	PurgeInterval int  `yaml:"purge_interval"` // 后台清理间隔（秒）
+	DryRun        bool `yaml:"dry_run"`        // 后台清理只生成报告，不修改数据
+}
+
+// DefaultDatabaseConfig 默认数据库配置
+var DefaultDatabaseConfig = DatabaseConfig{
+	Type: "sqlite",
+	SQLite: SQLiteConfig{
+		Path:       "./data/aigc-check.db",
+		WALEnabled: true,
+	},
+	PostgreSQL: PostgreSQLConfig{
+		Host:    "localhost",
+		Port:    5432,
+		SSLMode: "disable",
+	},
+	MySQL: MySQLConfig{
+		Host:    "localhost",
+		Port:    3306,
+		Charset: "utf8mb4",
+	},
+	Pool: PoolConfig{
+		MaxConnections: 10,
+		MinConnections: 2,
+		MaxIdleTime:    300,
+		MaxLifetime:    3600,
+	},
+	Retention: RetentionConfig{
+		PurgeInterval: 3600,
+	},
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/leoobai/aigc-check/internal/gemini"
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// fingerprintFields 参与配置指纹计算的字段
+// 只包含影响检测结果的配置，输出格式、数据库连接和 API Key 等不计入
+type fingerprintFields struct {
+	Thresholds Thresholds              `json:"thresholds"`
+	Scoring    ScoringConfig           `json:"scoring"`
+	Rules      map[string]RuleConfig   `json:"rules"`
+	Multimodal models.MultimodalConfig `json:"multimodal"`
+	Gemini     gemini.Config           `json:"gemini"`
+}
+
+// Fingerprint 计算配置指纹（SHA-256 十六进制）
+// 相同的检测配置得到相同的指纹，用于判断历史结果是否由当前配置产生
+func (c *Config) Fingerprint() string {
+	fields := fingerprintFields{
+		Thresholds: c.Thresholds,
+		Scoring:    c.Scoring,
+		Rules:      c.Rules,
+		Multimodal: c.Multimodal,
+		Gemini:     c.Gemini,
+	}
+	fields.Gemini.APIKey = ""
+
+	// map 键在 JSON 编码时按字典序输出，结果是确定的
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,43 @@
+package config
+
+// JobsConfig 异步检测任务配置
+type JobsConfig struct {
+	Workers        int    `yaml:"workers"`         // 并发执行任务的 worker 数量
+	QueueSize      int    `yaml:"queue_size"`      // 等待队列长度，队列满时拒绝新任务
+	LeaseTimeout   int    `yaml:"lease_timeout"`   // 任务租约时长（秒），执行实例超过该时间未续租时任务由其他实例重新执行
+	WebhookSecret  string `yaml:"webhook_secret"`  // 回调签名密钥（HMAC-SHA256），可通过环境变量 AIGC_WEBHOOK_SECRET 覆盖；未配置时不接受回调地址
+	WebhookTimeout int    `yaml:"webhook_timeout"` // 单次回调超时时间（秒）
+	WebhookRetries int    `yaml:"webhook_retries"` // 回调失败后的重试次数
+
+	WebhookAllowPrivate bool `yaml:"webhook_allow_private"` // 允许回调回环、私有网络和链路本地地址，仅用于内网部署和测试
+}
+
+// DefaultJobsConfig 默认异步任务配置
+var DefaultJobsConfig = JobsConfig{
+	Workers:        4,
+	QueueSize:      100,
+	LeaseTimeout:   60,
+	WebhookTimeout: 10,
+	WebhookRetries: 3,
+}
+
+// mergeJobsDefaults 补充缺失的异步任务配置项
+func mergeJobsDefaults(jobs *JobsConfig) {
+	defaults := DefaultJobsConfig
+
+	if jobs.Workers <= 0 {
+		jobs.Workers = defaults.Workers
+	}
+	if jobs.QueueSize <= 0 {
+		jobs.QueueSize = defaults.QueueSize
+	}
+	if jobs.LeaseTimeout <= 0 {
+		jobs.LeaseTimeout = defaults.LeaseTimeout
+	}
+	if jobs.WebhookTimeout <= 0 {
+		jobs.WebhookTimeout = defaults.WebhookTimeout
+	}
+	if jobs.WebhookRetries < 0 {
+		jobs.WebhookRetries = defaults.WebhookRetries
+	}
+}
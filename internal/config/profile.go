@@ -0,0 +1,127 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// ProfileCode 代码注释和文档字符串使用的规则配置档
+const ProfileCode = "code"
+
+// ErrUnknownProfile 未配置的规则配置档
+var ErrUnknownProfile = errors.New("unknown rule profile")
+
+// Profile 规则配置档：针对特定类型的输入（如代码注释）覆盖部分规则的阈值和启用状态，
+// 未设置的项（空列表、零阈值）沿用全局配置
+type Profile struct {
+	Description       string                  `yaml:"description"`        // 配置档说明
+	DisabledRules     []string                `yaml:"disabled_rules"`     // 在该配置档下关闭的规则
+	CollaborativeTone CollaborativeThresholds `yaml:"collaborative_tone"` // 覆盖 thresholds.collaborative_tone
+	Perfectionism     PerfectionismThresholds `yaml:"perfectionism"`      // 覆盖 thresholds.perfectionism
+}
+
+// DefaultProfiles 内置的规则配置档
+var DefaultProfiles = map[string]Profile{
+	ProfileCode: {
+		Description: "代码注释和文档字符串：注释本就少用第一人称和情感词，改以 TODO、临时方案等随手写下的痕迹衡量个人化表达；" +
+			"协作式语气改为检测生成代码时常见的示例说明和客套话；文档字符串中的 Markdown 是正常写法，不检测 Markdown 残留",
+		DisabledRules: []string{"markdown_residue"},
+		CollaborativeTone: CollaborativeThresholds{
+			Phrases: []string{
+				"I hope this helps",
+				"Let me know if",
+				"Feel free to",
+				"Happy coding",
+				"adjust as needed",
+				"Replace this with",
+				"Replace with your",
+				"In a real application",
+				"In a real-world",
+				"For demonstration purposes",
+				"Example usage",
+				"This ensures that",
+				"可以根据需要",
+				"根据实际情况修改",
+				"示例用法",
+			},
+			Threshold: 2,
+		},
+		Perfectionism: PerfectionismThresholds{
+			FirstPersonPronouns: []string{"I'm", "I've", "we ", "our ", "我们"},
+			EmotionalWords:      []string{"hack", "ugly", "ugh", "annoying", "sadly", "unfortunately", "丑陋", "坑"},
+			UncertaintyMarkers:  []string{"TODO", "FIXME", "XXX", "not sure", "probably", "for now", "workaround", "暂时", "先这样"},
+			Threshold:           2,
+		},
+	},
+}
+
+// ProfileNames 返回已配置的规则配置档名称，按名称排序
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithProfile 返回应用了指定规则配置档的配置副本，name 为空时返回原配置
+func (c *Config) WithProfile(name string) (*Config, error) {
+	if name == "" {
+		return c, nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (available: %s)", ErrUnknownProfile, name, strings.Join(c.ProfileNames(), ", "))
+	}
+
+	derived := *c
+	derived.Rules = make(map[string]RuleConfig, len(c.Rules))
+	for ruleType, rule := range c.Rules {
+		derived.Rules[ruleType] = rule
+	}
+	for _, ruleType := range profile.DisabledRules {
+		rule := derived.GetRuleConfig(models.RuleType(ruleType))
+		rule.Enabled = false
+		derived.Rules[ruleType] = rule
+	}
+
+	collaborative := &derived.Thresholds.CollaborativeTone
+	if len(profile.CollaborativeTone.Phrases) > 0 {
+		collaborative.Phrases = profile.CollaborativeTone.Phrases
+	}
+	if profile.CollaborativeTone.Threshold > 0 {
+		collaborative.Threshold = profile.CollaborativeTone.Threshold
+	}
+
+	perfectionism := &derived.Thresholds.Perfectionism
+	if len(profile.Perfectionism.FirstPersonPronouns) > 0 {
+		perfectionism.FirstPersonPronouns = profile.Perfectionism.FirstPersonPronouns
+	}
+	if len(profile.Perfectionism.EmotionalWords) > 0 {
+		perfectionism.EmotionalWords = profile.Perfectionism.EmotionalWords
+	}
+	if len(profile.Perfectionism.UncertaintyMarkers) > 0 {
+		perfectionism.UncertaintyMarkers = profile.Perfectionism.UncertaintyMarkers
+	}
+	if profile.Perfectionism.Threshold > 0 {
+		perfectionism.Threshold = profile.Perfectionism.Threshold
+	}
+	return &derived, nil
+}
+
+// mergeProfileDefaults 补充配置文件中没有的内置规则配置档
+func mergeProfileDefaults(config *Config) {
+	if config.Profiles == nil {
+		config.Profiles = make(map[string]Profile, len(DefaultProfiles))
+	}
+	for name, profile := range DefaultProfiles {
+		if _, exists := config.Profiles[name]; !exists {
+			config.Profiles[name] = profile
+		}
+	}
+}
@@ -12,12 +12,15 @@ type Thresholds struct {
 	KnowledgeCutoff     KnowledgeCutoffThresholds `yaml:"knowledge_cutoff"`     // Signal 8
 	CollaborativeTone   CollaborativeThresholds  `yaml:"collaborative_tone"`    // Signal 9
 	Perfectionism       PerfectionismThresholds  `yaml:"perfectionism"`         // Signal 10
+	HiddenCharacters    HiddenCharsThresholds    `yaml:"hidden_characters"`     // Signal 11
+	ParallelStructure   ParallelStructureThresholds `yaml:"parallel_structure"` // Signal 12
 }
 
 // HighFreqWordsThresholds Signal 1 阈值
 type HighFreqWordsThresholds struct {
-	Keywords  []string `yaml:"keywords"`  // 关键词列表
-	Threshold int      `yaml:"threshold"` // 出现次数阈值
+	Keywords  []string          `yaml:"keywords"`  // 关键词列表
+	Threshold int               `yaml:"threshold"` // 出现次数阈值
+	Synonyms  map[string]string `yaml:"synonyms"`  // 自动修复使用的同义词替换表
 }
 
 // SentenceStartersThresholds Signal 2 阈值
@@ -43,8 +46,17 @@ type CitationAnomalyThresholds struct {
 type MarkdownThresholds struct {
 	Patterns  []string `yaml:"patterns"`  // Markdown模式
 	Threshold int      `yaml:"threshold"` // 检测阈值
+
+	// MarkdownInput 输入本身是 Markdown 时的行为：leak 只检测渲染后仍残留在正文中的格式标记，off 关闭本规则
+	MarkdownInput string `yaml:"markdown_input"`
 }
 
+// Markdown 输入模式下 MarkdownRule 的行为
+const (
+	MarkdownInputLeak = "leak"
+	MarkdownInputOff  = "off"
+)
+
 // EmojiThresholds Signal 7 阈值
 type EmojiThresholds struct {
 	Threshold int `yaml:"threshold"` // 表情符号数量阈值
@@ -69,6 +81,19 @@ type PerfectionismThresholds struct {
 	Threshold           int      `yaml:"threshold"`             // 检测阈值
 }
 
+// HiddenCharsThresholds Signal 11 阈值
+type HiddenCharsThresholds struct {
+	Threshold int      `yaml:"threshold"` // 隐形字符和同形字母数量阈值
+	Allowed   []string `yaml:"allowed"`   // 不检测的码位（如 "U+00AD"），用于本就包含这些字符的文本
+}
+
+// ParallelStructureThresholds Signal 12 阈值
+type ParallelStructureThresholds struct {
+	Density    float64 `yaml:"density"`     // 每千字三项并列和排比句式数量阈值
+	MinRepeats int     `yaml:"min_repeats"` // 排比：句式相同的分句连续出现的最少次数
+	Threshold  int     `yaml:"threshold"`   // 最少匹配数量，避免短文本中出现一两处即超过密度阈值
+}
+
 // DefaultThresholds 默认阈值配置
 var DefaultThresholds = Thresholds{
 	HighFrequencyWords: HighFreqWordsThresholds{
@@ -78,6 +103,18 @@ var DefaultThresholds = Thresholds{
 			"关键", "至关重要", "革命性", "突破性",
 		},
 		Threshold: 3,
+		Synonyms: map[string]string{
+			"crucial":        "important",
+			"pivotal":        "key",
+			"vital":          "essential",
+			"groundbreaking": "new",
+			"revolutionary":  "innovative",
+			"profound":       "deep",
+			"significant":    "notable",
+			"至关重要":           "很重要",
+			"革命性":            "创新性",
+			"突破性":            "重大",
+		},
 	},
 	SentenceStarters: SentenceStartersThresholds{
 		Patterns: []string{
@@ -117,7 +154,8 @@ var DefaultThresholds = Thresholds{
 			"[.+]\\(.+\\)",
 			"```",
 		},
-		Threshold: 3,
+		Threshold:     3,
+		MarkdownInput: MarkdownInputLeak,
 	},
 	EmojiAnomaly: EmojiThresholds{
 		Threshold: 5,
@@ -156,4 +194,12 @@ var DefaultThresholds = Thresholds{
 		},
 		Threshold: 5,
 	},
+	HiddenCharacters: HiddenCharsThresholds{
+		Threshold: 1,
+	},
+	ParallelStructure: ParallelStructureThresholds{
+		Density:    3.0, // 每千字3处
+		MinRepeats: 3,
+		Threshold:  3,
+	},
 }
@@ -0,0 +1,28 @@
+package config
+
+// UploadConfig 文件上传检测配置
+type UploadConfig struct {
+	MaxSizeMB    int      `yaml:"max_size_mb"`   // 上传文件大小上限（MB）
+	AllowedTypes []string `yaml:"allowed_types"` // 允许的文档格式，为空时允许所有支持的格式
+}
+
+// DefaultUploadConfig 默认文件上传配置
+var DefaultUploadConfig = UploadConfig{
+	MaxSizeMB:    10,
+	AllowedTypes: []string{"txt", "md", "html", "docx", "odt", "pdf", "tex", "srt", "vtt", "ipynb", "epub", "go", "py", "js", "ts", "java", "c"},
+}
+
+// MaxSize 上传文件大小上限（字节）
+func (c UploadConfig) MaxSize() int64 {
+	return int64(c.MaxSizeMB) << 20
+}
+
+// mergeUploadDefaults 补充缺失的文件上传配置项
+func mergeUploadDefaults(upload *UploadConfig) {
+	if upload.MaxSizeMB <= 0 {
+		upload.MaxSizeMB = DefaultUploadConfig.MaxSizeMB
+	}
+	if upload.AllowedTypes == nil {
+		upload.AllowedTypes = DefaultUploadConfig.AllowedTypes
+	}
+}
@@ -0,0 +1,101 @@
+package corpus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// maxFailures 检查点中保留的失败行数上限
+const maxFailures = 100
+
+// checkpoint 检查点：输入文件中已处理到的位置、输出文件中对应的长度和累计的统计
+type checkpoint struct {
+	Input        string    `json:"input"`
+	Format       Format    `json:"format"`
+	TextField    string    `json:"text_field"`
+	IDField      string    `json:"id_field,omitempty"`
+	InputOffset  int64     `json:"input_offset"`  // 下一行在输入文件中的字节偏移量
+	OutputOffset int64     `json:"output_offset"` // 已写出并落盘的输出长度
+	Progress     Progress  `json:"progress"`
+	Failures     []Failure `json:"failures,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// newCheckpoint 创建新运行的检查点
+func newCheckpoint(input string, opts Options) *checkpoint {
+	if abs, err := filepath.Abs(input); err == nil {
+		input = abs
+	}
+	return &checkpoint{
+		Input:     input,
+		Format:    opts.Format,
+		TextField: opts.TextField,
+		IDField:   opts.IDField,
+		Progress:  Progress{RiskLevels: make(map[models.RiskLevel]int64)},
+	}
+}
+
+// loadCheckpoint 读取检查点，不存在时返回 nil
+func loadCheckpoint(path string) (*checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	return &cp, nil
+}
+
+// matches 检查保存的检查点是否属于同一输入和同样的字段选项
+func (cp *checkpoint) matches(current *checkpoint) error {
+	switch {
+	case cp.Input != current.Input:
+		return fmt.Errorf("%w: it was created for %s", ErrCheckpointMismatch, cp.Input)
+	case cp.Format != current.Format || cp.TextField != current.TextField || cp.IDField != current.IDField:
+		return fmt.Errorf("%w: it was created with format %s, text field %q and id field %q",
+			ErrCheckpointMismatch, cp.Format, cp.TextField, cp.IDField)
+	}
+	info, err := os.Stat(cp.Input)
+	if err != nil {
+		return fmt.Errorf("failed to stat input: %w", err)
+	}
+	if info.Size() < cp.InputOffset {
+		return fmt.Errorf("%w: input is shorter than the checkpoint position", ErrCheckpointMismatch)
+	}
+	return nil
+}
+
+// addFailure 记录失败的行，超过上限后只计数
+func (cp *checkpoint) addFailure(f Failure) {
+	if len(cp.Failures) < maxFailures {
+		cp.Failures = append(cp.Failures, f)
+	}
+}
+
+// save 以先写临时文件再重命名的方式保存检查点，中断时不会留下不完整的检查点
+func (cp *checkpoint) save(path string) error {
+	cp.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
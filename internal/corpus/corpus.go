@@ -0,0 +1,471 @@
+package corpus
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// Format 语料文件格式
+type Format string
+
+const (
+	FormatJSONL Format = "jsonl"
+	FormatCSV   Format = "csv"
+	FormatTSV   Format = "tsv"
+)
+
+// 写回每行的检测结果列
+const (
+	ColumnScore     = "aigc_score"
+	ColumnRiskLevel = "aigc_risk_level"
+	ColumnRules     = "aigc_rules"
+	ColumnError     = "aigc_error"
+)
+
+// DefaultCheckpointEvery 默认每写出多少行保存一次检查点
+const DefaultCheckpointEvery = 1000
+
+// ErrCheckpointMismatch 检查点与本次运行的输入或选项不一致
+var ErrCheckpointMismatch = errors.New("checkpoint does not match this run")
+
+// errEmptyText 文本字段为空
+var errEmptyText = errors.New("text is empty")
+
+// Detector 检测单条文本
+type Detector func(text string) (*models.DetectionResult, error)
+
+// Options 语料检测选项
+type Options struct {
+	Format          Format // 为空时根据输入文件扩展名判断
+	TextField       string // 待检测文本所在的字段（JSONL 的键名或 CSV 的列名）
+	IDField         string // 行 ID 所在的字段，为空时使用行号
+	Workers         int    // 并发检测的 worker 数量
+	CheckpointEvery int    // 每写出多少行保存一次检查点
+	Resume          bool   // 从检查点继续上次中断的运行
+}
+
+// Progress 处理进度
+type Progress struct {
+	Rows       int64                      `json:"rows"` // 已写出的行数
+	Succeeded  int64                      `json:"succeeded"`
+	Failed     int64                      `json:"failed"`
+	RiskLevels map[models.RiskLevel]int64 `json:"risk_levels"` // 各风险等级的行数
+}
+
+// Failure 一行检测失败的原因
+type Failure struct {
+	Row int64  `json:"row"`          // 数据行的序号（从 1 开始，不含 CSV 表头）
+	ID  string `json:"id,omitempty"` // 行 ID，未指定 ID 字段时为空
+	Err string `json:"error"`
+}
+
+// Summary 语料检测汇总
+type Summary struct {
+	Progress
+	Resumed  bool      // 是否从检查点继续
+	Skipped  int64     // 从检查点继续时跳过的已处理行数
+	Failures []Failure // 最先失败的若干行（包括从检查点继续之前的）
+}
+
+// row 一行输入数据
+type row struct {
+	seq    int64    // 数据行序号，从 0 开始
+	id     string   // 行 ID
+	text   string   // 待检测文本
+	err    error    // 行数据不可检测的原因（如缺少文本字段），不为空时不检测
+	raw    []byte   // JSONL 的原始行（不含换行符）
+	record []string // CSV 的记录
+	end    int64    // 该行结束处在输入文件中的字节偏移量
+}
+
+// outcome 一行的检测结果
+type outcome struct {
+	score float64
+	risk  models.RiskLevel
+	rules []string
+	err   string
+}
+
+// source 按顺序读取数据行，读完时返回 io.EOF
+type source interface {
+	next() (*row, error)
+}
+
+// sink 写出带检测结果的数据行
+type sink interface {
+	header() error // 写出表头（CSV），从检查点继续时不调用
+	write(r *row, o *outcome) error
+}
+
+// DetectFormat 根据文件扩展名判断语料格式
+func DetectFormat(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson", ".json":
+		return FormatJSONL, nil
+	case ".csv":
+		return FormatCSV, nil
+	case ".tsv", ".tab":
+		return FormatTSV, nil
+	}
+	return "", fmt.Errorf("cannot determine corpus format of %s, specify jsonl, csv or tsv", path)
+}
+
+// ParseFormat 解析格式名称
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case FormatJSONL, FormatCSV, FormatTSV:
+		return f, nil
+	case "ndjson":
+		return FormatJSONL, nil
+	}
+	return "", fmt.Errorf("unsupported corpus format %q (supported: jsonl, csv, tsv)", name)
+}
+
+// Run 流式读取语料文件，以 worker 池并发检测各行，按输入顺序把每行连同检测结果写入输出文件。
+// 内存占用与 worker 数量成正比，与文件大小无关；定期保存检查点（输出文件路径加 .checkpoint），
+// ctx 取消时等待进行中的行完成、保存检查点后返回 ctx 的错误，之后可以用 Resume 选项继续；正常完成时删除检查点。
+// onProgress 在每次保存检查点后调用，可以为 nil
+func Run(ctx context.Context, input, output string, opts Options, detect Detector, onProgress func(Progress)) (*Summary, error) {
+	if opts.TextField == "" {
+		return nil, errors.New("text field is required")
+	}
+	if opts.Format == "" {
+		format, err := DetectFormat(input)
+		if err != nil {
+			return nil, err
+		}
+		opts.Format = format
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.CheckpointEvery <= 0 {
+		opts.CheckpointEvery = DefaultCheckpointEvery
+	}
+
+	cp := newCheckpoint(input, opts)
+	checkpointPath := output + ".checkpoint"
+	summary := &Summary{}
+	if !opts.Resume {
+		if err := os.Remove(checkpointPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove checkpoint: %w", err)
+		}
+	} else {
+		saved, err := loadCheckpoint(checkpointPath)
+		if err != nil {
+			return nil, err
+		}
+		if saved != nil {
+			if err := saved.matches(cp); err != nil {
+				return nil, err
+			}
+			cp = saved
+			summary.Resumed = true
+			summary.Skipped = cp.Progress.Rows
+		}
+	}
+
+	in, err := os.Open(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open input: %w", err)
+	}
+	defer in.Close()
+
+	out, err := openOutput(output, summary.Resumed, cp.OutputOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer out.Close()
+
+	counter := &countingWriter{w: out, n: cp.OutputOffset}
+	buffered := bufio.NewWriterSize(counter, 256*1024)
+
+	src, dst, err := open(opts, in, buffered, cp.InputOffset)
+	if err != nil {
+		return nil, err
+	}
+	if !summary.Resumed {
+		if err := dst.header(); err != nil {
+			return nil, fmt.Errorf("failed to write output: %w", err)
+		}
+	}
+
+	r := &runner{
+		opts:       opts,
+		detect:     detect,
+		src:        src,
+		dst:        dst,
+		cp:         cp,
+		path:       checkpointPath,
+		out:        out,
+		buffered:   buffered,
+		counter:    counter,
+		onProgress: onProgress,
+	}
+	if cp.Progress.RiskLevels == nil {
+		cp.Progress.RiskLevels = make(map[models.RiskLevel]int64)
+	}
+	runErr := r.run(ctx)
+	summary.Progress = cp.Progress
+	summary.Failures = cp.Failures
+	if runErr != nil {
+		return summary, runErr
+	}
+	if err := os.Remove(checkpointPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return summary, fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+	return summary, nil
+}
+
+// openOutput 打开输出文件：新运行时清空，从检查点继续时截断到检查点记录的长度（丢弃检查点之后写出的行）
+func openOutput(path string, resume bool, offset int64) (*os.File, error) {
+	if !resume {
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create output: %w", err)
+		}
+		return f, nil
+	}
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open output: %w", err)
+	}
+	if err := f.Truncate(offset); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to truncate output: %w", err)
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to seek output: %w", err)
+	}
+	return f, nil
+}
+
+// open 按格式创建读取器和写出器，offset 为继续读取的输入位置
+func open(opts Options, in *os.File, out io.Writer, offset int64) (source, sink, error) {
+	switch opts.Format {
+	case FormatJSONL:
+		if _, err := in.Seek(offset, io.SeekStart); err != nil {
+			return nil, nil, fmt.Errorf("failed to seek input: %w", err)
+		}
+		return newJSONLSource(in, offset, opts), &jsonlSink{w: out}, nil
+	case FormatCSV, FormatTSV:
+		comma := ','
+		if opts.Format == FormatTSV {
+			comma = '\t'
+		}
+		src, header, err := newCSVSource(in, comma, offset, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return src, newCSVSink(out, comma, header), nil
+	}
+	return nil, nil, fmt.Errorf("unsupported corpus format %q", opts.Format)
+}
+
+// runner 一次语料检测运行
+type runner struct {
+	opts       Options
+	detect     Detector
+	src        source
+	dst        sink
+	cp         *checkpoint
+	path       string
+	out        *os.File
+	buffered   *bufio.Writer
+	counter    *countingWriter
+	onProgress func(Progress)
+}
+
+// result worker 完成的一行
+type result struct {
+	row     *row
+	outcome *outcome
+}
+
+// run 读取、检测并按输入顺序写出各行：读取协程最多领先写出 2×worker 行，超出时等待，内存占用因此有界
+func (r *runner) run(ctx context.Context) error {
+	// 写出失败时取消读取，让读取协程和 worker 退出
+	readCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	window := 2 * r.opts.Workers
+	slots := make(chan struct{}, window)
+	rows := make(chan *row)
+	results := make(chan result)
+
+	base := r.cp.Progress.Rows
+	var readErr error
+	go func() {
+		defer close(rows)
+		for {
+			select {
+			case slots <- struct{}{}:
+			case <-readCtx.Done():
+				return
+			}
+			rw, err := r.src.next()
+			if err != nil {
+				if err != io.EOF {
+					readErr = err
+				}
+				return
+			}
+			rw.seq += base
+			select {
+			case rows <- rw:
+			case <-readCtx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < r.opts.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rw := range rows {
+				results <- result{row: rw, outcome: r.detectRow(rw)}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// 按序号重排后写出
+	pending := make(map[int64]result, window)
+	next := base
+	var writeErr error
+	for res := range results {
+		if writeErr != nil {
+			continue
+		}
+		pending[res.row.seq] = res
+		for {
+			ready, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			if err := r.write(ready); err != nil {
+				writeErr = err
+				cancel()
+				break
+			}
+			next++
+			<-slots
+		}
+	}
+
+	if writeErr != nil {
+		return writeErr
+	}
+	// 读取协程在通道关闭后才结束，此时 readErr 已写入
+	if err := r.save(); err != nil {
+		return err
+	}
+	if readErr != nil {
+		return readErr
+	}
+	return ctx.Err()
+}
+
+// detectRow 检测一行，panic 同样作为该行的错误
+func (r *runner) detectRow(rw *row) (o *outcome) {
+	o = &outcome{}
+	if rw.err != nil {
+		o.err = rw.err.Error()
+		return o
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			o = &outcome{err: fmt.Sprintf("detection panicked: %v", p)}
+		}
+	}()
+	if strings.TrimSpace(rw.text) == "" {
+		o.err = errEmptyText.Error()
+		return o
+	}
+	detection, err := r.detect(rw.text)
+	if err != nil {
+		o.err = err.Error()
+		return o
+	}
+	o.score = detection.Score.Total
+	o.risk = detection.RiskLevel
+	for _, ruleResult := range detection.RuleResults {
+		if ruleResult.Detected {
+			o.rules = append(o.rules, string(ruleResult.RuleType))
+		}
+	}
+	return o
+}
+
+// write 写出一行并在达到间隔时保存检查点
+func (r *runner) write(res result) error {
+	if err := r.dst.write(res.row, res.outcome); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	p := &r.cp.Progress
+	p.Rows++
+	if res.outcome.err == "" {
+		p.Succeeded++
+		p.RiskLevels[res.outcome.risk]++
+	} else {
+		p.Failed++
+		r.cp.addFailure(Failure{Row: res.row.seq + 1, ID: res.row.id, Err: res.outcome.err})
+	}
+	r.cp.InputOffset = res.row.end
+	if p.Rows%int64(r.opts.CheckpointEvery) == 0 {
+		return r.save()
+	}
+	return nil
+}
+
+// save 把已写出的行落盘后保存检查点
+func (r *runner) save() error {
+	if err := r.buffered.Flush(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	if err := r.out.Sync(); err != nil {
+		return fmt.Errorf("failed to sync output: %w", err)
+	}
+	r.cp.OutputOffset = r.counter.n
+	if err := r.cp.save(r.path); err != nil {
+		return err
+	}
+	if r.onProgress != nil {
+		r.onProgress(r.cp.Progress)
+	}
+	return nil
+}
+
+// countingWriter 统计写出的字节数
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// formatScore 格式化分数
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', 1, 64)
+}
@@ -0,0 +1,217 @@
+package corpus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// fakeDetect 按文本长度给分，含 "delve" 的文本检出高频词规则
+func fakeDetect(text string) (*models.DetectionResult, error) {
+	if text == "boom" {
+		return nil, errors.New("detector failed")
+	}
+	score := float64(100 - len(text))
+	result := &models.DetectionResult{Score: models.Score{Total: score}, RiskLevel: models.GetRiskLevel(score)}
+	if strings.Contains(text, "delve") {
+		result.RuleResults = []models.RuleResult{{RuleType: models.RuleTypeHighFreqWords, Detected: true}}
+	}
+	return result, nil
+}
+
+// writeInput 在临时目录中写入输入文件
+func writeInput(t *testing.T, name, content string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	input := filepath.Join(dir, name)
+	if err := os.WriteFile(input, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return input, filepath.Join(dir, "out"+filepath.Ext(name))
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		input    string
+		opts     Options
+		want     string
+		failed   int64
+	}{
+		{
+			name:     "jsonl keeps fields and order",
+			filename: "data.jsonl",
+			input: "{\"id\": 7, \"text\": \"we delve\", \"lang\": \"en\"}\r\n\n" +
+				"{\"text\":\"\",\"id\":\"b\"}\n{\"id\":\"c\"}\n{}\n{\"text\":\"boom\"}",
+			opts: Options{TextField: "text", IDField: "id", Workers: 3},
+			want: "{\"id\": 7, \"text\": \"we delve\", \"lang\": \"en\",\"aigc_score\":92.0,\"aigc_risk_level\":\"low\",\"aigc_rules\":[\"high_frequency_words\"]}\n" +
+				"{\"text\":\"\",\"id\":\"b\",\"aigc_error\":\"text is empty\"}\n" +
+				"{\"id\":\"c\",\"aigc_error\":\"field \\\"text\\\" not found\"}\n" +
+				"{\"aigc_error\":\"field \\\"text\\\" not found\"}\n" +
+				"{\"text\":\"boom\",\"aigc_error\":\"detector failed\"}\n",
+			failed: 4,
+		},
+		{
+			name:     "jsonl invalid lines do not stop the run",
+			filename: "data.jsonl",
+			input:    "{\"text\":\"a\"}\r\n[1,2]\r\n{\"text\":\n\n{\"text\":\"b\"}\n",
+			opts:     Options{TextField: "text"},
+			want: "{\"text\":\"a\",\"aigc_score\":99.0,\"aigc_risk_level\":\"low\",\"aigc_rules\":[]}\n" +
+				"{\"aigc_error\":\"input line 2 (byte offset 14) is not a JSON object\"}\n" +
+				"{\"aigc_error\":\"input line 3 (byte offset 21) is not a JSON object\"}\n" +
+				"{\"text\":\"b\",\"aigc_score\":99.0,\"aigc_risk_level\":\"low\",\"aigc_rules\":[]}\n",
+			failed: 2,
+		},
+		{
+			name:     "csv with quoted multi-line field",
+			filename: "survey.csv",
+			input:    "\ufeffID,Answer\n1,\"Line one,\nline two\"\n2,short\n3\n",
+			opts:     Options{TextField: "answer", IDField: "id", Workers: 2},
+			want: "ID,Answer,aigc_score,aigc_risk_level,aigc_rules,aigc_error\n" +
+				"1,\"Line one,\nline two\",82.0,low,,\n" +
+				"2,short,95.0,low,,\n" +
+				"3,,,,,\"row has only 1 columns, text column is missing\"\n",
+			failed: 1,
+		},
+		{
+			name:     "csv ragged rows align with header",
+			filename: "ragged.csv",
+			input:    "id,text\n1,short,extra,more\n2\n3,ok\n",
+			opts:     Options{TextField: "text", IDField: "id"},
+			want: "id,text,aigc_score,aigc_risk_level,aigc_rules,aigc_error\n" +
+				"1,short,95.0,low,,\n" +
+				"2,,,,,\"row has only 1 columns, text column is missing\"\n" +
+				"3,ok,98.0,low,,\n",
+			failed: 1,
+		},
+		{
+			name:     "tsv",
+			filename: "rows.tsv",
+			input:    "text\tsource\nwe delve deep\tweb\n",
+			opts:     Options{TextField: "text"},
+			want:     "text\tsource\taigc_score\taigc_risk_level\taigc_rules\taigc_error\nwe delve deep\tweb\t87.0\tlow\thigh_frequency_words\t\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, output := writeInput(t, tt.filename, tt.input)
+			summary, err := Run(context.Background(), input, output, tt.opts, fakeDetect, nil)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			got, _ := os.ReadFile(output)
+			if string(got) != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", got, tt.want)
+			}
+			if summary.Failed != tt.failed {
+				t.Errorf("Failed = %d, want %d", summary.Failed, tt.failed)
+			}
+			if _, err := os.Stat(output + ".checkpoint"); !os.IsNotExist(err) {
+				t.Error("checkpoint should be removed after a complete run")
+			}
+		})
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		input    string
+		opts     Options
+		want     string
+	}{
+		{"missing csv column", "data.csv", "id,body\n1,a\n", Options{TextField: "text"}, `text column "text" not found`},
+		{"unknown format", "data.xml", "<rows/>", Options{TextField: "text"}, "cannot determine corpus format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, output := writeInput(t, tt.filename, tt.input)
+			_, err := Run(context.Background(), input, output, tt.opts, fakeDetect, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Run() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_Resume(t *testing.T) {
+	for _, filename := range []string{"data.jsonl", "data.csv"} {
+		t.Run(filename, func(t *testing.T) {
+			var sb strings.Builder
+			if strings.HasSuffix(filename, ".csv") {
+				sb.WriteString("id,text\n")
+			}
+			for i := 0; i < 50; i++ {
+				if strings.HasSuffix(filename, ".csv") {
+					fmt.Fprintf(&sb, "%d,\"row %d, delve\"\n", i, i)
+				} else {
+					fmt.Fprintf(&sb, "{\"id\":%d,\"text\":\"row %d\"}\n", i, i)
+				}
+			}
+			input, output := writeInput(t, filename, sb.String())
+			opts := Options{TextField: "text", IDField: "id", Workers: 4, CheckpointEvery: 7}
+
+			if _, err := Run(context.Background(), input, output, opts, fakeDetect, nil); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			want, _ := os.ReadFile(output)
+
+			// 检测到第 20 行时中断
+			ctx, cancel := context.WithCancel(context.Background())
+			var calls atomic.Int64
+			interrupted := func(text string) (*models.DetectionResult, error) {
+				if calls.Add(1) == 20 {
+					cancel()
+				}
+				return fakeDetect(text)
+			}
+			summary, err := Run(ctx, input, output, opts, interrupted, nil)
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("interrupted Run() error = %v, want context.Canceled", err)
+			}
+			if summary.Rows == 0 || summary.Rows >= 50 {
+				t.Fatalf("interrupted run wrote %d rows", summary.Rows)
+			}
+			// 模拟检查点之后还写出了部分内容
+			f, _ := os.OpenFile(output, os.O_APPEND|os.O_WRONLY, 0)
+			f.WriteString("partial row")
+			f.Close()
+
+			opts.Resume = true
+			resumed, err := Run(context.Background(), input, output, opts, fakeDetect, nil)
+			if err != nil {
+				t.Fatalf("resumed Run() error = %v", err)
+			}
+			if !resumed.Resumed || resumed.Skipped != summary.Rows || resumed.Rows != 50 {
+				t.Errorf("resumed summary = %+v, want skipped %d and 50 rows", resumed, summary.Rows)
+			}
+			got, _ := os.ReadFile(output)
+			if string(got) != string(want) {
+				t.Errorf("resumed output differs from an uninterrupted run:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestRun_ResumeMismatch(t *testing.T) {
+	input, output := writeInput(t, "data.jsonl", "{\"text\":\"a\"}\n")
+	cp := newCheckpoint(input, Options{Format: FormatJSONL, TextField: "body"})
+	if err := cp.save(output + ".checkpoint"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Run(context.Background(), input, output, Options{TextField: "text", Resume: true}, fakeDetect, nil)
+	if !errors.Is(err, ErrCheckpointMismatch) {
+		t.Errorf("Run() error = %v, want ErrCheckpointMismatch", err)
+	}
+}
@@ -0,0 +1,128 @@
+package corpus
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// csvSource 逐条读取带表头的 CSV/TSV，按列名取文本和 ID
+type csvSource struct {
+	r      *csv.Reader
+	base   int64 // r 开头在输入文件中的位置
+	seq    int64
+	textAt int
+	idAt   int // 未指定 ID 列时为 -1
+}
+
+// newCSVSource 读取表头并定位到 offset（从检查点继续时为上次处理到的位置，新运行为 0），返回读取器和表头
+func newCSVSource(in *os.File, comma rune, offset int64, opts Options) (*csvSource, []string, error) {
+	if _, err := in.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, fmt.Errorf("failed to seek input: %w", err)
+	}
+	r := newCSVReader(in, comma)
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("input has no header row")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	header = append([]string(nil), header...)
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	s := &csvSource{r: r, idAt: -1, textAt: columnIndex(header, opts.TextField)}
+	if s.textAt < 0 {
+		return nil, nil, fmt.Errorf("text column %q not found in header (columns: %s)", opts.TextField, strings.Join(header, ", "))
+	}
+	if opts.IDField != "" {
+		if s.idAt = columnIndex(header, opts.IDField); s.idAt < 0 {
+			return nil, nil, fmt.Errorf("id column %q not found in header (columns: %s)", opts.IDField, strings.Join(header, ", "))
+		}
+	}
+
+	// 新运行从表头之后继续读取；从检查点继续时重新定位
+	if offset > 0 {
+		if _, err := in.Seek(offset, io.SeekStart); err != nil {
+			return nil, nil, fmt.Errorf("failed to seek input: %w", err)
+		}
+		s.r, s.base = newCSVReader(in, comma), offset
+	}
+	return s, header, nil
+}
+
+// newCSVReader 创建宽松的 CSV 读取器：允许各行列数不同和未转义的引号
+func newCSVReader(r io.Reader, comma rune) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	return reader
+}
+
+// columnIndex 按列名查找列，忽略大小写和首尾空白
+func columnIndex(header []string, name string) int {
+	for i, column := range header {
+		if strings.EqualFold(strings.TrimSpace(column), strings.TrimSpace(name)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *csvSource) next() (*row, error) {
+	record, err := s.r.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	rw := &row{seq: s.seq, record: record, end: s.base + s.r.InputOffset()}
+	s.seq++
+	if s.idAt >= 0 && s.idAt < len(record) {
+		rw.id = record[s.idAt]
+	}
+	if s.textAt < len(record) {
+		rw.text = record[s.textAt]
+	} else {
+		rw.err = fmt.Errorf("row has only %d columns, text column is missing", len(record))
+	}
+	return rw, nil
+}
+
+// csvSink 在每行末尾追加检测结果列
+type csvSink struct {
+	w       *csv.Writer
+	columns []string // 输入的表头
+}
+
+// newCSVSink 创建 CSV 写出器
+func newCSVSink(w io.Writer, comma rune, header []string) *csvSink {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+	return &csvSink{w: writer, columns: header}
+}
+
+func (s *csvSink) header() error {
+	s.w.Write(append(s.columns, ColumnScore, ColumnRiskLevel, ColumnRules, ColumnError))
+	s.w.Flush()
+	return s.w.Error()
+}
+
+func (s *csvSink) write(r *row, o *outcome) error {
+	// 每行截断或补齐到表头的列数，使检测结果列与表头对齐；多出的列没有列名，不写出
+	record := make([]string, len(s.columns), len(s.columns)+4)
+	copy(record, r.record)
+	if o.err != "" {
+		record = append(record, "", "", "", o.err)
+	} else {
+		record = append(record, formatScore(o.score), string(o.risk), strings.Join(o.rules, ";"), "")
+	}
+	s.w.Write(record)
+	// csv.Writer 自带缓冲，每行刷新到下层的缓冲写出器，使写出的字节数与检查点一致
+	s.w.Flush()
+	return s.w.Error()
+}
@@ -0,0 +1,123 @@
+package corpus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// jsonlSource 逐行读取 JSON Lines，每行应是一个 JSON 对象，空行被跳过
+// 不是 JSON 对象的行输出为只含 aigc_error 字段的对象
+type jsonlSource struct {
+	r      *bufio.Reader
+	offset int64 // 已读取到的输入位置
+	line   int64 // 已读取的物理行数，从检查点继续时从 0 计数
+	seq    int64
+	opts   Options
+}
+
+// newJSONLSource 创建 JSON Lines 读取器，offset 为 r 开头在输入文件中的位置
+func newJSONLSource(r io.Reader, offset int64, opts Options) *jsonlSource {
+	return &jsonlSource{r: bufio.NewReaderSize(r, 64*1024), offset: offset, opts: opts}
+}
+
+func (s *jsonlSource) next() (*row, error) {
+	for {
+		line, err := s.r.ReadBytes('\n')
+		s.offset += int64(len(line))
+		if len(line) == 0 && err != nil {
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("failed to read input: %w", err)
+		}
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read input: %w", err)
+		}
+		s.line++
+		start := s.offset - int64(len(line))
+		line = bytes.TrimRight(line, "\r\n")
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		rw := &row{seq: s.seq, raw: line, end: s.offset}
+		s.seq++
+
+		// 无法解析的行作为失败行输出，不中断整个语料的处理
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(line, &fields); err != nil || fields == nil {
+			rw.raw = []byte("{}")
+			rw.err = fmt.Errorf("input line %d (byte offset %d) is not a JSON object", s.line, start)
+			return rw, nil
+		}
+		rw.id = jsonID(fields[s.opts.IDField])
+		text, ok := fields[s.opts.TextField]
+		switch {
+		case !ok:
+			rw.err = fmt.Errorf("field %q not found", s.opts.TextField)
+		case json.Unmarshal(text, &rw.text) != nil:
+			rw.err = fmt.Errorf("field %q is not a string", s.opts.TextField)
+		}
+		return rw, nil
+	}
+}
+
+// jsonID 把 ID 字段的值转换为字符串：字符串取其内容，数字等其他值取 JSON 文本
+func jsonID(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+// jsonlSink 在每行 JSON 对象末尾追加检测结果字段，原有字段的内容和顺序保持不变
+type jsonlSink struct {
+	w io.Writer
+}
+
+func (s *jsonlSink) header() error { return nil }
+
+func (s *jsonlSink) write(r *row, o *outcome) error {
+	obj := bytes.TrimSpace(r.raw)
+	body := bytes.TrimSpace(obj[1 : len(obj)-1])
+
+	var buf bytes.Buffer
+	buf.Grow(len(obj) + 128)
+	buf.Write(obj[:len(obj)-1])
+	if len(body) > 0 {
+		buf.WriteByte(',')
+	}
+	if o.err != "" {
+		writeJSONField(&buf, ColumnError, o.err)
+	} else {
+		rules := o.rules
+		if rules == nil {
+			rules = []string{}
+		}
+		writeJSONField(&buf, ColumnScore, json.Number(formatScore(o.score)))
+		buf.WriteByte(',')
+		writeJSONField(&buf, ColumnRiskLevel, o.risk)
+		buf.WriteByte(',')
+		writeJSONField(&buf, ColumnRules, rules)
+	}
+	buf.WriteString("}\n")
+	_, err := s.w.Write(buf.Bytes())
+	return err
+}
+
+// writeJSONField 写出一个 "键":值
+func writeJSONField(buf *bytes.Buffer, key string, value any) {
+	k, _ := json.Marshal(key)
+	v, _ := json.Marshal(value)
+	buf.Write(k)
+	buf.WriteByte(':')
+	buf.Write(v)
+}
@@ -5,7 +5,11 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -13,27 +17,58 @@ import (
 
 var DB *gorm.DB
 
+// 支持的数据库类型
+const (
+	TypeSQLite   = "sqlite"
+	TypePostgres = "postgres"
+	TypeMySQL    = "mysql"
+)
+
 // Config 数据库配置
 type Config struct {
 	Type     string // sqlite, postgres, mysql
 	DSN      string // 数据源名称
 	LogLevel logger.LogLevel
+
+	// 连接池配置，零值表示使用驱动默认值
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxIdleTime time.Duration
+	ConnMaxLifetime time.Duration
 }
 
 // Initialize 初始化数据库连接
 func Initialize(config Config) error {
+	db, err := Open(config)
+	if err != nil {
+		return err
+	}
+
+	DB = db
+	log.Printf("Database connected successfully (type: %s)", NormalizeType(config.Type))
+
+	return nil
+}
+
+// Open 按配置打开数据库连接并设置连接池，不修改全局实例
+func Open(config Config) (*gorm.DB, error) {
 	var dialector gorm.Dialector
 
-	switch config.Type {
-	case "sqlite":
+	switch NormalizeType(config.Type) {
+	case TypeSQLite:
 		// 确保数据目录存在
-		dbDir := filepath.Dir(config.DSN)
-		if err := os.MkdirAll(dbDir, 0755); err != nil {
-			return fmt.Errorf("failed to create database directory: %w", err)
+		if path := sqlitePath(config.DSN); path != "" {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return nil, fmt.Errorf("failed to create database directory: %w", err)
+			}
 		}
-		dialector = sqlite.Open(config.DSN)
+		dialector = sqlite.Open(withSQLiteBusyTimeout(config.DSN))
+	case TypePostgres:
+		dialector = postgres.Open(config.DSN)
+	case TypeMySQL:
+		dialector = mysql.Open(config.DSN)
 	default:
-		return fmt.Errorf("unsupported database type: %s", config.Type)
+		return nil, fmt.Errorf("unsupported database type: %s", config.Type)
 	}
 
 	// 配置 GORM
@@ -44,13 +79,53 @@ func Initialize(config Config) error {
 	// 连接数据库
 	db, err := gorm.Open(dialector, gormConfig)
 	if err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	DB = db
-	log.Printf("Database connected successfully (type: %s)", config.Type)
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database instance: %w", err)
+	}
+	if config.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	}
+	if config.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	}
+	if config.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+	}
+	if config.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
+	}
 
-	return nil
+	return db, nil
+}
+
+// NormalizeType 规范化数据库类型名称（postgresql/pg → postgres，sqlite3 → sqlite）
+func NormalizeType(dbType string) string {
+	switch dbType {
+	case "postgresql", "pg":
+		return TypePostgres
+	case "sqlite3":
+		return TypeSQLite
+	case "mariadb":
+		return TypeMySQL
+	}
+	return dbType
+}
+
+// sqlitePath 从 SQLite DSN 中提取文件路径，内存数据库返回空字符串
+func sqlitePath(dsn string) string {
+	path := dsn
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	path = strings.TrimPrefix(path, "file:")
+	if path == "" || path == ":memory:" {
+		return ""
+	}
+	return path
 }
 
 // Close 关闭数据库连接
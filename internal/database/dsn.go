@@ -0,0 +1,135 @@
+package database
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/leoobai/aigc-check/internal/config"
+	"gorm.io/gorm/logger"
+)
+
+// NewConfig 根据配置文件中的 database 配置块构建数据库连接配置
+func NewConfig(cfg config.DatabaseConfig, logLevel logger.LogLevel) (Config, error) {
+	dbType := NormalizeType(cfg.Type)
+
+	dsn, err := BuildDSN(cfg)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return Config{
+		Type:            dbType,
+		DSN:             dsn,
+		LogLevel:        logLevel,
+		MaxOpenConns:    cfg.Pool.MaxConnections,
+		MaxIdleConns:    cfg.Pool.MinConnections,
+		ConnMaxIdleTime: time.Duration(cfg.Pool.MaxIdleTime) * time.Second,
+		ConnMaxLifetime: time.Duration(cfg.Pool.MaxLifetime) * time.Second,
+	}, nil
+}
+
+// BuildDSN 根据数据库类型构建数据源名称
+func BuildDSN(cfg config.DatabaseConfig) (string, error) {
+	switch NormalizeType(cfg.Type) {
+	case TypeSQLite:
+		return sqliteDSN(cfg.SQLite)
+	case TypePostgres:
+		return postgresDSN(cfg.PostgreSQL)
+	case TypeMySQL:
+		return mysqlDSN(cfg.MySQL)
+	default:
+		return "", fmt.Errorf("unsupported database type: %s", cfg.Type)
+	}
+}
+
+// sqliteBusyTimeout SQLite 等待写锁的毫秒数，批量检测和任务队列会并发写入同一个数据库文件
+const sqliteBusyTimeout = 5000
+
+// sqliteDSN 构建 SQLite 数据源名称，总是设置忙等待超时
+func sqliteDSN(cfg config.SQLiteConfig) (string, error) {
+	if cfg.Path == "" {
+		return "", fmt.Errorf("sqlite path is required")
+	}
+	if !cfg.WALEnabled {
+		return withSQLiteBusyTimeout(cfg.Path), nil
+	}
+	return withSQLiteBusyTimeout(cfg.Path + "?_journal_mode=WAL"), nil
+}
+
+// withSQLiteBusyTimeout 为 SQLite 数据源名称补充并发写入所需的参数，否则并发写入时会立即返回 "database is locked"：
+// _busy_timeout 让写入等待锁释放；_txlock=immediate 让事务开始时即获取写锁，
+// 避免两个事务都持有读锁后同时升级为写锁（这种情况下 SQLite 不等待超时，直接报错）
+func withSQLiteBusyTimeout(dsn string) string {
+	if !strings.Contains(dsn, "_busy_timeout=") && !strings.Contains(dsn, "_timeout=") {
+		dsn = appendSQLiteParam(dsn, fmt.Sprintf("_busy_timeout=%d", sqliteBusyTimeout))
+	}
+	if !strings.Contains(dsn, "_txlock=") {
+		dsn = appendSQLiteParam(dsn, "_txlock=immediate")
+	}
+	return dsn
+}
+
+// appendSQLiteParam 向 SQLite 数据源名称追加查询参数
+func appendSQLiteParam(dsn, param string) string {
+	if strings.Contains(dsn, "?") {
+		return dsn + "&" + param
+	}
+	return dsn + "?" + param
+}
+
+// postgresDSN 构建 PostgreSQL 连接 URL，用户名和密码会被正确转义
+func postgresDSN(cfg config.PostgreSQLConfig) (string, error) {
+	if cfg.Database == "" {
+		return "", fmt.Errorf("postgresql database name is required")
+	}
+
+	u := url.URL{
+		Scheme: "postgres",
+		Host:   net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Path:   "/" + cfg.Database,
+	}
+	if cfg.User != "" {
+		if cfg.Password != "" {
+			u.User = url.UserPassword(cfg.User, cfg.Password)
+		} else {
+			u.User = url.User(cfg.User)
+		}
+	}
+
+	query := url.Values{}
+	if cfg.SSLMode != "" {
+		query.Set("sslmode", cfg.SSLMode)
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// mysqlDSN 构建 MySQL 数据源名称
+func mysqlDSN(cfg config.MySQLConfig) (string, error) {
+	if cfg.Database == "" {
+		return "", fmt.Errorf("mysql database name is required")
+	}
+
+	charset := cfg.Charset
+	if charset == "" {
+		charset = "utf8mb4"
+	}
+
+	mc := mysql.NewConfig()
+	mc.User = cfg.User
+	mc.Passwd = cfg.Password
+	mc.Net = "tcp"
+	mc.Addr = net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	mc.DBName = cfg.Database
+	mc.ParseTime = true
+	mc.Loc = time.Local
+	mc.Params = map[string]string{"charset": charset}
+
+	return mc.FormatDSN(), nil
+}
@@ -0,0 +1,148 @@
+package database
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leoobai/aigc-check/internal/config"
+	"gorm.io/gorm/logger"
+)
+
+func TestBuildDSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.DatabaseConfig
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "sqlite",
+			cfg: config.DatabaseConfig{
+				Type:   "sqlite",
+				SQLite: config.SQLiteConfig{Path: "./data/aigc.db"},
+			},
+			want: "./data/aigc.db?_busy_timeout=5000&_txlock=immediate",
+		},
+		{
+			name: "sqlite with WAL",
+			cfg: config.DatabaseConfig{
+				Type:   "sqlite",
+				SQLite: config.SQLiteConfig{Path: "./data/aigc.db", WALEnabled: true},
+			},
+			want: "./data/aigc.db?_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate",
+		},
+		{
+			name: "postgresql",
+			cfg: config.DatabaseConfig{
+				Type: "postgresql",
+				PostgreSQL: config.PostgreSQLConfig{
+					Host: "db", Port: 5432, User: "aigc", Password: "secret", Database: "aigc_check", SSLMode: "disable",
+				},
+			},
+			want: "postgres://aigc:secret@db:5432/aigc_check?sslmode=disable",
+		},
+		{
+			name: "mysql",
+			cfg: config.DatabaseConfig{
+				Type: "mysql",
+				MySQL: config.MySQLConfig{
+					Host: "db", Port: 3306, User: "aigc", Password: "secret", Database: "aigc_check", Charset: "utf8mb4",
+				},
+			},
+			want: "aigc:secret@tcp(db:3306)/aigc_check?loc=Local&parseTime=true&charset=utf8mb4",
+		},
+		{
+			name:    "postgresql without database",
+			cfg:     config.DatabaseConfig{Type: "postgres", PostgreSQL: config.PostgreSQLConfig{Host: "db", Port: 5432}},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported type",
+			cfg:     config.DatabaseConfig{Type: "oracle"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildDSN(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BuildDSN() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("BuildDSN() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildDSN_PostgresEscapesCredentials(t *testing.T) {
+	cfg := config.DatabaseConfig{
+		Type: "postgresql",
+		PostgreSQL: config.PostgreSQLConfig{
+			Host: "db", Port: 5432, User: "aigc", Password: "p@ss word/:?", Database: "aigc_check",
+		},
+	}
+
+	dsn, err := BuildDSN(cfg)
+	if err != nil {
+		t.Fatalf("BuildDSN() error = %v", err)
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("url.Parse(%s) error = %v", dsn, err)
+	}
+	if password, _ := u.User.Password(); password != "p@ss word/:?" {
+		t.Errorf("password = %q, want original value", password)
+	}
+	if u.Host != "db:5432" {
+		t.Errorf("host = %s, want db:5432", u.Host)
+	}
+}
+
+func TestNewConfig_Pool(t *testing.T) {
+	cfg := config.DefaultDatabaseConfig
+
+	dbConfig, err := NewConfig(cfg, logger.Silent)
+	if err != nil {
+		t.Fatalf("NewConfig() error = %v", err)
+	}
+
+	if dbConfig.Type != TypeSQLite {
+		t.Errorf("Type = %s, want %s", dbConfig.Type, TypeSQLite)
+	}
+	if dbConfig.MaxOpenConns != 10 || dbConfig.MaxIdleConns != 2 {
+		t.Errorf("MaxOpenConns/MaxIdleConns = %d/%d, want 10/2", dbConfig.MaxOpenConns, dbConfig.MaxIdleConns)
+	}
+	if dbConfig.ConnMaxIdleTime != 300*time.Second || dbConfig.ConnMaxLifetime != time.Hour {
+		t.Errorf("ConnMaxIdleTime/ConnMaxLifetime = %v/%v, want 5m/1h", dbConfig.ConnMaxIdleTime, dbConfig.ConnMaxLifetime)
+	}
+}
+
+func TestNormalizeType(t *testing.T) {
+	tests := map[string]string{
+		"postgresql": TypePostgres,
+		"postgres":   TypePostgres,
+		"pg":         TypePostgres,
+		"sqlite3":    TypeSQLite,
+		"sqlite":     TypeSQLite,
+		"mariadb":    TypeMySQL,
+		"mysql":      TypeMySQL,
+	}
+
+	for input, want := range tests {
+		if got := NormalizeType(input); got != want {
+			t.Errorf("NormalizeType(%s) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestOpen_UnsupportedType(t *testing.T) {
+	_, err := Open(Config{Type: "oracle"})
+	if err == nil || !strings.Contains(err.Error(), "unsupported database type") {
+		t.Errorf("Open() error = %v, want unsupported database type", err)
+	}
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// detectionRecordV1 初始版本的检测记录表结构
+type detectionRecordV1 struct {
+	ID               string  `gorm:"primaryKey;size:64"`
+	RequestID        string  `gorm:"uniqueIndex;size:64;not null"`
+	Text             string  `gorm:"not null"`
+	TextPreview      string  `gorm:"size:512"`
+	Score            float64 `gorm:"not null"`
+	RiskLevel        string  `gorm:"size:32;not null;index"`
+	RuleResults      string
+	Suggestions      string
+	MultimodalResult string
+	ProcessTime      string    `gorm:"size:64"`
+	CreatedAt        time.Time `gorm:"index"`
+	UpdatedAt        time.Time
+}
+
+// TableName 指定表名
+func (detectionRecordV1) TableName() string {
+	return "detection_records"
+}
+
+func init() {
+	register(Migration{
+		Version: 1,
+		Name:    "create_detection_records",
+		Up: func(tx *gorm.DB) error {
+			// 早期版本通过 AutoMigrate 建表，已存在时视为已完成
+			if tx.Migrator().HasTable(&detectionRecordV1{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&detectionRecordV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&detectionRecordV1{})
+		},
+	})
+}
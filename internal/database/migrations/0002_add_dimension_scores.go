@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// detectionRecordV2 新增五个维度评分列，旧记录为 NULL
+type detectionRecordV2 struct {
+	VocabularyDiversityScore   *float64
+	SentenceComplexityScore    *float64
+	PersonalizationScore       *float64
+	LogicalCoherenceScore      *float64
+	EmotionalAuthenticityScore *float64
+}
+
+// TableName 指定表名
+func (detectionRecordV2) TableName() string {
+	return "detection_records"
+}
+
+// dimensionScoreFields 维度评分字段
+var dimensionScoreFields = []string{
+	"VocabularyDiversityScore",
+	"SentenceComplexityScore",
+	"PersonalizationScore",
+	"LogicalCoherenceScore",
+	"EmotionalAuthenticityScore",
+}
+
+func init() {
+	register(Migration{
+		Version: 2,
+		Name:    "add_dimension_scores",
+		Up: func(tx *gorm.DB) error {
+			for _, field := range dimensionScoreFields {
+				if err := addColumn(tx, &detectionRecordV2{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, field := range dimensionScoreFields {
+				if err := dropColumn(tx, &detectionRecordV2{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// addColumn 添加列，列已存在时跳过
+func addColumn(tx *gorm.DB, model interface{}, field string) error {
+	if tx.Migrator().HasColumn(model, field) {
+		return nil
+	}
+	return tx.Migrator().AddColumn(model, field)
+}
+
+// dropColumn 删除列，列不存在时跳过
+func dropColumn(tx *gorm.DB, model interface{}, field string) error {
+	if !tx.Migrator().HasColumn(model, field) {
+		return nil
+	}
+	return tx.Migrator().DropColumn(model, field)
+}
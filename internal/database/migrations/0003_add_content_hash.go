@@ -0,0 +1,76 @@
+package migrations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// contentHashIndex 内容哈希索引名
+const contentHashIndex = "idx_detection_records_content_hash"
+
+// detectionRecordV3 新增内容哈希列
+type detectionRecordV3 struct {
+	ID          string `gorm:"primaryKey;size:64"`
+	Text        string
+	ContentHash string `gorm:"size:64;index:idx_detection_records_content_hash"`
+}
+
+// TableName 指定表名
+func (detectionRecordV3) TableName() string {
+	return "detection_records"
+}
+
+func init() {
+	register(Migration{
+		Version: 3,
+		Name:    "add_content_hash",
+		Up: func(tx *gorm.DB) error {
+			if err := addColumn(tx, &detectionRecordV3{}, "ContentHash"); err != nil {
+				return err
+			}
+			if !tx.Migrator().HasIndex(&detectionRecordV3{}, contentHashIndex) {
+				if err := tx.Migrator().CreateIndex(&detectionRecordV3{}, contentHashIndex); err != nil {
+					return err
+				}
+			}
+			return backfillContentHash(tx)
+		},
+		Down: func(tx *gorm.DB) error {
+			if tx.Migrator().HasIndex(&detectionRecordV3{}, contentHashIndex) {
+				if err := tx.Migrator().DropIndex(&detectionRecordV3{}, contentHashIndex); err != nil {
+					return err
+				}
+			}
+			return dropColumn(tx, &detectionRecordV3{}, "ContentHash")
+		},
+	})
+}
+
+// backfillContentHash 为已有记录计算内容哈希
+func backfillContentHash(tx *gorm.DB) error {
+	var batch []detectionRecordV3
+	return tx.Select("id", "text").
+		Where("content_hash IS NULL OR content_hash = ?", "").
+		FindInBatches(&batch, 200, func(batchTx *gorm.DB, _ int) error {
+			for _, record := range batch {
+				if err := tx.Model(&detectionRecordV3{}).
+					Where("id = ?", record.ID).
+					Update("content_hash", contentHashV3(record.Text)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// contentHashV3 迁移 3 使用的内容哈希：统一换行符并去除首尾空白后计算 SHA-256
+// 与迁移时的 repository.ContentHash 一致，此后哈希规则的变化不影响本迁移写入的值
+func contentHashV3(text string) string {
+	normalized := strings.ReplaceAll(text, "\r\n", "\n")
+	normalized = strings.TrimSpace(normalized)
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// detectionRecordV4 新增完整评分、风险描述、分析器版本和配置指纹
+type detectionRecordV4 struct {
+	ScoreDetail       string // JSON，完整的 models.Score
+	RiskDescription   string `gorm:"size:128"`
+	AnalyzerVersion   string `gorm:"size:32"`
+	ConfigFingerprint string `gorm:"size:64"`
+}
+
+// TableName 指定表名
+func (detectionRecordV4) TableName() string {
+	return "detection_records"
+}
+
+// scoreDetailFields 新增字段
+var scoreDetailFields = []string{
+	"ScoreDetail",
+	"RiskDescription",
+	"AnalyzerVersion",
+	"ConfigFingerprint",
+}
+
+func init() {
+	register(Migration{
+		Version: 4,
+		Name:    "add_score_detail",
+		Up: func(tx *gorm.DB) error {
+			for _, field := range scoreDetailFields {
+				if err := addColumn(tx, &detectionRecordV4{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, field := range scoreDetailFields {
+				if err := dropColumn(tx, &detectionRecordV4{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,158 @@
+package migrations
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// textSearchIndex PostgreSQL 全文检索索引名
+const textSearchIndex = "idx_detection_records_text_fts"
+
+// detectionRecordV5 新增触发规则和标签列，用于历史记录过滤
+type detectionRecordV5 struct {
+	ID            string `gorm:"primaryKey;size:64"`
+	RuleResults   string
+	DetectedRules string `gorm:"size:1024"`
+	Tags          string `gorm:"size:1024"`
+}
+
+// TableName 指定表名
+func (detectionRecordV5) TableName() string {
+	return "detection_records"
+}
+
+// sqliteFTSStatements 创建 SQLite FTS5 索引表及同步触发器
+// 使用独立存储而非外部内容表：detection_records 以字符串为主键，隐式 rowid 在 VACUUM 后可能变化
+var sqliteFTSStatements = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS detection_records_fts USING fts5(id UNINDEXED, text, tokenize = 'trigram')`,
+	`CREATE TRIGGER IF NOT EXISTS detection_records_fts_ai AFTER INSERT ON detection_records BEGIN
+		INSERT INTO detection_records_fts (id, text) VALUES (new.id, new.text);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS detection_records_fts_ad AFTER DELETE ON detection_records BEGIN
+		DELETE FROM detection_records_fts WHERE id = old.id;
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS detection_records_fts_au AFTER UPDATE OF text ON detection_records BEGIN
+		UPDATE detection_records_fts SET text = new.text WHERE id = old.id;
+	END`,
+	`INSERT INTO detection_records_fts (id, text) SELECT id, text FROM detection_records`,
+}
+
+func init() {
+	register(Migration{
+		Version: 5,
+		Name:    "add_search_filters",
+		Up: func(tx *gorm.DB) error {
+			for _, field := range []string{"DetectedRules", "Tags"} {
+				if err := addColumn(tx, &detectionRecordV5{}, field); err != nil {
+					return err
+				}
+			}
+			if err := backfillDetectedRules(tx); err != nil {
+				return err
+			}
+			return createTextSearchIndex(tx)
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropTextSearchIndex(tx); err != nil {
+				return err
+			}
+			for _, field := range []string{"Tags", "DetectedRules"} {
+				if err := dropColumn(tx, &detectionRecordV5{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// backfillDetectedRules 从已保存的规则结果中提取触发的规则类型
+func backfillDetectedRules(tx *gorm.DB) error {
+	var batch []detectionRecordV5
+	return tx.Select("id", "rule_results").
+		Where("detected_rules IS NULL OR detected_rules = ?", "").
+		FindInBatches(&batch, 200, func(batchTx *gorm.DB, _ int) error {
+			for _, record := range batch {
+				var results []struct {
+					RuleType string `json:"rule_type"`
+					Detected bool   `json:"detected"`
+				}
+				if err := json.Unmarshal([]byte(record.RuleResults), &results); err != nil {
+					continue
+				}
+
+				var detected []string
+				for _, result := range results {
+					if result.Detected {
+						detected = append(detected, result.RuleType)
+					}
+				}
+				if len(detected) == 0 {
+					continue
+				}
+				if err := tx.Model(&detectionRecordV5{}).
+					Where("id = ?", record.ID).
+					Update("detected_rules", joinListV5(detected)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// joinListV5 迁移 5 使用的列表编码：首尾和元素之间以逗号分隔，便于按 ",item," 匹配
+// 与迁移时的 repository.JoinList 一致，此后编码方式的变化不影响本迁移写入的值
+func joinListV5(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return "," + strings.Join(values, ",") + ","
+}
+
+// createTextSearchIndex 创建全文检索索引
+// SQLite 仅在编译了 FTS5 时创建（构建标签 sqlite_fts5），否则检索退回 LIKE 匹配
+func createTextSearchIndex(tx *gorm.DB) error {
+	switch tx.Dialector.Name() {
+	case "sqlite":
+		var enabled int
+		if err := tx.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled).Error; err != nil {
+			return err
+		}
+		if enabled == 0 {
+			log.Println("SQLite FTS5 not available, full-text search falls back to LIKE")
+			return nil
+		}
+		for _, stmt := range sqliteFTSStatements {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+	case "postgres":
+		return tx.Exec("CREATE INDEX IF NOT EXISTS " + textSearchIndex +
+			" ON detection_records USING GIN (to_tsvector('simple', text))").Error
+	}
+	return nil
+}
+
+// dropTextSearchIndex 删除全文检索索引
+func dropTextSearchIndex(tx *gorm.DB) error {
+	switch tx.Dialector.Name() {
+	case "sqlite":
+		for _, stmt := range []string{
+			"DROP TRIGGER IF EXISTS detection_records_fts_ai",
+			"DROP TRIGGER IF EXISTS detection_records_fts_ad",
+			"DROP TRIGGER IF EXISTS detection_records_fts_au",
+			"DROP TABLE IF EXISTS detection_records_fts",
+		} {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+	case "postgres":
+		return tx.Exec("DROP INDEX IF EXISTS " + textSearchIndex).Error
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// duplicateOfIndex 重复提交关联索引名
+const duplicateOfIndex = "idx_detection_records_duplicate_of"
+
+// detectionRecordV6 新增重复提交关联列和提交次数
+type detectionRecordV6 struct {
+	ID          string `gorm:"primaryKey;size:64"`
+	ContentHash string `gorm:"size:64"`
+	DuplicateOf string `gorm:"size:64;index:idx_detection_records_duplicate_of"`
+	SubmitCount int    `gorm:"not null;default:1"`
+}
+
+// TableName 指定表名
+func (detectionRecordV6) TableName() string {
+	return "detection_records"
+}
+
+func init() {
+	register(Migration{
+		Version: 6,
+		Name:    "add_duplicate_tracking",
+		Up: func(tx *gorm.DB) error {
+			for _, field := range []string{"DuplicateOf", "SubmitCount"} {
+				if err := addColumn(tx, &detectionRecordV6{}, field); err != nil {
+					return err
+				}
+			}
+			if !tx.Migrator().HasIndex(&detectionRecordV6{}, duplicateOfIndex) {
+				if err := tx.Migrator().CreateIndex(&detectionRecordV6{}, duplicateOfIndex); err != nil {
+					return err
+				}
+			}
+			return backfillDuplicateOf(tx)
+		},
+		Down: func(tx *gorm.DB) error {
+			if tx.Migrator().HasIndex(&detectionRecordV6{}, duplicateOfIndex) {
+				if err := tx.Migrator().DropIndex(&detectionRecordV6{}, duplicateOfIndex); err != nil {
+					return err
+				}
+			}
+			for _, field := range []string{"SubmitCount", "DuplicateOf"} {
+				if err := dropColumn(tx, &detectionRecordV6{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// backfillDuplicateOf 将已有的重复记录关联到同一内容最早的记录
+func backfillDuplicateOf(tx *gorm.DB) error {
+	var hashes []string
+	if err := tx.Model(&detectionRecordV6{}).
+		Where("content_hash IS NOT NULL AND content_hash <> ?", "").
+		Group("content_hash").
+		Having("COUNT(*) > 1").
+		Pluck("content_hash", &hashes).Error; err != nil {
+		return err
+	}
+
+	for _, hash := range hashes {
+		var ids []string
+		if err := tx.Model(&detectionRecordV6{}).
+			Where("content_hash = ?", hash).
+			Order("created_at").Order("id").
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&detectionRecordV6{}).
+			Where("id IN ?", ids[1:]).
+			Update("duplicate_of", ids[0]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
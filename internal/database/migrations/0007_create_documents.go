@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// documentV7 文档表
+type documentV7 struct {
+	ID        string `gorm:"primaryKey;size:64"`
+	Title     string `gorm:"size:255"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName 指定表名
+func (documentV7) TableName() string {
+	return "documents"
+}
+
+// documentRevisionV7 文档修订版本表
+type documentRevisionV7 struct {
+	ID         uint   `gorm:"primaryKey"`
+	DocumentID string `gorm:"size:64;not null;uniqueIndex:idx_document_revisions_document_revision"`
+	Revision   int    `gorm:"not null;uniqueIndex:idx_document_revisions_document_revision"`
+	RecordID   string `gorm:"size:64;not null;index"`
+	CreatedAt  time.Time
+}
+
+// TableName 指定表名
+func (documentRevisionV7) TableName() string {
+	return "document_revisions"
+}
+
+func init() {
+	register(Migration{
+		Version: 7,
+		Name:    "create_documents",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&documentV7{}, &documentRevisionV7{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&documentRevisionV7{}, &documentV7{})
+		},
+	})
+}
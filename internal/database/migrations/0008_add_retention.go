@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// detectionRecordV8 新增原文清除时间列
+type detectionRecordV8 struct {
+	ID           string `gorm:"primaryKey;size:64"`
+	TextPurgedAt *time.Time
+}
+
+// TableName 指定表名
+func (detectionRecordV8) TableName() string {
+	return "detection_records"
+}
+
+// auditLogV8 审计日志表
+type auditLogV8 struct {
+	ID        uint   `gorm:"primaryKey"`
+	Action    string `gorm:"size:64;not null;index"`
+	Actor     string `gorm:"size:128"`
+	Details   string
+	CreatedAt time.Time `gorm:"index"`
+}
+
+// TableName 指定表名
+func (auditLogV8) TableName() string {
+	return "audit_logs"
+}
+
+func init() {
+	register(Migration{
+		Version: 8,
+		Name:    "add_retention",
+		Up: func(tx *gorm.DB) error {
+			if err := addColumn(tx, &detectionRecordV8{}, "TextPurgedAt"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateTable(&auditLogV8{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&auditLogV8{}); err != nil {
+				return err
+			}
+			return dropColumn(tx, &detectionRecordV8{}, "TextPurgedAt")
+		},
+	})
+}
@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// jobV9 异步检测任务表
+type jobV9 struct {
+	ID      string `gorm:"primaryKey;size:64"`
+	Status  string `gorm:"size:16;not null;index"`
+	Text    string
+	Options string
+	Layers  string
+
+	ResultID string `gorm:"size:64"`
+	Error    string
+
+	WebhookURL      string `gorm:"size:2048"`
+	WebhookStatus   string `gorm:"size:16"`
+	WebhookAttempts int    `gorm:"not null;default:0"`
+	WebhookError    string
+
+	CreatedAt  time.Time `gorm:"index"`
+	UpdatedAt  time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// TableName 指定表名
+func (jobV9) TableName() string {
+	return "jobs"
+}
+
+func init() {
+	register(Migration{
+		Version: 9,
+		Name:    "create_jobs",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&jobV9{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&jobV9{})
+		},
+	})
+}
@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// jobV10 新增任务执行者和租约心跳列
+type jobV10 struct {
+	ID          string `gorm:"primaryKey;size:64"`
+	Owner       string `gorm:"size:64"`
+	HeartbeatAt *time.Time
+}
+
+// TableName 指定表名
+func (jobV10) TableName() string {
+	return "jobs"
+}
+
+func init() {
+	register(Migration{
+		Version: 10,
+		Name:    "add_job_lease",
+		Up: func(tx *gorm.DB) error {
+			for _, field := range []string{"Owner", "HeartbeatAt"} {
+				if err := addColumn(tx, &jobV10{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, field := range []string{"HeartbeatAt", "Owner"} {
+				if err := dropColumn(tx, &jobV10{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
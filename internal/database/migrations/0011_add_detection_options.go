@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// detectionRecordV11 新增影响检测结果的检测选项列，用于判断已有结果能否复用
+type detectionRecordV11 struct {
+	ID          string `gorm:"primaryKey;size:64"`
+	InputFormat string `gorm:"size:16"`
+	Language    string `gorm:"size:16"`
+}
+
+// TableName 指定表名
+func (detectionRecordV11) TableName() string {
+	return "detection_records"
+}
+
+func init() {
+	register(Migration{
+		Version: 11,
+		Name:    "add_detection_options",
+		Up: func(tx *gorm.DB) error {
+			for _, field := range []string{"InputFormat", "Language"} {
+				if err := addColumn(tx, &detectionRecordV11{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, field := range []string{"Language", "InputFormat"} {
+				if err := dropColumn(tx, &detectionRecordV11{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
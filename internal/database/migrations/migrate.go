@@ -1,24 +1,244 @@
 package migrations
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"time"
 
-	"github.com/leoobai/aigc-check/internal/repository"
 	"gorm.io/gorm"
 )
 
-// AutoMigrate 自动迁移数据库表
+// ErrSchemaTooNew 数据库 schema 版本高于当前程序支持的最高版本
+var ErrSchemaTooNew = errors.New("database schema is newer than this binary")
+
+// Migration 版本化迁移
+// 每个迁移位于独立的编号文件中（如 0002_add_dimension_scores.go），
+// Up/Down 在事务中执行，只能使用迁移文件内定义的结构体，不能依赖会随版本变化的业务模型
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration 迁移记录表
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus 单个迁移的执行状态
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Unknown   bool // 数据库中存在但当前程序不认识的迁移（由更新版本的程序执行）
+}
+
+// registry 已注册的迁移，按版本号排序
+var registry []Migration
+
+// register 注册迁移，由各编号迁移文件在 init 中调用
+func register(m Migration) {
+	for _, existing := range registry {
+		if existing.Version == m.Version {
+			panic(fmt.Sprintf("duplicate migration version %d", m.Version))
+		}
+	}
+	registry = append(registry, m)
+	sort.Slice(registry, func(i, j int) bool {
+		return registry[i].Version < registry[j].Version
+	})
+}
+
+// All 返回所有已注册的迁移
+func All() []Migration {
+	result := make([]Migration, len(registry))
+	copy(result, registry)
+	return result
+}
+
+// LatestVersion 当前程序支持的最高 schema 版本
+func LatestVersion() int {
+	if len(registry) == 0 {
+		return 0
+	}
+	return registry[len(registry)-1].Version
+}
+
+// AutoMigrate 启动时执行：检查 schema 版本并应用所有未执行的迁移
 func AutoMigrate(db *gorm.DB) error {
 	log.Println("Starting database migration...")
 
-	// 自动迁移所有模型
-	if err := db.AutoMigrate(
-		&repository.DetectionRecord{},
-	); err != nil {
-		return fmt.Errorf("failed to migrate database: %w", err)
+	applied, err := Migrate(db)
+	if err != nil {
+		return err
 	}
 
-	log.Println("Database migration completed successfully")
+	log.Printf("Database migration completed successfully (applied: %d, version: %d)", len(applied), LatestVersion())
+	return nil
+}
+
+// CheckVersion 检查数据库 schema 版本，高于程序支持的版本时返回 ErrSchemaTooNew
+// 用于不执行迁移、只读取数据库的启动流程
+func CheckVersion(db *gorm.DB) error {
+	current, err := CurrentVersion(db)
+	if err != nil {
+		return err
+	}
+	if current > LatestVersion() {
+		return fmt.Errorf("%w: database version %d, supported version %d", ErrSchemaTooNew, current, LatestVersion())
+	}
 	return nil
 }
+
+// CurrentVersion 获取数据库当前 schema 版本，未执行过迁移时返回 0
+func CurrentVersion(db *gorm.DB) (int, error) {
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return 0, nil
+	}
+
+	var version int
+	if err := db.Model(&SchemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error; err != nil {
+		return 0, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return version, nil
+}
+
+// Migrate 按版本顺序应用所有未执行的迁移，返回本次应用的迁移
+func Migrate(db *gorm.DB) ([]Migration, error) {
+	if err := CheckVersion(db); err != nil {
+		return nil, err
+	}
+
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range registry {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{
+				Version:   m.Version,
+				Name:      m.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("failed to apply migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+
+		log.Printf("Applied migration %04d_%s", m.Version, m.Name)
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+// Rollback 回滚最近执行的 steps 个迁移，返回本次回滚的迁移
+func Rollback(db *gorm.DB, steps int) ([]Migration, error) {
+	if err := CheckVersion(db); err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(registry) - 1; i >= 0 && len(done) < steps; i-- {
+		m := registry[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Where("version = ?", m.Version).Delete(&SchemaMigration{}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("failed to roll back migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+
+		log.Printf("Rolled back migration %04d_%s", m.Version, m.Name)
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+// Status 返回所有迁移的执行状态，包括数据库中存在但程序不认识的迁移
+func Status(db *gorm.DB) ([]MigrationStatus, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	for _, m := range registry {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if record, ok := applied[m.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = record.AppliedAt
+			delete(applied, m.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, record := range applied {
+		statuses = append(statuses, MigrationStatus{
+			Version:   record.Version,
+			Name:      record.Name,
+			Applied:   true,
+			AppliedAt: record.AppliedAt,
+			Unknown:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// appliedVersions 读取已执行的迁移记录，迁移记录表不存在时返回空集合
+func appliedVersions(db *gorm.DB) (map[int]SchemaMigration, error) {
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return map[int]SchemaMigration{}, nil
+	}
+
+	var records []SchemaMigration
+	if err := db.Order("version").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema migrations: %w", err)
+	}
+
+	applied := make(map[int]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
@@ -0,0 +1,239 @@
+package migrations
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leoobai/aigc-check/internal/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestRegistry_Ordered(t *testing.T) {
+	migrations := All()
+	if len(migrations) == 0 {
+		t.Fatal("no migrations registered")
+	}
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("migrations[%d].Version = %d, want %d (versions must be contiguous)", i, m.Version, i+1)
+		}
+		if m.Up == nil || m.Down == nil {
+			t.Errorf("migration %d missing Up or Down", m.Version)
+		}
+	}
+}
+
+func TestMigrate_FreshDatabase(t *testing.T) {
+	db := openTestDB(t)
+
+	applied, err := Migrate(db)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(applied) != len(All()) {
+		t.Errorf("Migrate() applied %d migrations, want %d", len(applied), len(All()))
+	}
+
+	version, err := CurrentVersion(db)
+	if err != nil {
+		t.Fatalf("CurrentVersion() error = %v", err)
+	}
+	if version != LatestVersion() {
+		t.Errorf("CurrentVersion() = %d, want %d", version, LatestVersion())
+	}
+
+	// 迁移后的表结构必须覆盖仓储模型的所有字段
+	for _, model := range []interface{}{
+		&repository.DetectionRecord{},
+		&repository.Document{},
+		&repository.DocumentRevision{},
+		&repository.AuditLog{},
+		&repository.Job{},
+	} {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatalf("failed to parse model: %v", err)
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			if !db.Migrator().HasColumn(model, field.DBName) {
+				t.Errorf("column %s.%s missing after migration", stmt.Schema.Table, field.DBName)
+			}
+		}
+	}
+
+	// 再次执行不应有任何迁移
+	applied, err = Migrate(db)
+	if err != nil {
+		t.Fatalf("second Migrate() error = %v", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("second Migrate() applied %d migrations, want 0", len(applied))
+	}
+}
+
+func TestRollback(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := Migrate(db); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	rolledBack, err := Rollback(db, 1)
+	if err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if len(rolledBack) != 1 || rolledBack[0].Version != LatestVersion() {
+		t.Fatalf("Rollback() = %v, want latest migration", rolledBack)
+	}
+	if version, _ := CurrentVersion(db); version != LatestVersion()-1 {
+		t.Errorf("CurrentVersion() after rollback = %d, want %d", version, LatestVersion()-1)
+	}
+
+	// 回滚到版本 2 后内容哈希列被删除
+	if _, err := Rollback(db, LatestVersion()-3); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if db.Migrator().HasColumn(&repository.DetectionRecord{}, "content_hash") {
+		t.Error("content_hash column still exists after rollback")
+	}
+
+	if _, err := Rollback(db, len(All())); err != nil {
+		t.Fatalf("Rollback(all) error = %v", err)
+	}
+	if db.Migrator().HasTable(&repository.DetectionRecord{}) {
+		t.Error("detection_records table still exists after full rollback")
+	}
+	if version, _ := CurrentVersion(db); version != 0 {
+		t.Errorf("CurrentVersion() after full rollback = %d, want 0", version)
+	}
+
+	// 回滚后可以重新迁移
+	if _, err := Migrate(db); err != nil {
+		t.Fatalf("Migrate() after rollback error = %v", err)
+	}
+}
+
+func TestMigrate_LegacyDatabase(t *testing.T) {
+	db := openTestDB(t)
+
+	// 模拟早期版本通过 AutoMigrate 创建、没有迁移记录的数据库
+	if err := db.Migrator().CreateTable(&detectionRecordV1{}); err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	legacy := detectionRecordV1{
+		ID:        "legacy-1",
+		RequestID: "req-legacy-1",
+		Text:      "旧版本保存的文本\r\n",
+		Score:     60,
+		RiskLevel: "medium",
+		CreatedAt: time.Now(),
+	}
+	if err := db.Create(&legacy).Error; err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	resubmitted := legacy
+	resubmitted.ID = "legacy-2"
+	resubmitted.RequestID = "req-legacy-2"
+	resubmitted.Text = "旧版本保存的文本"
+	resubmitted.CreatedAt = legacy.CreatedAt.Add(time.Minute)
+	if err := db.Create(&resubmitted).Error; err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if _, err := Migrate(db); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	var record repository.DetectionRecord
+	if err := db.First(&record, "id = ?", "legacy-1").Error; err != nil {
+		t.Fatalf("First() error = %v", err)
+	}
+	if record.ContentHash != repository.ContentHash("旧版本保存的文本") {
+		t.Errorf("ContentHash = %q, want backfilled hash", record.ContentHash)
+	}
+	if record.VocabularyDiversityScore != nil {
+		t.Errorf("VocabularyDiversityScore = %v, want nil for legacy record", *record.VocabularyDiversityScore)
+	}
+	if record.DuplicateOf != "" || record.SubmitCount != 1 {
+		t.Errorf("DuplicateOf/SubmitCount = %q/%d, want \"\"/1 for original record", record.DuplicateOf, record.SubmitCount)
+	}
+
+	var duplicate repository.DetectionRecord
+	if err := db.First(&duplicate, "id = ?", "legacy-2").Error; err != nil {
+		t.Fatalf("First() error = %v", err)
+	}
+	if duplicate.DuplicateOf != "legacy-1" {
+		t.Errorf("DuplicateOf = %q, want backfilled link to legacy-1", duplicate.DuplicateOf)
+	}
+}
+
+func TestMigrate_SchemaTooNew(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := Migrate(db); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	future := SchemaMigration{Version: LatestVersion() + 1, Name: "from_the_future", AppliedAt: time.Now()}
+	if err := db.Create(&future).Error; err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if err := CheckVersion(db); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("CheckVersion() error = %v, want ErrSchemaTooNew", err)
+	}
+	if _, err := Migrate(db); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("Migrate() error = %v, want ErrSchemaTooNew", err)
+	}
+	if _, err := Rollback(db, 1); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("Rollback() error = %v, want ErrSchemaTooNew", err)
+	}
+
+	statuses, err := Status(db)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	last := statuses[len(statuses)-1]
+	if !last.Unknown || last.Version != future.Version {
+		t.Errorf("Status() last = %+v, want unknown future migration", last)
+	}
+}
+
+// 迁移中的辅助函数是冻结的副本，写入的值不随业务代码变化
+func TestMigrate_FrozenHelpers(t *testing.T) {
+	const helloHash = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	for _, text := range []string{"hello", "\r\nhello\r\n", "  hello\n"} {
+		if got := contentHashV3(text); got != helloHash {
+			t.Errorf("contentHashV3(%q) = %s, want %s", text, got, helloHash)
+		}
+	}
+
+	if got := joinListV5([]string{"em_dash", "high_freq_words"}); got != ",em_dash,high_freq_words," {
+		t.Errorf("joinListV5() = %q", got)
+	}
+	if got := joinListV5(nil); got != "" {
+		t.Errorf("joinListV5(nil) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,77 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// separatorRe mbox 分隔行："From " 后跟信封发件人和 asctime 格式的时间，如 "From alice@example.com Mon Jan  2 15:04:05 2024"
+var separatorRe = regexp.MustCompile(`^From \S+\s+(?:Mon|Tue|Wed|Thu|Fri|Sat|Sun)\s`)
+
+// escapedFromRe mboxrd 格式中为避免与分隔行混淆而加了 ">" 的正文行
+var escapedFromRe = regexp.MustCompile(`^>+From `)
+
+// IsMbox 根据文件开头判断是否为 mbox 邮箱文件（以分隔行开头）
+func IsMbox(head []byte) bool {
+	return separatorRe.Match(bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\ufeff")), "\r\n"))
+}
+
+// ReadMbox 逐封读取 mbox 邮箱文件，对每封邮件的原文调用 fn；fn 返回错误时停止读取并返回该错误。
+// 分隔行为文件开头或空行之后的 "From <发件人> <时间>" 行，正文中的 ">From " 按 mboxrd 格式还原，内存占用与文件大小无关
+func ReadMbox(r io.Reader, fn func(raw []byte) error) error {
+	br := bufio.NewReaderSize(r, 64*1024)
+	var (
+		msg       bytes.Buffer
+		started   bool
+		prevBlank = true
+		lineNo    int
+	)
+
+	emit := func() error {
+		if !started {
+			return nil
+		}
+		// 去掉分隔行前由 mbox 格式添加的空行
+		raw := msg.Bytes()
+		raw = bytes.TrimSuffix(raw, []byte("\n"))
+		raw = bytes.TrimSuffix(raw, []byte("\r"))
+		err := fn(raw)
+		msg.Reset()
+		return err
+	}
+
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			lineNo++
+			if lineNo == 1 {
+				line = bytes.TrimPrefix(line, []byte("\ufeff"))
+			}
+			switch {
+			case prevBlank && separatorRe.Match(line):
+				if err := emit(); err != nil {
+					return err
+				}
+				started = true
+			case !started && len(bytes.TrimSpace(line)) == 0:
+			case !started:
+				return fmt.Errorf("mbox line %d: expected a \"From \" separator line", lineNo)
+			case escapedFromRe.Match(line):
+				msg.Write(line[1:])
+			default:
+				msg.Write(line)
+			}
+			prevBlank = len(bytes.TrimRight(line, "\r\n")) == 0
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read mbox: %w", err)
+		}
+	}
+	return emit()
+}
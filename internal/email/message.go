@@ -0,0 +1,246 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/htmlindex"
+
+	"github.com/leoobai/aigc-check/internal/extract"
+)
+
+// ErrNoBody 邮件中没有可检测的正文（只有附件，或去除引用和签名后为空）
+var ErrNoBody = errors.New("message has no body text")
+
+// Message 一封邮件：元数据和去除引用、签名后的正文
+type Message struct {
+	ID          string `json:"message_id"`          // Message-ID，去掉尖括号，邮件中没有时为空
+	From        string `json:"from"`                // 发件人地址
+	FromName    string `json:"from_name,omitempty"` // 发件人显示名
+	Date        string `json:"date,omitempty"`      // 发送时间（RFC 3339），无法解析时原样保留
+	Subject     string `json:"subject,omitempty"`
+	ContentType string `json:"content_type"` // 正文所取的部分：text/plain 或 text/html
+	Body        string `json:"-"`            // 去除引用的历史邮件和签名后的正文
+}
+
+// wordDecoder 解码 RFC 2047 编码的邮件头，支持 GBK、Big5、ISO-8859-1 等字符集
+var wordDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+// Parse 解析一封 RFC 5322 邮件：解码邮件头，从 MIME 结构中取正文（优先 text/plain，否则把 text/html 转为纯文本），
+// 并去除引用的历史邮件和签名。没有可检测的正文时返回 ErrNoBody 和已解析的元数据
+func Parse(data []byte) (*Message, error) {
+	msg, err := mail.ReadMessage(bufio.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message: %w", err)
+	}
+
+	m := &Message{
+		ID:      strings.Trim(strings.TrimSpace(msg.Header.Get("Message-Id")), "<>"),
+		Subject: decodeHeader(msg.Header.Get("Subject")),
+	}
+	from := msg.Header.Get("From")
+	if addr, err := (&mail.AddressParser{WordDecoder: wordDecoder}).Parse(from); err == nil {
+		m.From, m.FromName = addr.Address, addr.Name
+	} else {
+		m.From = decodeHeader(from)
+	}
+	if date := msg.Header.Get("Date"); date != "" {
+		if t, err := mail.ParseDate(date); err == nil {
+			m.Date = t.Format(time.RFC3339)
+		} else {
+			m.Date = strings.TrimSpace(date)
+		}
+	}
+
+	body, contentType, err := readPart(textproto.MIMEHeader(msg.Header), msg.Body)
+	if err != nil {
+		return m, err
+	}
+	m.ContentType = contentType
+	m.Body = StripSignature(StripQuoted(body))
+	if strings.TrimSpace(m.Body) == "" {
+		return m, ErrNoBody
+	}
+	return m, nil
+}
+
+// Document 把正文转换为提取文档，检测结果据此标注段落位置
+func (m *Message) Document() (*extract.Document, error) {
+	return extract.ExtractAs(extract.FormatText, []byte(m.Body), extract.Options{})
+}
+
+// decodeHeader 解码 RFC 2047 编码的邮件头，解码失败时原样返回
+func decodeHeader(value string) string {
+	decoded, err := wordDecoder.DecodeHeader(value)
+	if err != nil {
+		return strings.TrimSpace(value)
+	}
+	return strings.TrimSpace(decoded)
+}
+
+// readPart 从一个 MIME 部分中取正文，返回正文和所取部分的类型；部分中没有正文（如附件）时返回空字符串
+func readPart(header textproto.MIMEHeader, body io.Reader) (string, string, error) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		// 缺失或无法解析的 Content-Type 按 RFC 2045 视为 text/plain
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	if disposition, _, _ := mime.ParseMediaType(header.Get("Content-Disposition")); disposition == "attachment" {
+		return "", "", nil
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		return readMultipart(mediaType, params["boundary"], body)
+	case mediaType != "text/plain" && mediaType != "text/html":
+		return "", "", nil
+	}
+
+	data, err := io.ReadAll(decodeTransfer(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decode %s part: %w", mediaType, err)
+	}
+	text, err := decodeCharset(data, params["charset"])
+	if err != nil {
+		return "", "", err
+	}
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n")
+
+	if mediaType == "text/html" {
+		text, err = htmlText(text)
+		if err != nil {
+			return "", "", err
+		}
+		return text, mediaType, nil
+	}
+	if strings.EqualFold(params["format"], "flowed") {
+		text = unflow(text, strings.EqualFold(params["delsp"], "yes"))
+	}
+	return text, mediaType, nil
+}
+
+// readMultipart 从 multipart 部分中取正文：multipart/alternative 优先取 text/plain，
+// 其他类型（mixed、related、signed 等）取第一个有正文的部分
+func readMultipart(mediaType, boundary string, body io.Reader) (string, string, error) {
+	if boundary == "" {
+		return "", "", fmt.Errorf("%s part has no boundary", mediaType)
+	}
+	r := multipart.NewReader(body, boundary)
+	var html string
+	for {
+		part, err := r.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read %s part: %w", mediaType, err)
+		}
+		text, contentType, err := readPart(part.Header, part)
+		if err != nil {
+			return "", "", err
+		}
+		switch {
+		case strings.TrimSpace(text) == "":
+		case contentType == "text/html" && mediaType == "multipart/alternative":
+			if html == "" {
+				html = text
+			}
+		default:
+			return text, contentType, nil
+		}
+	}
+	if html != "" {
+		return html, "text/html", nil
+	}
+	return "", "", nil
+}
+
+// decodeTransfer 按 Content-Transfer-Encoding 解码部分内容
+func decodeTransfer(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		// base64 解码器忽略换行，其他空白需要先去掉
+		return base64.NewDecoder(base64.StdEncoding, &spaceStripper{r: r})
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	default:
+		return r
+	}
+}
+
+// spaceStripper 去掉空格和制表符
+type spaceStripper struct {
+	r io.Reader
+}
+
+func (s *spaceStripper) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	kept := 0
+	for _, c := range p[:n] {
+		if c != ' ' && c != '\t' {
+			p[kept] = c
+			kept++
+		}
+	}
+	return kept, err
+}
+
+// decodeCharset 按 charset 参数把正文解码为 UTF-8，未声明字符集且不是合法 UTF-8 时替换无效字节
+func decodeCharset(data []byte, charset string) (string, error) {
+	charset = strings.ToLower(strings.TrimSpace(charset))
+	switch charset {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return strings.ToValidUTF8(string(data), "\ufffd"), nil
+	}
+	r, err := charsetReader(charset, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s text: %w", charset, err)
+	}
+	return string(decoded), nil
+}
+
+// charsetReader 返回把指定字符集转换为 UTF-8 的读取器
+func charsetReader(charset string, r io.Reader) (io.Reader, error) {
+	// gb2312 邮件中常出现 GBK 扩展字符，统一按 GB18030 解码
+	if name := strings.ToLower(charset); name == "gb2312" || name == "gbk" {
+		charset = "gb18030"
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported charset %q", charset)
+	}
+	return enc.NewDecoder().Reader(r), nil
+}
+
+// unflow 合并 format=flowed（RFC 3676）正文中以空格结尾的软换行，签名分隔线 "-- " 除外
+func unflow(text string, delsp bool) string {
+	lines := strings.Split(text, "\n")
+	var sb strings.Builder
+	for i, line := range lines {
+		line = strings.TrimPrefix(line, " ")
+		soft := i < len(lines)-1 && strings.HasSuffix(line, " ") && line != "-- "
+		if soft && delsp {
+			line = line[:len(line)-1]
+		}
+		sb.WriteString(line)
+		if !soft && i < len(lines)-1 {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
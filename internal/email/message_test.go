@@ -0,0 +1,160 @@
+package email
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		want     Message
+		wantBody string
+		wantErr  error
+	}{
+		{
+			name: "plain text with quoted reply and signature",
+			raw: "Message-ID: <abc@example.com>\r\n" +
+				"From: Support Team <help@example.com>\r\n" +
+				"Date: Tue, 2 Jan 2024 15:04:05 +0800\r\n" +
+				"Subject: Re: Refund\r\n" +
+				"\r\n" +
+				"Hi Alice,\r\n\r\nI hope this helps! Let me know if you have any other questions.\r\n\r\n" +
+				"Best regards,\r\nBob\r\nSupport Team\r\n\r\n" +
+				"On Mon, Jan 1, 2024 at 10:00 AM Alice <alice@example.com> wrote:\r\n> Where is my refund?\r\n",
+			want: Message{
+				ID:          "abc@example.com",
+				From:        "help@example.com",
+				FromName:    "Support Team",
+				Date:        "2024-01-02T15:04:05+08:00",
+				Subject:     "Re: Refund",
+				ContentType: "text/plain",
+			},
+			wantBody: "Hi Alice,\n\nI hope this helps! Let me know if you have any other questions.",
+		},
+		{
+			name: "encoded headers and quoted-printable gbk body",
+			raw: "Message-ID: <cn@example.com>\n" +
+				"From: =?UTF-8?B?5byg5LiJ?= <zhang@example.com>\n" +
+				"Subject: =?gb2312?B?u9i4tA==?=\n" +
+				"Content-Type: text/plain; charset=gb2312\n" +
+				"Content-Transfer-Encoding: quoted-printable\n" +
+				"\n" +
+				"=C4=FA=BA=C3=A3=A1\n",
+			want: Message{
+				ID:          "cn@example.com",
+				From:        "zhang@example.com",
+				FromName:    "张三",
+				Subject:     "回复",
+				ContentType: "text/plain",
+			},
+			wantBody: "您好！",
+		},
+		{
+			name: "alternative prefers plain text",
+			raw: "From: a@example.com\n" +
+				"Content-Type: multipart/alternative; boundary=\"b1\"\n" +
+				"\n" +
+				"--b1\nContent-Type: text/html\n\n<p>html body</p>\n" +
+				"--b1\nContent-Type: text/plain; charset=utf-8\nContent-Transfer-Encoding: base64\n\n" +
+				"cGxhaW4gYm9k\neQ==\n" +
+				"--b1--\n",
+			want:     Message{From: "a@example.com", ContentType: "text/plain"},
+			wantBody: "plain body",
+		},
+		{
+			name: "html only with gmail quote",
+			raw: "From: a@example.com\n" +
+				"Content-Type: multipart/mixed; boundary=m\n" +
+				"\n" +
+				"--m\nContent-Type: text/html; charset=utf-8\n\n" +
+				"<div dir=\"ltr\"><p>Thanks for reaching out.</p><p>We have fixed it.</p>" +
+				"<div class=\"gmail_quote\"><blockquote>old message</blockquote></div></div>\n" +
+				"--m\nContent-Type: text/plain\nContent-Disposition: attachment; filename=log.txt\n\nattached log\n" +
+				"--m--\n",
+			want:     Message{From: "a@example.com", ContentType: "text/html"},
+			wantBody: "Thanks for reaching out.\n\nWe have fixed it.",
+		},
+		{
+			name: "format flowed",
+			raw: "From: a@example.com\n" +
+				"Content-Type: text/plain; format=flowed\n" +
+				"\n" +
+				"This line is \nsoft wrapped.\n",
+			want:     Message{From: "a@example.com", ContentType: "text/plain"},
+			wantBody: "This line is soft wrapped.",
+		},
+		{
+			name: "attachment only",
+			raw: "From: a@example.com\n" +
+				"Content-Type: multipart/mixed; boundary=m\n" +
+				"\n" +
+				"--m\nContent-Type: application/pdf\nContent-Transfer-Encoding: base64\n\nJVBERi0=\n--m--\n",
+			want:    Message{From: "a@example.com"},
+			wantErr: ErrNoBody,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse([]byte(tt.raw))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			body := m.Body
+			m.Body = ""
+			if *m != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", *m, tt.want)
+			}
+			if body != tt.wantBody {
+				t.Errorf("Body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestReadMbox(t *testing.T) {
+	mbox := "From alice@example.com Mon Jan  1 10:00:00 2024\n" +
+		"Message-ID: <1@example.com>\n" +
+		"From: alice@example.com\n" +
+		"\n" +
+		"First message.\n" +
+		">From the start, it was escaped.\n" +
+		"\n" +
+		"From bob@example.com Tue Jan  2 10:00:00 2024\n" +
+		"Message-ID: <2@example.com>\n" +
+		"From: bob@example.com\n" +
+		"\n" +
+		"From here on, this is body text.\n"
+
+	if !IsMbox([]byte(mbox)) {
+		t.Error("IsMbox() = false, want true")
+	}
+	var messages []*Message
+	err := ReadMbox(strings.NewReader(mbox), func(raw []byte) error {
+		m, err := Parse(raw)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, m)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadMbox() error = %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("ReadMbox() read %d messages, want 2", len(messages))
+	}
+	if messages[0].ID != "1@example.com" || messages[0].Body != "First message.\nFrom the start, it was escaped." {
+		t.Errorf("first message = %+v", messages[0])
+	}
+	if messages[1].ID != "2@example.com" || messages[1].Body != "From here on, this is body text." {
+		t.Errorf("second message = %+v", messages[1])
+	}
+
+	if err := ReadMbox(strings.NewReader("Subject: hi\n\nbody\n"), func([]byte) error { return nil }); err == nil {
+		t.Error("ReadMbox() should reject a file without a separator line")
+	}
+}
@@ -0,0 +1,166 @@
+package email
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/leoobai/aigc-check/internal/extract"
+)
+
+var (
+	// htmlQuoteRe HTML 邮件中引用的历史邮件或签名的起始元素（Gmail、Outlook、Thunderbird、Apple Mail、Yahoo），其后的内容均被去除
+	htmlQuoteRe = regexp.MustCompile(`(?i)<(?:div|blockquote|hr|span)\b[^>]*?(?:` +
+		`class\s*=\s*["']?[^"'>]*\b(?:gmail_quote|gmail_signature|moz-cite-prefix|moz-signature|yahoo_quoted|OutlookMessageHeader)\b|` +
+		`id\s*=\s*["']?(?:divRplyFwdMsg|appendonsend|stopSpelling|Signature|x_divRplyFwdMsg)\b|` +
+		`type\s*=\s*["']?cite\b)`)
+
+	// replySeparatorRe 引用历史邮件或转发邮件的分隔行
+	replySeparatorRe = regexp.MustCompile(`(?i)^\s*(?:-{2,}\s*(?:original message|forwarded message|原始邮件|转发邮件|邮件原文)\s*-{2,}|begin forwarded message:)\s*$`)
+
+	// attributionRe 引用历史邮件前的引述行，如 "On Mon, Jan 2, 2024, Alice wrote:"、"在 2024年1月2日，张三 写道："
+	attributionRe = regexp.MustCompile(`(?i)^\s*(?:.*\b(?:wrote|schrieb|escribió)|.*\ba écrit\s?|.*写道)\s*[:：]\s*$`)
+
+	// headerFromRe Outlook 等客户端引用历史邮件时插入的邮件头块的首行
+	headerFromRe = regexp.MustCompile(`(?i)^\s*\*?(?:from|发件人|寄件者)\s*\*?\s*[:：]`)
+	// headerFieldRe 引用邮件头块中的其他字段
+	headerFieldRe = regexp.MustCompile(`(?i)^\s*\*?(?:sent|date|to|cc|subject|发送时间|日期|时间|收件人|抄送|主题)\s*\*?\s*[:：]`)
+	// underscoreRe Outlook 在引用邮件头块前插入的横线
+	underscoreRe = regexp.MustCompile(`^\s*_{10,}\s*$`)
+
+	// mobileFooterRe 移动客户端自动添加的落款
+	mobileFooterRe = regexp.MustCompile(`(?i)^\s*(?:sent from my\s.+|sent from (?:outlook|mail) for\s.+|get outlook for\s.+|发自我的.+|来自我的.+)$`)
+
+	// valedictionRe 结尾的致意语，其后通常是署名和联系方式
+	valedictionRe = regexp.MustCompile(`(?i)^\s*(?:(?:best|kind|warm|warmest)?\s*regards|best wishes|all the best|best|cheers|sincerely|yours(?: truly| sincerely)?|(?:many )?thanks(?: again)?|thank you|此致|敬礼|祝好|顺祝商祺|顺颂商祺|谢谢|感谢|祝工作顺利)\s*[,.!，。！]?\s*$`)
+)
+
+const (
+	// maxSignatureLines 致意语之后的署名最多行数，超过时不视为签名
+	maxSignatureLines = 6
+	// maxSignatureLineRunes 署名中每行的最大字符数
+	maxSignatureLineRunes = 60
+	// signatureWindow 在最后若干个非空行中查找致意语
+	signatureWindow = 8
+)
+
+// htmlText 把 HTML 正文转换为纯文本，引用的历史邮件和签名元素之后的内容被去除
+func htmlText(html string) (string, error) {
+	if loc := htmlQuoteRe.FindStringIndex(html); loc != nil {
+		html = html[:loc[0]]
+	}
+	doc, err := extract.ExtractAs(extract.FormatHTML, []byte(html), extract.Options{})
+	if errors.Is(err, extract.ErrNoText) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return doc.Text, nil
+}
+
+// StripQuoted 去除回复中引用的历史邮件：从引述行（"On ... wrote:"、"在 ... 写道："）、"Original Message" 分隔行
+// 或 Outlook 邮件头块开始截断，并去除其余以 ">" 开头的引用行
+func StripQuoted(text string) string {
+	lines := strings.Split(text, "\n")
+	if cut := quoteStart(lines); cut >= 0 {
+		lines = lines[:cut]
+	}
+
+	kept := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(strings.TrimLeft(line, " \t"), ">") {
+			kept = append(kept, line)
+		}
+	}
+	return trimLines(kept)
+}
+
+// quoteStart 返回引用的历史邮件开始的行，没有时返回 -1
+func quoteStart(lines []string) int {
+	for i, line := range lines {
+		switch {
+		case replySeparatorRe.MatchString(line), attributionRe.MatchString(line):
+			return i
+		case i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(line), "On ") &&
+			attributionRe.MatchString(line+" "+lines[i+1]):
+			// 客户端折行的引述行
+			return i
+		case headerFromRe.MatchString(line) && isHeaderBlock(lines[i+1:]):
+			if j := prevNonBlank(lines, i); j >= 0 && underscoreRe.MatchString(lines[j]) {
+				return j
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// isHeaderBlock 判断发件人行之后的几行是否为引用邮件头块的其他字段
+func isHeaderBlock(lines []string) bool {
+	for _, line := range lines[:min(len(lines), 4)] {
+		if headerFieldRe.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// prevNonBlank 返回第 i 行之前最近的非空行，没有时返回 -1
+func prevNonBlank(lines []string, i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if strings.TrimSpace(lines[j]) != "" {
+			return j
+		}
+	}
+	return -1
+}
+
+// StripSignature 去除签名：签名分隔线 "-- " 之后的内容、移动客户端的落款，
+// 以及结尾的致意语（"Best regards,"、"此致"）连同其后不超过 6 行的署名
+func StripSignature(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.TrimRight(line, " \t") == "--" {
+			lines = lines[:i]
+			break
+		}
+	}
+
+	var tail []int // 最后若干个非空行
+	for i := len(lines) - 1; i >= 0 && len(tail) < signatureWindow; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			tail = append(tail, i)
+		}
+	}
+	for k := len(tail) - 1; k >= 0; k-- {
+		i := tail[k]
+		if mobileFooterRe.MatchString(lines[i]) || valedictionRe.MatchString(lines[i]) && isSignature(tail[:k], lines) {
+			lines = lines[:i]
+			break
+		}
+	}
+	return trimLines(lines)
+}
+
+// isSignature 判断致意语之后的行（tail 中的行号）是否像署名：行数和每行长度都不超过上限
+func isSignature(tail []int, lines []string) bool {
+	if len(tail) > maxSignatureLines {
+		return false
+	}
+	for _, i := range tail {
+		if utf8.RuneCountInString(strings.TrimSpace(lines[i])) > maxSignatureLineRunes {
+			return false
+		}
+	}
+	return true
+}
+
+// trimLines 连接各行并去除首尾空白行和行尾空白
+func trimLines(lines []string) string {
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
@@ -0,0 +1,92 @@
+package email
+
+import "testing"
+
+func TestStripQuoted(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "wrapped attribution",
+			text: "Sure, done.\n\nOn Mon, Jan 1, 2024 at 10:00 AM Alice Smith <\nalice@example.com> wrote:\n> old",
+			want: "Sure, done.",
+		},
+		{
+			name: "chinese attribution",
+			text: "已处理。\n\n在 2024年1月2日 10:00，张三 <zhang@example.com> 写道：\n原来的问题",
+			want: "已处理。",
+		},
+		{
+			name: "outlook header block",
+			text: "Please see below.\n\n________________________________\nFrom: Alice\nSent: Monday\nTo: Support\nSubject: Help\n\nold text",
+			want: "Please see below.",
+		},
+		{
+			name: "qq mail separator",
+			text: "好的\n------------------ 原始邮件 ------------------\n发件人: 李四",
+			want: "好的",
+		},
+		{
+			name: "inline quotes are removed, answers kept",
+			text: "> Can you reset it?\nYes, it is reset.\n> And the invoice?\nSent again.",
+			want: "Yes, it is reset.\nSent again.",
+		},
+		{
+			name: "from line without header block is kept",
+			text: "From: the billing team we heard back.\nAll good.",
+			want: "From: the billing team we heard back.\nAll good.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripQuoted(tt.text); got != tt.want {
+				t.Errorf("StripQuoted() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripSignature(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "signature delimiter",
+			text: "Body text.\n\n-- \nBob\n+1 555 0100",
+			want: "Body text.",
+		},
+		{
+			name: "valediction and name",
+			text: "Your order has shipped.\n\nKind regards,\nBob Lee\nCustomer Support | Example Inc.",
+			want: "Your order has shipped.",
+		},
+		{
+			name: "chinese valediction",
+			text: "问题已经解决。\n\n此致\n敬礼\n客服部 王五",
+			want: "问题已经解决。",
+		},
+		{
+			name: "mobile footer",
+			text: "On my way.\n\nSent from my iPhone",
+			want: "On my way.",
+		},
+		{
+			name: "thanks followed by long paragraph is not a signature",
+			text: "Thanks!\nWe looked into the logs and found that the export job failed because the storage quota was exceeded last night.",
+			want: "Thanks!\nWe looked into the logs and found that the export job failed because the storage quota was exceeded last night.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripSignature(tt.text); got != tt.want {
+				t.Errorf("StripSignature() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,606 @@
+package extract
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+func init() {
+	register(FormatGo, codeExtractor{langGo}, ".go")
+	register(FormatPython, codeExtractor{langPython}, ".py", ".pyi")
+	register(FormatJavaScript, codeExtractor{langJavaScript}, ".js", ".jsx", ".mjs", ".cjs")
+	register(FormatTypeScript, codeExtractor{langJavaScript}, ".ts", ".tsx", ".mts", ".cts")
+	register(FormatJava, codeExtractor{langJava}, ".java")
+	register(FormatC, codeExtractor{langC}, ".c", ".h")
+}
+
+// codeFormats 源代码格式
+var codeFormats = map[Format]bool{
+	FormatGo:         true,
+	FormatPython:     true,
+	FormatJavaScript: true,
+	FormatTypeScript: true,
+	FormatJava:       true,
+	FormatC:          true,
+}
+
+// IsSourceCode 格式是否为源代码（只检测注释和文档字符串）
+func IsSourceCode(format Format) bool {
+	return codeFormats[format]
+}
+
+// codeLanguage 源代码语言，决定词法规则
+type codeLanguage int
+
+const (
+	langGo codeLanguage = iota
+	langPython
+	langJavaScript
+	langJava
+	langC
+)
+
+// codeExtractor 源代码注释提取器
+// 按各语言的词法跳过字符串、字符、模板字符串和正则表达式字面量，只提取注释、文档注释和 Python 文档字符串，
+// 去除注释标记、编译指令、注释掉的代码、许可证头和 Javadoc/JSDoc 标签名后作为正文检测；
+// 提取文本中的位置可以通过 SourceOffset 映射回源文件
+type codeExtractor struct {
+	lang codeLanguage
+}
+
+// codeSpan 源文件中 [start, end) 的一段文本
+type codeSpan struct {
+	start, end int
+}
+
+// codeComment 一条注释或文档字符串，连续多行的单行注释合并为一条
+type codeComment struct {
+	lines      []codeSpan // 去除注释标记后的各行内容
+	line       int        // 起始行号
+	endLine    int        // 结束行号
+	doc        bool       // 是否为文档注释或文档字符串
+	standalone bool       // 是否独占一行（而非跟在代码之后）
+	block      bool       // 是否为块注释
+	depth      int        // 注释所在位置的大括号嵌套深度
+}
+
+// Extract 提取源代码中的注释和文档字符串
+func (e codeExtractor) Extract(data []byte, _ Options) (*Document, error) {
+	source, err := decodeText(data)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &codeScanner{src: source, lang: e.lang}
+	for i, c := range source {
+		if c == '\n' {
+			s.lineStarts = append(s.lineStarts, i+1)
+		}
+	}
+	if e.lang == langPython {
+		s.scanPython()
+	} else {
+		s.scanCode(0, false)
+	}
+
+	var b builder
+	for i, comment := range s.comments {
+		if i == 0 && isLicenseHeader(source, comment) {
+			continue
+		}
+		if e.lang == langGo {
+			comment.doc = s.isGoDoc(comment)
+		}
+		kind := KindCodeComment
+		if comment.doc {
+			kind = KindDocstring
+		}
+		for _, para := range commentParagraphs(source, comment.lines) {
+			pieces := make([]mappedText, 0, 2*len(para))
+			for j, line := range para {
+				if j > 0 {
+					// 行之间以空格连接，空格整体对应上一行末尾
+					pieces = append(pieces, mappedText{text: " ", source: para[j-1].end})
+				}
+				pieces = append(pieces, mappedText{text: source[line.start:line.end], source: line.start, sourceLength: line.end - line.start})
+			}
+			b.addMapped(Paragraph{Kind: kind, Line: s.lineOf(para[0].start)}, pieces)
+		}
+	}
+
+	doc := b.document()
+	doc.Source = source
+	return doc, nil
+}
+
+// codeScanner 源代码词法扫描器
+type codeScanner struct {
+	src        string
+	lang       codeLanguage
+	lineStarts []int
+	comments   []codeComment
+	depth      int
+}
+
+// lineOf 返回偏移量所在的行号（从 1 开始）
+func (s *codeScanner) lineOf(offset int) int {
+	return sort.Search(len(s.lineStarts), func(i int) bool { return s.lineStarts[i] > offset }) + 1
+}
+
+// lineStart 返回偏移量所在行的起始偏移量
+func (s *codeScanner) lineStart(offset int) int {
+	if i := s.lineOf(offset) - 2; i >= 0 {
+		return s.lineStarts[i]
+	}
+	return 0
+}
+
+// isStandalone 偏移量之前同一行中是否只有空白
+func (s *codeScanner) isStandalone(offset int) bool {
+	return strings.TrimSpace(s.src[s.lineStart(offset):offset]) == ""
+}
+
+// lineEnd 返回从 offset 开始到行尾（不含换行符）的位置
+func (s *codeScanner) lineEnd(offset int) int {
+	if i := strings.IndexByte(s.src[offset:], '\n'); i >= 0 {
+		return offset + i
+	}
+	return len(s.src)
+}
+
+// jsRegexKeywords 其后的 / 是正则表达式而不是除号的关键字
+var jsRegexKeywords = map[string]bool{
+	"return": true, "typeof": true, "instanceof": true, "in": true, "of": true, "new": true, "delete": true,
+	"void": true, "throw": true, "case": true, "do": true, "else": true, "yield": true, "await": true,
+}
+
+// scanCode 扫描 C 系语言（Go、JavaScript/TypeScript、Java、C）的代码
+// nested 为 true 时扫描模板字符串中的 ${} 表达式，遇到与之匹配的 } 时返回其后的位置
+func (s *codeScanner) scanCode(i int, nested bool) int {
+	src := s.src
+	var (
+		prev     byte // 上一个有意义的代码字符，用于判断 / 是否为正则表达式
+		prevWord string
+		braces   int
+	)
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+			continue
+		case strings.HasPrefix(src[i:], "//"):
+			end := s.lineEnd(i)
+			s.lineComment(i, 2, end)
+			i = end
+			continue
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src)
+			} else {
+				end += i + 2
+			}
+			s.blockComment(i, end)
+			i = min(end+2, len(src))
+			continue
+		case c == '"' && s.lang == langJava && strings.HasPrefix(src[i:], `"""`):
+			i = skipDelimited(src, i+3, `"""`, true)
+		case c == '"' || c == '\'':
+			i = skipQuoted(src, i)
+		case c == '`' && s.lang == langGo:
+			i = skipDelimited(src, i+1, "`", false)
+		case c == '`' && s.lang == langJavaScript:
+			i = s.skipTemplate(i + 1)
+		case c == '/' && s.lang == langJavaScript && (prev == 0 || strings.IndexByte("(,=:[!&|?{};+-*%<>~^", prev) >= 0 || jsRegexKeywords[prevWord]):
+			i = skipRegex(src, i+1)
+		case isIdentByte(c):
+			j := i
+			for j < len(src) && isIdentByte(src[j]) {
+				j++
+			}
+			prev, prevWord = 'a', src[i:j]
+			i = j
+			continue
+		case c == '{':
+			braces++
+			s.depth++
+			i++
+		case c == '}':
+			if nested && braces == 0 {
+				return i + 1
+			}
+			braces--
+			s.depth--
+			i++
+		default:
+			i++
+		}
+		prev, prevWord = c, ""
+	}
+	return i
+}
+
+// skipTemplate 跳过 JavaScript 模板字符串（从开头的 ` 之后开始），返回结束的 ` 之后的位置
+func (s *codeScanner) skipTemplate(i int) int {
+	src := s.src
+	for i < len(src) {
+		switch {
+		case src[i] == '\\':
+			i += 2
+		case src[i] == '`':
+			return i + 1
+		case strings.HasPrefix(src[i:], "${"):
+			i = s.scanCode(i+2, true)
+		default:
+			i++
+		}
+	}
+	return len(src)
+}
+
+// lineComment 记录从 start 开始、标记长度为 marker 的单行注释，紧邻的独占一行的单行注释合并为一条
+func (s *codeScanner) lineComment(start, marker, end int) {
+	doc := false
+	content := start + marker
+	if s.lang != langPython && strings.HasPrefix(s.src[content:], "/") {
+		// /// 为文档注释（Doxygen、TypeScript 三斜线指令由指令过滤处理），更多的斜线为装饰线
+		doc = !strings.HasPrefix(s.src[content:], "//")
+		for content < end && s.src[content] == '/' {
+			content++
+		}
+	}
+	line := s.lineOf(start)
+	standalone := s.isStandalone(start)
+
+	if n := len(s.comments); n > 0 && standalone {
+		last := &s.comments[n-1]
+		if last.standalone && !last.block && last.endLine == line-1 && last.doc == doc {
+			last.lines = append(last.lines, codeSpan{content, end})
+			last.endLine = line
+			return
+		}
+	}
+	s.comments = append(s.comments, codeComment{
+		lines:      []codeSpan{{content, end}},
+		line:       line,
+		endLine:    line,
+		doc:        doc,
+		standalone: standalone,
+		depth:      s.depth,
+	})
+}
+
+// blockComment 记录从 start 开始、内容在 end 处结束的块注释，每行行首的 * 被去除
+func (s *codeScanner) blockComment(start, end int) {
+	content := start + 2
+	doc := strings.HasPrefix(s.src[content:end], "*")
+	for content < end && s.src[content] == '*' {
+		content++
+	}
+	for end > content && s.src[end-1] == '*' {
+		end--
+	}
+
+	var lines []codeSpan
+	for _, line := range splitSpan(s.src, codeSpan{content, end}) {
+		if text := strings.TrimLeft(s.src[line.start:line.end], " \t"); strings.HasPrefix(text, "*") {
+			line.start = line.end - len(text) + 1
+		}
+		lines = append(lines, line)
+	}
+	s.comments = append(s.comments, codeComment{
+		lines:      lines,
+		line:       s.lineOf(start),
+		endLine:    s.lineOf(end),
+		doc:        doc,
+		standalone: s.isStandalone(start),
+		block:      true,
+		depth:      s.depth,
+	})
+}
+
+// isGoDoc 判断 Go 注释是否为文档注释：独占一行，位于顶层，且紧接在声明之前
+func (s *codeScanner) isGoDoc(comment codeComment) bool {
+	if !comment.standalone || comment.depth != 0 || comment.endLine >= len(s.lineStarts)+1 {
+		return false
+	}
+	next := s.lineStarts[comment.endLine-1]
+	text := strings.TrimSpace(s.src[next:s.lineEnd(next)])
+	return text != "" && !strings.HasPrefix(text, "//") && !strings.HasPrefix(text, ")") && !strings.HasPrefix(text, "}")
+}
+
+// scanPython 扫描 Python 代码：# 注释，以及位于语句开头的字符串字面量（模块、类、函数和属性的文档字符串）
+func (s *codeScanner) scanPython() {
+	src := s.src
+	depth := 0
+	statement := true // 当前位置是否为一条语句的开头
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			if depth == 0 {
+				statement = true
+			}
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			i++
+		case c == '\\' && i+1 < len(src) && (src[i+1] == '\n' || src[i+1] == '\r'):
+			// 续行
+			i += 2
+		case c == '#':
+			end := s.lineEnd(i)
+			if i != 0 || !strings.HasPrefix(src, "#!") {
+				s.lineComment(i, 1, end)
+			}
+			i = end
+		case c == '"' || c == '\'' || isIdentByte(c):
+			j := i
+			for j < len(src) && isIdentByte(src[j]) {
+				j++
+			}
+			if j < len(src) && (src[j] == '"' || src[j] == '\'') && j-i <= 2 && strings.Trim(strings.ToLower(src[i:j]), "rbuf") == "" {
+				start, end, next := skipPythonString(src, j)
+				if statement && depth == 0 && endsPythonStatement(src, next) {
+					s.docstring(start, end)
+				}
+				i = next
+			} else {
+				i = j
+			}
+			statement = false
+		case c == '(' || c == '[' || c == '{':
+			depth++
+			statement = false
+			i++
+		case c == ')' || c == ']' || c == '}':
+			depth = max(depth-1, 0)
+			i++
+		case c == ';':
+			statement = true
+			i++
+		default:
+			statement = false
+			i++
+		}
+	}
+}
+
+// docstring 记录 Python 文档字符串，内容为原文件中的 [start, end)
+func (s *codeScanner) docstring(start, end int) {
+	s.comments = append(s.comments, codeComment{
+		lines:      splitSpan(s.src, codeSpan{start, end}),
+		line:       s.lineOf(start),
+		endLine:    s.lineOf(end),
+		doc:        true,
+		standalone: true,
+		block:      true,
+	})
+}
+
+// skipPythonString 跳过从引号开始的 Python 字符串，返回内容范围和字符串之后的位置
+func skipPythonString(src string, i int) (int, int, int) {
+	quote := src[i : i+1]
+	if strings.HasPrefix(src[i:], strings.Repeat(quote, 3)) {
+		delim := strings.Repeat(quote, 3)
+		end := skipDelimited(src, i+3, delim, true)
+		if strings.HasSuffix(src[:end], delim) {
+			return i + 3, end - 3, end
+		}
+		return i + 3, end, end
+	}
+	end := skipQuoted(src, i)
+	if end > i+1 && src[end-1] == src[i] {
+		return i + 1, end - 1, end
+	}
+	return i + 1, end, end
+}
+
+// endsPythonStatement 位置之后同一行中是否只有空白、注释或分号
+func endsPythonStatement(src string, i int) bool {
+	for ; i < len(src); i++ {
+		switch src[i] {
+		case ' ', '\t', '\r':
+		case '\n', '#', ';':
+			return true
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// skipQuoted 跳过从引号开始的单行字符串或字符字面量，返回结束引号之后的位置，未闭合时到行尾为止
+func skipQuoted(src string, i int) int {
+	quote := src[i]
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case quote:
+			return j + 1
+		case '\n':
+			return j
+		}
+	}
+	return len(src)
+}
+
+// skipDelimited 跳过到结束符 delim 为止的内容，返回结束符之后的位置
+func skipDelimited(src string, i int, delim string, escapes bool) int {
+	for j := i; j < len(src); j++ {
+		if escapes && src[j] == '\\' {
+			j++
+			continue
+		}
+		if strings.HasPrefix(src[j:], delim) {
+			return j + len(delim)
+		}
+	}
+	return len(src)
+}
+
+// skipRegex 跳过 JavaScript 正则表达式字面量（从开头的 / 之后开始）及其标志
+func skipRegex(src string, i int) int {
+	class := false
+	for ; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '[':
+			class = true
+		case ']':
+			class = false
+		case '\n':
+			return i
+		case '/':
+			if !class {
+				i++
+				for i < len(src) && isIdentByte(src[i]) {
+					i++
+				}
+				return i
+			}
+		}
+	}
+	return i
+}
+
+// isIdentByte 是否为标识符中的字符，非 ASCII 字符按标识符处理
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// splitSpan 将一段文本按行拆分，每行去除首尾空白
+func splitSpan(src string, span codeSpan) []codeSpan {
+	var lines []codeSpan
+	for start := span.start; start <= span.end; {
+		end := strings.IndexByte(src[start:span.end], '\n')
+		if end < 0 {
+			end = span.end
+		} else {
+			end += start
+		}
+		lines = append(lines, trimSpan(src, codeSpan{start, end}))
+		start = end + 1
+	}
+	return lines
+}
+
+// trimSpan 去除一段文本首尾的空白
+func trimSpan(src string, span codeSpan) codeSpan {
+	text := src[span.start:span.end]
+	trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+	span.start += len(text) - len(trimmed)
+	span.end = span.start + len(strings.TrimRightFunc(trimmed, unicode.IsSpace))
+	return span
+}
+
+var (
+	// codeDirective 编译器、代码检查工具和编辑器的指令注释
+	codeDirective = regexp.MustCompile(`^(go:|line \S+:\d|export \w|extern \w|nolint|\+build|@ts-|eslint|jshint|jscs:|prettier-ignore|istanbul ignore|c8 ignore|type:|noqa|pylint:|pyright:|pragma|mypy:|fmt: (on|off|skip)|isort:|-\*-|vim?:|NOLINT|clang-format|<reference|#?(end)?region\b|Code generated .*DO NOT EDIT)`)
+	// commentedCode 被注释掉的代码：import 语句、预处理指令、单独的函数调用和赋值
+	commentedCode = regexp.MustCompile(`^(import|package|#include|#define|#if|#endif)\s+\S+$|^[\w.$]+\([^()]*\)[;,]?$|^[\w.$\[\]"']+\s*(:=|=|\+=|-=)\s*\S+$`)
+	// codeTagWithName 带参数名的 Javadoc/JSDoc/Doxygen 标签，保留其后的说明
+	codeTagWithName = regexp.MustCompile(`^[@\\](param|arg|argument|prop|property|tparam|template|throws|exception|raise|raises)\s+(\{[^}]*\}\s*)?(\[[^\]]*\]|[\w.$]+)?\s*(-\s+)?`)
+	// codeTagTyped 带类型的 Javadoc/JSDoc 标签，保留其后的说明
+	codeTagTyped = regexp.MustCompile(`^[@\\](returns?|yields?|throws|exception|brief|details|deprecated|description|desc|summary|remarks|note)\b\s*(\{[^}]*\}\s*)?(-\s+)?`)
+	// codeTag 其他标签（作者、版本、参见、示例等），整行不检测
+	codeTag = regexp.MustCompile(`^[@\\]\w+`)
+	// pyField reStructuredText 字段，保留说明
+	pyField = regexp.MustCompile(`^:(param|parameter|arg|argument|key|keyword|raises?|except|exception|returns?|yields?)(\s+[^:]*)?:\s*`)
+	// pyTypeField reStructuredText 类型字段，整行不检测
+	pyTypeField = regexp.MustCompile(`^:(type|rtype|vartype|ytype|ivar|cvar|var|meta)\b[^:]*:`)
+	// pySection 文档字符串中 Google、NumPy 风格的小节标题
+	pySection = regexp.MustCompile(`^(Args|Arguments|Parameters|Params|Keyword Args|Returns?|Yields?|Raises|Examples?|Attributes|Notes?|See Also|References|Todo|Warnings?|Methods)\s*:?$|^[-=~]{3,}$`)
+)
+
+// commentParagraphs 将注释的各行整理为段落：空行、装饰线、被过滤的行处分段，标签从新段落开始
+func commentParagraphs(src string, lines []codeSpan) [][]codeSpan {
+	var (
+		paragraphs [][]codeSpan
+		current    []codeSpan
+		fenced     bool // 在 ``` 代码块中
+		example    bool // 在 @example 标签的示例代码中
+	)
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, current)
+			current = nil
+		}
+	}
+	for _, line := range lines {
+		line = trimSpan(src, line)
+		text := src[line.start:line.end]
+
+		if strings.HasPrefix(text, "```") {
+			fenced = !fenced
+			flush()
+			continue
+		}
+		if strings.HasPrefix(text, "@") || strings.HasPrefix(text, "\\") {
+			example = strings.HasPrefix(text, "@example")
+		}
+		if fenced || example || text == "" || isDecoration(text) || codeDirective.MatchString(text) ||
+			isCommentedCode(text) || strings.HasPrefix(text, ">>>") || strings.HasPrefix(text, "...") ||
+			pySection.MatchString(text) || pyTypeField.MatchString(text) {
+			flush()
+			continue
+		}
+
+		cut := 0
+		for _, re := range []*regexp.Regexp{codeTagWithName, codeTagTyped, pyField} {
+			if loc := re.FindStringIndex(text); loc != nil {
+				cut = loc[1]
+				break
+			}
+		}
+		if cut == 0 && codeTag.MatchString(text) {
+			flush()
+			continue
+		}
+		if cut > 0 || strings.HasPrefix(text, ":") {
+			flush()
+			line.start += cut
+			if line.start == line.end {
+				continue
+			}
+		}
+		current = append(current, line)
+	}
+	flush()
+	return paragraphs
+}
+
+// isCommentedCode 判断注释中的一行是否为被注释掉的代码
+func isCommentedCode(text string) bool {
+	switch {
+	case strings.HasSuffix(text, "{"), strings.HasPrefix(text, "}"), strings.HasPrefix(text, ")"), strings.HasPrefix(text, "]"):
+		return true
+	case strings.HasSuffix(text, ";") && strings.ContainsAny(text, "(="):
+		return true
+	}
+	return commentedCode.MatchString(text)
+}
+
+// isDecoration 是否为由符号组成的装饰线，如 -----、=====、*****
+func isDecoration(text string) bool {
+	return strings.Trim(text, "-=*_#/~+ \t") == ""
+}
+
+// isLicenseHeader 判断文件开头的注释是否为版权或许可证声明
+func isLicenseHeader(src string, comment codeComment) bool {
+	var text strings.Builder
+	for _, line := range comment.lines {
+		text.WriteString(src[line.start:line.end])
+		text.WriteByte(' ')
+	}
+	for _, marker := range []string{"Copyright", "SPDX-License-Identifier", "Licensed under", "All rights reserved", "版权所有"} {
+		if strings.Contains(text.String(), marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+func TestCode_Languages(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		source   string
+		want     []string
+		kinds    []string
+	}{
+		{
+			name:     "go",
+			filename: "server.go",
+			source: "// Copyright 2024 The Authors. All rights reserved.\n\n" +
+				"// Package server handles requests.\n//\n// It is safe for concurrent use.\npackage server\n\n" +
+				"//go:generate stringer -type=Mode\n" +
+				"var s = \"// not a comment\" + `/* raw */`\n\n" +
+				"func f() {\n\t// fmt.Println(s)\n\tx := 1 // keep it small\n}\n",
+			want:  []string{"Package server handles requests.", "It is safe for concurrent use.", "keep it small"},
+			kinds: []string{KindDocstring, KindDocstring, KindCodeComment},
+		},
+		{
+			name:     "python",
+			filename: "util.py",
+			source: "#!/usr/bin/env python3\n\"\"\"Helpers for\nparsing.\"\"\"\n\nPATTERN = r'''not a docstring'''\n\n" +
+				"def f(a):\n    '''Parse a value.\n\n    :param a: the raw value.\n    :type a: str\n\n    >>> f(1)\n    '''\n" +
+				"    return \"# not a comment\"  # type: ignore\n\n# I'm not sure this is right.\n",
+			want:  []string{"Helpers for parsing.", "Parse a value.", "the raw value.", "I'm not sure this is right."},
+			kinds: []string{KindDocstring, KindDocstring, KindDocstring, KindCodeComment},
+		},
+		{
+			name:     "typescript",
+			filename: "add.ts",
+			source: "/**\n * Adds two numbers.\n * @param {number} a - The first number.\n * @returns The sum.\n * @example\n * add(1, 2)\n */\n" +
+				"export function add(a: number, b: number) {\n  const re = /\\/\\*x*\\//g;\n" +
+				"  const t = `${a /* inner */} // no`;\n  return a / b; // divide\n}\n",
+			want:  []string{"Adds two numbers.", "The first number.", "The sum.", "inner", "divide"},
+			kinds: []string{KindDocstring, KindDocstring, KindDocstring, KindCodeComment, KindCodeComment},
+		},
+		{
+			name:     "java",
+			filename: "App.java",
+			source: "/** Entry point.\n * @author someone\n */\npublic class App {\n" +
+				"  String s = \"\"\"\n    /* text block */\n    \"\"\";\n  char c = '\"'; /* quote char */\n}\n",
+			want:  []string{"Entry point.", "quote char"},
+			kinds: []string{KindDocstring, KindCodeComment},
+		},
+		{
+			name:     "c",
+			filename: "main.c",
+			source: "#include <stdio.h>\n/*\n * Prints a greeting.\n * -----------------\n * Feel free to\n * change it.\n */\n" +
+				"int main(void) {\n  /* printf(\"hi\"); */\n  puts(\"/* no */\"); // done\n}\n",
+			want:  []string{"Prints a greeting.", "Feel free to change it.", "done"},
+			kinds: []string{KindCodeComment, KindCodeComment, KindCodeComment},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Extract(tt.filename, []byte(tt.source), Options{})
+			if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			if !IsSourceCode(doc.Format) {
+				t.Errorf("Format = %q, want a source code format", doc.Format)
+			}
+			got := paragraphTexts(doc)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Fatalf("paragraphs = %q, want %q", got, tt.want)
+			}
+			for i, kind := range tt.kinds {
+				if doc.Paragraphs[i].Kind != kind {
+					t.Errorf("Paragraphs[%d].Kind = %q, want %q", i, doc.Paragraphs[i].Kind, kind)
+				}
+			}
+			assertSourceMapping(t, doc)
+		})
+	}
+}
+
+func TestCode_MapResult(t *testing.T) {
+	source := "package main\n\n// run starts the server.\n// Feel free to adjust as needed.\nfunc run() {}\n"
+	doc, err := Extract("cmd/main.go", []byte(source), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	offset := strings.Index(doc.Text, "Feel free")
+	position := models.Position{Line: 1, Offset: offset, Length: len("Feel free")}
+	if location, _ := doc.Describe(position); location != "cmd/main.go:4" {
+		t.Errorf("Describe() = %q, want cmd/main.go:4", location)
+	}
+
+	result := &models.DetectionResult{
+		Text:        doc.Text,
+		RuleResults: []models.RuleResult{{Matches: []models.Match{{Text: "Feel free", Position: position}}}},
+		// 句子锚点从连接两行的空格开始
+		Suggestions: []models.Suggestion{{Anchors: []models.Anchor{{Position: models.Position{Offset: offset - 1, Length: 10}}}}},
+	}
+	doc.MapResult(result)
+	match := result.RuleResults[0].Matches[0]
+	if match.Location != "cmd/main.go:4" || match.Position.Line != 4 || match.Position.Column != 4 {
+		t.Errorf("match = %q at %d:%d, want cmd/main.go:4 at 4:4", match.Location, match.Position.Line, match.Position.Column)
+	}
+	if got := result.Suggestions[0].Anchors[0].Location; got != "cmd/main.go:4" {
+		t.Errorf("anchor location = %q, want cmd/main.go:4", got)
+	}
+	if result.Text != source {
+		t.Error("result text should be the source file")
+	}
+}
@@ -0,0 +1,373 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	register(FormatDOCX, docxExtractor{}, ".docx")
+}
+
+// wordNamespace WordprocessingML 命名空间
+const wordNamespace = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+
+// maxZipEntrySize 解压单个压缩包条目的大小上限，防止压缩炸弹
+const maxZipEntrySize = 64 << 20
+
+// detectZip 根据压缩包中的条目判断基于 ZIP 的文档格式
+func detectZip(filename string, data []byte) (Format, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s is not a valid zip archive", ErrUnsupportedFormat, filename)
+	}
+	for _, f := range archive.File {
+		if f.Name == "word/document.xml" {
+			return FormatDOCX, nil
+		}
+	}
+
+	// ODF 和 EPUB 文档的首个条目 mimetype 标明具体类型，缺失时以扩展名为准
+	mimetype, err := readZipEntry(archive, "mimetype")
+	if err != nil {
+		return "", fmt.Errorf("%w: %s is not a valid zip archive", ErrUnsupportedFormat, filename)
+	}
+	switch {
+	case strings.TrimSpace(string(mimetype)) == odtMimetype:
+		return FormatODT, nil
+	case strings.TrimSpace(string(mimetype)) == epubMimetype:
+		return FormatEPUB, nil
+	case mimetype == nil && strings.EqualFold(filepath.Ext(filename), ".odt"):
+		for _, f := range archive.File {
+			if f.Name == "content.xml" {
+				return FormatODT, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, strings.ToLower(filepath.Ext(filename)))
+}
+
+// readZipEntry 读取压缩包中的条目，不存在时返回 nil
+func readZipEntry(archive *zip.Reader, name string) ([]byte, error) {
+	for _, f := range archive.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+
+		data, err := io.ReadAll(io.LimitReader(rc, maxZipEntrySize+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > maxZipEntrySize {
+			return nil, fmt.Errorf("%s is too large", name)
+		}
+		return data, nil
+	}
+	return nil, nil
+}
+
+// docxExtractor DOCX 提取器：按段落提取正文，识别标题样式、列表和表格，可选提取脚注、尾注和批注
+type docxExtractor struct{}
+
+// docxParts 正文之外可选提取的部件
+var docxParts = []struct {
+	name string
+	kind string
+}{
+	{"word/footnotes.xml", KindFootnote},
+	{"word/endnotes.xml", KindFootnote},
+	{"word/comments.xml", KindComment},
+}
+
+// Extract 提取 DOCX 正文
+func (docxExtractor) Extract(data []byte, opts Options) (*Document, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := readZipEntry(archive, "word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, errors.New("word/document.xml not found")
+	}
+
+	styles, err := readZipEntry(archive, "word/styles.xml")
+	if err != nil {
+		return nil, err
+	}
+	styleNames, err := parseDocxStyles(styles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse styles: %w", err)
+	}
+
+	parser := &docxParser{styles: styleNames, b: &builder{}, notes: map[string]int{}}
+	if err := parser.parse(body, ""); err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+	for _, part := range docxParts {
+		if (part.kind == KindFootnote && !opts.Footnotes) || (part.kind == KindComment && !opts.Comments) {
+			continue
+		}
+		data, err := readZipEntry(archive, part.name)
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			continue
+		}
+		if err := parser.parse(data, part.kind); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", part.name, err)
+		}
+	}
+
+	doc := parser.b.document()
+	doc.Revisions = parser.revisions
+	return doc, nil
+}
+
+// parseDocxStyles 解析样式 ID 到样式名称的映射
+// 本地化的 Word 中样式 ID 可能是数字（如中文版的标题 1 为 "1"），名称始终为英文内置名（如 "heading 1"）
+func parseDocxStyles(data []byte) (map[string]string, error) {
+	names := make(map[string]string)
+	if data == nil {
+		return names, nil
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var current string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if start, ok := token.(xml.StartElement); ok && start.Name.Space == wordNamespace {
+			switch start.Name.Local {
+			case "style":
+				current = wordAttr(start, "styleId")
+			case "name":
+				if current != "" {
+					names[current] = wordAttr(start, "val")
+				}
+			}
+		}
+	}
+}
+
+// docxParagraph 正在解析的段落
+type docxParagraph struct {
+	text    strings.Builder
+	style   string
+	list    bool
+	outline int // w:outlineLvl + 1，0 表示未设置
+}
+
+// docxParser 逐个部件解析段落，记录修订
+type docxParser struct {
+	styles    map[string]string
+	b         *builder
+	revisions []Revision
+	notes     map[string]int // 各类注释已分配的编号
+}
+
+// parse 解析一个部件中的段落。kind 为空时解析正文，否则解析脚注（尾注）或批注，
+// 每条注释的段落使用相同的编号，批注段落记录作者
+func (d *docxParser) parse(data []byte, kind string) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var (
+		paragraph *docxParagraph
+		tables    int         // 所在表格的嵌套层数
+		inText    bool        // 位于 w:t 或 w:delText 中
+		deleted   int         // 位于修订删除内容中
+		changes   []*Revision // 所在的修订，内层在后
+		pending   []Revision  // 当前段落中已结束的修订
+		note      int         // 当前注释的编号，0 表示不在注释中或为分隔符
+		author    string      // 当前批注的作者
+	)
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Space != wordNamespace {
+				continue
+			}
+			switch t.Name.Local {
+			case "footnote", "endnote", "comment":
+				// 脚注分隔符等特殊注释没有正文
+				if kind != "" && (t.Name.Local == "comment" || docxNoteNormal(t)) {
+					d.notes[kind]++
+					note = d.notes[kind]
+					author = wordAttr(t, "author")
+				}
+			case "tbl":
+				tables++
+			case "p":
+				paragraph = &docxParagraph{}
+			case "pStyle":
+				if paragraph != nil {
+					paragraph.style = wordAttr(t, "val")
+				}
+			case "numPr":
+				if paragraph != nil {
+					paragraph.list = true
+				}
+			case "outlineLvl":
+				if paragraph != nil {
+					if level, err := strconv.Atoi(wordAttr(t, "val")); err == nil && level < 9 {
+						paragraph.outline = level + 1
+					}
+				}
+			case "ins", "moveTo":
+				changes = append(changes, &Revision{Type: RevisionInsert, Author: wordAttr(t, "author"), Date: wordAttr(t, "date")})
+			case "del", "moveFrom":
+				deleted++
+				changes = append(changes, &Revision{Type: RevisionDelete, Author: wordAttr(t, "author"), Date: wordAttr(t, "date")})
+			case "t":
+				inText = deleted == 0
+			case "delText":
+				inText = deleted > 0
+			case "tab":
+				if paragraph != nil && deleted == 0 {
+					paragraph.text.WriteString("\t")
+				}
+			case "br", "cr":
+				if paragraph != nil && deleted == 0 {
+					paragraph.text.WriteString("\n")
+				}
+			}
+
+		case xml.CharData:
+			if !inText || paragraph == nil {
+				continue
+			}
+			if deleted == 0 {
+				paragraph.text.Write(t)
+			}
+			if n := len(changes); n > 0 {
+				changes[n-1].Text += string(t)
+			}
+
+		case xml.EndElement:
+			if t.Name.Space != wordNamespace {
+				continue
+			}
+			switch t.Name.Local {
+			case "footnote", "endnote", "comment":
+				note, author = 0, ""
+			case "tbl":
+				tables--
+			case "ins", "moveTo", "del", "moveFrom":
+				if n := len(changes); n > 0 {
+					if changes[n-1].Text != "" {
+						pending = append(pending, *changes[n-1])
+					}
+					changes = changes[:n-1]
+				}
+				if t.Name.Local == "del" || t.Name.Local == "moveFrom" {
+					deleted--
+				}
+			case "t", "delText":
+				inText = false
+			case "p":
+				if paragraph == nil {
+					continue
+				}
+				index := -1
+				if kind == "" || note > 0 {
+					info := docxParagraphInfo(paragraph, d.styles, tables > 0)
+					if kind != "" {
+						info.Kind, info.Level, info.Number, info.Author = kind, 0, note, author
+					}
+					before := len(d.b.paragraphs)
+					d.b.add(info, paragraph.text.String())
+					if len(d.b.paragraphs) > before {
+						index = before
+					}
+				}
+				for _, r := range pending {
+					r.Paragraph = index
+					d.revisions = append(d.revisions, r)
+				}
+				paragraph, pending = nil, nil
+			}
+		}
+	}
+}
+
+// docxNoteNormal 判断脚注或尾注是否为普通注释（而非分隔符等特殊注释）
+func docxNoteNormal(start xml.StartElement) bool {
+	noteType := wordAttr(start, "type")
+	return noteType == "" || noteType == "normal"
+}
+
+// docxParagraphInfo 根据样式、大纲级别和编号判断段落类型
+func docxParagraphInfo(p *docxParagraph, styleNames map[string]string, inTable bool) Paragraph {
+	name := styleNames[p.style]
+	if name == "" {
+		name = p.style
+	}
+	return styleParagraphInfo(name, p.outline, p.list, inTable)
+}
+
+// styleParagraphInfo 根据样式名、大纲级别（0 表示未设置）、是否带编号和是否位于表格中判断段落类型，
+// 样式名按 Word 和 LibreOffice 的内置英文名识别
+func styleParagraphInfo(name string, outline int, list, inTable bool) Paragraph {
+	info := Paragraph{Kind: KindParagraph, Style: name}
+
+	lower := strings.ToLower(strings.ReplaceAll(name, " ", ""))
+	switch {
+	case lower == "title":
+		// 文档标题不参与章节编号
+		info.Kind = KindHeading
+	case strings.HasPrefix(lower, "heading"):
+		if level, err := strconv.Atoi(strings.TrimPrefix(lower, "heading")); err == nil {
+			info.Kind, info.Level = KindHeading, level
+		}
+	case outline > 0:
+		info.Kind, info.Level = KindHeading, outline
+	case inTable:
+		info.Kind = KindTableCell
+	case list || strings.HasPrefix(lower, "list"):
+		info.Kind = KindListItem
+	case strings.Contains(lower, "quot"): // Quote、Intense Quote、Quotations
+		info.Kind = KindQuote
+	}
+	return info
+}
+
+// wordAttr 读取 WordprocessingML 命名空间下的属性
+func wordAttr(start xml.StartElement, local string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == local && (attr.Name.Space == wordNamespace || attr.Name.Space == "") {
+			return attr.Value
+		}
+	}
+	return ""
+}
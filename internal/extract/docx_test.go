@@ -0,0 +1,193 @@
+package extract
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// wantParagraph 期望的段落结构
+type wantParagraph struct {
+	text     string
+	kind     string
+	section  string
+	number   int
+	location string
+}
+
+// assertParagraphs 检查段落文本、类型、章节编号和位置描述
+func assertParagraphs(t *testing.T, doc *Document, want []wantParagraph) {
+	t.Helper()
+	got := paragraphTexts(doc)
+	if len(got) != len(want) {
+		t.Fatalf("paragraphs = %q, want %d", got, len(want))
+	}
+	for i, w := range want {
+		p := doc.Paragraphs[i]
+		if got[i] != w.text || p.Kind != w.kind || p.Section != w.section || p.Number != w.number || p.Location() != w.location {
+			t.Errorf("Paragraphs[%d] = %q %s §%q #%d %q, want %q %s §%q #%d %q",
+				i, got[i], p.Kind, p.Section, p.Number, p.Location(), w.text, w.kind, w.section, w.number, w.location)
+		}
+	}
+}
+
+// docxReport 包含标题、脚注、批注、修订和表格的中文报告
+func docxReport(t *testing.T) []byte {
+	t.Helper()
+	styles := `<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="1"><w:name w:val="heading 1"/></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="2"><w:name w:val="heading 2"/></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="a"><w:name w:val="footnote text"/></w:style>`
+	heading := func(style, text string) string {
+		return `<w:p><w:pPr><w:pStyle w:val="` + style + `"/></w:pPr><w:r><w:t>` + text + `</w:t></w:r></w:p>`
+	}
+	cell := func(text string) string {
+		return `<w:tc><w:p><w:r><w:t>` + text + `</w:t></w:r></w:p></w:tc>`
+	}
+	body := heading("Title", "年度报告") +
+		heading("1", "引言") +
+		`<w:p><w:commentRangeStart w:id="0"/><w:r><w:t>本报告总结了今年的工作。</w:t></w:r><w:commentRangeEnd w:id="0"/>` +
+		`<w:r><w:footnoteReference w:id="1"/></w:r><w:r><w:commentReference w:id="0"/></w:r></w:p>` +
+		heading("1", "方法") +
+		heading("2", "数据来源") +
+		`<w:p><w:r><w:t>我们收集了</w:t></w:r>` +
+		`<w:ins w:id="1" w:author="张三" w:date="2024-05-01T10:00:00Z"><w:r><w:t>三类</w:t></w:r></w:ins>` +
+		`<w:r><w:t>数据。</w:t></w:r>` +
+		`<w:del w:id="2" w:author="李四" w:date="2024-05-02T09:00:00Z"><w:r><w:delText>（草稿）</w:delText></w:r></w:del></w:p>` +
+		`<w:tbl><w:tr>` + cell("指标") + cell("数值") + `</w:tr><w:tr>` + cell("准确率") + cell("95%") + `</w:tr></w:tbl>` +
+		`<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/></w:numPr></w:pPr><w:r><w:t>It is crucial to delve.</w:t></w:r></w:p>` +
+		`<w:p><w:pPr><w:rPr><w:del w:id="3" w:author="李四"/></w:rPr></w:pPr>` +
+		`<w:del w:id="4" w:author="李四"><w:r><w:delText>整段删除</w:delText></w:r></w:del></w:p>`
+
+	footnotes := `<w:footnotes>` +
+		`<w:footnote w:type="separator" w:id="-1"><w:p><w:r><w:separator/></w:r></w:p></w:footnote>` +
+		`<w:footnote w:type="continuationSeparator" w:id="0"><w:p><w:r><w:t>续</w:t></w:r></w:p></w:footnote>` +
+		`<w:footnote w:id="1"><w:p><w:pPr><w:pStyle w:val="a"/></w:pPr><w:r><w:footnoteRef/></w:r>` +
+		`<w:r><w:t xml:space="preserve"> 数据来自内部统计。</w:t></w:r></w:p></w:footnote></w:footnotes>`
+	comments := `<w:comments><w:comment w:id="0" w:author="王五" w:date="2024-05-03T08:00:00Z" w:initials="WW">` +
+		`<w:p><w:r><w:t>请补充来源</w:t></w:r></w:p></w:comment></w:comments>`
+
+	return buildDOCXParts(t, body, styles, map[string]string{
+		"word/footnotes.xml": footnotes,
+		"word/comments.xml":  comments,
+	})
+}
+
+func TestDOCX_Structure(t *testing.T) {
+	doc, err := Extract("report.docx", docxReport(t), Options{Footnotes: true, Comments: true})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	assertParagraphs(t, doc, []wantParagraph{
+		{"年度报告", KindHeading, "", 0, "标题"},
+		{"引言", KindHeading, "1", 0, "第 1 节标题"},
+		{"本报告总结了今年的工作。", KindParagraph, "1", 1, "第 1 节第 1 段"},
+		{"方法", KindHeading, "2", 0, "第 2 节标题"},
+		{"数据来源", KindHeading, "2.1", 0, "第 2.1 节标题"},
+		{"我们收集了三类数据。", KindParagraph, "2.1", 1, "第 2.1 节第 1 段"},
+		{"指标", KindTableCell, "2.1", 2, "第 2.1 节第 2 段"},
+		{"数值", KindTableCell, "2.1", 3, "第 2.1 节第 3 段"},
+		{"准确率", KindTableCell, "2.1", 4, "第 2.1 节第 4 段"},
+		{"95%", KindTableCell, "2.1", 5, "第 2.1 节第 5 段"},
+		{"It is crucial to delve.", KindListItem, "2.1", 6, "第 2.1 节第 6 段"},
+		{"数据来自内部统计。", KindFootnote, "", 1, "脚注 1"},
+		{"请补充来源", KindComment, "", 1, "批注 1（王五）"},
+	})
+	if got := doc.Paragraphs[11].Style; got != "footnote text" {
+		t.Errorf("footnote style = %q, want footnote text", got)
+	}
+
+	wantRevisions := []Revision{
+		{Type: RevisionInsert, Author: "张三", Date: "2024-05-01T10:00:00Z", Text: "三类", Paragraph: 5},
+		{Type: RevisionDelete, Author: "李四", Date: "2024-05-02T09:00:00Z", Text: "（草稿）", Paragraph: 5},
+		{Type: RevisionDelete, Author: "李四", Text: "整段删除", Paragraph: -1},
+	}
+	if !reflect.DeepEqual(doc.Revisions, wantRevisions) {
+		t.Errorf("Revisions = %+v, want %+v", doc.Revisions, wantRevisions)
+	}
+	wantAuthors := []models.RevisionAuthor{
+		{Author: "张三", Insertions: 1, InsertedChars: 2},
+		{Author: "李四", Deletions: 2, DeletedChars: 8},
+	}
+	if got := doc.RevisionAuthors(); !reflect.DeepEqual(got, wantAuthors) {
+		t.Errorf("RevisionAuthors() = %+v, want %+v", got, wantAuthors)
+	}
+}
+
+func TestDOCX_Options(t *testing.T) {
+	data := docxReport(t)
+	tests := []struct {
+		name  string
+		opts  Options
+		kinds map[string]bool
+	}{
+		{"body only", Options{}, map[string]bool{}},
+		{"footnotes", Options{Footnotes: true}, map[string]bool{KindFootnote: true}},
+		{"comments", Options{Comments: true}, map[string]bool{KindComment: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Extract("report.docx", data, tt.opts)
+			if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			for _, kind := range []string{KindFootnote, KindComment} {
+				found := false
+				for _, p := range doc.Paragraphs {
+					found = found || p.Kind == kind
+				}
+				if found != tt.kinds[kind] {
+					t.Errorf("%s extracted = %v, want %v", kind, found, tt.kinds[kind])
+				}
+			}
+			if strings.Contains(doc.Text, "续") {
+				t.Errorf("Text contains continuation separator: %q", doc.Text)
+			}
+			// 修订记录与是否提取注释无关
+			if len(doc.Revisions) != 3 {
+				t.Errorf("Revisions = %d, want 3", len(doc.Revisions))
+			}
+		})
+	}
+}
+
+func TestDOCX_MapResult(t *testing.T) {
+	doc, err := Extract("report.docx", docxReport(t), Options{Footnotes: true})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	position := func(substr string) models.Position {
+		return models.Position{Line: 1, Offset: strings.Index(doc.Text, substr), Length: len(substr)}
+	}
+	result := &models.DetectionResult{
+		Text: doc.Text,
+		RuleResults: []models.RuleResult{{Matches: []models.Match{
+			{Text: "crucial", Position: position("crucial")},
+			{Text: "内部统计", Position: position("内部统计")},
+		}}},
+		Suggestions: []models.Suggestion{{Anchors: []models.Anchor{{Text: "三类", Position: position("三类")}}}},
+	}
+	doc.MapResult(result)
+
+	if result.Text != doc.Text {
+		t.Errorf("Text changed for a format without source mapping")
+	}
+	matches := result.RuleResults[0].Matches
+	if matches[0].Location != "第 2.1 节第 6 段" || matches[1].Location != "脚注 1" {
+		t.Errorf("match locations = %q, %q", matches[0].Location, matches[1].Location)
+	}
+	if matches[0].Position != position("crucial") {
+		t.Errorf("match position changed: %+v", matches[0].Position)
+	}
+	if got := result.Suggestions[0].Anchors[0].Location; got != "第 2.1 节第 1 段" {
+		t.Errorf("anchor location = %q", got)
+	}
+	if len(result.RevisionAuthors) != 2 {
+		t.Errorf("RevisionAuthors = %+v, want 2 authors", result.RevisionAuthors)
+	}
+}
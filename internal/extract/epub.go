@@ -0,0 +1,135 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+func init() {
+	register(FormatEPUB, epubExtractor{}, ".epub")
+}
+
+// epubMimetype EPUB 的 MIME 类型
+const epubMimetype = "application/epub+zip"
+
+// epubExtractor EPUB 提取器：按书脊（spine）顺序提取各章节 XHTML 文件的正文，每个章节文件作为一个部分，
+// 段落位置描述为 "章节文件 第 M 段"；书脊中标记为 linear="no" 的辅助内容（如注释页）和没有正文的文件（如封面）被跳过
+type epubExtractor struct{}
+
+// epubContainer META-INF/container.xml，指向包文件（OPF）
+type epubContainer struct {
+	Rootfiles []struct {
+		FullPath  string `xml:"full-path,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+// epubPackage 包文件中的清单和书脊
+type epubPackage struct {
+	Manifest []struct {
+		ID        string `xml:"id,attr"`
+		Href      string `xml:"href,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"manifest>item"`
+	Spine []struct {
+		IDRef  string `xml:"idref,attr"`
+		Linear string `xml:"linear,attr"`
+	} `xml:"spine>itemref"`
+}
+
+// Extract 按书脊顺序提取 EPUB 各章节的正文
+func (epubExtractor) Extract(data []byte, _ Options) (*Document, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	opfPath, err := epubPackagePath(archive)
+	if err != nil {
+		return nil, err
+	}
+	opf, err := readZipEntry(archive, opfPath)
+	if err != nil {
+		return nil, err
+	}
+	if opf == nil {
+		return nil, fmt.Errorf("package document %s not found", opfPath)
+	}
+	var pkg epubPackage
+	if err := xml.Unmarshal(opf, &pkg); err != nil {
+		return nil, fmt.Errorf("failed to parse package document: %w", err)
+	}
+
+	type item struct{ href, mediaType string }
+	manifest := make(map[string]item, len(pkg.Manifest))
+	for _, it := range pkg.Manifest {
+		manifest[it.ID] = item{it.Href, it.MediaType}
+	}
+
+	b := &builder{}
+	var parts []Part
+	for _, ref := range pkg.Spine {
+		it, ok := manifest[ref.IDRef]
+		if !ok || ref.Linear == "no" || it.mediaType != "application/xhtml+xml" && it.mediaType != "text/html" {
+			continue
+		}
+		name := epubResolve(opfPath, it.href)
+		content, err := readZipEntry(archive, name)
+		if err != nil {
+			return nil, err
+		}
+		if content == nil {
+			return nil, fmt.Errorf("spine item %s not found", name)
+		}
+		doc, err := ExtractAs(FormatHTML, content, Options{})
+		if errors.Is(err, ErrNoText) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if part, ok := b.addPart(Part{Index: len(parts), Name: name, ID: ref.IDRef}, doc); ok {
+			parts = append(parts, part)
+		}
+	}
+
+	doc := b.document()
+	doc.Parts = parts
+	return doc, nil
+}
+
+// epubPackagePath 从 container.xml 中读取包文件的路径
+func epubPackagePath(archive *zip.Reader) (string, error) {
+	data, err := readZipEntry(archive, "META-INF/container.xml")
+	if err != nil {
+		return "", err
+	}
+	if data == nil {
+		return "", errors.New("META-INF/container.xml not found")
+	}
+	var container epubContainer
+	if err := xml.Unmarshal(data, &container); err != nil {
+		return "", fmt.Errorf("failed to parse container.xml: %w", err)
+	}
+	for _, rootfile := range container.Rootfiles {
+		if rootfile.MediaType == "" || rootfile.MediaType == "application/oebps-package+xml" {
+			return rootfile.FullPath, nil
+		}
+	}
+	return "", errors.New("container.xml has no package document")
+}
+
+// epubResolve 将清单中相对于包文件的链接解析为压缩包中的路径
+func epubResolve(opfPath, href string) string {
+	href, _, _ = strings.Cut(href, "#")
+	if unescaped, err := url.PathUnescape(href); err == nil {
+		href = unescaped
+	}
+	return path.Join(path.Dir(opfPath), href)
+}
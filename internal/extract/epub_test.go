@@ -0,0 +1,94 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+)
+
+// buildEPUB 构造 EPUB 文件，chapters 为 OEBPS 目录下的章节文件名和 body 内容，按顺序列入书脊
+func buildEPUB(t *testing.T, chapters [][2]string, extra string) []byte {
+	t.Helper()
+	entries := map[string]string{
+		"mimetype": epubMimetype,
+		"META-INF/container.xml": `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles><rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles>
+</container>`,
+	}
+	var manifest, spine strings.Builder
+	for i, ch := range chapters {
+		id := "item" + string(rune('a'+i))
+		manifest.WriteString(`<item id="` + id + `" href="` + ch[0] + `" media-type="application/xhtml+xml"/>`)
+		spine.WriteString(`<itemref idref="` + id + `"/>`)
+		name, _, _ := strings.Cut(strings.ReplaceAll(ch[0], "%20", " "), "#")
+		entries["OEBPS/"+name] = `<?xml version="1.0" encoding="utf-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml"><head><title>t</title></head><body>` + ch[1] + `</body></html>`
+	}
+	entries["OEBPS/content.opf"] = `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <manifest>` + manifest.String() + `<item id="css" href="style.css" media-type="text/css"/></manifest>
+  <spine>` + spine.String() + extra + `</spine>
+</package>`
+	return buildZip(t, entries)
+}
+
+func TestEPUB_Chapters(t *testing.T) {
+	data := buildEPUB(t, [][2]string{
+		{"cover.xhtml", `<img src="cover.jpg"/>`},
+		{"Text/ch01.xhtml", `<h1>Chapter One</h1><p>It was a cold morning.</p><p>She left early.</p>`},
+		{"Text/chapter%202.xhtml#start", `<h1>Chapter Two</h1><p>In today's fast-paced world, it is worth noting.</p>`},
+	}, `<itemref idref="css"/><itemref idref="missing"/>`)
+
+	format, err := Detect("book.zip", data)
+	if err != nil || format != FormatEPUB {
+		t.Fatalf("Detect() = %q, %v, want epub", format, err)
+	}
+	doc, err := Extract("book.epub", data, Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	want := []Part{
+		{Index: 0, Name: "OEBPS/Text/ch01.xhtml", ID: "itemb", Title: "Chapter One"},
+		{Index: 1, Name: "OEBPS/Text/chapter 2.xhtml", ID: "itemc", Title: "Chapter Two"},
+	}
+	if len(doc.Parts) != len(want) {
+		t.Fatalf("Parts = %+v, want %d parts", doc.Parts, len(want))
+	}
+	for i, w := range want {
+		got := doc.Parts[i]
+		w.Offset, w.Length = got.Offset, got.Length
+		if got != w {
+			t.Errorf("Parts[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+	if got := doc.Location(strings.Index(doc.Text, "She left")); got != "OEBPS/Text/ch01.xhtml 第 2 段" {
+		t.Errorf("Location() = %q", got)
+	}
+	if got := doc.Location(strings.Index(doc.Text, "In today's")); got != "OEBPS/Text/chapter 2.xhtml 第 1 段" {
+		t.Errorf("Location() = %q", got)
+	}
+}
+
+func TestEPUB_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries map[string]string
+		want    string
+	}{
+		{"missing container", map[string]string{"mimetype": epubMimetype}, "container.xml not found"},
+		{"missing package", map[string]string{
+			"mimetype":               epubMimetype,
+			"META-INF/container.xml": `<container><rootfiles><rootfile full-path="content.opf"/></rootfiles></container>`,
+		}, "package document content.opf not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Extract("book.epub", buildZip(t, tt.entries), Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Extract() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,686 @@
+// Package extract 从各种文档格式中提取待检测的纯文本，并记录段落结构
+// 检测结果中的偏移量均指向提取出的文本，通过段落结构映射可以定位到原文档中的位置
+package extract
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// Format 文档格式
+type Format string
+
+const (
+	FormatText     Format = "txt"
+	FormatMarkdown Format = "md"
+	FormatHTML     Format = "html"
+	FormatDOCX     Format = "docx"
+	FormatODT      Format = "odt"
+	FormatPDF      Format = "pdf"
+	FormatLaTeX    Format = "tex"
+	FormatSRT      Format = "srt"
+	FormatVTT      Format = "vtt"
+	FormatIPYNB    Format = "ipynb"
+	FormatEPUB     Format = "epub"
+
+	// 源代码格式只提取注释和文档字符串
+	FormatGo         Format = "go"
+	FormatPython     Format = "py"
+	FormatJavaScript Format = "js"
+	FormatTypeScript Format = "ts"
+	FormatJava       Format = "java"
+	FormatC          Format = "c"
+)
+
+// 段落类型
+const (
+	KindParagraph = "paragraph"
+	KindHeading   = "heading"
+	KindListItem  = "list_item"
+	KindQuote     = "quote"
+	KindTableCell = "table_cell"
+	KindFootnote  = "footnote"
+	KindComment   = "comment"
+
+	KindCodeComment = "code_comment" // 源代码中的普通注释
+	KindDocstring   = "docstring"    // 源代码中的文档注释和文档字符串
+)
+
+// 修订类型
+const (
+	RevisionInsert = "insert"
+	RevisionDelete = "delete"
+)
+
+// ErrUnsupportedFormat 不支持的文档格式
+var ErrUnsupportedFormat = errors.New("unsupported document format")
+
+// ErrNoText 文档中没有可检测的文本
+var ErrNoText = errors.New("no text found in document")
+
+// Document 提取结果
+type Document struct {
+	Format     Format      `json:"format"`
+	Text       string      `json:"text"`                // 提取出的纯文本，段落之间以空行分隔
+	Paragraphs []Paragraph `json:"paragraphs"`          // 段落结构映射，按在 Text 中的顺序排列
+	Revisions  []Revision  `json:"revisions,omitempty"` // 修订记录（DOCX、ODT 的修订模式），按在文档中的顺序排列
+	Cues       []Cue       `json:"cues,omitempty"`      // 字幕的时间轴（SRT、WebVTT），按在 Text 中的顺序排列
+	Parts      []Part      `json:"parts,omitempty"`     // 笔记本的单元格、EPUB 的章节，按在 Text 中的顺序排列
+
+	// Name 原文件名，源代码格式的检测结果据此标注 "文件名:行号"
+	Name string `json:"-"`
+
+	// Source 解码后的原文件文本，只有能逐字节映射回原文件的格式（如 Markdown）才会设置
+	Source   string    `json:"-"`
+	segments []segment // Text 到 Source 的位置映射，按 offset 排列
+}
+
+// segment 一段位置映射：提取文本中 [offset, offset+length) 对应原文件中 [source, source+sourceLength)
+// 两者长度相同时逐字节对应，否则（如 HTML 实体被解码）整段对应
+type segment struct {
+	offset, length       int
+	source, sourceLength int
+}
+
+// mappedText 带原文件位置的文本片段
+type mappedText struct {
+	text                 string
+	source, sourceLength int
+}
+
+// Paragraph 提取文本中的一个段落及其在原文档中的位置
+type Paragraph struct {
+	Index  int    `json:"index"`           // 段落序号，从 0 开始
+	Offset int    `json:"offset"`          // 在提取文本中的字节偏移量
+	Length int    `json:"length"`          // 在提取文本中的字节长度
+	Kind   string `json:"kind"`            // paragraph, heading, list_item, quote, table_cell
+	Level  int    `json:"level,omitempty"` // 标题级别
+	Line   int    `json:"line,omitempty"`  // 在原文件中的起始行号（从 1 开始），无行号概念的格式为 0
+	Page   int    `json:"page,omitempty"`  // 所在页码（从 1 开始），无分页概念的格式为 0
+	Style  string `json:"style,omitempty"` // 原文档中的段落样式名
+
+	Section string `json:"section,omitempty"` // 所在章节编号（如 "3.2"），根据标题级别计算
+	Number  int    `json:"number,omitempty"`  // 在所在章节（分页格式为所在页）中的段落序号，脚注和批注为其编号，均从 1 开始
+	Author  string `json:"author,omitempty"`  // 批注作者
+	Part    string `json:"part,omitempty"`    // 所在单元格或章节的名称，只有 Jupyter 笔记本和 EPUB 才会设置
+
+	// Time 段落在音视频中的时间范围，只有字幕格式才会设置
+	Time *models.TimeRange `json:"time,omitempty"`
+
+	// Source 段落在原文件中的字节范围，只有能逐字节映射回原文件的格式（如 Markdown、HTML）才会设置
+	Source *SourceSpan `json:"source,omitempty"`
+}
+
+// Part 由多个独立部分组成的文档中的一个部分，如 Jupyter 笔记本的单元格、EPUB 书脊中的章节文件
+type Part struct {
+	Index  int    `json:"index"`           // 部分序号，从 0 开始
+	Name   string `json:"name"`            // 名称，如 "单元格 3"、"OEBPS/chapter02.xhtml"
+	ID     string `json:"id,omitempty"`    // 单元格 ID 或 EPUB 清单中的条目 ID
+	Title  string `json:"title,omitempty"` // 部分中的第一个标题
+	Offset int    `json:"offset"`          // 在提取文本中的字节偏移量
+	Length int    `json:"length"`          // 在提取文本中的字节长度
+}
+
+// SourceSpan 原文件中的字节范围
+type SourceSpan struct {
+	Offset int `json:"offset"`
+	Length int `json:"length"`
+}
+
+// Revision 一处修订
+type Revision struct {
+	Type      string `json:"type"`           // insert, delete
+	Author    string `json:"author"`         // 修订作者
+	Date      string `json:"date,omitempty"` // 修订时间，原样保留文档中的值
+	Text      string `json:"text"`           // 插入或删除的文本
+	Paragraph int    `json:"paragraph"`      // 所在段落序号，段落整体被删除时为 -1
+}
+
+// Options 提取选项，零值只提取正文
+type Options struct {
+	Footnotes bool // 提取脚注和尾注（DOCX、ODT、LaTeX）
+	Comments  bool // 提取批注（DOCX、ODT）
+}
+
+// Extractor 文档提取器
+type Extractor interface {
+	Extract(data []byte, opts Options) (*Document, error)
+}
+
+// extractors 已注册的提取器
+var extractors = map[Format]Extractor{}
+
+// extensions 文件扩展名到格式的映射
+var extensions = map[string]Format{}
+
+// register 注册提取器及其文件扩展名
+func register(format Format, extractor Extractor, exts ...string) {
+	extractors[format] = extractor
+	for _, ext := range exts {
+		extensions[ext] = format
+	}
+}
+
+// Formats 返回所有支持的格式，按名称排序
+func Formats() []Format {
+	formats := make([]Format, 0, len(extractors))
+	for format := range extractors {
+		formats = append(formats, format)
+	}
+	sort.Slice(formats, func(i, j int) bool { return formats[i] < formats[j] })
+	return formats
+}
+
+// formatAliases 不是文件扩展名的格式别名
+var formatAliases = map[string]Format{
+	"golang":     FormatGo,
+	"python":     FormatPython,
+	"javascript": FormatJavaScript,
+	"typescript": FormatTypeScript,
+}
+
+// ParseFormat 解析格式名称，接受格式名、文件扩展名（如 markdown、htm）或语言名（如 python）
+func ParseFormat(name string) (Format, error) {
+	name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "."))
+	if _, ok := extractors[Format(name)]; ok {
+		return Format(name), nil
+	}
+	if format, ok := formatAliases[name]; ok {
+		return format, nil
+	}
+	if format, ok := extensions["."+name]; ok {
+		return format, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, name)
+}
+
+// Detect 根据文件内容和扩展名判断文档格式
+// 二进制格式以文件头为准，文本格式以扩展名为准，无法判断时按纯文本处理
+func Detect(filename string, data []byte) (Format, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		return FormatPDF, nil
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return detectZip(filename, data)
+	}
+
+	if format, ok := extensions[strings.ToLower(filepath.Ext(filename))]; ok {
+		if format == FormatPDF || format == FormatDOCX || format == FormatODT || format == FormatEPUB {
+			return "", fmt.Errorf("%w: %s content does not match its extension", ErrUnsupportedFormat, filename)
+		}
+		return format, nil
+	}
+
+	head := strings.ToLower(string(bytes.TrimSpace(data[:min(len(data), 512)])))
+	head = strings.TrimPrefix(head, "\ufeff")
+	if strings.HasPrefix(head, "<!doctype html") || strings.HasPrefix(head, "<html") {
+		return FormatHTML, nil
+	}
+	if strings.HasPrefix(head, "webvtt") {
+		return FormatVTT, nil
+	}
+	if srtHead.MatchString(strings.ReplaceAll(head, "\r\n", "\n")) {
+		return FormatSRT, nil
+	}
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("%w: %s is not a text file", ErrUnsupportedFormat, filename)
+	}
+	return FormatText, nil
+}
+
+// Extract 判断文档格式并提取文本
+func Extract(filename string, data []byte, opts Options) (*Document, error) {
+	format, err := Detect(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := ExtractAs(format, data, opts)
+	if err != nil {
+		return nil, err
+	}
+	doc.Name = filename
+	return doc, nil
+}
+
+// ExtractAs 按指定格式提取文本
+func ExtractAs(format Format, data []byte, opts Options) (*Document, error) {
+	extractor, ok := extractors[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+
+	doc, err := extractor.Extract(data, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract %s: %w", format, err)
+	}
+	if strings.TrimSpace(doc.Text) == "" {
+		return nil, ErrNoText
+	}
+	doc.Format = format
+	numberParagraphs(doc.Paragraphs)
+	return doc, nil
+}
+
+// numberParagraphs 根据标题级别计算各段落的章节编号，以及段落在章节（分页格式为页）中的序号
+// 最高级别的标题只有一个且位于文档开头时视为文档标题，不参与编号
+func numberParagraphs(paragraphs []Paragraph) {
+	top, topCount := 0, 0
+	for _, p := range paragraphs {
+		if p.Kind != KindHeading || p.Level < 1 {
+			continue
+		}
+		switch {
+		case top == 0 || p.Level < top:
+			top, topCount = p.Level, 1
+		case p.Level == top:
+			topCount++
+		}
+	}
+	title := -1
+	if topCount == 1 && len(paragraphs) > 0 && paragraphs[0].Kind == KindHeading && paragraphs[0].Level == top {
+		title = 0
+	}
+
+	var (
+		counters []int
+		section  string
+		number   int
+		page     int
+		part     string
+		notes    = map[string]int{}
+	)
+	for i := range paragraphs {
+		p := &paragraphs[i]
+		switch {
+		case p.Kind == KindFootnote || p.Kind == KindComment:
+			// 编号由提取器按脚注、批注设置，未设置时按出现顺序编号
+			if p.Number == 0 {
+				notes[p.Kind]++
+				p.Number = notes[p.Kind]
+			}
+			continue
+		case p.Page != page || p.Part != part:
+			page, part, number = p.Page, p.Part, 0
+		}
+
+		if p.Kind == KindHeading {
+			if p.Level < 1 || i == title {
+				continue
+			}
+			depth := p.Level - top + 1
+			for len(counters) < depth {
+				counters = append(counters, 0)
+			}
+			counters = counters[:depth]
+			counters[depth-1]++
+			parts := make([]string, depth)
+			for j, n := range counters {
+				parts[j] = strconv.Itoa(n)
+			}
+			section = strings.Join(parts, ".")
+			// 单元格和章节中的段落按所在部分编号，不随标题重新编号
+			if p.Part == "" {
+				number = 0
+			}
+			p.Section = section
+			continue
+		}
+		number++
+		p.Section = section
+		p.Number = number
+	}
+}
+
+// Location 返回段落在原文档中的位置描述，如 "第 3.2 节第 14 段"、"第 7 页第 2 段"、"单元格 5 第 1 段"、"00:03:12–00:03:18"
+func (p *Paragraph) Location() string {
+	switch {
+	case p.Time != nil:
+		return p.Time.String()
+	case p.Kind == KindFootnote:
+		return fmt.Sprintf("脚注 %d", p.Number)
+	case p.Kind == KindComment && p.Author != "":
+		return fmt.Sprintf("批注 %d（%s）", p.Number, p.Author)
+	case p.Kind == KindComment:
+		return fmt.Sprintf("批注 %d", p.Number)
+	case p.Page > 0:
+		return fmt.Sprintf("第 %d 页第 %d 段", p.Page, p.Number)
+	case p.Part != "" && p.Kind == KindHeading:
+		return fmt.Sprintf("%s 标题", p.Part)
+	case p.Part != "":
+		return fmt.Sprintf("%s 第 %d 段", p.Part, p.Number)
+	case p.Kind == KindHeading && p.Section != "":
+		return fmt.Sprintf("第 %s 节标题", p.Section)
+	case p.Kind == KindHeading:
+		return "标题"
+	case p.Section != "":
+		return fmt.Sprintf("第 %s 节第 %d 段", p.Section, p.Number)
+	default:
+		return fmt.Sprintf("第 %d 段", p.Number)
+	}
+}
+
+// RevisionAuthors 按作者汇总修订记录，按作者首次出现的顺序排列
+func (d *Document) RevisionAuthors() []models.RevisionAuthor {
+	var authors []models.RevisionAuthor
+	index := make(map[string]int)
+	for _, r := range d.Revisions {
+		i, ok := index[r.Author]
+		if !ok {
+			i = len(authors)
+			index[r.Author] = i
+			authors = append(authors, models.RevisionAuthor{Author: r.Author})
+		}
+		chars := utf8.RuneCountInString(r.Text)
+		switch r.Type {
+		case RevisionInsert:
+			authors[i].Insertions++
+			authors[i].InsertedChars += chars
+		case RevisionDelete:
+			authors[i].Deletions++
+			authors[i].DeletedChars += chars
+		}
+	}
+	return authors
+}
+
+// Locate 返回包含提取文本中指定偏移量的段落，偏移量位于段落之间的空行时返回 nil
+func (d *Document) Locate(offset int) *Paragraph {
+	i := sort.Search(len(d.Paragraphs), func(i int) bool {
+		return d.Paragraphs[i].Offset+d.Paragraphs[i].Length > offset
+	})
+	if i < len(d.Paragraphs) && d.Paragraphs[i].Offset <= offset {
+		return &d.Paragraphs[i]
+	}
+	return nil
+}
+
+// SourceOffset 将提取文本中的字节偏移量映射为原文件中的字节偏移量
+// 没有位置映射的格式原样返回；偏移量位于段落之间时映射到下一段的起始位置
+func (d *Document) SourceOffset(offset int) int {
+	if d.segments == nil {
+		return offset
+	}
+	i := sort.Search(len(d.segments), func(i int) bool {
+		return d.segments[i].offset+d.segments[i].length > offset
+	})
+	if i == len(d.segments) {
+		return len(d.Source)
+	}
+	seg := d.segments[i]
+	if offset <= seg.offset {
+		return seg.source
+	}
+	if seg.length != seg.sourceLength {
+		return seg.source
+	}
+	return seg.source + offset - seg.offset
+}
+
+// sourceRange 将提取文本中的范围映射为原文件中的范围
+func (d *Document) sourceRange(offset, length int) (int, int) {
+	start := d.SourceOffset(offset)
+	if length <= 0 {
+		return start, 0
+	}
+
+	// 结束位置按最后一个字节所在的映射段计算
+	last := offset + length - 1
+	i := sort.Search(len(d.segments), func(i int) bool {
+		return d.segments[i].offset+d.segments[i].length > last
+	})
+	if i == len(d.segments) || d.segments[i].offset > last {
+		return start, length
+	}
+	seg := d.segments[i]
+	end := seg.source + seg.sourceLength
+	if seg.length == seg.sourceLength {
+		end = seg.source + last - seg.offset + 1
+	}
+	if end < start {
+		end = start
+	}
+	return start, end - start
+}
+
+// MapResult 将针对提取文本的检测结果映射回原文件：规则匹配和建议锚点的位置改为原文件中的位置，
+// 结果文本替换为原文件文本，源代码的位置描述为 "文件名:行号"。没有位置映射的格式保留原位置，
+// 对没有行号的段落（如 DOCX）补充段落位置描述，对字幕补充时间范围和各句的片段得分，并附上修订作者汇总
+func (d *Document) MapResult(result *models.DetectionResult) {
+	if result == nil {
+		return
+	}
+	result.RevisionAuthors = d.RevisionAuthors()
+	result.Segments = d.SegmentScores(result.RuleResults)
+	if d.segments == nil {
+		d.locateResult(result)
+		return
+	}
+
+	for i := range result.RuleResults {
+		for j := range result.RuleResults[i].Matches {
+			match := &result.RuleResults[i].Matches[j]
+			match.Location = d.fileLocation(d.sourceLine(match.Position.Offset))
+			d.mapPosition(&match.Position)
+		}
+	}
+	for i := range result.Suggestions {
+		for j := range result.Suggestions[i].Anchors {
+			anchor := &result.Suggestions[i].Anchors[j]
+			anchor.Location = d.fileLocation(d.sourceLine(anchor.Position.Offset))
+			d.mapPosition(&anchor.Position)
+		}
+	}
+	result.Text = d.Source
+}
+
+// locateResult 为规则匹配和建议锚点设置段落位置描述
+func (d *Document) locateResult(result *models.DetectionResult) {
+	for i := range result.RuleResults {
+		for j := range result.RuleResults[i].Matches {
+			match := &result.RuleResults[i].Matches[j]
+			match.Location, match.Time = d.Describe(match.Position)
+		}
+	}
+	for i := range result.Suggestions {
+		for j := range result.Suggestions[i].Anchors {
+			anchor := &result.Suggestions[i].Anchors[j]
+			anchor.Location, anchor.Time = d.Describe(anchor.Position)
+		}
+	}
+}
+
+// Describe 返回提取文本中指定位置的位置描述和时间范围：字幕返回所在字幕的时间范围（如 "00:03:12–00:03:18"），
+// 源代码返回 "文件名:行号"，其他格式返回 Location 的段落位置描述，时间范围为 nil
+func (d *Document) Describe(pos models.Position) (string, *models.TimeRange) {
+	if span := d.TimeRange(pos.Offset, pos.Length); span != nil {
+		return span.String(), span
+	}
+	if location := d.fileLocation(d.sourceLine(pos.Offset)); location != "" {
+		return location, nil
+	}
+	return d.Location(pos.Offset), nil
+}
+
+// sourceLine 返回提取文本中的偏移量在原文件中的行号，跳过开头的空白（段落内连接各行的空格不对应原文件中的位置）
+func (d *Document) sourceLine(offset int) int {
+	for offset < len(d.Text) && (d.Text[offset] == ' ' || d.Text[offset] == '\n') {
+		offset++
+	}
+	line, _ := lineColumn(d.Source, d.SourceOffset(offset))
+	return line
+}
+
+// fileLocation 返回源代码中指定行的 "文件名:行号" 描述，其他格式或没有文件名时返回空字符串
+func (d *Document) fileLocation(line int) string {
+	if !IsSourceCode(d.Format) || d.Name == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", d.Name, line)
+}
+
+// Location 返回提取文本中指定偏移量所在段落的位置描述，段落有行号（可直接按行定位）或偏移量不在段落中时返回空字符串
+func (d *Document) Location(offset int) string {
+	if p := d.Locate(offset); p != nil && p.Line == 0 {
+		return p.Location()
+	}
+	return ""
+}
+
+// mapPosition 将位置映射到原文件并重新计算行号和列号
+func (d *Document) mapPosition(pos *models.Position) {
+	pos.Offset, pos.Length = d.sourceRange(pos.Offset, pos.Length)
+	pos.Line, pos.Column = lineColumn(d.Source, pos.Offset)
+}
+
+// lineColumn 计算字节偏移量对应的行号和列号（均从 1 开始，列号按字符计）
+func lineColumn(text string, offset int) (int, int) {
+	offset = min(offset, len(text))
+	before := text[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := utf8.RuneCountInString(before[strings.LastIndexByte(before, '\n')+1:]) + 1
+	return line, column
+}
+
+// builder 逐段拼接提取文本并记录段落结构
+type builder struct {
+	text       strings.Builder
+	paragraphs []Paragraph
+	segments   []segment
+}
+
+// add 追加一个段落，首尾空白被去除，空段落被忽略
+func (b *builder) add(p Paragraph, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	if b.text.Len() > 0 {
+		b.text.WriteString("\n\n")
+	}
+	if p.Kind == "" {
+		p.Kind = KindParagraph
+	}
+	p.Index = len(b.paragraphs)
+	p.Offset = b.text.Len()
+	p.Length = len(text)
+	b.text.WriteString(text)
+	b.paragraphs = append(b.paragraphs, p)
+}
+
+// addPart 追加一个部分（单元格、章节）的提取结果：各段落保留类型和级别并标注所在部分，
+// 返回补充了在提取文本中的范围和标题的部分，部分中没有段落时返回 false
+func (b *builder) addPart(part Part, doc *Document) (Part, bool) {
+	first := len(b.paragraphs)
+	for _, p := range doc.Paragraphs {
+		text := doc.Text[p.Offset : p.Offset+p.Length]
+		b.add(Paragraph{Kind: p.Kind, Level: p.Level, Style: p.Style, Part: part.Name}, text)
+		if part.Title == "" && p.Kind == KindHeading {
+			part.Title = strings.TrimSpace(text)
+		}
+	}
+	if len(b.paragraphs) == first {
+		return part, false
+	}
+	part.Offset = b.paragraphs[first].Offset
+	part.Length = b.text.Len() - part.Offset
+	return part, true
+}
+
+// addMapped 追加一个由带位置的片段组成的段落，首尾空白被去除，空段落被忽略
+func (b *builder) addMapped(p Paragraph, pieces []mappedText) {
+	pieces = trimPieces(pieces)
+	if len(pieces) == 0 {
+		return
+	}
+	if b.text.Len() > 0 {
+		b.text.WriteString("\n\n")
+	}
+	if p.Kind == "" {
+		p.Kind = KindParagraph
+	}
+	p.Index = len(b.paragraphs)
+	p.Offset = b.text.Len()
+	for _, piece := range pieces {
+		seg := segment{
+			offset:       b.text.Len(),
+			length:       len(piece.text),
+			source:       piece.source,
+			sourceLength: piece.sourceLength,
+		}
+		// 与上一段在两侧都连续时合并
+		if n := len(b.segments); n > 0 {
+			last := &b.segments[n-1]
+			if last.length == last.sourceLength && seg.length == seg.sourceLength &&
+				last.offset+last.length == seg.offset && last.source+last.sourceLength == seg.source {
+				last.length += seg.length
+				last.sourceLength += seg.sourceLength
+				seg.length = 0
+			}
+		}
+		if seg.length > 0 {
+			b.segments = append(b.segments, seg)
+		}
+		b.text.WriteString(piece.text)
+	}
+	p.Length = b.text.Len() - p.Offset
+	last := pieces[len(pieces)-1]
+	p.Source = &SourceSpan{
+		Offset: pieces[0].source,
+		Length: last.source + last.sourceLength - pieces[0].source,
+	}
+	b.paragraphs = append(b.paragraphs, p)
+}
+
+// trimPieces 去除片段序列首尾的空白，逐字节对应的片段同时调整原文件位置
+func trimPieces(pieces []mappedText) []mappedText {
+	var trimmed []mappedText
+	for _, piece := range pieces {
+		if piece.text == "" {
+			continue
+		}
+		if len(trimmed) == 0 {
+			text := strings.TrimLeftFunc(piece.text, unicode.IsSpace)
+			if text == "" {
+				continue
+			}
+			if len(piece.text) == piece.sourceLength {
+				cut := len(piece.text) - len(text)
+				piece.source += cut
+				piece.sourceLength -= cut
+			}
+			piece.text = text
+		}
+		trimmed = append(trimmed, piece)
+	}
+	for len(trimmed) > 0 {
+		last := &trimmed[len(trimmed)-1]
+		text := strings.TrimRightFunc(last.text, unicode.IsSpace)
+		if text != "" {
+			if len(last.text) == last.sourceLength {
+				last.sourceLength = len(text)
+			}
+			last.text = text
+			break
+		}
+		trimmed = trimmed[:len(trimmed)-1]
+	}
+	return trimmed
+}
+
+// document 生成提取结果
+func (b *builder) document() *Document {
+	return &Document{
+		Text:       b.text.String(),
+		Paragraphs: b.paragraphs,
+		segments:   b.segments,
+	}
+}
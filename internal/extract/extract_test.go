@@ -0,0 +1,221 @@
+package extract
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// paragraphTexts 返回各段落在提取文本中对应的内容
+func paragraphTexts(doc *Document) []string {
+	texts := make([]string, len(doc.Paragraphs))
+	for i, p := range doc.Paragraphs {
+		texts[i] = doc.Text[p.Offset : p.Offset+p.Length]
+	}
+	return texts
+}
+
+func TestDetect(t *testing.T) {
+	docx := buildDOCX(t, `<w:p><w:r><w:t>Hello</w:t></w:r></w:p>`, "")
+	pdf := buildPDF(t, []string{"BT (Hello) Tj ET"}, false, "")
+
+	tests := []struct {
+		name     string
+		filename string
+		data     []byte
+		want     Format
+		wantErr  bool
+	}{
+		{"text", "notes.txt", []byte("hello"), FormatText, false},
+		{"markdown", "README.md", []byte("# Title"), FormatMarkdown, false},
+		{"latex", "paper.tex", []byte(`\section{Intro}`), FormatLaTeX, false},
+		{"html by extension", "page.htm", []byte("<p>hi</p>"), FormatHTML, false},
+		{"html by content", "upload", []byte("<!DOCTYPE html><html></html>"), FormatHTML, false},
+		{"srt", "talk.srt", []byte("1\n00:00:01,000 --> 00:00:02,000\nHi"), FormatSRT, false},
+		{"srt by content", "upload", []byte("1\r\n00:00:01,000 --> 00:00:02,000\r\nHi"), FormatSRT, false},
+		{"go", "main.go", []byte("package main"), FormatGo, false},
+		{"typescript", "app.tsx", []byte("export {}"), FormatTypeScript, false},
+		{"vtt by content", "upload", []byte("WEBVTT\n\n00:01.000 --> 00:02.000\nHi"), FormatVTT, false},
+		{"docx", "report.docx", docx, FormatDOCX, false},
+		{"docx without extension", "upload", docx, FormatDOCX, false},
+		{"pdf", "paper.pdf", pdf, FormatPDF, false},
+		{"pdf named txt", "paper.txt", pdf, FormatPDF, false},
+		{"unknown extension", "data", []byte("plain"), FormatText, false},
+		{"fake pdf", "paper.pdf", []byte("not a pdf"), "", true},
+		{"binary", "image.bin", []byte{0xff, 0xd8, 0xff, 0x00}, "", true},
+		{"other zip", "archive.zip", buildZip(t, map[string]string{"a.txt": "a"}), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Detect(tt.filename, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Detect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrUnsupportedFormat) {
+				t.Errorf("Detect() error = %v, want ErrUnsupportedFormat", err)
+			}
+			if got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"md": FormatMarkdown, "markdown": FormatMarkdown, ".HTM": FormatHTML, "pdf": FormatPDF, "latex": FormatLaTeX, "webvtt": FormatVTT, "python": FormatPython, "h": FormatC} {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("rtf"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("ParseFormat(rtf) error = %v, want ErrUnsupportedFormat", err)
+	}
+}
+
+func TestExtract_Text(t *testing.T) {
+	data := "\xEF\xBB\xBFFirst paragraph\r\nstill first.\r\n\r\n\r\n  Second paragraph.\r\n"
+	doc, err := Extract("notes.txt", []byte(data), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	// 纯文本原样保留，只统一换行符
+	if want := "First paragraph\nstill first.\n\n\n  Second paragraph.\n"; doc.Text != want {
+		t.Errorf("Text = %q, want %q", doc.Text, want)
+	}
+	got := paragraphTexts(doc)
+	if len(got) != 2 || got[0] != "First paragraph\nstill first." || got[1] != "Second paragraph." {
+		t.Errorf("paragraphs = %q", got)
+	}
+	if doc.Paragraphs[1].Line != 5 {
+		t.Errorf("Paragraphs[1].Line = %d, want 5", doc.Paragraphs[1].Line)
+	}
+
+	if p := doc.Locate(strings.Index(doc.Text, "still")); p == nil || p.Index != 0 {
+		t.Errorf("Locate(still) = %+v, want paragraph 0", p)
+	}
+	if p := doc.Locate(strings.Index(doc.Text, "\n\n")); p != nil {
+		t.Errorf("Locate(blank line) = %+v, want nil", p)
+	}
+}
+
+func TestExtract_UTF16(t *testing.T) {
+	data := []byte{0xFF, 0xFE, 'H', 0, 'i', 0, 0x2d, 0x4e} // "Hi中"
+	doc, err := Extract("notes.txt", data, Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if doc.Text != "Hi中" {
+		t.Errorf("Text = %q, want Hi中", doc.Text)
+	}
+}
+
+func TestExtract_Markdown(t *testing.T) {
+	data := "# Title\n\nSome prose.\n\n- item one\n- item two\n\n> quoted\n\n1. first\n"
+	doc, err := Extract("README.md", []byte(data), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	wantKinds := []string{KindHeading, KindParagraph, KindListItem, KindListItem, KindQuote, KindListItem}
+	if len(doc.Paragraphs) != len(wantKinds) {
+		t.Fatalf("got %d paragraphs, want %d", len(doc.Paragraphs), len(wantKinds))
+	}
+	for i, kind := range wantKinds {
+		if doc.Paragraphs[i].Kind != kind {
+			t.Errorf("Paragraphs[%d].Kind = %s, want %s", i, doc.Paragraphs[i].Kind, kind)
+		}
+	}
+	if doc.Paragraphs[0].Level != 1 {
+		t.Errorf("heading level = %d, want 1", doc.Paragraphs[0].Level)
+	}
+}
+
+func TestExtract_HTML(t *testing.T) {
+	data := `<!DOCTYPE html><html><head><title>T</title><style>p{}</style></head><body>
+<h2>Overview</h2>
+<p>It is   <b>crucial</b> to
+understand &amp; delve.</p>
+<script>var x = "hidden";</script>
+<ul><li>One</li><li>Two<br>lines</li></ul>
+<table><tr><td>Cell</td></tr></table>
+Trailing text
+</body></html>`
+
+	doc, err := Extract("page.html", []byte(data), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	want := []string{"Overview", "It is crucial to understand & delve.", "One", "Two\nlines", "Cell", "Trailing text"}
+	got := paragraphTexts(doc)
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("paragraphs = %q, want %q", got, want)
+	}
+	if doc.Paragraphs[0].Kind != KindHeading || doc.Paragraphs[0].Level != 2 {
+		t.Errorf("Paragraphs[0] = %+v, want level 2 heading", doc.Paragraphs[0])
+	}
+	if doc.Paragraphs[2].Kind != KindListItem || doc.Paragraphs[4].Kind != KindTableCell {
+		t.Errorf("kinds = %s, %s; want list_item, table_cell", doc.Paragraphs[2].Kind, doc.Paragraphs[4].Kind)
+	}
+	if strings.Contains(doc.Text, "hidden") || strings.Contains(doc.Text, "p{}") {
+		t.Error("Text contains script or style content")
+	}
+}
+
+func TestExtract_DOCX(t *testing.T) {
+	styles := `<w:style w:type="paragraph" w:styleId="1"><w:name w:val="heading 1"/></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Normal"><w:name w:val="Normal"/></w:style>`
+	body := `<w:p><w:pPr><w:pStyle w:val="1"/></w:pPr><w:r><w:t>第一章 引言</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t xml:space="preserve">It is crucial </w:t></w:r><w:r><w:t>to delve.</w:t></w:r>` +
+		`<w:del w:author="Editor"><w:r><w:delText>removed</w:delText></w:r></w:del></w:p>` +
+		`<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/></w:numPr></w:pPr><w:r><w:t>Item</w:t></w:r></w:p>` +
+		`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>单元格</w:t></w:r></w:p></w:tc></w:tr></w:tbl>` +
+		`<w:p></w:p>`
+
+	doc, err := Extract("report.docx", buildDOCX(t, body, styles), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	want := []string{"第一章 引言", "It is crucial to delve.", "Item", "单元格"}
+	got := paragraphTexts(doc)
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("paragraphs = %q, want %q", got, want)
+	}
+	if p := doc.Paragraphs[0]; p.Kind != KindHeading || p.Level != 1 || p.Style != "heading 1" {
+		t.Errorf("Paragraphs[0] = %+v, want heading 1 resolved from localized style id", p)
+	}
+	if doc.Paragraphs[2].Kind != KindListItem || doc.Paragraphs[3].Kind != KindTableCell {
+		t.Errorf("kinds = %s, %s; want list_item, table_cell", doc.Paragraphs[2].Kind, doc.Paragraphs[3].Kind)
+	}
+}
+
+func TestExtract_PDF(t *testing.T) {
+	page1 := `BT /F1 12 Tf 14 TL 72 720 Td (First line of the) Tj T* (para-) Tj T* (graph continues.) Tj
+0 -40 Td [(Second) -300 (paragraph)] TJ ET`
+	page2 := `BT /F1 12 Tf 72 720 Td <FEFF4E2D658751855BB96BB5> Tj ET`
+
+	for _, compress := range []bool{false, true} {
+		doc, err := Extract("paper.pdf", buildPDF(t, []string{page1, page2}, compress, ""), Options{})
+		if err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+
+		want := []string{"First line of the paragraph continues.", "Second paragraph", "中文内容段"}
+		got := paragraphTexts(doc)
+		if strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("compress=%v: paragraphs = %q, want %q", compress, got, want)
+		}
+		if len(doc.Paragraphs) == 3 && (doc.Paragraphs[1].Page != 1 || doc.Paragraphs[2].Page != 2) {
+			t.Errorf("pages = %d, %d; want 1, 2", doc.Paragraphs[1].Page, doc.Paragraphs[2].Page)
+		}
+	}
+}
+
+func TestExtract_NoText(t *testing.T) {
+	if _, err := Extract("empty.html", []byte("<html><body><script>x</script></body></html>"), Options{}); !errors.Is(err, ErrNoText) {
+		t.Errorf("Extract() error = %v, want ErrNoText", err)
+	}
+}
@@ -0,0 +1,135 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildZip 构造包含指定条目的 ZIP 文件
+func buildZip(t *testing.T, entries map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range entries {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// buildDOCX 构造只包含正文和样式的 DOCX 文件，body 为 w:body 的内容
+func buildDOCX(t *testing.T, body, styles string) []byte {
+	t.Helper()
+	return buildDOCXParts(t, body, styles, nil)
+}
+
+// buildDOCXParts 构造 DOCX 文件，parts 为附加部件（如 word/footnotes.xml）的根元素，自动补充 XML 声明和命名空间
+func buildDOCXParts(t *testing.T, body, styles string, parts map[string]string) []byte {
+	t.Helper()
+	entries := map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"/>`,
+		"word/document.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+			body + `</w:body></w:document>`,
+	}
+	if styles != "" {
+		entries["word/styles.xml"] = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` + styles + `</w:styles>`
+	}
+	for name, root := range parts {
+		entries[name] = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			strings.Replace(root, ">", ` xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`, 1)
+	}
+	return buildZip(t, entries)
+}
+
+// odtNamespaces 构造 ODT 时根元素声明的命名空间
+const odtNamespaces = `xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" ` +
+	`xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" ` +
+	`xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" ` +
+	`xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" ` +
+	`xmlns:dc="http://purl.org/dc/elements/1.1/"`
+
+// buildODT 构造 ODT 文件，text 为 office:text 的内容，automatic 和 styles 分别为自动样式和公共样式的定义
+func buildODT(t *testing.T, text, automatic, styles string) []byte {
+	t.Helper()
+	return buildZip(t, map[string]string{
+		"mimetype": "application/vnd.oasis.opendocument.text",
+		"content.xml": `<?xml version="1.0" encoding="UTF-8"?><office:document-content ` + odtNamespaces + `>` +
+			`<office:automatic-styles>` + automatic + `</office:automatic-styles>` +
+			`<office:body><office:text>` + text + `</office:text></office:body></office:document-content>`,
+		"styles.xml": `<?xml version="1.0" encoding="UTF-8"?><office:document-styles ` + odtNamespaces + `>` +
+			`<office:styles>` + styles + `</office:styles></office:document-styles>`,
+	})
+}
+
+// pdfObject 构造 PDF 时的一个间接对象
+type pdfObject struct {
+	dict   string // 字典内容（不含 << >>）
+	stream []byte // 非 nil 时为流对象
+}
+
+// buildPDF 构造 PDF 文件，pages 为各页未压缩的内容流，extra 为附加对象（从 3+2*len(pages) 开始编号）
+// 对象编号：1 为 Catalog，2 为 Pages，之后每页依次为 Page 和内容流
+func buildPDF(t *testing.T, pages []string, compress bool, pageResources string, extra ...pdfObject) []byte {
+	t.Helper()
+
+	objects := []pdfObject{
+		{dict: "/Type /Catalog /Pages 2 0 R"},
+	}
+	kids := ""
+	for i := range pages {
+		kids += fmt.Sprintf("%d 0 R ", 3+2*i)
+	}
+	objects = append(objects, pdfObject{dict: fmt.Sprintf("/Type /Pages /Count %d /Kids [%s]", len(pages), kids)})
+	for i, content := range pages {
+		objects = append(objects, pdfObject{
+			dict: fmt.Sprintf("/Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents %d 0 R %s", 4+2*i, pageResources),
+		})
+		data := []byte(content)
+		dict := ""
+		if compress {
+			var buf bytes.Buffer
+			w := zlib.NewWriter(&buf)
+			w.Write(data)
+			w.Close()
+			data = buf.Bytes()
+			dict = "/Filter /FlateDecode"
+		}
+		objects = append(objects, pdfObject{dict: dict, stream: data})
+	}
+	objects = append(objects, extra...)
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		if obj.stream != nil {
+			fmt.Fprintf(&buf, "%d 0 obj\n<< %s /Length %d >>\nstream\n", i+1, obj.dict, len(obj.stream))
+			buf.Write(obj.stream)
+			buf.WriteString("\nendstream\nendobj\n")
+		} else {
+			fmt.Fprintf(&buf, "%d 0 obj\n<< %s >>\nendobj\n", i+1, obj.dict)
+		}
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
@@ -0,0 +1,523 @@
+package extract
+
+import (
+	"bytes"
+	"errors"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func init() {
+	register(FormatHTML, htmlExtractor{}, ".html", ".htm", ".xhtml")
+}
+
+// htmlExtractor HTML 提取器
+// 优先提取 <main>、<article> 中的正文，去除导航、页眉页脚、侧栏、表单和链接密集的区块等页面模板内容；
+// 按块级元素划分段落，解码实体并合并空白，提取文本中的位置可以通过 SourceOffset 映射回原 HTML
+type htmlExtractor struct{}
+
+// htmlSkipped 内容不可见的元素
+var htmlSkipped = map[atom.Atom]bool{
+	atom.Head:     true,
+	atom.Title:    true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Svg:      true,
+	atom.Iframe:   true,
+	atom.Object:   true,
+	atom.Textarea: true,
+	atom.Select:   true,
+	atom.Button:   true,
+}
+
+// htmlBoilerplate 页面模板元素：导航、侧栏和表单总是去除，页眉页脚只在正文区域之外去除
+var htmlBoilerplate = map[atom.Atom]bool{
+	atom.Nav:    true,
+	atom.Aside:  true,
+	atom.Form:   true,
+	atom.Menu:   true,
+	atom.Dialog: true,
+}
+
+// htmlBoilerplateRoles 页面模板的 ARIA 角色
+var htmlBoilerplateRoles = map[string]bool{
+	"navigation":    true,
+	"banner":        true,
+	"contentinfo":   true,
+	"complementary": true,
+	"search":        true,
+	"menu":          true,
+	"menubar":       true,
+	"dialog":        true,
+	"alert":         true,
+}
+
+// htmlBoilerplateNameRe class 或 id 中表示页面模板的词
+var htmlBoilerplateNameRe = regexp.MustCompile(`(?i)(?:^|[\s_-])(nav|navbar|navigation|menu|breadcrumbs?|sidebar|footer|masthead|cookies?|consent|banner|share|sharing|social|related|comments?|advert|ads|promo|newsletter|subscribe|popup|modal|skip-link|pagination|toolbar)(?:$|[\s_-])`)
+
+// htmlBlocks 块级元素及对应的段落类型
+var htmlBlocks = map[atom.Atom]string{
+	atom.P:          KindParagraph,
+	atom.Div:        KindParagraph,
+	atom.Section:    KindParagraph,
+	atom.Article:    KindParagraph,
+	atom.Main:       KindParagraph,
+	atom.Header:     KindParagraph,
+	atom.Footer:     KindParagraph,
+	atom.Nav:        KindParagraph,
+	atom.Aside:      KindParagraph,
+	atom.Pre:        KindParagraph,
+	atom.Figure:     KindParagraph,
+	atom.Figcaption: KindParagraph,
+	atom.Caption:    KindParagraph,
+	atom.Dt:         KindParagraph,
+	atom.Dd:         KindParagraph,
+	atom.Ul:         KindParagraph,
+	atom.Ol:         KindParagraph,
+	atom.Dl:         KindParagraph,
+	atom.Table:      KindParagraph,
+	atom.Tr:         KindParagraph,
+	atom.Hr:         KindParagraph,
+	atom.Address:    KindParagraph,
+	atom.Details:    KindParagraph,
+	atom.Summary:    KindParagraph,
+	atom.Blockquote: KindQuote,
+	atom.Li:         KindListItem,
+	atom.Td:         KindTableCell,
+	atom.Th:         KindTableCell,
+	atom.H1:         KindHeading,
+	atom.H2:         KindHeading,
+	atom.H3:         KindHeading,
+	atom.H4:         KindHeading,
+	atom.H5:         KindHeading,
+	atom.H6:         KindHeading,
+}
+
+// headingLevels 标题元素级别
+var headingLevels = map[atom.Atom]int{
+	atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6,
+}
+
+// htmlVoid 没有结束标签的元素
+var htmlVoid = map[atom.Atom]bool{
+	atom.Area: true, atom.Base: true, atom.Br: true, atom.Col: true, atom.Embed: true,
+	atom.Hr: true, atom.Img: true, atom.Input: true, atom.Link: true, atom.Meta: true,
+	atom.Param: true, atom.Source: true, atom.Track: true, atom.Wbr: true,
+}
+
+// htmlAutoClose 遇到同名开始标签时隐式结束的元素，以及隐式结束时不能越过的容器
+var htmlAutoClose = map[atom.Atom][]atom.Atom{
+	atom.P:      {atom.Div, atom.Section, atom.Article, atom.Main, atom.Body, atom.Td, atom.Th, atom.Li, atom.Blockquote},
+	atom.Li:     {atom.Ul, atom.Ol, atom.Menu},
+	atom.Dt:     {atom.Dl},
+	atom.Dd:     {atom.Dl},
+	atom.Tr:     {atom.Table, atom.Tbody, atom.Thead, atom.Tfoot},
+	atom.Td:     {atom.Tr},
+	atom.Th:     {atom.Tr},
+	atom.Option: {atom.Select},
+}
+
+// 链接密集区块的判定：链接文本占比超过该值且链接数或文本长度满足条件时视为导航类内容
+const (
+	htmlMaxLinkDensity = 0.5
+	htmlMinNavLinks    = 3
+	htmlShortText      = 40
+)
+
+// htmlNode 解析得到的节点，文本节点记录其在原 HTML 中的位置
+type htmlNode struct {
+	tag      atom.Atom
+	attrs    map[string]string
+	parent   *htmlNode
+	children []*htmlNode
+
+	text       string // 文本节点的原始内容（未解码）
+	start, end int    // 文本节点或开始标签在原 HTML 中的范围
+	isText     bool
+}
+
+// Extract 提取 HTML 正文
+func (htmlExtractor) Extract(data []byte, _ Options) (*Document, error) {
+	source, err := decodeText(data)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parseHTMLTree(source)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &htmlWalker{source: source}
+	w.walk(mainContent(root))
+	w.flush(KindParagraph, 0)
+
+	doc := w.b.document()
+	doc.Source = source
+	return doc, nil
+}
+
+// parseHTMLTree 将 HTML 解析为节点树，容忍未闭合和错误嵌套的标签
+func parseHTMLTree(source string) (*htmlNode, error) {
+	root := &htmlNode{}
+	stack := []*htmlNode{root}
+	current := func() *htmlNode { return stack[len(stack)-1] }
+
+	z := xhtml.NewTokenizer(strings.NewReader(source))
+	offset := 0
+	for {
+		tt := z.Next()
+		raw := len(z.Raw())
+		start := offset
+		offset += raw
+
+		switch tt {
+		case xhtml.ErrorToken:
+			if errors.Is(z.Err(), io.EOF) {
+				return root, nil
+			}
+			return nil, z.Err()
+
+		case xhtml.TextToken:
+			parent := current()
+			parent.children = append(parent.children, &htmlNode{
+				parent: parent,
+				text:   source[start:offset],
+				start:  start,
+				end:    offset,
+				isText: true,
+			})
+
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			node := &htmlNode{tag: atom.Lookup(name), start: start, end: offset, attrs: map[string]string{}}
+			for hasAttr {
+				var key, value []byte
+				key, value, hasAttr = z.TagAttr()
+				node.attrs[string(key)] = string(value)
+			}
+
+			// 隐式结束同名元素（如连续的 <p>、<li>）
+			if boundaries, ok := htmlAutoClose[node.tag]; ok {
+				for i := len(stack) - 1; i > 0; i-- {
+					if stack[i].tag == node.tag {
+						stack = stack[:i]
+						break
+					}
+					if containsAtom(boundaries, stack[i].tag) {
+						break
+					}
+				}
+			}
+
+			parent := current()
+			node.parent = parent
+			parent.children = append(parent.children, node)
+			if tt == xhtml.StartTagToken && !htmlVoid[node.tag] {
+				stack = append(stack, node)
+			}
+
+		case xhtml.EndTagToken:
+			name, _ := z.TagName()
+			tag := atom.Lookup(name)
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].tag == tag {
+					stack = stack[:i]
+					break
+				}
+			}
+		}
+	}
+}
+
+// containsAtom 判断列表中是否包含指定元素
+func containsAtom(atoms []atom.Atom, a atom.Atom) bool {
+	for _, x := range atoms {
+		if x == a {
+			return true
+		}
+	}
+	return false
+}
+
+// mainContent 选择正文区域：优先 <main> 或 role="main"，其次正文最长的 <article>，都没有时使用整个文档
+func mainContent(root *htmlNode) *htmlNode {
+	var main, article *htmlNode
+	articleLength := 0
+	root.each(func(n *htmlNode) bool {
+		if n.isText || htmlSkipped[n.tag] {
+			return false
+		}
+		if main == nil && (n.tag == atom.Main || n.attrs["role"] == "main") {
+			main = n
+		}
+		if n.tag == atom.Article {
+			if length := n.textLength(); length > articleLength {
+				article, articleLength = n, length
+			}
+		}
+		return true
+	})
+
+	switch {
+	case main != nil:
+		return main
+	case article != nil:
+		return article
+	}
+	return root
+}
+
+// each 先序遍历节点，fn 返回 false 时不再遍历其子节点
+func (n *htmlNode) each(fn func(*htmlNode) bool) {
+	if !fn(n) {
+		return
+	}
+	for _, c := range n.children {
+		c.each(fn)
+	}
+}
+
+// textLength 节点中可见文本的字符数（不含空白）
+func (n *htmlNode) textLength() int {
+	length := 0
+	n.each(func(c *htmlNode) bool {
+		if c.isText {
+			length += utf8.RuneCountInString(strings.Join(strings.Fields(html.UnescapeString(c.text)), ""))
+			return false
+		}
+		return !htmlSkipped[c.tag]
+	})
+	return length
+}
+
+// linkDensity 返回节点中链接文本占全部文本的比例和链接个数
+func (n *htmlNode) linkDensity() (float64, int, int) {
+	total, linked, links := 0, 0, 0
+	var walk func(c *htmlNode, inLink bool)
+	walk = func(c *htmlNode, inLink bool) {
+		if c.isText {
+			length := utf8.RuneCountInString(strings.Join(strings.Fields(html.UnescapeString(c.text)), ""))
+			total += length
+			if inLink {
+				linked += length
+			}
+			return
+		}
+		if htmlSkipped[c.tag] {
+			return
+		}
+		if c.tag == atom.A {
+			links++
+			inLink = true
+		}
+		for _, child := range c.children {
+			walk(child, inLink)
+		}
+	}
+	walk(n, false)
+	if total == 0 {
+		return 0, links, 0
+	}
+	return float64(linked) / float64(total), links, total
+}
+
+// inContent 判断节点是否位于 <main> 或 <article> 中
+func (n *htmlNode) inContent() bool {
+	for p := n.parent; p != nil; p = p.parent {
+		if p.tag == atom.Main || p.tag == atom.Article || p.attrs["role"] == "main" {
+			return true
+		}
+	}
+	return false
+}
+
+// containsHeading 判断节点中是否包含一级标题
+func (n *htmlNode) containsHeading() bool {
+	found := false
+	n.each(func(c *htmlNode) bool {
+		if c.tag == atom.H1 {
+			found = true
+		}
+		return !found && !c.isText
+	})
+	return found
+}
+
+// isBoilerplate 判断元素是否为页面模板内容
+func isBoilerplate(n *htmlNode) bool {
+	if htmlBoilerplate[n.tag] || htmlBoilerplateRoles[n.attrs["role"]] {
+		return true
+	}
+	if _, hidden := n.attrs["hidden"]; hidden || n.attrs["aria-hidden"] == "true" {
+		return true
+	}
+	if (n.tag == atom.Header || n.tag == atom.Footer) && !n.inContent() {
+		return true
+	}
+
+	// class 或 id 表明是模板区块，但包含一级标题时保留（常见于文章头部）
+	if htmlBoilerplateNameRe.MatchString(n.attrs["class"]) || htmlBoilerplateNameRe.MatchString(n.attrs["id"]) {
+		return !n.containsHeading()
+	}
+
+	// 链接密集的列表和容器（菜单、标签云、相关链接等）
+	switch n.tag {
+	case atom.Ul, atom.Ol, atom.Div, atom.Section, atom.Table, atom.P, atom.Dl:
+		density, links, total := n.linkDensity()
+		if density > htmlMaxLinkDensity && (links >= htmlMinNavLinks || total < htmlShortText) {
+			return true
+		}
+	}
+	return false
+}
+
+// htmlWalker 遍历节点树，在块级元素边界处生成段落
+type htmlWalker struct {
+	source string
+	b      builder
+	pieces []mappedText
+	space  bool // 上一个输出是否为空白，用于合并连续空白
+	pre    int  // 所在 <pre> 的嵌套层数，其中的空白原样保留
+}
+
+// walk 递归遍历节点
+func (w *htmlWalker) walk(n *htmlNode) {
+	if n.isText {
+		w.text(n)
+		return
+	}
+	if htmlSkipped[n.tag] || (n.tag != 0 && isBoilerplate(n)) {
+		return
+	}
+	if n.tag == atom.Br {
+		w.pieces = append(w.pieces, mappedText{text: "\n", source: n.start, sourceLength: n.end - n.start})
+		w.space = true
+		return
+	}
+
+	kind, block := htmlBlocks[n.tag]
+	if block {
+		// 块级元素之前的行内文本自成一段
+		w.flush(KindParagraph, 0)
+	}
+	if n.tag == atom.Pre {
+		w.pre++
+	}
+	for _, c := range n.children {
+		w.walk(c)
+	}
+	if n.tag == atom.Pre {
+		w.pre--
+	}
+	if block {
+		w.flush(kind, headingLevels[n.tag])
+	}
+}
+
+// text 输出文本节点：解码实体，<pre> 之外合并连续空白，各片段记录在原 HTML 中的位置
+func (w *htmlWalker) text(n *htmlNode) {
+	raw := n.text
+	for i := 0; i < len(raw); {
+		c := raw[i]
+
+		// 空白
+		if isHTMLSpace(c) && w.pre == 0 {
+			j := i
+			for j < len(raw) && isHTMLSpace(raw[j]) {
+				j++
+			}
+			if !w.space {
+				w.pieces = append(w.pieces, mappedText{text: " ", source: n.start + i, sourceLength: j - i})
+				w.space = true
+			}
+			i = j
+			continue
+		}
+
+		// 实体
+		if c == '&' {
+			if end := strings.IndexByte(raw[i:], ';'); end > 1 && end <= 32 {
+				entity := raw[i : i+end+1]
+				if decoded := html.UnescapeString(entity); decoded != entity {
+					// 不换行空格按普通空白处理
+					if decoded == "\u00a0" && w.pre == 0 {
+						if !w.space {
+							w.pieces = append(w.pieces, mappedText{text: " ", source: n.start + i, sourceLength: len(entity)})
+							w.space = true
+						}
+					} else {
+						w.pieces = append(w.pieces, mappedText{text: decoded, source: n.start + i, sourceLength: len(entity)})
+						w.space = false
+					}
+					i += len(entity)
+					continue
+				}
+			}
+		}
+
+		// <pre> 中的换行单独输出，以便按行去除首尾空白
+		if c == '\n' {
+			w.pieces = append(w.pieces, mappedText{text: "\n", source: n.start + i, sourceLength: 1})
+			i++
+			continue
+		}
+
+		// 普通文本：原样输出到下一个空白、换行或实体
+		j := i + 1
+		for j < len(raw) && raw[j] != '&' && raw[j] != '\n' && (w.pre > 0 || !isHTMLSpace(raw[j])) {
+			j++
+		}
+		w.pieces = append(w.pieces, mappedText{text: raw[i:j], source: n.start + i, sourceLength: j - i})
+		w.space = false
+		i = j
+	}
+}
+
+// flush 将已收集的文本作为一个段落输出，每行首尾的空白被去除
+func (w *htmlWalker) flush(kind string, level int) {
+	var pieces, line []mappedText
+	var newline mappedText
+	addLine := func() {
+		if trimmed := trimPieces(line); len(trimmed) > 0 {
+			if len(pieces) > 0 {
+				pieces = append(pieces, newline)
+			}
+			pieces = append(pieces, trimmed...)
+		}
+		line = nil
+	}
+	for _, piece := range w.pieces {
+		if piece.text != "\n" {
+			line = append(line, piece)
+			continue
+		}
+		addLine()
+		newline = piece
+	}
+	addLine()
+
+	if len(pieces) > 0 {
+		w.b.addMapped(Paragraph{Kind: kind, Level: level, Line: lineNumber(w.source, pieces[0].source)}, pieces)
+	}
+	w.pieces = nil
+	w.space = false
+}
+
+// isHTMLSpace 判断是否为 HTML 空白字符
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// lineNumber 返回字节偏移量所在的行号，从 1 开始
+func lineNumber(text string, offset int) int {
+	return bytes.Count([]byte(text[:min(offset, len(text))]), []byte("\n")) + 1
+}
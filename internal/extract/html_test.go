@@ -0,0 +1,131 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+func TestHTML_Boilerplate(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want []string
+	}{
+		{
+			name: "article",
+			html: `<html><body>
+<header><div class="logo">Site</div><nav><a href="/">Home</a><a href="/a">About</a></nav></header>
+<div class="cookie-banner">We use cookies. <button>Accept</button></div>
+<article>
+  <header><h1>Real title</h1><p class="byline">By Someone</p></header>
+  <p>First paragraph of the story.</p>
+  <div class="share-buttons"><a href="#">Tweet</a> <a href="#">Share</a></div>
+  <p>Second paragraph with a <a href="/ref">reference</a> inside.</p>
+  <footer>Posted in essays</footer>
+</article>
+<aside><h2>Related</h2><p>Other stories</p></aside>
+<footer>Copyright 2024</footer>
+</body></html>`,
+			want: []string{"Real title", "By Someone", "First paragraph of the story.", "Second paragraph with a reference inside.", "Posted in essays"},
+		},
+		{
+			name: "main preferred over article",
+			html: `<body><article><p>Teaser card</p></article><div role="main"><p>Main text.</p><article><p>Inner article.</p></article></div></body>`,
+			want: []string{"Main text.", "Inner article."},
+		},
+		{
+			name: "no content element",
+			html: `<body>
+<div id="top-menu"><a href="/">Home</a> | <a href="/x">Blog</a> | <a href="/y">Contact</a></div>
+<ul><li><a href="/1">Link one</a></li><li><a href="/2">Link two</a></li><li><a href="/3">Link three</a></li></ul>
+<h2>Body heading</h2>
+<p>Plain body text that matters.</p>
+<p hidden>Hidden text</p>
+<div aria-hidden="true">Screen reader hidden</div>
+<footer><p>Footer text</p></footer>
+</body>`,
+			want: []string{"Body heading", "Plain body text that matters."},
+		},
+		{
+			name: "unclosed tags",
+			html: `<body><p>One<p>Two<ul><li>A<li>B</ul><table><tr><td>C<td>D</table>`,
+			want: []string{"One", "Two", "A", "B", "C", "D"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := ExtractAs(FormatHTML, []byte(tt.html), Options{})
+			if err != nil {
+				t.Fatalf("ExtractAs() error = %v", err)
+			}
+			if got := paragraphTexts(doc); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("paragraphs = %q, want %q", got, tt.want)
+			}
+			assertSourceMapping(t, doc)
+		})
+	}
+}
+
+func TestHTML_Entities(t *testing.T) {
+	source := "<p>Caf&eacute;&nbsp;&amp;&#160;bar &lt;div&gt; &#x4E2D;&#25991;</p>\n<pre>  keep\n    indent &amp; lines</pre>"
+	doc, err := ExtractAs(FormatHTML, []byte(source), Options{})
+	if err != nil {
+		t.Fatalf("ExtractAs() error = %v", err)
+	}
+
+	want := []string{"Café & bar <div> 中文", "keep\nindent & lines"}
+	if got := paragraphTexts(doc); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("paragraphs = %q, want %q", got, want)
+	}
+	if doc.Paragraphs[1].Line != 2 {
+		t.Errorf("Paragraphs[1].Line = %d, want 2", doc.Paragraphs[1].Line)
+	}
+	if span := doc.Paragraphs[1].Source; span == nil || source[span.Offset:span.Offset+span.Length] != "keep\n    indent &amp; lines" {
+		t.Errorf("Paragraphs[1].Source = %+v, want span of the pre text", span)
+	}
+
+	// 解码后的实体整体映射到原 HTML 中的实体
+	offset := strings.Index(doc.Text, "é")
+	start, length := doc.sourceRange(offset, len("é"))
+	if got := source[start : start+length]; got != "&eacute;" {
+		t.Errorf("é maps to %q, want &eacute;", got)
+	}
+	assertSourceMapping(t, doc)
+}
+
+func TestHTML_MapResult(t *testing.T) {
+	source := "<html><body>\n<nav><a href=\"/\">Home</a></nav>\n<main>\n  <p>It is <b>crucial</b>   to\n  delve.</p>\n</main>\n</body></html>"
+	doc, err := ExtractAs(FormatHTML, []byte(source), Options{})
+	if err != nil {
+		t.Fatalf("ExtractAs() error = %v", err)
+	}
+	if doc.Text != "It is crucial to delve." {
+		t.Fatalf("Text = %q", doc.Text)
+	}
+
+	result := &models.DetectionResult{RuleResults: []models.RuleResult{{Matches: []models.Match{
+		{Text: "crucial", Position: models.Position{Offset: strings.Index(doc.Text, "crucial"), Length: len("crucial")}},
+		{Text: "crucial to delve", Position: models.Position{Offset: strings.Index(doc.Text, "crucial"), Length: len("crucial to delve")}},
+	}}}}
+	doc.MapResult(result)
+
+	tests := []struct {
+		want         string
+		line, column int
+	}{
+		{"crucial", 4, 15},
+		{"crucial</b>   to\n  delve", 4, 15},
+	}
+	for i, tt := range tests {
+		pos := result.RuleResults[0].Matches[i].Position
+		if got := source[pos.Offset : pos.Offset+pos.Length]; got != tt.want {
+			t.Errorf("mapped span = %q, want %q", got, tt.want)
+		}
+		if pos.Line != tt.line || pos.Column != tt.column {
+			t.Errorf("%q at %d:%d, want %d:%d", tt.want, pos.Line, pos.Column, tt.line, tt.column)
+		}
+	}
+}
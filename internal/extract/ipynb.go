@@ -0,0 +1,87 @@
+package extract
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func init() {
+	register(FormatIPYNB, ipynbExtractor{}, ".ipynb")
+}
+
+// ipynbExtractor Jupyter 笔记本提取器：按顺序提取 Markdown 单元格中的正文（代码单元格和输出不参与检测），
+// 每个单元格作为一个部分，段落位置描述为 "单元格 N 第 M 段"，N 为单元格在笔记本中的序号（从 1 开始，包括代码单元格）
+type ipynbExtractor struct{}
+
+// ipynbNotebook 笔记本文件，nbformat 4 的单元格位于顶层，nbformat 3 的位于 worksheets 中
+type ipynbNotebook struct {
+	Cells      []ipynbCell `json:"cells"`
+	Worksheets []struct {
+		Cells []ipynbCell `json:"cells"`
+	} `json:"worksheets"`
+}
+
+// ipynbCell 笔记本单元格
+type ipynbCell struct {
+	Type   string      `json:"cell_type"`
+	ID     string      `json:"id"`
+	Source ipynbSource `json:"source"`
+}
+
+// ipynbSource 单元格源文本：字符串或按行拆分的字符串数组
+type ipynbSource string
+
+func (s *ipynbSource) UnmarshalJSON(data []byte) error {
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err == nil {
+		*s = ipynbSource(strings.Join(lines, ""))
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return errors.New("cell source is neither a string nor a list of strings")
+	}
+	*s = ipynbSource(text)
+	return nil
+}
+
+// Extract 提取笔记本中 Markdown 单元格的正文
+func (ipynbExtractor) Extract(data []byte, _ Options) (*Document, error) {
+	source, err := decodeText(data)
+	if err != nil {
+		return nil, err
+	}
+	var notebook ipynbNotebook
+	if err := json.Unmarshal([]byte(source), &notebook); err != nil {
+		return nil, fmt.Errorf("failed to parse notebook: %w", err)
+	}
+	cells := notebook.Cells
+	for _, ws := range notebook.Worksheets {
+		cells = append(cells, ws.Cells...)
+	}
+
+	b := &builder{}
+	var parts []Part
+	for i, cell := range cells {
+		if cell.Type != "markdown" {
+			continue
+		}
+		doc, err := ExtractAs(FormatMarkdown, []byte(cell.Source), Options{})
+		if errors.Is(err, ErrNoText) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cell %d: %w", i+1, err)
+		}
+		part := Part{Index: len(parts), Name: fmt.Sprintf("单元格 %d", i+1), ID: cell.ID}
+		if part, ok := b.addPart(part, doc); ok {
+			parts = append(parts, part)
+		}
+	}
+
+	doc := b.document()
+	doc.Parts = parts
+	return doc, nil
+}
@@ -0,0 +1,105 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+func TestIPYNB_Cells(t *testing.T) {
+	data := `{
+  "nbformat": 4,
+  "cells": [
+    {"cell_type": "markdown", "id": "intro", "source": ["# Sales Report\n", "\n", "Revenue grew **12%** this quarter.\n", "\n", "Costs were flat."]},
+    {"cell_type": "code", "source": "# load data\ndf = load()", "outputs": []},
+    {"cell_type": "markdown", "source": "   "},
+    {"cell_type": "markdown", "id": "summary", "source": "## Summary\n\nIn conclusion, it is worth noting that the results are robust."}
+  ]
+}`
+	doc, err := Extract("report.ipynb", []byte(data), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if doc.Format != FormatIPYNB {
+		t.Errorf("Format = %q, want ipynb", doc.Format)
+	}
+	if strings.Contains(doc.Text, "load data") || strings.Contains(doc.Text, "**") {
+		t.Errorf("Text should only contain markdown prose: %q", doc.Text)
+	}
+
+	wantParts := []Part{
+		{Index: 0, Name: "单元格 1", ID: "intro", Title: "Sales Report"},
+		{Index: 1, Name: "单元格 4", ID: "summary", Title: "Summary"},
+	}
+	if len(doc.Parts) != len(wantParts) {
+		t.Fatalf("Parts = %+v, want %d parts", doc.Parts, len(wantParts))
+	}
+	for i, want := range wantParts {
+		got := doc.Parts[i]
+		want.Offset, want.Length = got.Offset, got.Length
+		if got != want {
+			t.Errorf("Parts[%d] = %+v, want %+v", i, got, want)
+		}
+	}
+	if got := doc.Text[doc.Parts[1].Offset : doc.Parts[1].Offset+doc.Parts[1].Length]; !strings.HasPrefix(got, "Summary\n\nIn conclusion") {
+		t.Errorf("second part text = %q", got)
+	}
+
+	for _, tt := range []struct {
+		text string
+		want string
+	}{
+		{"Costs were flat", "单元格 1 第 2 段"},
+		{"Summary", "单元格 4 标题"},
+		{"In conclusion", "单元格 4 第 1 段"},
+	} {
+		if got := doc.Location(strings.Index(doc.Text, tt.text)); got != tt.want {
+			t.Errorf("Location(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestIPYNB_SegmentScores(t *testing.T) {
+	data := `{"worksheets": [{"cells": [
+    {"cell_type": "markdown", "source": ["Plain notes."]},
+    {"cell_type": "markdown", "source": ["I hope this helps. Feel free to ask."]}
+  ]}], "nbformat": 3}`
+	doc, err := Extract("old.ipynb", []byte(data), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	match := func(text string) models.Match {
+		offset := strings.Index(doc.Text, text)
+		return models.Match{Text: text, Position: models.Position{Offset: offset, Length: len(text)}}
+	}
+	result := &models.DetectionResult{
+		RuleResults: []models.RuleResult{
+			{RuleName: "协作式语气检测", Detected: true, Score: 70, Matches: []models.Match{match("I hope this helps"), match("Feel free")}},
+		},
+	}
+	doc.MapResult(result)
+
+	if got := result.RuleResults[0].Matches[0].Location; got != "单元格 2 第 1 段" {
+		t.Errorf("match location = %q, want 单元格 2 第 1 段", got)
+	}
+	if len(result.Segments) != 2 {
+		t.Fatalf("segments = %+v, want 2", result.Segments)
+	}
+	if s := result.Segments[0]; s.Part != "单元格 1" || s.Score != 100 || s.Matches != 0 {
+		t.Errorf("Segments[0] = %+v", s)
+	}
+	if s := result.Segments[1]; s.Part != "单元格 2" || s.Score != 70 || s.Matches != 2 || s.Time != nil {
+		t.Errorf("Segments[1] = %+v", s)
+	}
+}
+
+func TestIPYNB_Invalid(t *testing.T) {
+	if _, err := Extract("bad.ipynb", []byte(`{"cells": [{"cell_type": "markdown", "source": 3}]}`), Options{}); err == nil {
+		t.Error("Extract() should reject a cell with a non-string source")
+	}
+	if _, err := Extract("code.ipynb", []byte(`{"cells": [{"cell_type": "code", "source": "x = 1"}]}`), Options{}); err != ErrNoText {
+		t.Errorf("Extract() error = %v, want ErrNoText", err)
+	}
+}
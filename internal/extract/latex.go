@@ -0,0 +1,616 @@
+package extract
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func init() {
+	register(FormatLaTeX, latexExtractor{}, ".tex", ".latex", ".ltx")
+}
+
+// latexExtractor LaTeX 源文件提取器
+// 按 TeX 的词法逐个处理控制序列、分组和特殊字符，只保留正文：注释、数学公式、引用和交叉引用、
+// 图表、代码和参考文献等环境被去除，\section 等命令转换为带级别的标题；提取文本中的位置可以通过
+// SourceOffset 映射回 .tex 文件
+type latexExtractor struct{}
+
+// Extract 提取 LaTeX 正文，存在 \begin{document} 时只提取其中的内容和导言区中的 \title
+func (latexExtractor) Extract(data []byte, opts Options) (*Document, error) {
+	source, err := decodeText(data)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &texParser{source: source, opts: opts}
+	p.cur = p.newBuffer()
+
+	start, end := 0, len(source)
+	if m := texBeginDocumentRe.FindStringSubmatchIndex(source); m != nil {
+		p.preamble = true
+		p.walk(0, m[2], &texBuffer{})
+		p.preamble = false
+		start = m[3]
+		if i := strings.Index(source[start:], `\end{document}`); i >= 0 {
+			end = start + i
+		}
+	}
+	p.walk(start, end, nil)
+	p.flush()
+	for _, note := range p.notes {
+		p.emit(note)
+	}
+
+	doc := p.b.document()
+	doc.Source = source
+	return doc, nil
+}
+
+// texBeginDocumentRe 未被注释的 \begin{document}
+var texBeginDocumentRe = regexp.MustCompile(`(?m)^(?:[^%\n\\]|\\.)*?(\\begin\s*\{document\})`)
+
+// texHeadingLevels 章节命令的标题级别，\part 不参与章节编号
+var texHeadingLevels = map[string]int{
+	"part":          0,
+	"chapter":       1,
+	"section":       2,
+	"subsection":    3,
+	"subsubsection": 4,
+	"paragraph":     5,
+	"subparagraph":  6,
+}
+
+// texDroppedCommands 连同参数一起去除的命令及其必选参数个数：引用、交叉引用、导言区设置、排版调整等
+var texDroppedCommands = map[string]int{
+	"cite": 1, "citep": 1, "citet": 1, "citealp": 1, "citealt": 1, "citeauthor": 1, "citeyear": 1,
+	"parencite": 1, "textcite": 1, "autocite": 1, "footcite": 1, "nocite": 1,
+	"ref": 1, "eqref": 1, "autoref": 1, "cref": 1, "Cref": 1, "pageref": 1, "nameref": 1, "label": 1,
+	"url": 1, "includegraphics": 1, "input": 1, "include": 1, "caption": 1, "bibitem": 1,
+	"bibliography": 1, "bibliographystyle": 1, "addbibresource": 1,
+	"documentclass": 1, "usepackage": 1, "RequirePackage": 1, "geometry": 1, "hypersetup": 1, "graphicspath": 1,
+	"author": 1, "date": 1, "thanks": 1, "affil": 1, "affiliation": 1, "institute": 1, "email": 1, "address": 1,
+	"vspace": 1, "hspace": 1, "pagestyle": 1, "thispagestyle": 1, "color": 1, "theoremstyle": 1,
+	"newcommand": 2, "renewcommand": 2, "providecommand": 2, "DeclareMathOperator": 2, "newtheorem": 2,
+	"setlength": 2, "addtolength": 2, "setcounter": 2, "addtocounter": 2,
+	"newenvironment": 3, "renewenvironment": 3, "definecolor": 3,
+}
+
+// texSkippedArgs 去除前几个参数、保留其后文本参数的命令，如 \href{url}{text}
+var texSkippedArgs = map[string]int{
+	"href": 1, "textcolor": 1, "colorbox": 1, "foreignlanguage": 1,
+}
+
+// texSymbols 输出文字的命令
+var texSymbols = map[string]string{
+	"LaTeX": "LaTeX", "TeX": "TeX", "ldots": "…", "dots": "…", "textellipsis": "…",
+	"textendash": "–", "textemdash": "—", "textquoteleft": "‘", "textquoteright": "’",
+	"textquotedblleft": "“", "textquotedblright": "”", "textbackslash": "\\", "textasciitilde": "~", "ss": "ß",
+}
+
+// texAccents 重音命令对应的组合字符
+var texAccents = map[byte]string{
+	'\'': "\u0301", '`': "\u0300", '^': "\u0302", '"': "\u0308", '~': "\u0303", '=': "\u0304", '.': "\u0307",
+}
+
+// texDroppedEnvironments 整体去除的环境：数学公式、图表、代码、算法和参考文献
+var texDroppedEnvironments = map[string]bool{
+	"equation": true, "align": true, "gather": true, "multline": true, "flalign": true, "alignat": true,
+	"eqnarray": true, "math": true, "displaymath": true, "split": true,
+	"figure": true, "table": true, "wrapfigure": true, "subfigure": true, "subtable": true,
+	"tabular": true, "tabularx": true, "longtable": true, "tikzpicture": true, "picture": true,
+	"verbatim": true, "Verbatim": true, "lstlisting": true, "minted": true, "comment": true,
+	"algorithm": true, "algorithmic": true, "algorithm2e": true,
+	"thebibliography": true, "filecontents": true,
+}
+
+// texEnvironmentArgs 开始时带必选参数的环境及参数个数，参数不属于正文
+var texEnvironmentArgs = map[string]int{
+	"minipage": 1, "multicols": 1, "otherlanguage": 1, "CJK": 2,
+}
+
+// texEnvironmentKinds 环境中段落的类型
+var texEnvironmentKinds = map[string]string{
+	"itemize": KindListItem, "enumerate": KindListItem, "description": KindListItem,
+	"quote": KindQuote, "quotation": KindQuote, "verse": KindQuote,
+}
+
+// texBuffer 正在提取的段落
+type texBuffer struct {
+	para    Paragraph
+	pieces  []mappedText
+	space   *mappedText // 待输出的空白，连续的空白合并为一个空格
+	removed bool        // 待输出的空白之后去除了命令或公式
+}
+
+// write 输出文本，text 与原文件中 [source, source+length) 逐字节相同时逐字节映射，否则整段映射
+// 去除引用等内容后紧跟标点时，其前的空白不再输出，避免 "如文献 \cite{x}。" 变为 "如文献 。"
+func (buf *texBuffer) write(text string, source, length int) {
+	if buf.space != nil {
+		r, _ := utf8.DecodeRuneInString(text)
+		if !buf.removed || !strings.ContainsRune(".,;:!?)，。；：！？）", r) {
+			buf.pieces = append(buf.pieces, *buf.space)
+		}
+		buf.space = nil
+	}
+	buf.removed = false
+	buf.pieces = append(buf.pieces, mappedText{text: text, source: source, sourceLength: length})
+}
+
+// whitespace 记录一处空白，text 为输出的空白字符，对应原文件中 [source, source+length)，
+// 连续的空白只输出第一个，段首的空白被忽略
+func (buf *texBuffer) whitespace(text string, source, length int) {
+	if len(buf.pieces) == 0 || buf.space != nil {
+		return
+	}
+	buf.space = &mappedText{text: text, source: source, sourceLength: length}
+}
+
+// remove 记录去除了不输出的内容
+func (buf *texBuffer) remove() {
+	if buf.space != nil {
+		buf.removed = true
+	}
+}
+
+// texParser LaTeX 解析器
+type texParser struct {
+	source    string
+	opts      Options
+	b         builder
+	cur       *texBuffer   // 当前正文段落
+	kinds     []string     // 外层列表、引用环境的段落类型，其他环境为空
+	preamble  bool         // 正在处理导言区
+	notes     []*texBuffer // 脚注，附在正文之后
+	footnotes int          // 已出现的脚注个数
+}
+
+// container 外层列表或引用环境的段落类型，不在这些环境中时为空
+func (p *texParser) container() string {
+	if n := len(p.kinds); n > 0 {
+		return p.kinds[n-1]
+	}
+	return ""
+}
+
+// kind 当前环境中段落的类型
+func (p *texParser) kind() string {
+	return orKind(p.container(), KindParagraph)
+}
+
+// newBuffer 创建当前环境中的正文段落
+func (p *texParser) newBuffer() *texBuffer {
+	return &texBuffer{para: Paragraph{Kind: p.kind()}}
+}
+
+// target 输出目标：out 为 nil 时为当前正文段落
+func (p *texParser) target(out *texBuffer) *texBuffer {
+	if out != nil {
+		return out
+	}
+	return p.cur
+}
+
+// flush 结束当前正文段落
+func (p *texParser) flush() {
+	p.emit(p.cur)
+	p.cur = p.newBuffer()
+}
+
+// emit 追加一个段落，行号为其第一个字符在原文件中的行号
+func (p *texParser) emit(buf *texBuffer) {
+	if len(buf.pieces) == 0 {
+		return
+	}
+	buf.para.Line, _ = lineColumn(p.source, buf.pieces[0].source)
+	p.b.addMapped(buf.para, buf.pieces)
+}
+
+// walk 提取原文件 [start, end) 中的正文，out 为 nil 时输出到正文段落，空行和 \par 结束段落
+func (p *texParser) walk(start, end int, out *texBuffer) {
+	afterComment := false
+	for i := start; i < end; {
+		c := p.source[i]
+		switch {
+		// 注释：连同行尾换行符和下一行的行首空白一起去除
+		case c == '%':
+			n := strings.IndexByte(p.source[i:end], '\n')
+			if n < 0 {
+				i = end
+				continue
+			}
+			for i += n + 1; i < end && (p.source[i] == ' ' || p.source[i] == '\t'); i++ {
+			}
+			afterComment = true
+			continue
+
+		// 空白：包含空行时结束段落
+		case isTeXSpace(c):
+			j, newlines := i, 0
+			if afterComment {
+				newlines++
+			}
+			for ; j < end && isTeXSpace(p.source[j]); j++ {
+				if p.source[j] == '\n' {
+					newlines++
+				}
+			}
+			if newlines >= 2 && out == nil {
+				p.flush()
+			} else {
+				// 输出原文件中的第一个空白字符（\r\n 取 \n），与原文件逐字节对应
+				first := i
+				if p.source[first] == '\r' && first+1 < j {
+					first++
+				}
+				p.target(out).whitespace(p.source[first:first+1], first, 1)
+			}
+			i = j
+			afterComment = false
+
+		case c == '\\':
+			i = p.command(i, end, out)
+
+		// 行内和行间公式
+		case c == '$':
+			closing := "$"
+			if i+1 < end && p.source[i+1] == '$' {
+				closing = "$$"
+			}
+			i = p.skipPast(i+len(closing), end, closing)
+			p.target(out).remove()
+
+		// 分组本身不输出，~ 为不换行空格，& 为表格分隔符
+		case c == '{' || c == '}':
+			i++
+		case c == '~':
+			p.target(out).whitespace("\u00a0", i, 1)
+			i++
+		case c == '&':
+			p.target(out).whitespace(" ", i, 0)
+			i++
+
+		// 引号和连字符连写
+		case c == '`' || c == '\'' || c == '-':
+			n, text := texLigature(p.source[i:end])
+			length := n
+			if text != p.source[i:i+n] && len(text) == n {
+				// 与原文字节数相同的替换（--- 与 —）映射到原文的前 n-1 个字节，以免被当作逐字节对应
+				length--
+			}
+			p.target(out).write(text, i, length)
+			i += n
+
+		default:
+			j := i + 1
+			for j < end && !isTeXSpecial(p.source[j]) {
+				j++
+			}
+			p.target(out).write(p.source[i:j], i, j-i)
+			i = j
+		}
+		if c != '%' && !isTeXSpace(c) {
+			afterComment = false
+		}
+	}
+}
+
+// command 处理从 i 开始的控制序列，返回其后的位置
+func (p *texParser) command(i, end int, out *texBuffer) int {
+	j := i + 1
+	if j >= end {
+		return end
+	}
+	target := p.target(out)
+
+	// 控制符号
+	if !isTeXLetter(p.source[j]) {
+		next := j + 1
+		switch p.source[j] {
+		case '\\':
+			if next < end && p.source[next] == '*' {
+				next++
+			}
+			next = p.skipOptional(next, end)
+			target.whitespace(" ", i, next-i)
+		case '%', '&', '$', '#', '_', '{', '}':
+			target.write(p.source[j:next], i, 2)
+		case ' ', ',', ';', ':', '\n', '\t':
+			target.whitespace(" ", i, 2)
+		case '(':
+			next = p.skipPast(next, end, `\)`)
+			target.remove()
+		case '[':
+			next = p.skipPast(next, end, `\]`)
+			target.remove()
+		case '\'', '`', '^', '"', '~', '=', '.':
+			start, stop, after, ok := p.arg(next, end)
+			if ok {
+				target.write(norm.NFC.String(p.source[start:stop]+texAccents[p.source[j]]), i, after-i)
+			}
+			return after
+		}
+		// 其余控制符号（\-、\/ 等）不输出
+		return next
+	}
+
+	k := j
+	for k < end && isTeXLetter(p.source[k]) {
+		k++
+	}
+	name := p.source[j:k]
+	if k < end && p.source[k] == '*' {
+		k++
+	}
+
+	if level, ok := texHeadingLevels[name]; ok && out == nil {
+		return p.heading(level, k, end)
+	}
+	switch name {
+	case "title":
+		if out == nil || p.preamble {
+			return p.heading(0, k, end)
+		}
+	case "begin":
+		return p.environment(k, end, out)
+	case "end":
+		_, _, next, _ := p.arg(k, end)
+		return next
+	case "item":
+		if out == nil {
+			p.flush()
+		}
+		return p.skipOptional(k, end)
+	case "par":
+		if out == nil {
+			p.flush()
+		} else {
+			target.whitespace(" ", i, k-i)
+		}
+		return k
+	case "footnote", "footnotetext":
+		return p.footnote(p.skipOptional(k, end), end, target)
+	case "verb":
+		target.remove()
+		if k >= end {
+			return end
+		}
+		return p.skipPast(k+1, end, p.source[k:k+1])
+	}
+
+	if n, ok := texDroppedCommands[name]; ok {
+		next := k
+		for ; n > 0; n-- {
+			_, _, next, _ = p.arg(p.skipOptional(next, end), end)
+		}
+		target.remove()
+		return next
+	}
+	if n, ok := texSkippedArgs[name]; ok {
+		next := p.skipOptional(k, end)
+		for ; n > 0; n-- {
+			_, _, next, _ = p.arg(next, end)
+		}
+		return next
+	}
+
+	// 控制词之后的空格被忽略，其余命令（\emph、\textbf 等）的参数作为正文处理
+	next := k
+	for next < end && (p.source[next] == ' ' || p.source[next] == '\t') {
+		next++
+	}
+	if symbol, ok := texSymbols[name]; ok {
+		target.write(symbol, i, k-i)
+		return next
+	}
+	target.remove()
+	return p.skipOptional(next, end)
+}
+
+// heading 处理章节命令，标题作为单独的段落
+func (p *texParser) heading(level, i, end int) int {
+	start, stop, next, ok := p.arg(p.skipOptional(i, end), end)
+	if !ok {
+		return next
+	}
+	p.flush()
+	h := &texBuffer{para: Paragraph{Kind: KindHeading, Level: level}}
+	p.walk(start, stop, h)
+	p.emit(h)
+	return next
+}
+
+// footnote 处理脚注：从正文中去除，需要时作为脚注段落附在正文之后
+func (p *texParser) footnote(i, end int, target *texBuffer) int {
+	start, stop, next, ok := p.arg(i, end)
+	target.remove()
+	if !ok {
+		return next
+	}
+	p.footnotes++
+	if p.opts.Footnotes {
+		note := &texBuffer{para: Paragraph{Kind: KindFootnote, Number: p.footnotes}}
+		p.walk(start, stop, note)
+		p.notes = append(p.notes, note)
+	}
+	return next
+}
+
+// environment 处理 \begin{name} ... \end{name}：去除公式、图表等环境，其余环境的内容作为正文，
+// 列表和引用环境中的段落使用对应的类型
+func (p *texParser) environment(i, end int, out *texBuffer) int {
+	start, stop, next, ok := p.arg(i, end)
+	if !ok {
+		return next
+	}
+	name := p.source[start:stop]
+	bodyEnd, after := p.findEnd(name, next, end)
+
+	if texDroppedEnvironments[strings.TrimSuffix(name, "*")] {
+		p.target(out).remove()
+		return after
+	}
+
+	next = p.skipOptional(next, end)
+	for n := texEnvironmentArgs[strings.TrimSuffix(name, "*")]; n > 0; n-- {
+		_, _, next, _ = p.arg(next, end)
+	}
+	if out != nil {
+		p.walk(next, bodyEnd, out)
+		return after
+	}
+
+	p.flush()
+	kind := orKind(p.container(), texEnvironmentKinds[name])
+	p.kinds = append(p.kinds, kind)
+	p.cur = p.newBuffer()
+	p.walk(next, bodyEnd, nil)
+	p.flush()
+	p.kinds = p.kinds[:len(p.kinds)-1]
+	p.cur = p.newBuffer()
+	return after
+}
+
+// findEnd 查找与 \begin{name} 配对的 \end{name}，返回其开始和结束位置
+func (p *texParser) findEnd(name string, i, end int) (int, int) {
+	begin, closing := `\begin{`+name+`}`, `\end{`+name+`}`
+	depth := 1
+	for i < end {
+		c := strings.Index(p.source[i:end], closing)
+		if c < 0 {
+			return end, end
+		}
+		if b := strings.Index(p.source[i:end], begin); b >= 0 && b < c {
+			depth++
+			i += b + len(begin)
+			continue
+		}
+		depth--
+		if depth == 0 {
+			return i + c, i + c + len(closing)
+		}
+		i += c + len(closing)
+	}
+	return end, end
+}
+
+// arg 读取从 i 开始的必选参数（跳过前导空白），返回参数内容的范围和参数之后的位置
+// 参数不是分组时取单个字符或控制序列
+func (p *texParser) arg(i, end int) (int, int, int, bool) {
+	for i < end && isTeXSpace(p.source[i]) {
+		i++
+	}
+	if i >= end {
+		return end, end, end, false
+	}
+	switch p.source[i] {
+	case '{':
+		closing := texGroupEnd(p.source[i:end], '{', '}')
+		if closing < 0 {
+			return i + 1, end, end, true
+		}
+		return i + 1, i + closing, i + closing + 1, true
+	case '\\':
+		j := i + 1
+		for j < end && isTeXLetter(p.source[j]) {
+			j++
+		}
+		if j == i+1 && j < end {
+			j++
+		}
+		return i, j, j, true
+	}
+	_, size := utf8.DecodeRuneInString(p.source[i:end])
+	return i, i + size, i + size, true
+}
+
+// skipOptional 跳过从 i 开始的可选参数 [...]，没有可选参数时返回 i
+func (p *texParser) skipOptional(i, end int) int {
+	j := i
+	for j < end && (p.source[j] == ' ' || p.source[j] == '\t') {
+		j++
+	}
+	if j >= end || p.source[j] != '[' {
+		return i
+	}
+	if closing := texGroupEnd(p.source[j:end], '[', ']'); closing >= 0 {
+		return j + closing + 1
+	}
+	return i
+}
+
+// skipPast 跳过到 closing（未被转义）之后的位置，找不到时返回 end
+func (p *texParser) skipPast(i, end int, closing string) int {
+	for i < end {
+		n := strings.Index(p.source[i:end], closing)
+		if n < 0 {
+			return end
+		}
+		at := i + n
+		if closing != `\)` && closing != `\]` && at > 0 && p.source[at-1] == '\\' {
+			i = at + 1
+			continue
+		}
+		return at + len(closing)
+	}
+	return end
+}
+
+// texGroupEnd 查找与 s[0] 配对的结束符，跳过转义字符和注释，返回其位置
+func texGroupEnd(s string, open, close byte) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '%':
+			n := strings.IndexByte(s[i:], '\n')
+			if n < 0 {
+				return -1
+			}
+			i += n
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// texLigature 处理引号和连字符的连写：“ 和 ” 为双引号，-- 和 --- 为短划线和长划线，返回消耗的字节数和输出文本
+func texLigature(s string) (int, string) {
+	switch {
+	case strings.HasPrefix(s, "``"):
+		return 2, "“"
+	case strings.HasPrefix(s, "''"):
+		return 2, "”"
+	case strings.HasPrefix(s, "`"):
+		return 1, "‘"
+	case strings.HasPrefix(s, "---"):
+		return 3, "—"
+	case strings.HasPrefix(s, "--"):
+		return 2, "–"
+	}
+	return 1, s[:1]
+}
+
+// isTeXLetter 判断是否为控制词中的字母
+func isTeXLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '@'
+}
+
+// isTeXSpace 判断是否为空白
+func isTeXSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// isTeXSpecial 判断是否为需要单独处理的字符
+func isTeXSpecial(c byte) bool {
+	return isTeXSpace(c) || strings.IndexByte("%\\${}~&`'-", c) >= 0
+}
@@ -0,0 +1,198 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+func TestLaTeX_Inline(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"emphasis", `It is \textbf{crucial} to \emph{delve} into this.`, "It is crucial to delve into this."},
+		{"citation", `As shown in prior work~\cite{smith2020}, the results hold \citep[p.~3]{a,b}.`, "As shown in prior work, the results hold."},
+		{"cross reference", `as described in Section~\ref{sec:intro}.`, "as described in Section."},
+		{"inline math", `where $x_i$ denotes the input and \(y\) the output`, "where denotes the input and the output"},
+		{"display math", `Thus \[ a^2 + b^2 \] and $$c$$ hold.`, "Thus and hold."},
+		{"comment", "Keep this % drop this\nand this", "Keep this and this"},
+		{"comment joins lines", "long%\nword", "longword"},
+		{"escapes", `Costs \$5 \& 10\% more`, "Costs $5 & 10% more"},
+		{"ligatures", "``Quoted'' text --- pages 1--3", "“Quoted” text — pages 1–3"},
+		{"accents", `caf\'e na\"{i}ve`, "café naïve"},
+		{"href", `see \href{https://x.io}{the docs} now`, "see the docs now"},
+		{"footnote", `A claim\footnote{Details here.} stands.`, "A claim stands."},
+		{"verb", `use \verb|x=1| here`, "use here"},
+		{"cjk", `这是\textbf{重要}的内容，如文献\cite{a}所述。`, "这是重要的内容，如文献所述。"},
+		{"symbols", `\LaTeX{} is great\ldots`, "LaTeX is great…"},
+		{"unknown command", `\hl{highlighted} text \noindent here`, "highlighted text here"},
+		{"line break", `first\\second\\[2pt]third`, "first second third"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := ExtractAs(FormatLaTeX, []byte(tt.input), Options{})
+			if err != nil {
+				t.Fatalf("ExtractAs() error = %v", err)
+			}
+			if doc.Text != tt.want {
+				t.Errorf("Text = %q, want %q", doc.Text, tt.want)
+			}
+			assertSourceMapping(t, doc)
+		})
+	}
+}
+
+func TestLaTeX_Document(t *testing.T) {
+	data := `\documentclass{article}
+\usepackage{amsmath}
+\newcommand{\R}{\mathbb{R}}
+\title{A Study of \emph{Things}}
+\author{Someone}
+\begin{document}
+\maketitle
+
+\begin{abstract}
+We study things.
+\end{abstract}
+
+\section{Introduction}\label{sec:intro}
+It is crucial to delve
+into this topic.\footnote{See the appendix.}
+% a commented line
+
+Second paragraph with math
+\begin{equation}
+  E = mc^2
+\end{equation}
+that continues.
+
+\begin{figure}[t]
+  \includegraphics{plot.pdf}
+  \caption{A plot that should not be analyzed.}
+\end{figure}
+
+\subsection[Short]{Related Work}
+\begin{itemize}
+  \item First point.
+  \item[b)] Second point.
+\end{itemize}
+\begin{quote}
+Quoted text.
+\end{quote}
+\begin{table}
+\begin{tabular}{ll} a & b \\ \end{tabular}
+\end{table}
+\begin{comment}
+Hidden draft.
+\end{comment}
+
+\section*{Conclusion}
+Final words.
+
+\begin{thebibliography}{9}
+\bibitem{a} A. Author. Some Title. 2020.
+\end{thebibliography}
+\end{document}
+Trailing notes.
+`
+	doc, err := Extract("paper.tex", []byte(data), Options{Footnotes: true})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	want := []struct {
+		text    string
+		kind    string
+		level   int
+		line    int
+		section string
+	}{
+		{"A Study of Things", KindHeading, 0, 4, ""},
+		{"We study things.", KindParagraph, 0, 10, ""},
+		{"Introduction", KindHeading, 2, 13, "1"},
+		{"It is crucial to delve\ninto this topic.", KindParagraph, 0, 14, "1"},
+		{"Second paragraph with math\nthat continues.", KindParagraph, 0, 18, "1"},
+		{"Related Work", KindHeading, 3, 29, "1.1"},
+		{"First point.", KindListItem, 0, 31, "1.1"},
+		{"Second point.", KindListItem, 0, 32, "1.1"},
+		{"Quoted text.", KindQuote, 0, 35, "1.1"},
+		{"Conclusion", KindHeading, 2, 44, "2"},
+		{"Final words.", KindParagraph, 0, 45, "2"},
+		{"See the appendix.", KindFootnote, 0, 15, ""},
+	}
+	got := paragraphTexts(doc)
+	if len(got) != len(want) {
+		t.Fatalf("paragraphs = %q, want %d", got, len(want))
+	}
+	for i, w := range want {
+		p := doc.Paragraphs[i]
+		if got[i] != w.text || p.Kind != w.kind || p.Level != w.level || p.Line != w.line || p.Section != w.section {
+			t.Errorf("Paragraphs[%d] = %q %s/%d line %d §%q, want %q %s/%d line %d §%q",
+				i, got[i], p.Kind, p.Level, p.Line, p.Section, w.text, w.kind, w.level, w.line, w.section)
+		}
+	}
+	for _, skipped := range []string{"amsmath", "Someone", "mc^2", "plot", "Hidden", "Some Title", "Trailing", "sec:intro"} {
+		if strings.Contains(doc.Text, skipped) {
+			t.Errorf("Text contains %q, want it skipped", skipped)
+		}
+	}
+	assertSourceMapping(t, doc)
+
+	body, err := Extract("paper.tex", []byte(data), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if strings.Contains(body.Text, "appendix") {
+		t.Errorf("footnote extracted without Options.Footnotes: %q", body.Text)
+	}
+}
+
+func TestLaTeX_MapResult(t *testing.T) {
+	source := "\\section{Intro}\nIt is \\textbf{crucial} to\n  delve~\\cite{x} deeper.\n"
+	doc, err := ExtractAs(FormatLaTeX, []byte(source), Options{})
+	if err != nil {
+		t.Fatalf("ExtractAs() error = %v", err)
+	}
+
+	position := func(substr string) models.Position {
+		offset := strings.Index(doc.Text, substr)
+		if offset < 0 {
+			t.Fatalf("%q not found in %q", substr, doc.Text)
+		}
+		return models.Position{Offset: offset, Length: len(substr)}
+	}
+	result := &models.DetectionResult{
+		Text: doc.Text,
+		RuleResults: []models.RuleResult{{Matches: []models.Match{
+			{Text: "crucial", Position: position("crucial")},
+			{Text: "crucial to\ndelve", Position: position("crucial to\ndelve")},
+			{Text: "deeper", Position: position("deeper")},
+		}}},
+	}
+	doc.MapResult(result)
+
+	if result.Text != source {
+		t.Errorf("Text = %q, want source", result.Text)
+	}
+	tests := []struct {
+		pos          models.Position
+		want         string
+		line, column int
+	}{
+		{result.RuleResults[0].Matches[0].Position, "crucial", 2, 15},
+		{result.RuleResults[0].Matches[1].Position, "crucial} to\n  delve", 2, 15},
+		{result.RuleResults[0].Matches[2].Position, "deeper", 3, 18},
+	}
+	for _, tt := range tests {
+		if got := source[tt.pos.Offset : tt.pos.Offset+tt.pos.Length]; got != tt.want {
+			t.Errorf("mapped span = %q, want %q", got, tt.want)
+		}
+		if tt.pos.Line != tt.line || tt.pos.Column != tt.column {
+			t.Errorf("%q at line %d column %d, want %d:%d", tt.want, tt.pos.Line, tt.pos.Column, tt.line, tt.column)
+		}
+	}
+}
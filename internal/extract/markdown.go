@@ -0,0 +1,727 @@
+package extract
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+func init() {
+	register(FormatMarkdown, markdownExtractor{}, ".md", ".markdown", ".mdown")
+}
+
+// markdownExtractor Markdown 提取器
+// 将文档解析为块和行内结构，只保留正文（标题、段落、列表、引用），跳过代码块、front matter、表格、
+// HTML 块和链接地址，去除强调等格式标记；提取文本中的位置可以通过 SourceOffset 映射回原文件
+type markdownExtractor struct{}
+
+// Extract 提取 Markdown 正文
+func (markdownExtractor) Extract(data []byte, _ Options) (*Document, error) {
+	source, err := decodeText(data)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &mdParser{source: source}
+	lines := p.splitLines()
+	lines = p.skipFrontMatter(lines)
+	p.parseBlocks(lines, "")
+
+	doc := p.b.document()
+	doc.Source = source
+	return doc, nil
+}
+
+// mdLine 块解析中的一行：原文件中 [start, end) 的内容，不含换行符
+type mdLine struct {
+	start, end int
+	number     int // 在原文件中的行号，从 1 开始
+}
+
+// mdParser Markdown 解析器
+type mdParser struct {
+	source string
+	b      builder
+}
+
+// markdown 块语法
+var (
+	mdFenceRe       = regexp.MustCompile("^(`{3,}|~{3,})")
+	mdHeadingRe     = regexp.MustCompile(`^(#{1,6})(?:[ \t]+|$)`)
+	mdThematicRe    = regexp.MustCompile(`^(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	mdBulletRe      = regexp.MustCompile(`^([-*+])(?:[ \t]+|$)`)
+	mdOrderedRe     = regexp.MustCompile(`^(\d{1,9})[.)](?:[ \t]+|$)`)
+	mdTaskRe        = regexp.MustCompile(`^\[[ xX]\][ \t]+`)
+	mdFootnoteRe    = regexp.MustCompile(`^\[\^[^\]]+\]:[ \t]*`)
+	mdLinkDefRe     = regexp.MustCompile(`^\[[^\]^][^\]]*\]:[ \t]*\S`)
+	mdTableDelimRe  = regexp.MustCompile(`^\|?[ \t]*:?-+:?[ \t]*(\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	mdHTMLBlockRe   = regexp.MustCompile(`^</?[A-Za-z][A-Za-z0-9-]*(?:[ \t/>]|$)`)
+	mdSetextRe      = regexp.MustCompile(`^(?:=+|-+)[ \t]*$`)
+	mdClosingHashRe = regexp.MustCompile(`[ \t]+#+[ \t]*$`)
+)
+
+// splitLines 将原文件拆分为行
+func (p *mdParser) splitLines() []mdLine {
+	var lines []mdLine
+	start := 0
+	for number := 1; start <= len(p.source); number++ {
+		end := strings.IndexByte(p.source[start:], '\n')
+		if end < 0 {
+			if start < len(p.source) {
+				lines = append(lines, mdLine{start: start, end: len(p.source), number: number})
+			}
+			break
+		}
+		lines = append(lines, mdLine{start: start, end: start + end, number: number})
+		start += end + 1
+	}
+	return lines
+}
+
+// skipFrontMatter 跳过文件开头的 YAML（---）或 TOML（+++）front matter
+func (p *mdParser) skipFrontMatter(lines []mdLine) []mdLine {
+	if len(lines) == 0 {
+		return lines
+	}
+	open := strings.TrimRight(p.text(lines[0]), " \t")
+	if open != "---" && open != "+++" {
+		return lines
+	}
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimRight(p.text(lines[i]), " \t")
+		if line == open || (open == "---" && line == "...") {
+			return lines[i+1:]
+		}
+	}
+	return lines
+}
+
+// text 返回行的内容
+func (p *mdParser) text(line mdLine) string {
+	return p.source[line.start:line.end]
+}
+
+// indent 返回行首缩进宽度（制表符按 4 列计）和缩进的字节数
+func (p *mdParser) indent(line mdLine) (int, int) {
+	width := 0
+	for i := line.start; i < line.end; i++ {
+		switch p.source[i] {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return width, i - line.start
+		}
+	}
+	return width, line.end - line.start
+}
+
+// trimmed 去除行首缩进后的行
+func (p *mdParser) trimmed(line mdLine) mdLine {
+	_, n := p.indent(line)
+	line.start += n
+	return line
+}
+
+// blank 判断是否为空行
+func (p *mdParser) blank(line mdLine) bool {
+	return strings.TrimSpace(p.text(line)) == ""
+}
+
+// listMarker 判断行是否以列表标记开头，返回标记（含其后空白）的字节数
+func listMarker(text string) (int, bool) {
+	if m := mdBulletRe.FindString(text); m != "" {
+		return len(m), true
+	}
+	if m := mdOrderedRe.FindString(text); m != "" {
+		return len(m), true
+	}
+	return 0, false
+}
+
+// interrupts 判断行是否开始一个能打断段落的新块
+func (p *mdParser) interrupts(line mdLine) bool {
+	width, _ := p.indent(line)
+	if width >= 4 {
+		return false
+	}
+	text := p.text(p.trimmed(line))
+	switch {
+	case mdFenceRe.MatchString(text), mdHeadingRe.MatchString(text), mdThematicRe.MatchString(text),
+		strings.HasPrefix(text, ">"), mdHTMLBlockRe.MatchString(text), strings.HasPrefix(text, "<!--"):
+		return true
+	case mdBulletRe.MatchString(text) && strings.TrimSpace(text) != strings.TrimSpace(text[:1]):
+		return true
+	}
+	// 有序列表只有从 1 开始时才能打断段落，避免 "2019. 年" 之类的正文被误判
+	if m := mdOrderedRe.FindStringSubmatch(text); m != nil && m[1] == "1" {
+		return true
+	}
+	return false
+}
+
+// parseBlocks 解析块结构，kind 不为空时所有正文块都使用该类型（用于引用和列表项内的内容）
+func (p *mdParser) parseBlocks(lines []mdLine, kind string) {
+	inList := false
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		if p.blank(line) {
+			i++
+			continue
+		}
+
+		width, _ := p.indent(line)
+		inner := p.trimmed(line)
+		text := p.text(inner)
+
+		switch {
+		// 缩进代码块
+		case width >= 4 && !inList:
+			for i < len(lines) {
+				if w, _ := p.indent(lines[i]); w < 4 && !p.blank(lines[i]) {
+					break
+				}
+				i++
+			}
+			continue
+
+		// 围栏代码块
+		case mdFenceRe.MatchString(text):
+			fence := mdFenceRe.FindString(text)
+			i++
+			for i < len(lines) {
+				closing := strings.TrimRight(p.text(p.trimmed(lines[i])), " \t")
+				i++
+				if strings.HasPrefix(closing, fence) && strings.Trim(closing, fence[:1]) == "" {
+					break
+				}
+			}
+			continue
+
+		// HTML 注释
+		case strings.HasPrefix(text, "<!--"):
+			for i < len(lines) {
+				done := strings.Contains(p.text(lines[i]), "-->")
+				i++
+				if done {
+					break
+				}
+			}
+			continue
+
+		// HTML 块：到空行为止
+		case mdHTMLBlockRe.MatchString(text):
+			for i < len(lines) && !p.blank(lines[i]) {
+				i++
+			}
+			continue
+
+		// 分隔线
+		case mdThematicRe.MatchString(text):
+			i++
+			continue
+
+		// 链接引用定义
+		case mdLinkDefRe.MatchString(text):
+			i++
+			continue
+
+		// ATX 标题
+		case mdHeadingRe.MatchString(text):
+			marker := mdHeadingRe.FindStringSubmatch(text)
+			inner.start += len(marker[0])
+			if loc := mdClosingHashRe.FindStringIndex(p.text(inner)); loc != nil {
+				inner.end = inner.start + loc[0]
+			} else if strings.Trim(p.text(inner), "#") == "" {
+				inner.end = inner.start
+			}
+			p.emit(Paragraph{Kind: KindHeading, Level: len(marker[1]), Line: line.number}, []mdLine{inner})
+			inList = false
+			i++
+			continue
+
+		// 表格：表头行后跟分隔行，到空行或不含竖线的行为止
+		case strings.Contains(text, "|") && i+1 < len(lines) && mdTableDelimRe.MatchString(strings.TrimSpace(p.text(lines[i+1]))):
+			i += 2
+			for i < len(lines) && !p.blank(lines[i]) && strings.Contains(p.text(lines[i]), "|") {
+				i++
+			}
+			continue
+
+		// 引用
+		case strings.HasPrefix(text, ">"):
+			var content []mdLine
+			for i < len(lines) && !p.blank(lines[i]) {
+				l := p.trimmed(lines[i])
+				if strings.HasPrefix(p.text(l), ">") {
+					l.start++
+					if l.start < l.end && (p.source[l.start] == ' ' || p.source[l.start] == '\t') {
+						l.start++
+					}
+				} else if len(content) > 0 && p.interrupts(lines[i]) {
+					break
+				}
+				content = append(content, l)
+				i++
+			}
+			p.parseBlocks(content, orKind(kind, KindQuote))
+			inList = false
+			continue
+
+		// 脚注定义：内容作为普通段落
+		case mdFootnoteRe.MatchString(text):
+			markerLen := len(mdFootnoteRe.FindString(text))
+			content, next := p.collectItem(lines, i, width, markerLen)
+			p.parseBlocks(content, orKind(kind, KindParagraph))
+			i = next
+			continue
+		}
+
+		// 列表项
+		if markerLen, ok := listMarker(text); ok {
+			content, next := p.collectItem(lines, i, width, markerLen)
+			if len(content) > 0 {
+				if m := mdTaskRe.FindString(p.text(content[0])); m != "" {
+					content[0].start += len(m)
+				}
+			}
+			p.parseBlocks(content, orKind(kind, KindListItem))
+			inList = true
+			i = next
+			continue
+		}
+
+		// 段落：到空行或能打断段落的块为止，其后是 setext 下划线时为标题
+		var content []mdLine
+		heading := 0
+		for i < len(lines) && !p.blank(lines[i]) {
+			if len(content) > 0 {
+				next := p.text(p.trimmed(lines[i]))
+				if w, _ := p.indent(lines[i]); w < 4 && mdSetextRe.MatchString(next) {
+					heading = 2
+					if next[0] == '=' {
+						heading = 1
+					}
+					i++
+					break
+				}
+				if p.interrupts(lines[i]) {
+					break
+				}
+			}
+			content = append(content, p.trimmed(lines[i]))
+			i++
+		}
+		if heading > 0 {
+			p.emit(Paragraph{Kind: KindHeading, Level: heading, Line: content[0].number}, content)
+		} else {
+			p.emit(Paragraph{Kind: orKind(kind, KindParagraph), Line: content[0].number}, content)
+		}
+		if width < 2 {
+			inList = false
+		}
+	}
+}
+
+// collectItem 收集列表项（或脚注）的内容行，去除标记和续行缩进，返回内容行和下一行的位置
+func (p *mdParser) collectItem(lines []mdLine, i, indent, markerLen int) ([]mdLine, int) {
+	first := p.trimmed(lines[i])
+	first.start += markerLen
+	content := []mdLine{first}
+	contentIndent := indent + markerLen
+
+	blankBefore := false
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		if p.blank(line) {
+			blankBefore = true
+			content = append(content, line)
+			continue
+		}
+		width, n := p.indent(line)
+		switch {
+		case width >= contentIndent && width > indent:
+			// 续行：去除内容缩进，保留更深的缩进以便识别嵌套的代码块
+			strip := 0
+			for col := 0; strip < n && col < contentIndent; strip++ {
+				if p.source[line.start+strip] == '\t' {
+					col += 4 - col%4
+				} else {
+					col++
+				}
+			}
+			line.start += strip
+		case blankBefore || p.interrupts(line):
+			return trimBlankLines(p, content), i
+		default:
+			// 惰性续行
+			line = p.trimmed(line)
+		}
+		blankBefore = false
+		content = append(content, line)
+	}
+	return trimBlankLines(p, content), i
+}
+
+// trimBlankLines 去除末尾的空行
+func trimBlankLines(p *mdParser, lines []mdLine) []mdLine {
+	for len(lines) > 0 && p.blank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// orKind 容器内的块使用容器的类型
+func orKind(container, kind string) string {
+	if container != "" {
+		return container
+	}
+	return kind
+}
+
+// emit 解析一个正文块的行内结构并追加到提取结果
+func (p *mdParser) emit(para Paragraph, lines []mdLine) {
+	// 将各行连接为一个虚拟字符串，记录每个字节在原文件中的位置，行之间的换行符对应原文件中的行尾
+	var v strings.Builder
+	var positions []int
+	for i, line := range lines {
+		if i > 0 {
+			v.WriteByte('\n')
+			positions = append(positions, lines[i-1].end)
+		}
+		v.WriteString(p.source[line.start:line.end])
+		for j := line.start; j < line.end; j++ {
+			positions = append(positions, j)
+		}
+	}
+
+	inline := &mdInline{text: v.String()}
+	inline.parse(0, len(inline.text))
+
+	var pieces []mappedText
+	for _, piece := range inline.pieces {
+		if piece.start >= piece.end {
+			continue
+		}
+		if piece.replace {
+			first, last := positions[piece.start], positions[piece.end-1]
+			pieces = append(pieces, mappedText{text: piece.text, source: first, sourceLength: last - first + 1})
+			continue
+		}
+		// 逐字节对应的片段按原文件中的连续区间拆分
+		start := piece.start
+		for j := piece.start + 1; j <= piece.end; j++ {
+			if j == piece.end || positions[j] != positions[j-1]+1 {
+				pieces = append(pieces, mappedText{
+					text:         inline.text[start:j],
+					source:       positions[start],
+					sourceLength: j - start,
+				})
+				start = j
+			}
+		}
+	}
+
+	p.b.addMapped(para, pieces)
+}
+
+// mdPiece 行内解析的输出片段
+type mdPiece struct {
+	start, end int    // 在虚拟字符串中的范围
+	text       string // 替换文本
+	replace    bool   // 是否以 text 替换该范围
+
+	// 强调定界符
+	delim               byte
+	canOpen, canClose   bool
+	usedOpen, usedClose int // 作为开始（从右侧）和结束（从左侧）定界符被消耗的字节数
+}
+
+// mdInline 行内结构解析器
+type mdInline struct {
+	text   string
+	pieces []mdPiece
+}
+
+// parse 解析 [start, end) 范围内的行内结构，链接文本递归解析，强调定界符在各自范围内配对
+func (in *mdInline) parse(start, end int) {
+	first := len(in.pieces)
+	text := start
+
+	flush := func(i int) {
+		if i > text {
+			in.pieces = append(in.pieces, mdPiece{start: text, end: i})
+		}
+	}
+
+	for i := start; i < end; {
+		c := in.text[i]
+		switch {
+		// 反斜杠转义
+		case c == '\\' && i+1 < end && isASCIIPunct(in.text[i+1]):
+			flush(i)
+			in.pieces = append(in.pieces, mdPiece{start: i, end: i + 2, text: in.text[i+1 : i+2], replace: true})
+			i += 2
+			text = i
+			continue
+
+		// 行内代码：整段跳过
+		case c == '`':
+			run := countRun(in.text[i:end], '`')
+			if closing := findCodeSpanEnd(in.text[i+run:end], run); closing >= 0 {
+				flush(i)
+				i += run + closing + run
+				text = i
+				continue
+			}
+			i += run
+			continue
+
+		// 图片：整体跳过；链接：保留链接文本
+		case c == '!' && i+1 < end && in.text[i+1] == '[':
+			if _, _, next, ok := in.link(i+1, end); ok {
+				flush(i)
+				i = next
+				text = i
+				continue
+			}
+			i++
+			continue
+		case c == '[':
+			if textStart, textEnd, next, ok := in.link(i, end); ok {
+				flush(i)
+				in.parse(textStart, textEnd)
+				i = next
+				text = i
+				continue
+			}
+			i++
+			continue
+
+		// 自动链接、行内 HTML 和 HTML 注释
+		case c == '<':
+			if n := in.angle(i, end); n > 0 {
+				flush(i)
+				i += n
+				text = i
+				continue
+			}
+			i++
+			continue
+
+		// HTML 实体
+		case c == '&':
+			if n := strings.IndexByte(in.text[i:end], ';'); n > 1 && n <= 32 {
+				entity := in.text[i : i+n+1]
+				if decoded := html.UnescapeString(entity); decoded != entity {
+					flush(i)
+					in.pieces = append(in.pieces, mdPiece{start: i, end: i + n + 1, text: decoded, replace: true})
+					i += n + 1
+					text = i
+					continue
+				}
+			}
+			i++
+			continue
+
+		// 强调定界符
+		case c == '*' || c == '_' || c == '~':
+			flush(i)
+			run := countRun(in.text[i:end], c)
+			in.pieces = append(in.pieces, in.delimiter(i, i+run, start, end))
+			i += run
+			text = i
+			continue
+		}
+		i++
+	}
+	flush(end)
+
+	in.matchDelimiters(in.pieces[first:])
+}
+
+// link 解析从 [ 开始的链接，返回链接文本范围和链接之后的位置
+// 支持 [text](url "title")、[text][ref] 和 [text][]，脚注引用 [^1] 返回空的链接文本
+func (in *mdInline) link(i, end int) (int, int, int, bool) {
+	closing := matchBracket(in.text[i:end], '[', ']')
+	if closing < 0 {
+		return 0, 0, 0, false
+	}
+	textStart, textEnd := i+1, i+closing
+	next := textEnd + 1
+
+	if strings.HasPrefix(in.text[textStart:textEnd], "^") {
+		return textStart, textStart, next, true
+	}
+	if next < end && in.text[next] == '(' {
+		if n := matchBracket(in.text[next:end], '(', ')'); n >= 0 {
+			return textStart, textEnd, next + n + 1, true
+		}
+	}
+	if next < end && in.text[next] == '[' {
+		if n := strings.IndexByte(in.text[next:end], ']'); n >= 0 {
+			return textStart, textEnd, next + n + 1, true
+		}
+	}
+	return 0, 0, 0, false
+}
+
+// mdAutolinkRe 自动链接
+var mdAutolinkRe = regexp.MustCompile(`^<(?:[A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*|[A-Za-z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[A-Za-z0-9.-]+)>`)
+
+// mdInlineHTMLRe 行内 HTML 标签
+var mdInlineHTMLRe = regexp.MustCompile(`^</?[A-Za-z][A-Za-z0-9-]*(?:\s+[A-Za-z_:][\w.:-]*(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?)*\s*/?>`)
+
+// angle 匹配从 < 开始的自动链接、行内 HTML 标签或注释，返回其长度
+func (in *mdInline) angle(i, end int) int {
+	rest := in.text[i:end]
+	if strings.HasPrefix(rest, "<!--") {
+		if n := strings.Index(rest[4:], "-->"); n >= 0 {
+			return 4 + n + 3
+		}
+		return 0
+	}
+	if m := mdAutolinkRe.FindString(rest); m != "" {
+		return len(m)
+	}
+	return len(mdInlineHTMLRe.FindString(rest))
+}
+
+// delimiter 创建强调定界符片段并判断其能否作为开始或结束定界符
+func (in *mdInline) delimiter(start, end, scopeStart, scopeEnd int) mdPiece {
+	prev, next := ' ', ' '
+	if start > scopeStart {
+		prev, _ = utf8.DecodeLastRuneInString(in.text[scopeStart:start])
+	}
+	if end < scopeEnd {
+		next, _ = utf8.DecodeRuneInString(in.text[end:scopeEnd])
+	}
+
+	left := !unicode.IsSpace(next) && (!isPunctRune(next) || unicode.IsSpace(prev) || isPunctRune(prev))
+	right := !unicode.IsSpace(prev) && (!isPunctRune(prev) || unicode.IsSpace(next) || isPunctRune(next))
+
+	piece := mdPiece{start: start, end: end, delim: in.text[start], canOpen: left, canClose: right}
+	switch piece.delim {
+	case '_':
+		// 单词内部的下划线（如 snake_case）不是强调
+		piece.canOpen = left && (!right || isPunctRune(prev))
+		piece.canClose = right && (!left || isPunctRune(next))
+	case '~':
+		// 删除线至少需要两个 ~，单个 ~ 常用于表示范围
+		if end-start < 2 {
+			piece.canOpen, piece.canClose = false, false
+		}
+	}
+	return piece
+}
+
+// matchDelimiters 配对强调定界符，配对成功的定界符从输出中去除
+func (in *mdInline) matchDelimiters(pieces []mdPiece) {
+	var openers []int
+	for i := range pieces {
+		closer := &pieces[i]
+		if closer.delim == 0 {
+			continue
+		}
+		if closer.canClose {
+			for j := len(openers) - 1; j >= 0 && closer.remaining() > 0; j-- {
+				opener := &pieces[openers[j]]
+				if opener.delim != closer.delim || opener.remaining() == 0 {
+					continue
+				}
+				n := min(2, opener.remaining(), closer.remaining())
+				if closer.delim == '~' {
+					n = min(opener.remaining(), closer.remaining())
+				}
+				opener.usedOpen += n
+				closer.usedClose += n
+				// 中间未配对的开始定界符不再参与配对
+				openers = openers[:j+1]
+				if opener.remaining() == 0 {
+					openers = openers[:j]
+				}
+				j = len(openers)
+			}
+		}
+		if closer.canOpen && closer.remaining() > 0 {
+			openers = append(openers, i)
+		}
+	}
+
+	for i := range pieces {
+		piece := &pieces[i]
+		if piece.delim != 0 {
+			piece.start += piece.usedClose
+			piece.end -= piece.usedOpen
+		}
+	}
+}
+
+// remaining 定界符未被消耗的字节数
+func (p *mdPiece) remaining() int {
+	return p.end - p.start - p.usedOpen - p.usedClose
+}
+
+// countRun 统计字符串开头连续出现的字符 c 的个数
+func countRun(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
+
+// findCodeSpanEnd 查找与长度为 run 的反引号串配对的结束反引号串，返回其在 s 中的位置
+func findCodeSpanEnd(s string, run int) int {
+	for i := 0; i < len(s); {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		n := countRun(s[i:], '`')
+		if n == run {
+			return i
+		}
+		i += n
+	}
+	return -1
+}
+
+// matchBracket 查找与 s[0] 配对的结束括号，跳过反斜杠转义和行内代码，返回其位置
+func matchBracket(s string, open, close byte) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '`':
+			run := countRun(s[i:], '`')
+			if n := findCodeSpanEnd(s[i+run:], run); n >= 0 {
+				i += run + n + run - 1
+			} else {
+				i += run - 1
+			}
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// isASCIIPunct 判断是否为 ASCII 标点（可被反斜杠转义的字符）
+func isASCIIPunct(c byte) bool {
+	return c < utf8.RuneSelf && unicode.IsPunct(rune(c)) || strings.IndexByte("$+<=>^`|~", c) >= 0
+}
+
+// isPunctRune 判断是否为标点或符号
+func isPunctRune(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
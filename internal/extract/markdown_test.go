@@ -0,0 +1,202 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+func TestMarkdown_Inline(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"emphasis", "It is **crucial** to _delve_ into ~~this~~ it.", "It is crucial to delve into this it."},
+		{"nested emphasis", "***very*** important", "very important"},
+		{"cjk emphasis", "这是**重要**的内容", "这是重要的内容"},
+		{"snake case", "use snake_case_names here", "use snake_case_names here"},
+		{"unmatched", "a ** b and 5 * 3", "a ** b and 5 * 3"},
+		{"single tilde", "from 1~3 days", "from 1~3 days"},
+		{"link", "see [the docs](https://example.com/?utm_source=x \"title\") now", "see the docs now"},
+		{"reference link", "see [the docs][docs] and [more][]", "see the docs and more"},
+		{"emphasis in link", "[**bold** text](url)", "bold text"},
+		{"image", "before ![diagram](img.png) after", "before  after"},
+		{"code span", "call `fmt.Println()` here", "call  here"},
+		{"double code span", "a ``x ` y`` b", "a  b"},
+		{"autolink", "mail <me@example.com> or <https://example.com>", "mail  or"},
+		{"inline html", "a <span class=\"x\">styled</span> word", "a styled word"},
+		{"escape", `not \*emphasis\* here`, "not *emphasis* here"},
+		{"entity", "Tom &amp; Jerry", "Tom & Jerry"},
+		{"footnote ref", "a claim[^1] here", "a claim here"},
+		{"brackets", "array[0] and [note]", "array[0] and [note]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := ExtractAs(FormatMarkdown, []byte(tt.input), Options{})
+			if err != nil {
+				t.Fatalf("ExtractAs() error = %v", err)
+			}
+			if doc.Text != tt.want {
+				t.Errorf("Text = %q, want %q", doc.Text, tt.want)
+			}
+			assertSourceMapping(t, doc)
+		})
+	}
+}
+
+func TestMarkdown_Blocks(t *testing.T) {
+	data := `---
+title: Design notes
+tags: [a, b]
+---
+
+# Overview ##
+
+Some *prose* that spans
+two lines.
+
+Setext heading
+--------------
+
+` + "```go\nfunc main() {}\n```" + `
+
+    indented code
+
+| Name | Value |
+|------|-------|
+| a    | b     |
+
+- first item
+- second item
+  continues here
+
+  1. nested ordered
+  - [x] done task
+
+> quoted **text**
+> more quote
+>
+> second quote paragraph
+
+[docs]: https://example.com
+
+<div>
+html block
+</div>
+
+***
+
+Final paragraph.
+`
+	doc, err := Extract("notes.md", []byte(data), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	want := []struct {
+		text  string
+		kind  string
+		level int
+		line  int
+	}{
+		{"Overview", KindHeading, 1, 6},
+		{"Some prose that spans\ntwo lines.", KindParagraph, 0, 8},
+		{"Setext heading", KindHeading, 2, 11},
+		{"first item", KindListItem, 0, 24},
+		{"second item\ncontinues here", KindListItem, 0, 25},
+		{"nested ordered", KindListItem, 0, 28},
+		{"done task", KindListItem, 0, 29},
+		{"quoted text\nmore quote", KindQuote, 0, 31},
+		{"second quote paragraph", KindQuote, 0, 34},
+		{"Final paragraph.", KindParagraph, 0, 44},
+	}
+	got := paragraphTexts(doc)
+	if len(got) != len(want) {
+		t.Fatalf("paragraphs = %q, want %d", got, len(want))
+	}
+	for i, w := range want {
+		p := doc.Paragraphs[i]
+		if got[i] != w.text || p.Kind != w.kind || p.Level != w.level || p.Line != w.line {
+			t.Errorf("Paragraphs[%d] = %q %s/%d line %d, want %q %s/%d line %d",
+				i, got[i], p.Kind, p.Level, p.Line, w.text, w.kind, w.level, w.line)
+		}
+	}
+	for _, skipped := range []string{"title:", "func main", "indented code", "Name", "https://", "html block"} {
+		if strings.Contains(doc.Text, skipped) {
+			t.Errorf("Text contains %q, want it skipped", skipped)
+		}
+	}
+	assertSourceMapping(t, doc)
+}
+
+func TestMarkdown_MapResult(t *testing.T) {
+	source := "# Notes\n\n> It is **crucial** to\n> delve [deeper](https://x.io).\n"
+	doc, err := ExtractAs(FormatMarkdown, []byte(source), Options{})
+	if err != nil {
+		t.Fatalf("ExtractAs() error = %v", err)
+	}
+
+	position := func(substr string) models.Position {
+		offset := strings.Index(doc.Text, substr)
+		if offset < 0 {
+			t.Fatalf("%q not found in %q", substr, doc.Text)
+		}
+		return models.Position{Offset: offset, Length: len(substr)}
+	}
+	result := &models.DetectionResult{
+		Text: doc.Text,
+		RuleResults: []models.RuleResult{{Matches: []models.Match{
+			{Text: "crucial", Position: position("crucial")},
+			{Text: "delve", Position: position("delve")},
+			{Text: "crucial to\ndelve", Position: position("crucial to\ndelve")},
+		}}},
+		Suggestions: []models.Suggestion{{Anchors: []models.Anchor{{Text: "deeper", Position: position("deeper")}}}},
+	}
+	doc.MapResult(result)
+
+	if result.Text != source {
+		t.Errorf("Text = %q, want source", result.Text)
+	}
+	tests := []struct {
+		pos          models.Position
+		want         string
+		line, column int
+	}{
+		{result.RuleResults[0].Matches[0].Position, "crucial", 3, 11},
+		{result.RuleResults[0].Matches[1].Position, "delve", 4, 3},
+		{result.RuleResults[0].Matches[2].Position, "crucial** to\n> delve", 3, 11},
+		{result.Suggestions[0].Anchors[0].Position, "deeper", 4, 10},
+	}
+	for _, tt := range tests {
+		if got := source[tt.pos.Offset : tt.pos.Offset+tt.pos.Length]; got != tt.want {
+			t.Errorf("mapped span = %q, want %q", got, tt.want)
+		}
+		if tt.pos.Line != tt.line || tt.pos.Column != tt.column {
+			t.Errorf("%q at line %d column %d, want %d:%d", tt.want, tt.pos.Line, tt.pos.Column, tt.line, tt.column)
+		}
+	}
+
+	// 没有位置映射的格式不做修改
+	plain, _ := ExtractAs(FormatText, []byte("It is crucial."), Options{})
+	unchanged := &models.DetectionResult{Text: plain.Text, RuleResults: []models.RuleResult{{Matches: []models.Match{{Position: models.Position{Offset: 6, Length: 7}}}}}}
+	plain.MapResult(unchanged)
+	if unchanged.RuleResults[0].Matches[0].Position.Offset != 6 {
+		t.Errorf("text format position changed: %+v", unchanged.RuleResults[0].Matches[0].Position)
+	}
+}
+
+// assertSourceMapping 检查逐字节映射的段落文本与原文件中对应位置的字符一致
+func assertSourceMapping(t *testing.T, doc *Document) {
+	t.Helper()
+	for _, seg := range doc.segments {
+		if seg.length != seg.sourceLength {
+			continue
+		}
+		if got, want := doc.Text[seg.offset:seg.offset+seg.length], doc.Source[seg.source:seg.source+seg.sourceLength]; got != want {
+			t.Errorf("segment %+v maps %q to %q", seg, got, want)
+		}
+	}
+}
@@ -0,0 +1,517 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	register(FormatODT, odtExtractor{}, ".odt")
+}
+
+// ODF 命名空间
+const (
+	odtOfficeNamespace = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	odtTextNamespace   = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+	odtTableNamespace  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	odtStyleNamespace  = "urn:oasis:names:tc:opendocument:xmlns:style:1.0"
+	dcNamespace        = "http://purl.org/dc/elements/1.1/"
+)
+
+// odtSpaces 连续空白
+var odtSpaces = regexp.MustCompile(`[ \t\r\n]+`)
+
+// odtMimetype ODF 文本文档的 MIME 类型
+const odtMimetype = "application/vnd.oasis.opendocument.text"
+
+// odtSkipped 不提取内容的元素：目录、索引等自动生成的内容，以及脚注的编号
+var odtSkipped = map[string]bool{
+	"table-of-content":   true,
+	"illustration-index": true,
+	"table-index":        true,
+	"object-index":       true,
+	"user-index":         true,
+	"alphabetical-index": true,
+	"bibliography":       true,
+	"note-citation":      true,
+	"sequence-decls":     true,
+}
+
+// odtExtractor ODT 提取器：按段落提取正文，识别标题、列表和表格，可选提取脚注、尾注和批注
+type odtExtractor struct{}
+
+// Extract 提取 ODT 正文
+func (odtExtractor) Extract(data []byte, opts Options) (*Document, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := readZipEntry(archive, "content.xml")
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		return nil, errors.New("content.xml not found")
+	}
+
+	// 段落引用的自动样式定义在 content.xml 中，其父样式定义在 styles.xml 中
+	styles := make(map[string]odtStyle)
+	shared, err := readZipEntry(archive, "styles.xml")
+	if err != nil {
+		return nil, err
+	}
+	for _, part := range [][]byte{shared, content} {
+		if err := parseODTStyles(part, styles); err != nil {
+			return nil, fmt.Errorf("failed to parse styles: %w", err)
+		}
+	}
+
+	parser := &odtParser{styles: styles, opts: opts, b: &builder{}, changes: map[string]*Revision{}}
+	if err := parser.parse(content); err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+
+	doc := parser.b.document()
+	doc.Revisions = parser.revisions
+	return doc, nil
+}
+
+// odtStyle 段落样式
+type odtStyle struct {
+	parent    string
+	display   string
+	automatic bool // 自动样式（如 P1）只是对父样式的局部修改，不代表用户选择的样式
+}
+
+// parseODTStyles 解析样式定义
+func parseODTStyles(data []byte, styles map[string]odtStyle) error {
+	if data == nil {
+		return nil
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	automatic := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Space == odtOfficeNamespace && t.Name.Local == "automatic-styles":
+				automatic = true
+			case t.Name.Space == odtStyleNamespace && t.Name.Local == "style":
+				styles[odtAttr(t, odtStyleNamespace, "name")] = odtStyle{
+					parent:    odtAttr(t, odtStyleNamespace, "parent-style-name"),
+					display:   odtAttr(t, odtStyleNamespace, "display-name"),
+					automatic: automatic,
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Space == odtOfficeNamespace && t.Name.Local == "automatic-styles" {
+				automatic = false
+			}
+		}
+	}
+}
+
+// odtStyleName 返回段落样式的显示名称，自动样式取其父样式
+func odtStyleName(styles map[string]odtStyle, name string) string {
+	for range 16 {
+		style, ok := styles[name]
+		if !ok || !style.automatic || style.parent == "" {
+			if ok && !style.automatic && style.display != "" {
+				return style.display
+			}
+			break
+		}
+		name = style.parent
+	}
+	// 样式名中的特殊字符编码为 _xx_，如空格为 _20_
+	return strings.ReplaceAll(name, "_20_", " ")
+}
+
+// odtParagraph 正在解析的段落
+type odtParagraph struct {
+	text    strings.Builder
+	style   string
+	outline int // text:h 的大纲级别，0 表示普通段落
+}
+
+// odtNote 正在解析的脚注或批注
+type odtNote struct {
+	kind       string
+	number     int
+	author     string
+	paragraphs []*odtParagraph // 当前嵌套的段落，内层在后
+	finished   []odtNoteParagraph
+}
+
+// odtNoteParagraph 注释中已结束的段落
+type odtNoteParagraph struct {
+	info Paragraph
+	text string
+}
+
+// odtParser 解析 content.xml
+type odtParser struct {
+	styles    map[string]odtStyle
+	opts      Options
+	b         *builder
+	revisions []Revision
+
+	paragraphs []*odtParagraph // 正文中当前嵌套的段落（文本框可嵌套段落），内层在后
+	notes      []*odtNote      // 当前嵌套的注释，内层在后
+	footnotes  []odtNoteParagraph
+	comments   []odtNoteParagraph
+	numbers    map[string]int // 各类注释已分配的编号
+
+	lists, tables int
+
+	changes   map[string]*Revision // text:tracked-changes 中登记的修订
+	inserting map[string]*Revision // 正文中尚未结束的插入
+	pending   []Revision           // 当前正文段落中已结束的修订
+
+	capture *string // 非 nil 时文本写入此处（作者、时间）
+}
+
+// parse 流式解析文档内容
+func (d *odtParser) parse(data []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	d.numbers = map[string]int{}
+	d.inserting = map[string]*Revision{}
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if err := d.start(decoder, t); err != nil {
+				return err
+			}
+		case xml.CharData:
+			// ODF 中文本里的空白折叠为一个空格，连续空格、制表符和换行由 text:s 等元素表示
+			d.write(odtSpaces.ReplaceAllString(string(t), " "))
+		case xml.EndElement:
+			d.end(t)
+		}
+	}
+
+	for _, list := range [][]odtNoteParagraph{d.footnotes, d.comments} {
+		for _, p := range list {
+			d.b.add(p.info, p.text)
+		}
+	}
+	return nil
+}
+
+// start 处理开始标签
+func (d *odtParser) start(decoder *xml.Decoder, t xml.StartElement) error {
+	switch t.Name.Space {
+	case odtTextNamespace:
+		if odtSkipped[t.Name.Local] {
+			return decoder.Skip()
+		}
+		switch t.Name.Local {
+		case "p", "h":
+			p := &odtParagraph{style: odtAttr(t, odtTextNamespace, "style-name")}
+			if t.Name.Local == "h" {
+				p.outline = 1
+				if level, err := strconv.Atoi(odtAttr(t, odtTextNamespace, "outline-level")); err == nil && level > 0 {
+					p.outline = level
+				}
+			}
+			d.pushParagraph(p)
+		case "list":
+			d.lists++
+		case "note":
+			// 脚注和尾注（text:note-class）统一编号
+			if !d.opts.Footnotes {
+				return decoder.Skip()
+			}
+			d.numbers[KindFootnote]++
+			d.notes = append(d.notes, &odtNote{kind: KindFootnote, number: d.numbers[KindFootnote]})
+		case "s":
+			count := 1
+			if n, err := strconv.Atoi(odtAttr(t, odtTextNamespace, "c")); err == nil && n > 0 {
+				count = n
+			}
+			d.write(strings.Repeat(" ", count))
+		case "tab":
+			d.write("\t")
+		case "line-break":
+			d.write("\n")
+		case "tracked-changes":
+			return d.parseChanges(decoder)
+		case "change-start":
+			if r, ok := d.changes[odtAttr(t, odtTextNamespace, "change-id")]; ok && r.Type == RevisionInsert {
+				insertion := *r
+				d.inserting[odtAttr(t, odtTextNamespace, "change-id")] = &insertion
+			}
+		case "change-end":
+			id := odtAttr(t, odtTextNamespace, "change-id")
+			if r, ok := d.inserting[id]; ok {
+				delete(d.inserting, id)
+				d.finishRevision(*r)
+			}
+		case "change":
+			if r, ok := d.changes[odtAttr(t, odtTextNamespace, "change-id")]; ok && r.Type == RevisionDelete {
+				d.finishRevision(*r)
+			}
+		}
+	case odtTableNamespace:
+		if t.Name.Local == "table" {
+			d.tables++
+		}
+	case odtOfficeNamespace:
+		if t.Name.Local == "annotation" {
+			if !d.opts.Comments {
+				return decoder.Skip()
+			}
+			d.numbers[KindComment]++
+			d.notes = append(d.notes, &odtNote{kind: KindComment, number: d.numbers[KindComment]})
+		}
+	case dcNamespace:
+		if t.Name.Local == "creator" && len(d.notes) > 0 && d.notes[len(d.notes)-1].kind == KindComment {
+			d.capture = &d.notes[len(d.notes)-1].author
+		}
+	}
+	return nil
+}
+
+// end 处理结束标签
+func (d *odtParser) end(t xml.EndElement) {
+	switch t.Name.Space {
+	case odtTextNamespace:
+		switch t.Name.Local {
+		case "p", "h":
+			d.popParagraph()
+		case "list":
+			d.lists--
+		case "note":
+			d.popNote(&d.footnotes)
+		}
+	case odtTableNamespace:
+		if t.Name.Local == "table" {
+			d.tables--
+		}
+	case odtOfficeNamespace:
+		if t.Name.Local == "annotation" {
+			d.popNote(&d.comments)
+		}
+	case dcNamespace:
+		d.capture = nil
+	}
+}
+
+// write 将文本写入当前段落，同时计入尚未结束的插入修订
+func (d *odtParser) write(text string) {
+	if d.capture != nil {
+		*d.capture += text
+		return
+	}
+	p := d.current()
+	if p == nil {
+		return
+	}
+	p.text.WriteString(text)
+	if len(d.notes) == 0 {
+		for _, r := range d.inserting {
+			r.Text += text
+		}
+	}
+}
+
+// current 返回当前正在解析的段落
+func (d *odtParser) current() *odtParagraph {
+	paragraphs := d.paragraphs
+	if n := len(d.notes); n > 0 {
+		paragraphs = d.notes[n-1].paragraphs
+	}
+	if len(paragraphs) == 0 {
+		return nil
+	}
+	return paragraphs[len(paragraphs)-1]
+}
+
+// pushParagraph 开始一个段落
+func (d *odtParser) pushParagraph(p *odtParagraph) {
+	if n := len(d.notes); n > 0 {
+		d.notes[n-1].paragraphs = append(d.notes[n-1].paragraphs, p)
+		return
+	}
+	d.paragraphs = append(d.paragraphs, p)
+}
+
+// popParagraph 结束当前段落：正文段落直接追加，注释段落在注释结束后统一追加到正文之后
+func (d *odtParser) popParagraph() {
+	if n := len(d.notes); n > 0 {
+		note := d.notes[n-1]
+		if len(note.paragraphs) == 0 {
+			return
+		}
+		p := note.paragraphs[len(note.paragraphs)-1]
+		note.paragraphs = note.paragraphs[:len(note.paragraphs)-1]
+		info := d.paragraphInfo(p)
+		info.Kind, info.Level, info.Number, info.Author = note.kind, 0, note.number, note.author
+		note.finished = append(note.finished, odtNoteParagraph{info: info, text: p.text.String()})
+		return
+	}
+
+	if len(d.paragraphs) == 0 {
+		return
+	}
+	p := d.paragraphs[len(d.paragraphs)-1]
+	d.paragraphs = d.paragraphs[:len(d.paragraphs)-1]
+
+	before := len(d.b.paragraphs)
+	d.b.add(d.paragraphInfo(p), p.text.String())
+	index := -1
+	if len(d.b.paragraphs) > before {
+		index = before
+	}
+	if len(d.paragraphs) == 0 {
+		for _, r := range d.pending {
+			r.Paragraph = index
+			d.revisions = append(d.revisions, r)
+		}
+		d.pending = nil
+	}
+}
+
+// popNote 结束当前注释，批注作者在段落之前出现，段落结束后才补上
+func (d *odtParser) popNote(list *[]odtNoteParagraph) {
+	n := len(d.notes)
+	if n == 0 {
+		return
+	}
+	note := d.notes[n-1]
+	d.notes = d.notes[:n-1]
+	for _, p := range note.finished {
+		p.info.Author = note.author
+		*list = append(*list, p)
+	}
+}
+
+// paragraphInfo 根据样式、大纲级别和所在结构判断段落类型
+func (d *odtParser) paragraphInfo(p *odtParagraph) Paragraph {
+	name := odtStyleName(d.styles, p.style)
+	if p.outline > 0 {
+		return Paragraph{Kind: KindHeading, Level: p.outline, Style: name}
+	}
+	return styleParagraphInfo(name, 0, d.lists > 0, d.tables > 0)
+}
+
+// finishRevision 记录一处修订，位于正文段落中时在段落结束后确定段落序号
+func (d *odtParser) finishRevision(r Revision) {
+	if r.Text == "" {
+		return
+	}
+	if len(d.paragraphs) > 0 {
+		d.pending = append(d.pending, r)
+		return
+	}
+	r.Paragraph = len(d.b.paragraphs) - 1
+	d.revisions = append(d.revisions, r)
+}
+
+// parseChanges 解析 text:tracked-changes 中登记的修订：作者、时间以及删除的内容
+func (d *odtParser) parseChanges(decoder *xml.Decoder) error {
+	var (
+		id      string
+		change  *Revision
+		field   *string
+		deleted []string // 删除内容中的段落
+		inPara  bool
+	)
+	for depth := 1; depth > 0; {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case t.Name.Space == odtTextNamespace && t.Name.Local == "changed-region":
+				id = odtAttr(t, odtTextNamespace, "id")
+				if id == "" {
+					id = odtAttr(t, "http://www.w3.org/XML/1998/namespace", "id")
+				}
+				change, deleted = &Revision{}, nil
+			case t.Name.Space == odtTextNamespace && t.Name.Local == "insertion" && change != nil:
+				change.Type = RevisionInsert
+			case t.Name.Space == odtTextNamespace && t.Name.Local == "deletion" && change != nil:
+				change.Type = RevisionDelete
+			case t.Name.Space == odtTextNamespace && (t.Name.Local == "p" || t.Name.Local == "h"):
+				deleted = append(deleted, "")
+				inPara = true
+			case t.Name.Space == odtTextNamespace && t.Name.Local == "s":
+				if inPara {
+					deleted[len(deleted)-1] += " "
+				}
+			case t.Name.Space == dcNamespace && change != nil:
+				switch t.Name.Local {
+				case "creator":
+					field = &change.Author
+				case "date":
+					field = &change.Date
+				}
+			}
+
+		case xml.CharData:
+			switch {
+			case field != nil:
+				*field += string(t)
+			case inPara:
+				deleted[len(deleted)-1] += string(t)
+			}
+
+		case xml.EndElement:
+			depth--
+			switch {
+			case t.Name.Space == dcNamespace:
+				field = nil
+			case t.Name.Space == odtTextNamespace && (t.Name.Local == "p" || t.Name.Local == "h"):
+				inPara = false
+			case t.Name.Space == odtTextNamespace && t.Name.Local == "changed-region" && change != nil:
+				if change.Type != "" {
+					change.Text = strings.Join(deleted, "\n")
+					d.changes[id] = change
+				}
+				change = nil
+			}
+		}
+	}
+	return nil
+}
+
+// odtAttr 读取指定命名空间下的属性
+func odtAttr(start xml.StartElement, space, local string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Space == space && attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package extract
+
+import (
+	"reflect"
+	"testing"
+)
+
+// odtReport 与 docxReport 内容相同的 ODT 报告
+func odtReport(t *testing.T) []byte {
+	t.Helper()
+	changes := `<text:tracked-changes>` +
+		`<text:changed-region text:id="ct1"><text:insertion><office:change-info>` +
+		`<dc:creator>张三</dc:creator><dc:date>2024-05-01T10:00:00</dc:date></office:change-info></text:insertion></text:changed-region>` +
+		`<text:changed-region text:id="ct2"><text:deletion><office:change-info>` +
+		`<dc:creator>李四</dc:creator><dc:date>2024-05-02T09:00:00</dc:date></office:change-info>` +
+		`<text:p text:style-name="Standard">（草稿）</text:p></text:deletion></text:changed-region>` +
+		`</text:tracked-changes>`
+	cell := func(text string) string {
+		return `<table:table-cell office:value-type="string"><text:p text:style-name="Table_20_Contents">` + text + `</text:p></table:table-cell>`
+	}
+	text := changes +
+		`<text:sequence-decls><text:sequence-decl text:display-outline-level="0" text:name="Table"/></text:sequence-decls>` +
+		`<text:p text:style-name="Title">年度报告</text:p>` +
+		`<text:table-of-content text:name="目录"><text:index-body><text:p>引言 1</text:p></text:index-body></text:table-of-content>` +
+		`<text:h text:style-name="Heading_20_1" text:outline-level="1">引言</text:h>` +
+		`<text:p text:style-name="P1">本报告总结了今年的工作。` +
+		`<text:note text:id="ftn1" text:note-class="footnote"><text:note-citation>1</text:note-citation>` +
+		`<text:note-body><text:p text:style-name="Footnote">数据来自内部统计。</text:p></text:note-body></text:note>` +
+		`<office:annotation><dc:creator>王五</dc:creator><dc:date>2024-05-03T08:00:00</dc:date>` +
+		`<text:p>请补充来源</text:p></office:annotation></text:p>` +
+		`<text:h text:outline-level="1">方法</text:h>` +
+		`<text:h text:outline-level="2">数据来源</text:h>` +
+		`<text:p>我们收集了<text:change-start text:change-id="ct1"/>三类<text:change-end text:change-id="ct1"/>数据。` +
+		`<text:change text:change-id="ct2"/></text:p>` +
+		`<table:table table:name="表格1"><table:table-column/>` +
+		`<table:table-row>` + cell("指标") + cell("数值") + `</table:table-row>` +
+		`<table:table-row>` + cell("准确率") + cell("95%") + `</table:table-row></table:table>` +
+		`<text:list><text:list-item><text:p>It is <text:span>crucial</text:span>` + "\n  " + `to<text:s text:c="2"/>delve.</text:p></text:list-item></text:list>`
+	automatic := `<style:style style:name="P1" style:family="paragraph" style:parent-style-name="Text_20_body"/>`
+	styles := `<style:style style:name="Text_20_body" style:display-name="Text body" style:family="paragraph"/>` +
+		`<style:style style:name="Title" style:family="paragraph"/>`
+	return buildODT(t, text, automatic, styles)
+}
+
+func TestODT_Structure(t *testing.T) {
+	data := odtReport(t)
+	if format, err := Detect("upload", data); err != nil || format != FormatODT {
+		t.Fatalf("Detect() = %q, %v; want odt", format, err)
+	}
+
+	doc, err := Extract("report.odt", data, Options{Footnotes: true, Comments: true})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	assertParagraphs(t, doc, []wantParagraph{
+		{"年度报告", KindHeading, "", 0, "标题"},
+		{"引言", KindHeading, "1", 0, "第 1 节标题"},
+		{"本报告总结了今年的工作。", KindParagraph, "1", 1, "第 1 节第 1 段"},
+		{"方法", KindHeading, "2", 0, "第 2 节标题"},
+		{"数据来源", KindHeading, "2.1", 0, "第 2.1 节标题"},
+		{"我们收集了三类数据。", KindParagraph, "2.1", 1, "第 2.1 节第 1 段"},
+		{"指标", KindTableCell, "2.1", 2, "第 2.1 节第 2 段"},
+		{"数值", KindTableCell, "2.1", 3, "第 2.1 节第 3 段"},
+		{"准确率", KindTableCell, "2.1", 4, "第 2.1 节第 4 段"},
+		{"95%", KindTableCell, "2.1", 5, "第 2.1 节第 5 段"},
+		{"It is crucial to  delve.", KindListItem, "2.1", 6, "第 2.1 节第 6 段"},
+		{"数据来自内部统计。", KindFootnote, "", 1, "脚注 1"},
+		{"请补充来源", KindComment, "", 1, "批注 1（王五）"},
+	})
+	for i, want := range map[int]string{0: "Title", 1: "Heading 1", 2: "Text body", 6: "Table Contents"} {
+		if got := doc.Paragraphs[i].Style; got != want {
+			t.Errorf("Paragraphs[%d].Style = %q, want %q", i, got, want)
+		}
+	}
+
+	wantRevisions := []Revision{
+		{Type: RevisionInsert, Author: "张三", Date: "2024-05-01T10:00:00", Text: "三类", Paragraph: 5},
+		{Type: RevisionDelete, Author: "李四", Date: "2024-05-02T09:00:00", Text: "（草稿）", Paragraph: 5},
+	}
+	if !reflect.DeepEqual(doc.Revisions, wantRevisions) {
+		t.Errorf("Revisions = %+v, want %+v", doc.Revisions, wantRevisions)
+	}
+}
+
+func TestODT_Options(t *testing.T) {
+	doc, err := Extract("report.odt", odtReport(t), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	for _, p := range doc.Paragraphs {
+		if p.Kind == KindFootnote || p.Kind == KindComment {
+			t.Errorf("unexpected %s paragraph %+v", p.Kind, p)
+		}
+	}
+	if want := "本报告总结了今年的工作。"; paragraphTexts(doc)[2] != want {
+		t.Errorf("Paragraphs[2] = %q, want %q without note text", paragraphTexts(doc)[2], want)
+	}
+}
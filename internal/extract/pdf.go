@@ -0,0 +1,737 @@
+package extract
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+func init() {
+	register(FormatPDF, pdfExtractor{}, ".pdf")
+}
+
+// pdfExtractor PDF 提取器：按字体编码解码各页文本，恢复阅读顺序（分栏），去除页眉页脚和页码，
+// 按行距、缩进和字号划分段落，跨栏、跨页被拆开的段落重新合并
+type pdfExtractor struct{}
+
+// 版面分析参数，均以字号为单位
+const (
+	pdfColumnGap = 1.0  // 同一基线上的片段间距超过该值时视为分属不同的栏
+	pdfRowGap    = 0.5  // 上下两部分之间的空白超过该值时才尝试按行切分
+	pdfIndent    = 0.8  // 首行缩进
+	pdfWideBlock = 10.0 // 宽度超过该值的文本块才按行长判断段落结尾
+)
+
+// pdfMarginZone 页眉页脚区域占页面高度的比例
+const pdfMarginZone = 0.12
+
+// pdfSpan 一次文本绘制输出的文本片段，坐标为页面空间
+type pdfSpan struct {
+	text   string
+	x0, x1 float64
+	y      float64 // 基线
+	size   float64 // 字号（页面空间）
+}
+
+// pdfLine 页面上的一行文本：同一基线上相邻的片段
+type pdfLine struct {
+	text   string
+	x0, x1 float64
+	y      float64
+	size   float64
+}
+
+// pdfPage 一页的文本行和页面的纵向范围
+type pdfPage struct {
+	lines       []pdfLine
+	bottom, top float64
+}
+
+// pdfParagraph 版面分析得到的段落
+type pdfParagraph struct {
+	text     string
+	page     int
+	size     float64
+	indented bool // 首行缩进
+	lastFull bool // 末行排满整行，段落可能在下一栏或下一页继续
+}
+
+// Extract 提取 PDF 正文
+func (pdfExtractor) Extract(data []byte, _ Options) (*Document, error) {
+	f, err := parsePDF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &pdfReader{file: f, fonts: make(map[pdfRef]*pdfFont)}
+	var pages []pdfPage
+	for _, page := range f.pages() {
+		pages = append(pages, r.page(page))
+	}
+	removePDFFurniture(pages)
+
+	var paragraphs []pdfParagraph
+	for i, page := range pages {
+		for _, block := range orderPDFLines(page.lines) {
+			for _, p := range pdfBlockParagraphs(block) {
+				p.page = i + 1
+				if n := len(paragraphs); n > 0 && continuesPDFParagraph(paragraphs[n-1], p) {
+					paragraphs[n-1].text = joinPDFLines(paragraphs[n-1].text, p.text)
+					paragraphs[n-1].lastFull = p.lastFull
+					continue
+				}
+				paragraphs = append(paragraphs, p)
+			}
+		}
+	}
+
+	b := &builder{}
+	for _, p := range paragraphs {
+		b.add(Paragraph{Page: p.page}, p.text)
+	}
+	return b.document(), nil
+}
+
+// pdfReader 逐页解析内容流，缓存已读取的字体
+type pdfReader struct {
+	file  *pdfFile
+	fonts map[pdfRef]*pdfFont
+}
+
+// page 解析一页的文本行
+func (r *pdfReader) page(page pdfDict) pdfPage {
+	c := &pdfContent{reader: r, gs: pdfGraphicsState{ctm: identityMatrix, hScale: 1}}
+	c.run(r.file.contents(page), r.file.dict(r.file.inherited(page, "Resources")))
+
+	result := pdfPage{lines: pdfLines(c.spans)}
+	box, _ := r.file.inherited(page, "CropBox").(pdfArray)
+	if len(box) != 4 {
+		box, _ = r.file.inherited(page, "MediaBox").(pdfArray)
+	}
+	if len(box) == 4 {
+		y0, y1 := number(r.file.resolve(box[1])), number(r.file.resolve(box[3]))
+		result.bottom, result.top = math.Min(y0, y1), math.Max(y0, y1)
+	}
+	return result
+}
+
+// font 读取资源中的字体，间接引用的字体只读取一次
+func (r *pdfReader) font(v interface{}) *pdfFont {
+	ref, ok := v.(pdfRef)
+	if !ok {
+		return r.file.loadFont(v)
+	}
+	if font, ok := r.fonts[ref]; ok {
+		return font
+	}
+	font := r.file.loadFont(ref)
+	r.fonts[ref] = font
+	return font
+}
+
+// pdfGraphicsState 图形状态中与文本相关的部分，由 q/Q 保存和恢复
+type pdfGraphicsState struct {
+	ctm       [6]float64
+	font      *pdfFont
+	fontSize  float64
+	charSpace float64
+	wordSpace float64
+	hScale    float64 // Tz / 100
+	leading   float64
+	rise      float64
+}
+
+// pdfContent 内容流解释器
+type pdfContent struct {
+	reader *pdfReader
+	gs     pdfGraphicsState
+	stack  []pdfGraphicsState
+	tm, lm [6]float64 // 文本矩阵和文本行矩阵
+	spans  []pdfSpan
+	depth  int // Form XObject 的嵌套层数
+}
+
+var identityMatrix = [6]float64{1, 0, 0, 1, 0, 0}
+
+// maxFormDepth Form XObject 的最大嵌套层数
+const maxFormDepth = 8
+
+// run 解释内容流中的文本和图形状态操作
+func (c *pdfContent) run(content []byte, resources pdfDict) {
+	f := c.reader.file
+	fonts := f.dict(resources["Font"])
+	l := &pdfLexer{data: content}
+
+	var operands []interface{}
+	for {
+		token, err := l.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			break
+		}
+
+		keyword, ok := token.(pdfKeyword)
+		if !ok {
+			value, err := l.complete(token)
+			if err != nil {
+				break
+			}
+			operands = append(operands, value)
+			continue
+		}
+
+		switch keyword {
+		case "q":
+			c.stack = append(c.stack, c.gs)
+		case "Q":
+			if n := len(c.stack); n > 0 {
+				c.gs = c.stack[n-1]
+				c.stack = c.stack[:n-1]
+			}
+		case "cm":
+			if m, ok := matrixOperands(operands); ok {
+				c.gs.ctm = multiply(m, c.gs.ctm)
+			}
+		case "BT":
+			c.tm, c.lm = identityMatrix, identityMatrix
+		case "Tf":
+			if len(operands) >= 2 {
+				c.gs.fontSize = number(operands[len(operands)-1])
+				c.gs.font = nil
+				if name, ok := operands[len(operands)-2].(pdfName); ok && fonts != nil {
+					if ref, ok := fonts[name]; ok {
+						c.gs.font = c.reader.font(ref)
+					}
+				}
+			}
+		case "Tc":
+			if len(operands) >= 1 {
+				c.gs.charSpace = number(operands[0])
+			}
+		case "Tw":
+			if len(operands) >= 1 {
+				c.gs.wordSpace = number(operands[0])
+			}
+		case "Tz":
+			if len(operands) >= 1 {
+				c.gs.hScale = number(operands[0]) / 100
+			}
+		case "TL":
+			if len(operands) >= 1 {
+				c.gs.leading = number(operands[0])
+			}
+		case "Ts":
+			if len(operands) >= 1 {
+				c.gs.rise = number(operands[0])
+			}
+		case "Tm":
+			if m, ok := matrixOperands(operands); ok {
+				c.lm, c.tm = m, m
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				tx, ty := number(operands[0]), number(operands[1])
+				if keyword == "TD" {
+					c.gs.leading = -ty
+				}
+				c.nextLine(tx, ty)
+			}
+		case "T*":
+			c.nextLine(0, -c.gs.leading)
+		case "Tj":
+			if len(operands) >= 1 {
+				c.show(operands[0])
+			}
+		case "'":
+			c.nextLine(0, -c.gs.leading)
+			if len(operands) >= 1 {
+				c.show(operands[len(operands)-1])
+			}
+		case "\"":
+			if len(operands) >= 3 {
+				c.gs.wordSpace, c.gs.charSpace = number(operands[0]), number(operands[1])
+				c.nextLine(0, -c.gs.leading)
+				c.show(operands[2])
+			}
+		case "TJ":
+			if len(operands) >= 1 {
+				c.show(operands[0])
+			}
+		case "Do":
+			if len(operands) >= 1 {
+				if name, ok := operands[0].(pdfName); ok {
+					c.form(f.dict(resources["XObject"]), name, resources)
+				}
+			}
+		case "BI":
+			skipInlineImage(l)
+		}
+		operands = operands[:0]
+	}
+}
+
+// form 解释 Form XObject：在当前图形状态下叠加其 /Matrix 后执行其内容流
+func (c *pdfContent) form(xobjects pdfDict, name pdfName, resources pdfDict) {
+	if xobjects == nil || c.depth >= maxFormDepth {
+		return
+	}
+	f := c.reader.file
+	stream, ok := f.resolve(xobjects[name]).(*pdfStream)
+	if !ok || stream.dict["Subtype"] != pdfName("Form") {
+		return
+	}
+	data, err := f.decode(stream)
+	if err != nil {
+		return
+	}
+	if own := f.dict(stream.dict["Resources"]); own != nil {
+		resources = own
+	}
+
+	saved, tm, lm := c.gs, c.tm, c.lm
+	if matrix, ok := f.resolve(stream.dict["Matrix"]).(pdfArray); ok {
+		if m, ok := matrixOperands([]interface{}(matrix)); ok {
+			c.gs.ctm = multiply(m, c.gs.ctm)
+		}
+	}
+	c.depth++
+	c.run(data, resources)
+	c.depth--
+	c.gs, c.tm, c.lm = saved, tm, lm
+}
+
+// nextLine 移动到下一行的起点（Td）
+func (c *pdfContent) nextLine(tx, ty float64) {
+	c.lm = multiply([6]float64{1, 0, 0, 1, tx, ty}, c.lm)
+	c.tm = c.lm
+}
+
+// advance 在当前行内水平移动（文本空间）
+func (c *pdfContent) advance(tx float64) {
+	c.tm = multiply([6]float64{1, 0, 0, 1, tx, 0}, c.tm)
+}
+
+// renderMatrix 当前的文本渲染矩阵：文本空间到页面空间
+func (c *pdfContent) renderMatrix() [6]float64 {
+	size := c.gs.fontSize
+	return multiply([6]float64{size * c.gs.hScale, 0, 0, size, 0, c.gs.rise}, multiply(c.tm, c.gs.ctm))
+}
+
+// show 输出字符串或 TJ 数组，数组中的数值为千分之一字号的位移
+func (c *pdfContent) show(operand interface{}) {
+	switch v := operand.(type) {
+	case pdfString:
+		c.showString(v)
+	case pdfArray:
+		for _, item := range v {
+			switch t := item.(type) {
+			case pdfString:
+				c.showString(t)
+			case float64:
+				c.advance(-t / 1000 * c.gs.fontSize * c.gs.hScale)
+			}
+		}
+	}
+}
+
+// showString 输出一个字符串并记录其在页面上的位置，旋转或镜像的文字（如水印、侧边标注）只移动位置不输出
+func (c *pdfContent) showString(s pdfString) {
+	start := c.renderMatrix()
+
+	var text strings.Builder
+	if font := c.gs.font; font != nil {
+		for _, g := range font.glyphs(s) {
+			text.WriteString(g.text)
+			tx := g.width*c.gs.fontSize + c.gs.charSpace
+			if g.space {
+				tx += c.gs.wordSpace
+			}
+			c.advance(tx * c.gs.hScale)
+		}
+	} else {
+		// 未声明字体时按 UTF-16（带 BOM）或 Latin-1 解码，字宽按半个字号估计
+		decoded := decodePDFString(s)
+		text.WriteString(decoded)
+		for range utf8.RuneCountInString(decoded) {
+			c.advance((0.5*c.gs.fontSize + c.gs.charSpace) * c.gs.hScale)
+		}
+	}
+	end := c.renderMatrix()
+
+	horizontal := start[0] > 0 && start[3] > 0 &&
+		math.Abs(start[1]) < 0.05*start[0] && math.Abs(start[2]) < 0.05*start[3]
+	if !horizontal || text.Len() == 0 {
+		return
+	}
+	c.spans = append(c.spans, pdfSpan{
+		text: text.String(),
+		x0:   start[4],
+		x1:   math.Max(end[4], start[4]),
+		y:    start[5],
+		size: start[3],
+	})
+}
+
+// matrixOperands 读取 6 个数值组成的矩阵
+func matrixOperands(operands []interface{}) ([6]float64, bool) {
+	var m [6]float64
+	if len(operands) < 6 {
+		return m, false
+	}
+	for i, v := range operands[len(operands)-6:] {
+		n, ok := v.(float64)
+		if !ok {
+			return m, false
+		}
+		m[i] = n
+	}
+	return m, true
+}
+
+// multiply 矩阵乘法 a × b（PDF 的行向量约定）
+func multiply(a, b [6]float64) [6]float64 {
+	return [6]float64{
+		a[0]*b[0] + a[1]*b[2],
+		a[0]*b[1] + a[1]*b[3],
+		a[2]*b[0] + a[3]*b[2],
+		a[2]*b[1] + a[3]*b[3],
+		a[4]*b[0] + a[5]*b[2] + b[4],
+		a[4]*b[1] + a[5]*b[3] + b[5],
+	}
+}
+
+// skipInlineImage 跳过内联图像（BI ... ID 二进制数据 EI）
+func skipInlineImage(l *pdfLexer) {
+	i := bytes.Index(l.data[l.pos:], []byte("ID"))
+	if i < 0 {
+		l.pos = len(l.data)
+		return
+	}
+	l.pos += i + 2
+	for l.pos < len(l.data) {
+		j := bytes.Index(l.data[l.pos:], []byte("EI"))
+		if j < 0 {
+			l.pos = len(l.data)
+			return
+		}
+		end := l.pos + j
+		l.pos = end + 2
+		if end > 0 && isPDFSpace(l.data[end-1]) && (l.pos >= len(l.data) || isPDFSpace(l.data[l.pos])) {
+			return
+		}
+	}
+}
+
+// number 读取数值操作数
+func number(v interface{}) float64 {
+	n, _ := v.(float64)
+	return n
+}
+
+// decodePDFString 解码未指定字体编码的字符串：带 BOM 的为 UTF-16BE，否则按 Latin-1 处理
+func decodePDFString(s pdfString) string {
+	if len(s) >= 2 && s[0] == 0xFE && s[1] == 0xFF {
+		units := make([]uint16, (len(s)-2)/2)
+		for i := range units {
+			units[i] = uint16(s[2+2*i])<<8 | uint16(s[3+2*i])
+		}
+		return string(utf16.Decode(units))
+	}
+	runes := make([]rune, len(s))
+	for i, c := range s {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+// pdfLines 把文本片段按基线合并为行，同一基线上间距过大的片段（分栏、表格）分为不同的行
+func pdfLines(spans []pdfSpan) []pdfLine {
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].y > spans[j].y })
+
+	var lines []pdfLine
+	for start := 0; start < len(spans); {
+		end := start + 1
+		for end < len(spans) && spans[start].y-spans[end].y <= 0.3*math.Min(spans[start].size, spans[end].size) {
+			end++
+		}
+		baseline := spans[start:end]
+		sort.SliceStable(baseline, func(i, j int) bool { return baseline[i].x0 < baseline[j].x0 })
+
+		var line *pdfLine
+		var last pdfSpan
+		flush := func() {
+			if line != nil {
+				line.text = strings.Join(strings.Fields(line.text), " ")
+				if line.text != "" {
+					lines = append(lines, *line)
+				}
+			}
+		}
+		for i, span := range baseline {
+			size := math.Max(span.size, 1)
+			switch {
+			case line == nil || span.x0-line.x1 > pdfColumnGap*size:
+				flush()
+				line = &pdfLine{text: span.text, x0: span.x0, x1: span.x1, y: span.y, size: span.size}
+			case i > 0 && span.text == last.text && math.Abs(span.x0-last.x0) < 0.5*size:
+				// 重复绘制的文字（模拟加粗）只保留一次
+			default:
+				if span.x0-line.x1 > 0.15*size && !isCJKBoundary(line.text, span.text) {
+					line.text += " "
+				}
+				line.text += span.text
+				line.x1 = math.Max(line.x1, span.x1)
+				line.size = math.Max(line.size, span.size)
+			}
+			last = span
+		}
+		flush()
+		start = end
+	}
+	return lines
+}
+
+// pdfPageNumber 页码行
+var pdfPageNumber = regexp.MustCompile(`^(?i)(?:(?:page|p\.)\s*)?[-–—(\[]?\s*(?:\d+|[ivxlcdm]{1,6})\s*[-–—)\]]?(?:\s*(?:of|/)\s*\d+)?$|^第\s*\d+\s*页(?:\s*[,，/]?\s*共\s*\d+\s*页)?$`)
+
+// pdfDigits 页眉页脚比较时忽略其中的数字（页码、章节号）
+var pdfDigits = regexp.MustCompile(`\d+`)
+
+// removePDFFurniture 去除页眉、页脚和页码：页面上下边缘区域中的页码行，以及在至少一半页面（不少于 2 页）重复出现的行
+func removePDFFurniture(pages []pdfPage) {
+	inMargin := func(page pdfPage, line pdfLine) bool {
+		height := page.top - page.bottom
+		return height > 0 && (line.y > page.top-pdfMarginZone*height || line.y < page.bottom+pdfMarginZone*height)
+	}
+	key := func(line pdfLine) string {
+		return strings.ToLower(pdfDigits.ReplaceAllString(strings.Join(strings.Fields(line.text), ""), "#"))
+	}
+
+	counts := make(map[string]int)
+	for _, page := range pages {
+		seen := make(map[string]bool)
+		for _, line := range page.lines {
+			if k := key(line); inMargin(page, line) && !seen[k] {
+				seen[k] = true
+				counts[k]++
+			}
+		}
+	}
+	threshold := max(2, (len(pages)+1)/2)
+
+	for i := range pages {
+		page := &pages[i]
+		kept := page.lines[:0]
+		for _, line := range page.lines {
+			if inMargin(*page, line) && (pdfPageNumber.MatchString(strings.TrimSpace(line.text)) || counts[key(line)] >= threshold) {
+				continue
+			}
+			kept = append(kept, line)
+		}
+		page.lines = kept
+	}
+}
+
+// orderPDFLines 按阅读顺序把一页的文本行划分为文本块（递归 XY 切分）：
+// 优先在栏间空白处纵向切分，否则在较大的上下空白处横向切分；横向切分后各部分都不再分栏时整体作为一个文本块
+func orderPDFLines(lines []pdfLine) [][]pdfLine {
+	if len(lines) == 0 {
+		return nil
+	}
+	sizes := make([]float64, len(lines))
+	for i, line := range lines {
+		sizes[i] = line.size
+	}
+	sort.Float64s(sizes)
+	return xyCut(lines, math.Max(sizes[len(sizes)/2], 1))
+}
+
+// xyCut 递归切分文本行，size 为正文字号
+func xyCut(lines []pdfLine, size float64) [][]pdfLine {
+	if len(lines) > 1 {
+		if columns := cutPDFColumns(lines, size); len(columns) > 1 {
+			var blocks [][]pdfLine
+			for _, column := range columns {
+				blocks = append(blocks, xyCut(column, size)...)
+			}
+			return blocks
+		}
+		if rows := cutPDFRows(lines, size); len(rows) > 1 {
+			var blocks [][]pdfLine
+			for _, row := range rows {
+				blocks = append(blocks, xyCut(row, size)...)
+			}
+			if len(blocks) > len(rows) {
+				return blocks
+			}
+		}
+	}
+
+	block := append([]pdfLine(nil), lines...)
+	sort.SliceStable(block, func(i, j int) bool {
+		if math.Abs(block[i].y-block[j].y) > 0.3*size {
+			return block[i].y > block[j].y
+		}
+		return block[i].x0 < block[j].x0
+	})
+	return [][]pdfLine{block}
+}
+
+// cutPDFColumns 在水平投影中宽度足够的空白处切分，从左到右返回各栏
+func cutPDFColumns(lines []pdfLine, size float64) [][]pdfLine {
+	sorted := append([]pdfLine(nil), lines...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].x0 < sorted[j].x0 })
+
+	var columns [][]pdfLine
+	start, right := 0, sorted[0].x1
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].x0-right >= pdfColumnGap*size {
+			columns = append(columns, sorted[start:i])
+			start = i
+		}
+		right = math.Max(right, sorted[i].x1)
+	}
+	return append(columns, sorted[start:])
+}
+
+// cutPDFRows 在垂直投影中最大的空白处切分（接近最大值的空白一并切分），从上到下返回各部分
+func cutPDFRows(lines []pdfLine, size float64) [][]pdfLine {
+	sorted := append([]pdfLine(nil), lines...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].y > sorted[j].y })
+	top := func(l pdfLine) float64 { return l.y + 0.75*l.size }
+	bottom := func(l pdfLine) float64 { return l.y - 0.25*l.size }
+
+	gaps := make([]float64, len(sorted))
+	low, largest := bottom(sorted[0]), 0.0
+	for i := 1; i < len(sorted); i++ {
+		gaps[i] = low - top(sorted[i])
+		largest = math.Max(largest, gaps[i])
+		low = math.Min(low, bottom(sorted[i]))
+	}
+	if largest < pdfRowGap*size {
+		return nil
+	}
+
+	var rows [][]pdfLine
+	start := 0
+	for i := 1; i < len(sorted); i++ {
+		if gaps[i] >= 0.9*largest {
+			rows = append(rows, sorted[start:i])
+			start = i
+		}
+	}
+	return append(rows, sorted[start:])
+}
+
+// pdfBlockParagraphs 把文本块中的行划分为段落：行距明显变大、字号变化、上一行明显偏短、
+// 或者出现首行缩进（上一行未排满）时开始新段落
+func pdfBlockParagraphs(block []pdfLine) []pdfParagraph {
+	if len(block) == 0 {
+		return nil
+	}
+
+	left, right := block[0].x0, block[0].x1
+	var gaps []float64
+	for i, line := range block {
+		left, right = math.Min(left, line.x0), math.Max(right, line.x1)
+		if i > 0 {
+			if gap := block[i-1].y - line.y; gap > 0 {
+				gaps = append(gaps, gap)
+			}
+		}
+	}
+	normal := 0.0
+	if len(gaps) > 0 {
+		sort.Float64s(gaps)
+		normal = gaps[len(gaps)/2]
+	}
+	width := right - left
+	wide := width > pdfWideBlock*block[0].size
+	full := func(l pdfLine) bool { return wide && l.x1 >= right-2*l.size }
+	indented := func(l pdfLine) bool { return l.x0 > left+pdfIndent*l.size }
+
+	var paragraphs []pdfParagraph
+	start := func(l pdfLine) {
+		paragraphs = append(paragraphs, pdfParagraph{text: l.text, size: l.size, indented: indented(l), lastFull: full(l)})
+	}
+	start(block[0])
+	for i := 1; i < len(block); i++ {
+		prev, line := block[i-1], block[i]
+		gap := prev.y - line.y
+		current := &paragraphs[len(paragraphs)-1]
+		switch {
+		case gap <= 0.3*line.size,
+			normal > 0 && gap > normal*1.5,
+			math.Abs(line.size-prev.size) > 0.2*math.Max(line.size, prev.size),
+			wide && prev.x1 < right-0.2*width,
+			indented(line) && line.x0 > prev.x0+0.5*line.size && !full(prev),
+			startsWithBullet(line.text):
+			start(line)
+		default:
+			current.text = joinPDFLines(current.text, line.text)
+			current.lastFull = full(line)
+		}
+	}
+	return paragraphs
+}
+
+// continuesPDFParagraph 判断下一栏或下一页开头的段落是否为上一段的延续：
+// 上一段末行排满且不以句末标点结束，下一段没有首行缩进且字号相同
+func continuesPDFParagraph(prev, next pdfParagraph) bool {
+	return prev.lastFull && !next.indented && !endsSentence(prev.text) &&
+		math.Abs(prev.size-next.size) <= 0.1*math.Max(prev.size, next.size)
+}
+
+// endsSentence 文本是否以句末标点结束（忽略结尾的引号和括号）
+func endsSentence(text string) bool {
+	text = strings.TrimRight(text, " \"'”’)）」』")
+	r, _ := utf8.DecodeLastRuneInString(text)
+	return strings.ContainsRune(".!?。！？…:：", r)
+}
+
+// startsWithBullet 文本是否以项目符号开头
+func startsWithBullet(text string) bool {
+	r, _ := utf8.DecodeRuneInString(text)
+	return strings.ContainsRune("•●▪◦■□◆", r)
+}
+
+// joinPDFLines 连接段落内的两行：去除断词连字符，中日韩文字之间不加空格
+func joinPDFLines(prev, next string) string {
+	prev = strings.TrimRight(prev, " ")
+	next = strings.TrimLeft(next, " ")
+	if strings.HasSuffix(prev, "-") && len(prev) > 1 {
+		r := []rune(next)
+		before := []rune(prev)
+		if len(r) > 0 && unicode.IsLower(r[0]) && unicode.IsLetter(before[len(before)-2]) {
+			return prev[:len(prev)-1] + next
+		}
+	}
+	if isCJKBoundary(prev, next) {
+		return prev + next
+	}
+	return prev + " " + next
+}
+
+// isCJKBoundary 两段文本相接处是否为中日韩文字（相接时不需要空格）
+func isCJKBoundary(prev, next string) bool {
+	p := []rune(prev)
+	n := []rune(next)
+	if len(p) == 0 || len(n) == 0 {
+		return false
+	}
+	return isCJK(p[len(p)-1]) || isCJK(n[0])
+}
+
+// isCJK 是否为中日韩文字或全角标点
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r) || (r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
@@ -0,0 +1,436 @@
+package extract
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// pdfFont 字体：把字符串中的字符编码解码为 Unicode 文本，并给出字符宽度
+type pdfFont struct {
+	composite  bool              // Type0 字体，编码为多字节
+	codespaces []pdfCodespace    // 编码空间，决定每个字符编码的字节数
+	toUnicode  map[uint32]string // ToUnicode CMap
+	encoding   *[256]string      // 简单字体的编码
+	charset    encoding.Encoding // 预定义 CMap 对应的字符集，如 GBK-EUC-H
+	unicode    bool              // 预定义 CMap 的编码即为 UTF-16（如 UniGB-UCS2-H）
+
+	widths       map[uint32]float64 // 字形宽度（字形空间）
+	defaultWidth float64
+	scale        float64 // 字形空间到文本空间的比例，通常为 1/1000
+}
+
+// pdfCodespace 编码空间范围
+type pdfCodespace struct {
+	low, high []byte
+}
+
+// pdfGlyph 字符串中的一个字符
+type pdfGlyph struct {
+	text  string
+	width float64 // 文本空间宽度（字号为 1 时）
+	space bool    // 单字节编码 32，适用词间距
+}
+
+// pdfPredefinedCMaps 预定义 CMap 名称前缀对应的字符集
+var pdfPredefinedCMaps = []struct {
+	prefix  string
+	charset encoding.Encoding
+}{
+	{"GBK2K", simplifiedchinese.GB18030},
+	{"GBK", simplifiedchinese.GBK},
+	{"GBpc-EUC", simplifiedchinese.GBK},
+	{"GB-EUC", simplifiedchinese.GBK},
+	{"B5", traditionalchinese.Big5},
+	{"ETen-B5", traditionalchinese.Big5},
+	{"HKscs-B5", traditionalchinese.Big5},
+	{"90ms-RKSJ", japanese.ShiftJIS},
+	{"90msp-RKSJ", japanese.ShiftJIS},
+	{"EUC", japanese.EUCJP},
+	{"KSC-EUC", korean.EUCKR},
+	{"KSCms-UHC", korean.EUCKR},
+}
+
+// loadFont 读取字体字典
+func (f *pdfFile) loadFont(v interface{}) *pdfFont {
+	dict := f.dict(v)
+	if dict == nil {
+		return nil
+	}
+	font := &pdfFont{defaultWidth: 500, scale: 0.001}
+
+	subtype, _ := f.resolve(dict["Subtype"]).(pdfName)
+	if subtype == "Type0" {
+		font.composite = true
+		font.defaultWidth = 1000
+		switch enc := f.resolve(dict["Encoding"]).(type) {
+		case pdfName:
+			name := string(enc)
+			switch {
+			case strings.HasPrefix(name, "Identity"):
+			case strings.Contains(name, "UCS2") || strings.Contains(name, "UTF16"):
+				font.unicode = true
+			default:
+				for _, cmap := range pdfPredefinedCMaps {
+					if strings.HasPrefix(name, cmap.prefix) {
+						font.charset = cmap.charset
+						break
+					}
+				}
+			}
+		case *pdfStream:
+			if data, err := f.decode(enc); err == nil {
+				font.codespaces, _ = parseCMap(data)
+			}
+		}
+		if descendants, ok := f.resolve(dict["DescendantFonts"]).(pdfArray); ok && len(descendants) > 0 {
+			f.loadCIDWidths(font, f.dict(descendants[0]))
+		}
+	} else {
+		font.encoding = f.simpleEncoding(dict)
+		f.loadSimpleWidths(font, dict)
+		if subtype == "Type3" {
+			if matrix, ok := f.resolve(dict["FontMatrix"]).(pdfArray); ok && len(matrix) == 6 {
+				if scale := number(f.resolve(matrix[0])); scale > 0 {
+					font.scale = scale
+				}
+			}
+		}
+	}
+
+	if stream, ok := f.resolve(dict["ToUnicode"]).(*pdfStream); ok {
+		if data, err := f.decode(stream); err == nil {
+			codespaces, mapping := parseCMap(data)
+			if font.codespaces == nil {
+				font.codespaces = codespaces
+			}
+			font.toUnicode = mapping
+		}
+	}
+	return font
+}
+
+// loadSimpleWidths 读取简单字体的 /FirstChar 和 /Widths
+func (f *pdfFile) loadSimpleWidths(font *pdfFont, dict pdfDict) {
+	widths, ok := f.resolve(dict["Widths"]).(pdfArray)
+	if !ok {
+		return
+	}
+	first := int(number(f.resolve(dict["FirstChar"])))
+	font.widths = make(map[uint32]float64, len(widths))
+	for i, w := range widths {
+		font.widths[uint32(first+i)] = number(f.resolve(w))
+	}
+	if descriptor := f.dict(dict["FontDescriptor"]); descriptor != nil {
+		if missing := number(f.resolve(descriptor["MissingWidth"])); missing > 0 {
+			font.defaultWidth = missing
+		}
+	}
+}
+
+// loadCIDWidths 读取 CID 字体的 /DW 和 /W：[c [w1 w2 ...]] 或 [c_first c_last w]
+func (f *pdfFile) loadCIDWidths(font *pdfFont, dict pdfDict) {
+	if dict == nil {
+		return
+	}
+	if dw, ok := f.resolve(dict["DW"]).(float64); ok {
+		font.defaultWidth = dw
+	}
+	w, ok := f.resolve(dict["W"]).(pdfArray)
+	if !ok {
+		return
+	}
+	font.widths = make(map[uint32]float64)
+	for i := 0; i+1 < len(w); {
+		first := uint32(number(f.resolve(w[i])))
+		if list, ok := f.resolve(w[i+1]).(pdfArray); ok {
+			for j, width := range list {
+				font.widths[first+uint32(j)] = number(f.resolve(width))
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= len(w) {
+			break
+		}
+		last := uint32(number(f.resolve(w[i+1])))
+		width := number(f.resolve(w[i+2]))
+		for c := first; c <= last && c-first < 1<<16; c++ {
+			font.widths[c] = width
+		}
+		i += 3
+	}
+}
+
+// simpleEncoding 根据 /Encoding 确定简单字体的编码：基础编码加上 /Differences
+func (f *pdfFile) simpleEncoding(dict pdfDict) *[256]string {
+	base := "WinAnsiEncoding"
+	var differences pdfArray
+	switch enc := f.resolve(dict["Encoding"]).(type) {
+	case pdfName:
+		base = string(enc)
+	case pdfDict:
+		if name, ok := f.resolve(enc["BaseEncoding"]).(pdfName); ok {
+			base = string(name)
+		}
+		differences, _ = f.resolve(enc["Differences"]).(pdfArray)
+	}
+
+	table := pdfBaseEncoding(base)
+	code := 0
+	for _, item := range differences {
+		switch v := f.resolve(item).(type) {
+		case float64:
+			code = int(v)
+		case pdfName:
+			if code >= 0 && code < 256 {
+				table[code] = glyphText(string(v))
+			}
+			code++
+		}
+	}
+	return &table
+}
+
+// pdfBaseEncoding 返回基础编码表
+func pdfBaseEncoding(name string) [256]string {
+	var table [256]string
+	cm := charmap.Windows1252
+	if name == "MacRomanEncoding" {
+		cm = charmap.Macintosh
+	}
+	for i := range table {
+		if r := cm.DecodeByte(byte(i)); r != utf8.RuneError {
+			table[i] = string(r)
+		}
+	}
+	if name == "StandardEncoding" {
+		table['\''] = "’"
+		table['`'] = "‘"
+	}
+	return table
+}
+
+// pdfGlyphNames 常用字形名称对应的文本（连字展开为普通字母），uniXXXX 等形式由 glyphText 处理
+var pdfGlyphNames = map[string]string{
+	"space": " ", "exclam": "!", "quotedbl": "\"", "numbersign": "#", "dollar": "$", "percent": "%",
+	"ampersand": "&", "quotesingle": "'", "quoteright": "’", "quoteleft": "‘", "parenleft": "(",
+	"parenright": ")", "asterisk": "*", "plus": "+", "comma": ",", "hyphen": "-", "period": ".",
+	"slash": "/", "colon": ":", "semicolon": ";", "less": "<", "equal": "=", "greater": ">",
+	"question": "?", "at": "@", "bracketleft": "[", "backslash": "\\", "bracketright": "]",
+	"asciicircum": "^", "underscore": "_", "grave": "`", "braceleft": "{", "bar": "|",
+	"braceright": "}", "asciitilde": "~", "zero": "0", "one": "1", "two": "2", "three": "3",
+	"four": "4", "five": "5", "six": "6", "seven": "7", "eight": "8", "nine": "9",
+	"quotedblleft": "“", "quotedblright": "”", "quotesinglbase": "‚", "quotedblbase": "„",
+	"endash": "–", "emdash": "—", "bullet": "•", "ellipsis": "…", "minus": "−", "periodcentered": "·",
+	"fi": "fi", "fl": "fl", "ff": "ff", "ffi": "ffi", "ffl": "ffl", "dagger": "†", "daggerdbl": "‡",
+	"section": "§", "paragraph": "¶", "copyright": "©", "registered": "®", "trademark": "™",
+	"degree": "°", "nbspace": " ", "dotlessi": "ı", "germandbls": "ß", "eacute": "é",
+	"egrave": "è", "aacute": "á", "agrave": "à", "udieresis": "ü", "odieresis": "ö", "adieresis": "ä",
+}
+
+// glyphText 字形名称对应的文本，无法识别时返回空字符串
+func glyphText(name string) string {
+	name, _, _ = strings.Cut(name, ".") // 去掉 a.sc 等变体后缀
+	if s, ok := pdfGlyphNames[name]; ok {
+		return s
+	}
+	if len(name) == 1 {
+		return name
+	}
+	if hex, ok := strings.CutPrefix(name, "uni"); ok && len(hex) >= 4 {
+		if code, err := strconv.ParseUint(hex[:4], 16, 32); err == nil {
+			return string(rune(code))
+		}
+	}
+	if hex, ok := strings.CutPrefix(name, "u"); ok && len(hex) >= 4 && len(hex) <= 6 {
+		if code, err := strconv.ParseUint(hex, 16, 32); err == nil {
+			return string(rune(code))
+		}
+	}
+	return ""
+}
+
+// glyphs 把字符串拆分为字符
+func (font *pdfFont) glyphs(s pdfString) []pdfGlyph {
+	var glyphs []pdfGlyph
+	for i := 0; i < len(s); {
+		n := font.codeLength(s[i:])
+		raw := s[i : i+n]
+		var code uint32
+		for _, c := range raw {
+			code = code<<8 | uint32(c)
+		}
+		i += n
+
+		width, ok := font.widths[code]
+		if !ok {
+			width = font.defaultWidth
+		}
+		glyphs = append(glyphs, pdfGlyph{
+			text:  font.text(code, raw),
+			width: width * font.scale,
+			space: n == 1 && code == 32,
+		})
+	}
+	return glyphs
+}
+
+// codeLength 字符编码的字节数：按编码空间匹配，没有编码空间时简单字体为 1 字节，组合字体为 2 字节
+func (font *pdfFont) codeLength(s []byte) int {
+	for _, cs := range font.codespaces {
+		n := len(cs.low)
+		if n == 0 || n > len(s) {
+			continue
+		}
+		match := true
+		for j := 0; j < n; j++ {
+			if s[j] < cs.low[j] || s[j] > cs.high[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return n
+		}
+	}
+	switch {
+	case font.charset != nil && s[0] >= 0x80 && len(s) >= 2:
+		return 2
+	case font.charset != nil || !font.composite:
+		return 1
+	case len(s) >= 2:
+		return 2
+	}
+	return 1
+}
+
+// text 字符编码对应的文本，无法解码时返回空字符串
+func (font *pdfFont) text(code uint32, raw []byte) string {
+	if s, ok := font.toUnicode[code]; ok {
+		return s
+	}
+	switch {
+	case font.unicode:
+		return string(rune(code))
+	case font.charset != nil:
+		s, err := font.charset.NewDecoder().Bytes(raw)
+		if err != nil {
+			return ""
+		}
+		return string(s)
+	case font.encoding != nil && code < 256:
+		return font.encoding[code]
+	}
+	return ""
+}
+
+// parseCMap 解析 CMap 中的编码空间以及 bfchar、bfrange 映射
+func parseCMap(data []byte) ([]pdfCodespace, map[uint32]string) {
+	var (
+		codespaces []pdfCodespace
+		mapping    = make(map[uint32]string)
+		operands   []interface{}
+		l          = &pdfLexer{data: data}
+	)
+	for {
+		token, err := l.next()
+		if err != nil {
+			break
+		}
+		keyword, ok := token.(pdfKeyword)
+		if !ok {
+			value, err := l.complete(token)
+			if err != nil {
+				break
+			}
+			operands = append(operands, value)
+			continue
+		}
+
+		switch keyword {
+		case "endcodespacerange":
+			for i := 0; i+1 < len(operands); i += 2 {
+				low, ok1 := operands[i].(pdfString)
+				high, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 && len(low) == len(high) && len(low) > 0 {
+					codespaces = append(codespaces, pdfCodespace{low: low, high: high})
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 {
+					mapping[cmapCode(src)] = utf16String(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				low, ok1 := operands[i].(pdfString)
+				high, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 {
+					continue
+				}
+				lo, hi := cmapCode(low), cmapCode(high)
+				if hi < lo || hi-lo > 1<<16 {
+					continue
+				}
+				switch dst := operands[i+2].(type) {
+				case pdfString:
+					units := utf16Units(dst)
+					if len(units) == 0 {
+						continue
+					}
+					for c := lo; c <= hi; c++ {
+						next := append([]uint16(nil), units...)
+						next[len(next)-1] += uint16(c - lo)
+						mapping[c] = string(utf16.Decode(next))
+					}
+				case pdfArray:
+					for j, item := range dst {
+						if s, ok := item.(pdfString); ok && lo+uint32(j) <= hi {
+							mapping[lo+uint32(j)] = utf16String(s)
+						}
+					}
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+	return codespaces, mapping
+}
+
+// cmapCode 把字节串转换为编码值
+func cmapCode(s pdfString) uint32 {
+	var code uint32
+	for _, c := range s {
+		code = code<<8 | uint32(c)
+	}
+	return code
+}
+
+// utf16Units 把字节串按 UTF-16BE 拆分
+func utf16Units(s pdfString) []uint16 {
+	units := make([]uint16, len(s)/2)
+	for i := range units {
+		units[i] = uint16(s[2*i])<<8 | uint16(s[2*i+1])
+	}
+	return units
+}
+
+// utf16String 解码 UTF-16BE 字节串
+func utf16String(s pdfString) string {
+	if len(s) == 1 {
+		return string(rune(s[0]))
+	}
+	return string(utf16.Decode(utf16Units(s)))
+}
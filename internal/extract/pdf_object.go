@@ -0,0 +1,603 @@
+package extract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// PDF 对象类型
+type (
+	pdfName    string
+	pdfString  []byte
+	pdfKeyword string
+	pdfArray   []interface{}
+	pdfDict    map[pdfName]interface{}
+	pdfDelim   string
+	pdfRef     struct{ num, gen int }
+	pdfStream  struct {
+		dict pdfDict
+		raw  []byte
+	}
+)
+
+// maxStreamSize 解压单个流的大小上限
+const maxStreamSize = 64 << 20
+
+// pdfLexer PDF 词法分析器，同时用于文件结构和页面内容流
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+// isPDFSpace 是否为 PDF 空白字符
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+// isPDFDelim 是否为 PDF 分隔符
+func isPDFDelim(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+// skipSpace 跳过空白和注释
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isPDFSpace(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+// next 读取下一个词法单元，到达末尾时返回 io.EOF
+func (l *pdfLexer) next() (interface{}, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, io.EOF
+	}
+
+	c := l.data[l.pos]
+	switch c {
+	case '(':
+		return l.literalString(), nil
+	case '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return pdfDelim("<<"), nil
+		}
+		return l.hexString(), nil
+	case '>':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return pdfDelim(">>"), nil
+		}
+		l.pos++
+		return l.next()
+	case '[', ']', '{', '}':
+		l.pos++
+		return pdfDelim(string(c)), nil
+	case '/':
+		return l.name(), nil
+	case ')':
+		l.pos++
+		return l.next()
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelim(l.data[l.pos]) {
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+	if n, err := strconv.ParseFloat(word, 64); err == nil {
+		return n, nil
+	}
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	return pdfKeyword(word), nil
+}
+
+// literalString 读取括号字符串，处理嵌套括号和转义
+func (l *pdfLexer) literalString() pdfString {
+	l.pos++ // (
+	var buf []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return buf
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return buf
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				buf = append(buf, '\n')
+			case 'r':
+				buf = append(buf, '\r')
+			case 't':
+				buf = append(buf, '\t')
+			case 'b':
+				buf = append(buf, '\b')
+			case 'f':
+				buf = append(buf, '\f')
+			case '\r':
+				// 行尾续行
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					buf = append(buf, byte(v))
+				} else {
+					buf = append(buf, e)
+				}
+			}
+			continue
+		}
+		buf = append(buf, c)
+	}
+	return buf
+}
+
+// hexString 读取十六进制字符串
+func (l *pdfLexer) hexString() pdfString {
+	l.pos++ // <
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		c := l.data[l.pos]
+		if (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	if l.pos < len(l.data) {
+		l.pos++ // >
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	hex.Decode(out, digits)
+	return out
+}
+
+// name 读取名称对象，处理 #xx 转义
+func (l *pdfLexer) name() pdfName {
+	l.pos++ // /
+	var buf []byte
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelim(l.data[l.pos]) {
+		c := l.data[l.pos]
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				buf = append(buf, byte(v))
+				l.pos += 3
+				continue
+			}
+		}
+		buf = append(buf, c)
+		l.pos++
+	}
+	return pdfName(buf)
+}
+
+// object 读取一个完整对象（数组、字典或间接引用）
+func (l *pdfLexer) object() (interface{}, error) {
+	token, err := l.next()
+	if err != nil {
+		return nil, err
+	}
+	return l.complete(token)
+}
+
+// complete 以已读取的词法单元为开头，读取完整对象
+func (l *pdfLexer) complete(token interface{}) (interface{}, error) {
+	switch t := token.(type) {
+	case pdfDelim:
+		switch t {
+		case "<<":
+			dict := pdfDict{}
+			for {
+				key, err := l.next()
+				if err != nil {
+					return dict, err
+				}
+				if key == pdfDelim(">>") {
+					return dict, nil
+				}
+				name, ok := key.(pdfName)
+				if !ok {
+					continue
+				}
+				value, err := l.object()
+				if err != nil {
+					return dict, err
+				}
+				if value == pdfDelim(">>") {
+					return dict, nil
+				}
+				dict[name] = value
+			}
+		case "[":
+			var array pdfArray
+			for {
+				token, err := l.next()
+				if err != nil {
+					return array, err
+				}
+				if token == pdfDelim("]") {
+					return array, nil
+				}
+				value, err := l.complete(token)
+				if err != nil {
+					return array, err
+				}
+				array = append(array, value)
+			}
+		}
+	case float64:
+		// 尝试读取 "num gen R" 形式的间接引用
+		save := l.pos
+		gen, err1 := l.next()
+		r, err2 := l.next()
+		if g, ok := gen.(float64); ok && err1 == nil && err2 == nil && r == pdfKeyword("R") {
+			return pdfRef{num: int(t), gen: int(g)}, nil
+		}
+		l.pos = save
+	}
+	return token, nil
+}
+
+// pdfFile 已解析的 PDF 文件
+type pdfFile struct {
+	objects map[int]interface{}
+}
+
+// objHeader 间接对象的开头 "num gen obj"
+var objHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// errEncryptedPDF 加密的 PDF
+var errEncryptedPDF = errors.New("encrypted PDF files are not supported")
+
+// parsePDF 扫描文件中的所有间接对象，并展开对象流
+// 不依赖交叉引用表，因此也能读取交叉引用表损坏的文件；增量更新中后出现的对象覆盖先出现的对象
+func parsePDF(data []byte) (*pdfFile, error) {
+	f := &pdfFile{objects: make(map[int]interface{})}
+
+	end := 0
+	for _, m := range objHeader.FindAllSubmatchIndex(data, -1) {
+		if m[0] < end {
+			continue // 位于上一个对象的流数据中
+		}
+		num, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+
+		l := &pdfLexer{data: data, pos: m[1]}
+		value, err := l.object()
+		if err != nil && err != io.EOF {
+			continue
+		}
+		end = l.pos
+		if err == io.EOF || l.pos >= len(data) {
+			// 文件被截断：之后没有完整的对象，读到末尾的对象也可能不完整
+			if err == nil {
+				f.objects[num] = value
+			}
+			break
+		}
+
+		if dict, ok := value.(pdfDict); ok {
+			l.skipSpace()
+			if bytes.HasPrefix(data[l.pos:], []byte("stream")) {
+				raw, streamEnd := f.streamData(data, l.pos+len("stream"), dict)
+				value = &pdfStream{dict: dict, raw: raw}
+				end = streamEnd
+			}
+			if _, ok := dict["Encrypt"]; ok {
+				return nil, errEncryptedPDF
+			}
+		}
+		f.objects[num] = value
+	}
+
+	if len(f.objects) == 0 {
+		return nil, errors.New("no PDF objects found")
+	}
+	f.expandObjectStreams()
+	return f, nil
+}
+
+// streamData 读取 stream 关键字之后的流数据，返回数据和 endstream 之后的位置
+func (f *pdfFile) streamData(data []byte, start int, dict pdfDict) ([]byte, int) {
+	if start < len(data) && data[start] == '\r' {
+		start++
+	}
+	if start < len(data) && data[start] == '\n' {
+		start++
+	}
+
+	// 优先使用直接给出的长度，长度是间接引用或与 endstream 位置不符时搜索 endstream
+	if n, ok := dict["Length"].(float64); ok {
+		end := start + int(n)
+		if end <= len(data) && end >= start {
+			rest := bytes.TrimLeft(data[end:min(len(data), end+32)], "\r\n \t")
+			if bytes.HasPrefix(rest, []byte("endstream")) {
+				return data[start:end], end
+			}
+		}
+	}
+
+	i := bytes.Index(data[start:], []byte("endstream"))
+	if i < 0 {
+		return data[start:], len(data)
+	}
+	raw := bytes.TrimRight(data[start:start+i], "\r\n")
+	return raw, start + i + len("endstream")
+}
+
+// expandObjectStreams 展开对象流（/Type /ObjStm）中压缩存储的对象
+func (f *pdfFile) expandObjectStreams() {
+	nums := make([]int, 0, len(f.objects))
+	for num := range f.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+
+	for _, num := range nums {
+		stream, ok := f.objects[num].(*pdfStream)
+		if !ok || stream.dict["Type"] != pdfName("ObjStm") {
+			continue
+		}
+		data, err := f.decode(stream)
+		if err != nil {
+			continue
+		}
+		n, _ := f.resolve(stream.dict["N"]).(float64)
+		first, _ := f.resolve(stream.dict["First"]).(float64)
+		if int(first) > len(data) {
+			continue
+		}
+
+		header := &pdfLexer{data: data[:int(first)]}
+		for i := 0; i < int(n); i++ {
+			objNum, err1 := header.next()
+			offset, err2 := header.next()
+			on, ok1 := objNum.(float64)
+			off, ok2 := offset.(float64)
+			if err1 != nil || err2 != nil || !ok1 || !ok2 {
+				break
+			}
+			pos := int(first) + int(off)
+			if pos >= len(data) {
+				continue
+			}
+			// 直接存储的对象（通常来自增量更新）优先
+			if _, exists := f.objects[int(on)]; exists {
+				continue
+			}
+			value, err := (&pdfLexer{data: data, pos: pos}).object()
+			if err != nil && err != io.EOF {
+				continue
+			}
+			f.objects[int(on)] = value
+		}
+	}
+}
+
+// resolve 解析间接引用
+func (f *pdfFile) resolve(v interface{}) interface{} {
+	for i := 0; i < 8; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = f.objects[ref.num]
+	}
+	return nil
+}
+
+// dict 解析为字典，流对象返回其字典
+func (f *pdfFile) dict(v interface{}) pdfDict {
+	switch t := f.resolve(v).(type) {
+	case pdfDict:
+		return t
+	case *pdfStream:
+		return t.dict
+	}
+	return nil
+}
+
+// decode 按 /Filter 解码流数据
+func (f *pdfFile) decode(stream *pdfStream) ([]byte, error) {
+	var filters []pdfName
+	switch filter := f.resolve(stream.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []pdfName{filter}
+	case pdfArray:
+		for _, item := range filter {
+			if name, ok := f.resolve(item).(pdfName); ok {
+				filters = append(filters, name)
+			}
+		}
+	}
+
+	data := stream.raw
+	for _, filter := range filters {
+		var err error
+		switch filter {
+		case "FlateDecode", "Fl":
+			data, err = inflate(data)
+		case "ASCIIHexDecode", "AHx":
+			data, err = decodeASCIIHex(data)
+		case "ASCII85Decode", "A85":
+			data, err = decodeASCII85(data)
+		default:
+			return nil, fmt.Errorf("unsupported stream filter: %s", filter)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// inflate 解压 zlib 数据，数据被截断时返回已解压的部分
+func inflate(data []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	out, err := io.ReadAll(io.LimitReader(r, maxStreamSize))
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return out, nil
+}
+
+// decodeASCIIHex 解码 ASCIIHexDecode 数据
+func decodeASCIIHex(data []byte) ([]byte, error) {
+	if i := bytes.IndexByte(data, '>'); i >= 0 {
+		data = data[:i]
+	}
+	return (&pdfLexer{data: append(append([]byte{'<'}, data...), '>')}).hexString(), nil
+}
+
+// decodeASCII85 解码 ASCII85Decode 数据
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	out := make([]byte, 4*len(data)/5+4)
+	n, _, err := ascii85.Decode(out, data, true)
+	if err != nil {
+		return nil, err
+	}
+	return out[:n], nil
+}
+
+// pages 按页面树顺序返回所有页面字典
+func (f *pdfFile) pages() []pdfDict {
+	var catalog pdfDict
+	nums := make([]int, 0, len(f.objects))
+	for num := range f.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		if dict := f.dict(f.objects[num]); dict != nil && dict["Type"] == pdfName("Catalog") && dict["Pages"] != nil {
+			catalog = dict
+		}
+	}
+
+	var pages []pdfDict
+	visited := make(map[interface{}]bool)
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		if ref, ok := node.(pdfRef); ok {
+			if visited[ref] {
+				return
+			}
+			visited[ref] = true
+		}
+		dict := f.dict(node)
+		if dict == nil {
+			return
+		}
+		if kids, ok := f.resolve(dict["Kids"]).(pdfArray); ok {
+			for _, kid := range kids {
+				walk(kid)
+			}
+			return
+		}
+		if dict["Type"] == pdfName("Page") || dict["Contents"] != nil {
+			pages = append(pages, dict)
+		}
+	}
+	if catalog != nil {
+		walk(catalog["Pages"])
+	}
+
+	// 没有可用的页面树时按对象编号顺序收集页面
+	if len(pages) == 0 {
+		for _, num := range nums {
+			if dict := f.dict(f.objects[num]); dict != nil && dict["Type"] == pdfName("Page") {
+				pages = append(pages, dict)
+			}
+		}
+	}
+	return pages
+}
+
+// contents 返回页面的内容流，多个内容流依次拼接
+func (f *pdfFile) contents(page pdfDict) []byte {
+	var streams []interface{}
+	switch contents := f.resolve(page["Contents"]).(type) {
+	case pdfArray:
+		streams = contents
+	case *pdfStream:
+		streams = []interface{}{contents}
+	}
+
+	var buf bytes.Buffer
+	for _, item := range streams {
+		stream, ok := f.resolve(item).(*pdfStream)
+		if !ok {
+			continue
+		}
+		data, err := f.decode(stream)
+		if err != nil {
+			continue
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// inherited 读取页面属性，页面中没有时沿 /Parent 向上查找（Resources、MediaBox 等属性可从页面树继承）
+func (f *pdfFile) inherited(page pdfDict, key pdfName) interface{} {
+	for i := 0; page != nil && i < 32; i++ {
+		if v, ok := page[key]; ok {
+			return f.resolve(v)
+		}
+		page = f.dict(page["Parent"])
+	}
+	return nil
+}
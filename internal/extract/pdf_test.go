@@ -0,0 +1,242 @@
+package extract
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// pdfFontResources 页面资源：/F1 为第 5 个对象（单页文档的第一个附加对象）
+const pdfFontResources = "/Resources << /Font << /F1 5 0 R >> >>"
+
+// pdfTextAt 在页面坐标 (x, y) 处以指定字号输出一行文本
+func pdfTextAt(x, y, size float64, text string) string {
+	return fmt.Sprintf("BT /F1 %g Tf 1 0 0 1 %g %g Tm (%s) Tj ET\n", size, x, y, text)
+}
+
+// pdfColumnLines 生成 n 行约 40 个字符的正文（未声明字体时每个字符宽半个字号，10 号字的行宽约 200）
+func pdfColumnLines(seed string, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		s := fmt.Sprintf("%s line %d", seed, i+1)
+		for len(s) < 40 {
+			s += " text"
+		}
+		lines[i] = strings.TrimRight(s[:40], " ")
+	}
+	return lines
+}
+
+// pdfColumn 从 (x, y) 开始按 12 的行距输出多行，返回内容流和下一行的纵坐标
+func pdfColumn(x, y float64, lines []string, indentFirst bool) (string, float64) {
+	var b strings.Builder
+	for i, line := range lines {
+		lx := x
+		if i == 0 && indentFirst {
+			lx += 20
+		}
+		b.WriteString(pdfTextAt(lx, y, 10, line))
+		y -= 12
+	}
+	return b.String(), y
+}
+
+func TestPDF_Fonts(t *testing.T) {
+	toUnicode := "/CIDInit /ProcSet findresource begin 12 dict begin begincmap\n" +
+		"1 begincodespacerange <0000> <FFFF> endcodespacerange\n" +
+		"2 beginbfchar <0001> <672C> <0002> <6587> endbfchar\n" +
+		"1 beginbfrange <0003> <0004> [<0041> <0042>] endbfrange\n" +
+		"2 beginbfrange <0005> <0006> <4E00> <0007> <0007> <D835DC00> endbfrange\n" +
+		"endcmap CMapName currentdict /CMap defineresource pop end end"
+
+	tests := []struct {
+		name    string
+		content string
+		objects []pdfObject
+		want    string
+	}{
+		{
+			name:    "CID font with ToUnicode",
+			content: "BT /F1 12 Tf 72 700 Td [<00010002> -500 <0003>] TJ <00040005> Tj <00060007> Tj ET",
+			objects: []pdfObject{
+				{dict: "/Type /Font /Subtype /Type0 /BaseFont /ABCDEF+SimSun /Encoding /Identity-H /DescendantFonts [6 0 R] /ToUnicode 7 0 R"},
+				{dict: "/Type /Font /Subtype /CIDFontType2 /BaseFont /ABCDEF+SimSun /DW 1000 /W [3 [500 500]]"},
+				{stream: []byte(toUnicode)},
+			},
+			want: "本文AB一丁𝐀",
+		},
+		{
+			name:    "predefined GBK CMap",
+			content: "BT /F1 12 Tf 72 700 Td <D6D0CEC4C4DAC8DD> Tj ET",
+			objects: []pdfObject{
+				{dict: "/Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /GBK-EUC-H /DescendantFonts [6 0 R]"},
+				{dict: "/Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light"},
+			},
+			want: "中文内容",
+		},
+		{
+			name:    "simple font with differences",
+			content: "BT /F1 12 Tf 72 700 Td (We \\001nd it\\002s) Tj ( ) Tj (caf\\351) Tj ET",
+			objects: []pdfObject{
+				{dict: "/Type /Font /Subtype /Type1 /BaseFont /Times-Roman /Encoding 6 0 R"},
+				{dict: "/Type /Encoding /BaseEncoding /WinAnsiEncoding /Differences [1 /fi /quoteright]"},
+			},
+			want: "We find it’s café",
+		},
+		{
+			name:    "Form XObject",
+			content: "q 1 0 0 1 0 -200 cm /X1 Do Q",
+			objects: []pdfObject{
+				{dict: "/Type /Font /Subtype /Type1 /BaseFont /Helvetica"},
+				{dict: "/Type /XObject /Subtype /Form /BBox [0 0 612 792] /Matrix [1 0 0 1 10 0]", stream: []byte("BT /F1 12 Tf 72 700 Td (Drawn inside a form) Tj ET")},
+			},
+			want: "Drawn inside a form",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources := pdfFontResources
+			if strings.Contains(tt.content, "Do") {
+				resources = "/Resources << /Font << /F1 5 0 R >> /XObject << /X1 6 0 R >> >>"
+			}
+			doc, err := Extract("paper.pdf", buildPDF(t, []string{tt.content}, true, resources, tt.objects...), Options{})
+			if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			if got := paragraphTexts(doc); len(got) != 1 || got[0] != tt.want {
+				t.Errorf("paragraphs = %q, want [%q]", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPDF_Layout(t *testing.T) {
+	header := pdfTextAt(72, 760, 9, "Journal of Testing, Vol. 12")
+	a := append(pdfColumnLines("alpha", 3), "alpha ends here.")
+	b1, b2 := pdfColumnLines("beta", 5), append(pdfColumnLines("beta more", 2), "beta ends.")
+	c1, c2 := pdfColumnLines("gamma", 3), []string{"gamma continues on the next page."}
+	d := []string{"A new paragraph on the last page."}
+
+	// 第 1 页：页眉、跨两栏的标题，左栏为段落 A 和段落 B 的前半部分，右栏为 B 的后半部分和段落 C 的前半部分
+	page1 := header + pdfTextAt(180, 700, 18, "A Study of Layout")
+	left, y := pdfColumn(72, 660, a, true)
+	page1 += left
+	left, _ = pdfColumn(72, y, b1, true)
+	page1 += left
+	right, y := pdfColumn(330, 660, b2, false)
+	page1 += right
+	right, _ = pdfColumn(330, y, c1, true)
+	page1 += right + pdfTextAt(300, 30, 9, "- 1 -")
+
+	// 第 2 页：段落 C 的剩余部分，没有首行缩进
+	page2 := header + pdfTextAt(72, 660, 10, c2[0]) + pdfTextAt(300, 30, 9, "- 2 -")
+	// 第 3 页：新段落，页码为 Page 3 of 3
+	page3 := header + pdfTextAt(92, 660, 10, d[0]) + pdfTextAt(280, 30, 9, "Page 3 of 3")
+
+	doc, err := Extract("paper.pdf", buildPDF(t, []string{page1, page2, page3}, false, ""), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	join := func(parts ...[]string) string {
+		var lines []string
+		for _, p := range parts {
+			lines = append(lines, p...)
+		}
+		return strings.Join(lines, " ")
+	}
+	want := []struct {
+		text     string
+		page     int
+		location string
+	}{
+		{"A Study of Layout", 1, "第 1 页第 1 段"},
+		{join(a), 1, "第 1 页第 2 段"},
+		{join(b1, b2), 1, "第 1 页第 3 段"},
+		{join(c1, c2), 1, "第 1 页第 4 段"},
+		{d[0], 3, "第 3 页第 1 段"},
+	}
+	got := paragraphTexts(doc)
+	if len(got) != len(want) {
+		t.Fatalf("paragraphs = %q, want %d", got, len(want))
+	}
+	for i, w := range want {
+		p := doc.Paragraphs[i]
+		if got[i] != w.text || p.Page != w.page || p.Location() != w.location {
+			t.Errorf("Paragraphs[%d] = %q page %d %q, want %q page %d %q", i, got[i], p.Page, p.Location(), w.text, w.page, w.location)
+		}
+	}
+	for _, furniture := range []string{"Journal", "- 1 -", "Page 3"} {
+		if strings.Contains(doc.Text, furniture) {
+			t.Errorf("Text contains %q: %q", furniture, doc.Text)
+		}
+	}
+
+	result := &models.DetectionResult{
+		Text: doc.Text,
+		RuleResults: []models.RuleResult{{Matches: []models.Match{{
+			Text:     "new paragraph",
+			Position: models.Position{Line: 1, Offset: strings.Index(doc.Text, "new paragraph"), Length: len("new paragraph")},
+		}}}},
+	}
+	doc.MapResult(result)
+	if got := result.RuleResults[0].Matches[0].Location; got != "第 3 页第 1 段" {
+		t.Errorf("match location = %q, want 第 3 页第 1 段", got)
+	}
+}
+
+func TestRemovePDFFurniture(t *testing.T) {
+	tests := []struct {
+		text   string
+		y      float64
+		remove bool
+	}{
+		{"12", 30, true},
+		{"- 12 -", 30, true},
+		{"第 12 页", 30, true},
+		{"第 3 页 共 20 页", 30, true},
+		{"12 / 20", 770, true},
+		{"xii", 30, true},
+		{"12", 400, false},
+		{"Conclusion", 30, false},
+		{"Running Title 2024", 770, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			var pages []pdfPage
+			for i := 0; i < 3; i++ {
+				page := pdfPage{bottom: 0, top: 792, lines: []pdfLine{{text: "Body text", y: 400, size: 10}}}
+				if tt.text == "Running Title 2024" || i == 0 {
+					page.lines = append(page.lines, pdfLine{text: tt.text, y: tt.y, size: 10})
+				}
+				pages = append(pages, page)
+			}
+			removePDFFurniture(pages)
+			kept := len(pages[0].lines) == 2
+			if kept == tt.remove {
+				t.Errorf("%q at y=%g removed = %v, want %v", tt.text, tt.y, !kept, tt.remove)
+			}
+		})
+	}
+}
+
+func TestPDF_Truncated(t *testing.T) {
+	data := buildPDF(t, []string{"BT (Hello truncated world) Tj ET", "BT (Second page) Tj ET"}, false, "",
+		pdfObject{dict: "/Type /Info /ID <0123456789abcdef0123456789abcdef>"})
+
+	// 在任意位置截断都不应 panic（包括截断在未闭合的十六进制字符串中），只能返回提取结果或错误
+	for n := 1; n < len(data); n++ {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("Extract() panicked on file truncated to %d of %d bytes: %v", n, len(data), r)
+				}
+			}()
+			Extract("cut.pdf", data[:n], Options{})
+		}()
+	}
+}
@@ -0,0 +1,360 @@
+package extract
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+func init() {
+	register(FormatSRT, subtitleExtractor{}, ".srt")
+	register(FormatVTT, subtitleExtractor{}, ".vtt", ".webvtt")
+}
+
+const (
+	// subtitlePause 相邻两条字幕之间超过该间隔时，前后的文本不连成同一句
+	subtitlePause = 2 * time.Second
+	// subtitleMaxSpan 一句的最长时间，用于没有标点的字幕
+	subtitleMaxSpan = 15 * time.Second
+)
+
+var (
+	// subtitleTag 字幕中的格式标签，如 <i>、<font color="red">、<v 讲者>、<00:00:01.000>
+	subtitleTag = regexp.MustCompile(`<[^<>]*>`)
+	// subtitleStyle SSA 风格的覆盖标签，如 {\an8}
+	subtitleStyle = regexp.MustCompile(`\{\\[^{}]*\}`)
+	// srtHead SRT 文件开头的序号和时间轴行
+	srtHead = regexp.MustCompile(`^\d+[ \t]*\n[ \t]*(\d+:)?\d{1,2}:\d{2}[,.]\d{1,3}[ \t]*-->`)
+)
+
+// subtitleExtractor SRT 和 WebVTT 字幕提取器
+// 字幕按时间顺序连接成句，每句作为一个段落，并保留每条字幕的时间范围，检测结果据此定位到时间轴
+type subtitleExtractor struct{}
+
+// Cue 提取文本中来自同一条字幕的一段文本及其时间范围
+// 一条字幕跨越句子边界时被拆分为多段，各段的时间范围相同
+type Cue struct {
+	Offset int              `json:"offset"` // 在提取文本中的字节偏移量
+	Length int              `json:"length"` // 在提取文本中的字节长度
+	Line   int              `json:"line"`   // 字幕文本在原文件中的起始行号（从 1 开始）
+	Time   models.TimeRange `json:"time"`   // 字幕的显示时间
+}
+
+// subtitleCue 解析出的一条字幕
+type subtitleCue struct {
+	text string
+	line int
+	time models.TimeRange
+}
+
+// subtitlePiece 一句中来自同一条字幕的文本
+type subtitlePiece struct {
+	text string
+	cue  *subtitleCue
+}
+
+func (subtitleExtractor) Extract(data []byte, opts Options) (*Document, error) {
+	source, err := decodeText(data)
+	if err != nil {
+		return nil, err
+	}
+	cues, err := parseSubtitles(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		b        builder
+		cueSpans []Cue
+		pieces   []subtitlePiece
+		prev     *subtitleCue
+	)
+	flush := func() {
+		if len(pieces) == 0 {
+			return
+		}
+		var text string
+		offsets := make([]int, len(pieces))
+		for i, piece := range pieces {
+			if i > 0 {
+				text = joinPDFLines(text, piece.text)
+				offsets[i] = len(text) - len(piece.text)
+			} else {
+				text = piece.text
+			}
+		}
+		span := models.TimeRange{Start: pieces[0].cue.time.Start, End: pieces[len(pieces)-1].cue.time.End}
+		b.add(Paragraph{Line: pieces[0].cue.line, Time: &span}, text)
+		p := b.paragraphs[len(b.paragraphs)-1]
+		for i, piece := range pieces {
+			cueSpans = append(cueSpans, Cue{
+				Offset: p.Offset + offsets[i],
+				Length: len(piece.text),
+				Line:   piece.cue.line,
+				Time:   piece.cue.time,
+			})
+		}
+		pieces = nil
+	}
+
+	for i := range cues {
+		cue := &cues[i]
+		if prev != nil && cue.time.Start-prev.time.End > subtitlePause ||
+			len(pieces) > 0 && cue.time.End-pieces[0].cue.time.Start > subtitleMaxSpan {
+			flush()
+		}
+		for _, sentence := range splitCueSentences(cue.text) {
+			pieces = append(pieces, subtitlePiece{text: sentence, cue: cue})
+			if endsSentence(sentence) {
+				flush()
+			}
+		}
+		prev = cue
+	}
+	flush()
+
+	doc := b.document()
+	doc.Cues = cueSpans
+	return doc, nil
+}
+
+// parseSubtitles 解析 SRT 或 WebVTT 字幕，返回按时间排序的字幕列表
+// 以空行分隔的块中，含 "-->" 的行为时间轴，其后各行为字幕文本；WebVTT 的文件头和 NOTE、STYLE、REGION 块被忽略
+func parseSubtitles(source string) ([]subtitleCue, error) {
+	var cues []subtitleCue
+	lines := strings.Split(source, "\n")
+	for i := 0; i < len(lines); {
+		// 跳过块之间的空行
+		if strings.TrimSpace(lines[i]) == "" {
+			i++
+			continue
+		}
+		start := i
+		for i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+			i++
+		}
+		block := lines[start:i]
+
+		first := strings.TrimSpace(block[0])
+		if start == 0 && strings.HasPrefix(first, "WEBVTT") {
+			continue
+		}
+		if first == "NOTE" || first == "STYLE" || first == "REGION" ||
+			strings.HasPrefix(first, "NOTE ") || strings.HasPrefix(first, "NOTE\t") {
+			continue
+		}
+
+		timing := -1
+		for j, line := range block {
+			if strings.Contains(line, "-->") {
+				timing = j
+				break
+			}
+		}
+		if timing < 0 {
+			continue
+		}
+		span, err := parseCueTiming(block[timing])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", start+timing+1, err)
+		}
+		text := cleanCueText(block[timing+1:])
+		if text == "" {
+			continue
+		}
+		cues = append(cues, subtitleCue{text: text, line: start + timing + 2, time: span})
+	}
+	return cues, nil
+}
+
+// parseCueTiming 解析时间轴行，如 "00:03:12,000 --> 00:03:18,500" 或 "03:12.000 --> 03:18.500 align:start"
+func parseCueTiming(line string) (models.TimeRange, error) {
+	from, to, _ := strings.Cut(line, "-->")
+	fields := strings.Fields(to)
+	if len(fields) == 0 {
+		return models.TimeRange{}, fmt.Errorf("invalid cue timing %q", line)
+	}
+	start, err := parseTimestamp(strings.TrimSpace(from))
+	if err != nil {
+		return models.TimeRange{}, err
+	}
+	end, err := parseTimestamp(fields[0])
+	if err != nil {
+		return models.TimeRange{}, err
+	}
+	if end < start {
+		end = start
+	}
+	return models.TimeRange{Start: start, End: end}, nil
+}
+
+// parseTimestamp 解析 [时:]分:秒[,.]毫秒 格式的时间
+func parseTimestamp(s string) (time.Duration, error) {
+	clock, fraction, _ := strings.Cut(strings.Replace(s, ",", ".", 1), ".")
+	parts := strings.Split(clock, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	var d time.Duration
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		d = d*60 + time.Duration(n)
+	}
+	d *= time.Second
+	if fraction != "" {
+		ms, err := strconv.Atoi((fraction + "00")[:3])
+		if err != nil {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		d += time.Duration(ms) * time.Millisecond
+	}
+	return d, nil
+}
+
+// cleanCueText 去除字幕文本中的格式标签和对话破折号，解码 HTML 实体并将多行连成一行
+func cleanCueText(lines []string) string {
+	var text string
+	for _, line := range lines {
+		line = subtitleStyle.ReplaceAllString(subtitleTag.ReplaceAllString(line, ""), "")
+		line = strings.TrimSpace(html.UnescapeString(line))
+		line = strings.TrimSpace(strings.TrimPrefix(line, "- "))
+		if line == "" {
+			continue
+		}
+		if text == "" {
+			text = line
+		} else {
+			text = joinPDFLines(text, line)
+		}
+	}
+	return text
+}
+
+// splitCueSentences 在句末标点后拆分一条字幕的文本，句末标点后紧跟的引号和括号归入前一句
+func splitCueSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		if !strings.ContainsRune(".!?。！？…", r) {
+			continue
+		}
+		end := i
+		for end < len(text) {
+			next, n := utf8.DecodeRuneInString(text[end:])
+			if !strings.ContainsRune(".!?。！？…\"'”’)）」』", next) {
+				break
+			}
+			end += n
+		}
+		// 英文句号后需有空白，避免拆开小数和缩写中的点
+		if end < len(text) && r == '.' && text[end] != ' ' {
+			i = end
+			continue
+		}
+		if sentence := strings.TrimSpace(text[start:end]); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start, i = end, end
+	}
+	if rest := strings.TrimSpace(text[start:]); rest != "" {
+		sentences = append(sentences, rest)
+	}
+	return sentences
+}
+
+// TimeRange 返回提取文本中 [offset, offset+length) 涉及的字幕的时间范围，不在任何字幕中时返回 nil
+func (d *Document) TimeRange(offset, length int) *models.TimeRange {
+	end := offset + max(length, 1)
+	var span *models.TimeRange
+	for _, cue := range d.Cues {
+		if cue.Offset >= end || cue.Offset+cue.Length <= offset {
+			continue
+		}
+		if span == nil {
+			t := cue.Time
+			span = &t
+			continue
+		}
+		span.Start = min(span.Start, cue.Time.Start)
+		span.End = max(span.End, cue.Time.End)
+	}
+	return span
+}
+
+// SegmentScores 计算片段得分：每条已检出规则的扣分（100 减规则得分）按匹配项平均分摊到匹配项所在的片段，
+// 片段得分为 100 减去分摊到的扣分。由多个部分组成的文档（笔记本、EPUB）以各单元格或章节为片段，
+// 其他文档只以带时间范围的段落（如字幕中的句子）为片段，结果中的匹配位置应指向提取文本
+func (d *Document) SegmentScores(results []models.RuleResult) []models.SegmentScore {
+	var segments []models.SegmentScore
+	index := make(map[int]int) // 段落序号到所在片段的映射
+	if len(d.Parts) > 0 {
+		parts := make(map[string]int, len(d.Parts))
+		for _, part := range d.Parts {
+			parts[part.Name] = len(segments)
+			segments = append(segments, models.SegmentScore{
+				Index: part.Index,
+				Part:  part.Name,
+				Title: part.Title,
+				Score: 100,
+			})
+		}
+		for _, p := range d.Paragraphs {
+			if i, ok := parts[p.Part]; ok {
+				index[p.Index] = i
+			}
+		}
+	}
+	for _, p := range d.Paragraphs {
+		if p.Time == nil || len(d.Parts) > 0 {
+			continue
+		}
+		index[p.Index] = len(segments)
+		segments = append(segments, models.SegmentScore{
+			Index: p.Index,
+			Text:  d.Text[p.Offset : p.Offset+p.Length],
+			Time:  p.Time,
+			Score: 100,
+		})
+	}
+	if len(segments) == 0 {
+		return nil
+	}
+
+	for _, r := range results {
+		if !r.Detected || len(r.Matches) == 0 {
+			continue
+		}
+		share := (100 - r.Score) / float64(len(r.Matches))
+		for _, m := range r.Matches {
+			p := d.Locate(m.Position.Offset)
+			if p == nil {
+				continue
+			}
+			i, ok := index[p.Index]
+			if !ok {
+				continue
+			}
+			s := &segments[i]
+			s.Score -= share
+			s.Matches++
+			if len(s.Rules) == 0 || s.Rules[len(s.Rules)-1] != r.RuleName {
+				s.Rules = append(s.Rules, r.RuleName)
+			}
+		}
+	}
+	for i := range segments {
+		segments[i].Score = max(segments[i].Score, 0)
+	}
+	return segments
+}
@@ -0,0 +1,138 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// at 将 时:分:秒 转换为时间
+func at(h, m, s int) time.Duration {
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+}
+
+func TestSubtitle_Parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		data     string
+		want     []string
+		times    []string
+	}{
+		{
+			name:     "srt cues joined into sentences",
+			filename: "talk.srt",
+			data: "1\r\n00:03:12,000 --> 00:03:14,500\r\n<i>Let's work through</i>\r\nthis together.\r\n\r\n" +
+				"2\r\n00:03:15,000 --> 00:03:18,000\r\nIt costs 3.5 dollars. I hope\r\n\r\n" +
+				"3\r\n00:03:18,200 --> 00:03:20,000\r\n{\\an8}this helps &amp; more!\r\n",
+			want:  []string{"Let's work through this together.", "It costs 3.5 dollars.", "I hope this helps & more!"},
+			times: []string{"00:03:12–00:03:14", "00:03:15–00:03:18", "00:03:15–00:03:20"},
+		},
+		{
+			name:     "vtt header, notes and voice tags",
+			filename: "talk.vtt",
+			data: "WEBVTT - lecture\nKind: captions\n\nNOTE reviewed\nby editor\n\nSTYLE\n::cue { color: red }\n\n" +
+				"intro\n00:01.000 --> 00:02.500 align:start\n<v Alice>我们一起来看</v>\n\n" +
+				"00:02.600 --> 00:04.000\n这个问题。\n",
+			want:  []string{"我们一起来看这个问题。"},
+			times: []string{"00:00:01–00:00:04"},
+		},
+		{
+			name:     "pause splits unpunctuated cues",
+			filename: "talk.srt",
+			data: "1\n00:00:01,000 --> 00:00:02,000\n- first speaker\n\n" +
+				"2\n00:00:10,000 --> 00:00:11,000\n- second speaker\n",
+			want:  []string{"first speaker", "second speaker"},
+			times: []string{"00:00:01–00:00:02", "00:00:10–00:00:11"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Extract(tt.filename, []byte(tt.data), Options{})
+			if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			got := paragraphTexts(doc)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Fatalf("paragraphs = %q, want %q", got, tt.want)
+			}
+			for i, want := range tt.times {
+				if loc := doc.Paragraphs[i].Location(); loc != want {
+					t.Errorf("Paragraphs[%d].Location() = %q, want %q", i, loc, want)
+				}
+			}
+			for _, cue := range doc.Cues {
+				if text := doc.Text[cue.Offset : cue.Offset+cue.Length]; strings.TrimSpace(text) != text || text == "" {
+					t.Errorf("cue %+v covers %q", cue, text)
+				}
+			}
+		})
+	}
+}
+
+func TestSubtitle_InvalidTiming(t *testing.T) {
+	_, err := Extract("talk.srt", []byte("1\n00:00:01,000 --> soon\nHello"), Options{})
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Extract() error = %v, want invalid timing on line 2", err)
+	}
+}
+
+func TestSubtitle_MapResult(t *testing.T) {
+	data := "1\n00:03:10,000 --> 00:03:12,000\nThanks for watching.\n\n" +
+		"2\n00:03:12,000 --> 00:03:15,000\nI hope this helps, and\n\n" +
+		"3\n00:03:15,500 --> 00:03:18,000\nfeel free to ask.\n"
+	doc, err := Extract("talk.srt", []byte(data), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	match := func(text string) models.Match {
+		offset := strings.Index(doc.Text, text)
+		return models.Match{Text: text, Position: models.Position{Line: 3, Offset: offset, Length: len(text)}}
+	}
+	result := &models.DetectionResult{
+		Text: doc.Text,
+		RuleResults: []models.RuleResult{
+			{RuleName: "协作式语气检测", Detected: true, Score: 60, Matches: []models.Match{match("I hope this helps"), match("helps, and feel free")}},
+			{RuleName: "高频词汇检测", Detected: false, Score: 90, Matches: []models.Match{match("watching")}},
+		},
+		Suggestions: []models.Suggestion{{Anchors: []models.Anchor{{Text: "feel free", Position: match("feel free").Position}}}},
+	}
+	doc.MapResult(result)
+
+	matches := result.RuleResults[0].Matches
+	if got := matches[0].Location; got != "00:03:12–00:03:15" {
+		t.Errorf("match location = %q, want 00:03:12–00:03:15", got)
+	}
+	if got := matches[1].Time; got == nil || *got != (models.TimeRange{Start: at(0, 3, 12), End: at(0, 3, 18)}) {
+		t.Errorf("match spanning two cues time = %v, want 00:03:12–00:03:18", got)
+	}
+	if got := result.Suggestions[0].Anchors[0].Location; got != "00:03:15–00:03:18" {
+		t.Errorf("anchor location = %q, want 00:03:15–00:03:18", got)
+	}
+
+	// 协作式语气扣 40 分，两个匹配项都在第二句，各分摊 20 分；未检出的规则不扣分
+	want := []struct {
+		score   float64
+		matches int
+		rules   string
+	}{
+		{100, 0, ""},
+		{60, 2, "协作式语气检测"},
+	}
+	if len(result.Segments) != len(want) {
+		t.Fatalf("segments = %+v, want %d", result.Segments, len(want))
+	}
+	for i, w := range want {
+		s := result.Segments[i]
+		if s.Score != w.score || s.Matches != w.matches || strings.Join(s.Rules, ",") != w.rules {
+			t.Errorf("Segments[%d] = %+v, want score %v matches %d rules %q", i, s, w.score, w.matches, w.rules)
+		}
+	}
+	if got := result.Segments[1].Time.String(); got != "00:03:12–00:03:18" {
+		t.Errorf("segment time = %q, want 00:03:12–00:03:18", got)
+	}
+}
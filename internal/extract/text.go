@@ -0,0 +1,104 @@
+package extract
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+func init() {
+	register(FormatText, textExtractor{}, ".txt", ".text", ".log")
+}
+
+// textExtractor 纯文本提取器：文本原样保留（只统一换行符），以空行划分段落
+type textExtractor struct{}
+
+// Extract 提取纯文本
+func (textExtractor) Extract(data []byte, _ Options) (*Document, error) {
+	text, err := decodeText(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Document{
+		Text:       text,
+		Paragraphs: splitBlocks(text, nil),
+	}, nil
+}
+
+// decodeText 将文本文件解码为 UTF-8：去除 BOM，支持带 BOM 的 UTF-16，并将 CRLF/CR 统一为 LF
+func decodeText(data []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		data = data[3:]
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		data = decodeUTF16(data[2:], false)
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		data = decodeUTF16(data[2:], true)
+	}
+	if !utf8.Valid(data) {
+		return "", errors.New("text is not valid UTF-8")
+	}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	return strings.ReplaceAll(text, "\r", "\n"), nil
+}
+
+// decodeUTF16 将 UTF-16 字节解码为 UTF-8
+func decodeUTF16(data []byte, bigEndian bool) []byte {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+		} else {
+			units[i] = uint16(data[2*i+1])<<8 | uint16(data[2*i])
+		}
+	}
+	return []byte(string(utf16.Decode(units)))
+}
+
+// splitBlocks 以空行划分段落，段落偏移量直接指向 text
+// classify 根据段落首行判断段落类型和级别，为 nil 时均视为普通段落
+func splitBlocks(text string, classify func(firstLine string) (string, int)) []Paragraph {
+	var paragraphs []Paragraph
+	start, startLine := -1, 0
+	line := 1
+	offset := 0
+
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		block := strings.TrimRight(text[start:end], " \t\n")
+		p := Paragraph{
+			Index:  len(paragraphs),
+			Offset: start,
+			Length: len(block),
+			Kind:   KindParagraph,
+			Line:   startLine,
+		}
+		if classify != nil {
+			firstLine, _, _ := strings.Cut(block, "\n")
+			p.Kind, p.Level = classify(firstLine)
+		}
+		paragraphs = append(paragraphs, p)
+		start = -1
+	}
+
+	for _, l := range strings.SplitAfter(text, "\n") {
+		trimmed := strings.TrimSpace(l)
+		if trimmed == "" {
+			flush(offset)
+		} else if start < 0 {
+			// 段落从首个非空白字符开始
+			start = offset + strings.Index(l, trimmed)
+			startLine = line
+		}
+		offset += len(l)
+		line++
+	}
+	flush(len(text))
+
+	return paragraphs
+}
@@ -0,0 +1,337 @@
+package fixer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext 统一diff格式中每个变更块前后保留的上下文行数
+const diffContext = 3
+
+// opKind 行级编辑操作类型
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// lineOp 行级编辑操作
+type lineOp struct {
+	kind opKind
+	line string
+}
+
+// UnifiedDiff 生成两段文本之间的统一diff（unified diff）
+// 文本相同时返回空字符串
+func UnifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("--- %s\n", oldName))
+	sb.WriteString(fmt.Sprintf("+++ %s\n", newName))
+
+	for _, h := range buildHunks(ops) {
+		sb.WriteString(fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(h.oldStart, h.oldLines), hunkRange(h.newStart, h.newLines)))
+		for _, op := range h.ops {
+			switch op.kind {
+			case opEqual:
+				sb.WriteString(" ")
+			case opDelete:
+				sb.WriteString("-")
+			case opInsert:
+				sb.WriteString("+")
+			}
+			sb.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// splitLines 按行切分文本，每行保留换行符
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines 使用 Myers 算法计算两组行之间的最短编辑脚本
+// 采用线性空间的分治版本：每次只查找中间蛇形再递归求解两侧，
+// 内存占用为 O(n+m)，不随编辑距离增长，修改较多的长文档也不会占用大量内存
+func diffLines(a, b []string) []lineOp {
+	n, m := len(a), len(b)
+	size := n + m + 4
+	d := &lineDiff{
+		a:        a,
+		b:        b,
+		ops:      make([]lineOp, 0, max(n, m)),
+		forward:  make([]int, size),
+		backward: make([]int, size),
+	}
+	d.compare(0, n, 0, m)
+	return d.ops
+}
+
+// lineDiff 线性空间 Myers 算法的工作状态
+type lineDiff struct {
+	a, b              []string
+	ops               []lineOp
+	forward, backward []int // 正向和反向搜索中各对角线到达的最远位置，在递归中复用
+}
+
+// compare 计算 a[aLo:aHi] 与 b[bLo:bHi] 之间的编辑脚本并追加到 ops
+func (d *lineDiff) compare(aLo, aHi, bLo, bHi int) {
+	// 公共前缀和后缀直接作为相同行
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.ops = append(d.ops, lineOp{kind: opEqual, line: d.a[aLo]})
+		aLo++
+		bLo++
+	}
+	suffix := aHi
+	for aHi > aLo && bHi > bLo && d.a[aHi-1] == d.b[bHi-1] {
+		aHi--
+		bHi--
+	}
+
+	switch {
+	case aLo == aHi:
+		for _, line := range d.b[bLo:bHi] {
+			d.ops = append(d.ops, lineOp{kind: opInsert, line: line})
+		}
+	case bLo == bHi:
+		for _, line := range d.a[aLo:aHi] {
+			d.ops = append(d.ops, lineOp{kind: opDelete, line: line})
+		}
+	default:
+		x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.compare(aLo, x, bLo, y)
+		for _, line := range d.a[x:u] {
+			d.ops = append(d.ops, lineOp{kind: opEqual, line: line})
+		}
+		d.compare(u, aHi, v, bHi)
+	}
+
+	for _, line := range d.a[aHi:suffix] {
+		d.ops = append(d.ops, lineOp{kind: opEqual, line: line})
+	}
+}
+
+// middleSnake 同时从两端搜索最短编辑路径，返回两条路径相遇处的蛇形（连续相同行）
+// 起点为 (x, y)，终点为 (u, v)；两侧的子问题编辑距离都约为整体的一半
+func (d *lineDiff) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	half := (n + m + 1) / 2
+	offset := half + 1
+	vf, vb := d.forward, d.backward
+	vf[offset+1] = 0
+	vb[offset+1] = 0
+
+	for step := 0; step <= half; step++ {
+		// 正向：vf[k] 为对角线 k = x - y 上到达的最大 x
+		for k := -step; k <= step; k += 2 {
+			var x int
+			if k == -step || (k != step && vf[offset+k-1] < vf[offset+k+1]) {
+				x = vf[offset+k+1]
+			} else {
+				x = vf[offset+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && d.a[aLo+x] == d.b[bLo+y] {
+				x++
+				y++
+			}
+			vf[offset+k] = x
+			if rk := delta - k; odd && rk >= -(step-1) && rk <= step-1 && x+vb[offset+rk] >= n {
+				return aLo + x0, bLo + y0, aLo + x, bLo + y
+			}
+		}
+
+		// 反向：在倒序的两组行上搜索，vb[rk] 为对角线 rk 上从末尾回退的最大行数
+		for rk := -step; rk <= step; rk += 2 {
+			var x int
+			if rk == -step || (rk != step && vb[offset+rk-1] < vb[offset+rk+1]) {
+				x = vb[offset+rk+1]
+			} else {
+				x = vb[offset+rk-1] + 1
+			}
+			y := x - rk
+			x0, y0 := x, y
+			for x < n && y < m && d.a[aHi-1-x] == d.b[bHi-1-y] {
+				x++
+				y++
+			}
+			vb[offset+rk] = x
+			if k := delta - rk; !odd && k >= -step && k <= step && x+vf[offset+k] >= n {
+				return aLo + n - x, bLo + m - y, aLo + n - x0, bLo + m - y0
+			}
+		}
+	}
+
+	// 两端的搜索在 half 步内必然相遇
+	panic("fixer: diff search did not converge")
+}
+
+// hunk 统一diff中的变更块
+type hunk struct {
+	oldStart, oldLines int
+	newStart, newLines int
+	ops                []lineOp
+}
+
+// buildHunks 将编辑脚本按上下文行数合并为变更块
+func buildHunks(ops []lineOp) []hunk {
+	var hunks []hunk
+	oldLine, newLine := 1, 1
+
+	i := 0
+	for i < len(ops) {
+		// 跳到下一个变更
+		if ops[i].kind == opEqual {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+
+		// 变更块起点（包含前置上下文）
+		start := i
+		for start > 0 && i-start < diffContext && ops[start-1].kind == opEqual {
+			start--
+		}
+		h := hunk{
+			oldStart: oldLine - (i - start),
+			newStart: newLine - (i - start),
+		}
+
+		// 向后扩展，直到出现超过两倍上下文的连续相同行
+		end := i
+		trailing := 0
+		for end < len(ops) {
+			if ops[end].kind == opEqual {
+				if trailing == 2*diffContext {
+					break
+				}
+				trailing++
+			} else {
+				trailing = 0
+			}
+			end++
+		}
+		// 只保留 diffContext 行尾部上下文
+		if trailing > diffContext {
+			end -= trailing - diffContext
+		}
+
+		h.ops = ops[start:end]
+		for _, op := range h.ops {
+			switch op.kind {
+			case opEqual:
+				h.oldLines++
+				h.newLines++
+			case opDelete:
+				h.oldLines++
+			case opInsert:
+				h.newLines++
+			}
+		}
+		hunks = append(hunks, h)
+
+		// 更新行号
+		for _, op := range ops[i:end] {
+			switch op.kind {
+			case opEqual:
+				oldLine++
+				newLine++
+			case opDelete:
+				oldLine++
+			case opInsert:
+				newLine++
+			}
+		}
+		i = end
+	}
+
+	return hunks
+}
+
+// hunkRange 格式化变更块的行范围
+func hunkRange(start, lines int) string {
+	if lines == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if lines == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, lines)
+}
+
+// Change 两段文本之间一处连续的行级变更
+type Change struct {
+	OldOffset int    `json:"old_offset"` // 变更在旧文本中的字节偏移
+	OldLine   int    `json:"old_line"`   // 变更在旧文本中的起始行号（从1开始）
+	Removed   string `json:"removed"`    // 删除的文本
+	NewOffset int    `json:"new_offset"` // 变更在新文本中的字节偏移
+	NewLine   int    `json:"new_line"`   // 变更在新文本中的起始行号（从1开始）
+	Added     string `json:"added"`      // 新增的文本
+}
+
+// Changes 计算两段文本之间的行级变更，相邻的删除和插入合并为一处变更
+func Changes(oldText, newText string) []Change {
+	if oldText == newText {
+		return nil
+	}
+
+	var changes []Change
+	var current *Change
+	oldOffset, newOffset := 0, 0
+	oldLine, newLine := 1, 1
+
+	for _, op := range diffLines(splitLines(oldText), splitLines(newText)) {
+		if op.kind == opEqual {
+			if current != nil {
+				changes = append(changes, *current)
+				current = nil
+			}
+			oldOffset += len(op.line)
+			newOffset += len(op.line)
+			oldLine++
+			newLine++
+			continue
+		}
+
+		if current == nil {
+			current = &Change{OldOffset: oldOffset, OldLine: oldLine, NewOffset: newOffset, NewLine: newLine}
+		}
+		if op.kind == opDelete {
+			current.Removed += op.line
+			oldOffset += len(op.line)
+			oldLine++
+		} else {
+			current.Added += op.line
+			newOffset += len(op.line)
+			newLine++
+		}
+	}
+	if current != nil {
+		changes = append(changes, *current)
+	}
+
+	return changes
+}
@@ -0,0 +1,130 @@
+package fixer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/detector"
+	"github.com/leoobai/aigc-check/internal/models"
+	"github.com/leoobai/aigc-check/internal/rules"
+)
+
+// Fixer 基于规则的离线自动修复引擎
+type Fixer struct {
+	config     *config.Config
+	ruleEngine *detector.RuleEngine
+}
+
+// Result 修复结果
+type Result struct {
+	Original  string        `json:"original"`  // 原始文本
+	Fixed     string        `json:"fixed"`     // 修复后文本
+	Applied   []models.Edit `json:"applied"`   // 已应用的修改
+	Conflicts []models.Edit `json:"conflicts"` // 因范围重叠被跳过的修改
+}
+
+// Changed 是否有修改被应用
+func (r *Result) Changed() bool {
+	return len(r.Applied) > 0
+}
+
+// NewFixer 创建自动修复引擎
+func NewFixer(cfg *config.Config) *Fixer {
+	ruleEngine := detector.NewRuleEngine(cfg)
+	for _, rule := range rules.All(cfg) {
+		ruleEngine.RegisterRule(rule)
+	}
+
+	return &Fixer{
+		config:     cfg,
+		ruleEngine: ruleEngine,
+	}
+}
+
+// Fix 检测文本并应用所有可自动修复的修改
+func (f *Fixer) Fix(text string) *Result {
+	edits := f.CollectEdits(text)
+	applied, conflicts := ResolveConflicts(edits)
+
+	return &Result{
+		Original:  text,
+		Fixed:     Apply(text, applied),
+		Applied:   applied,
+		Conflicts: conflicts,
+	}
+}
+
+// CollectEdits 执行规则检测并收集实现了 models.Fixer 的规则给出的修改
+func (f *Fixer) CollectEdits(text string) []models.Edit {
+	var edits []models.Edit
+
+	for _, result := range f.ruleEngine.Check(text) {
+		rule, ok := f.ruleEngine.GetRule(result.RuleType)
+		if !ok {
+			continue
+		}
+		fixable, ok := rule.(models.Fixer)
+		if !ok {
+			continue
+		}
+		for _, edit := range fixable.Fix(text, result) {
+			if edit.Offset < 0 || edit.End() > len(text) {
+				continue
+			}
+			edits = append(edits, edit)
+		}
+	}
+
+	return edits
+}
+
+// ResolveConflicts 解决重叠的修改
+// 按起始位置排序，同一位置优先保留范围更大的修改；与已保留修改重叠的修改被丢弃，
+// 完全相同的修改只保留一份
+func ResolveConflicts(edits []models.Edit) (applied, conflicts []models.Edit) {
+	sorted := make([]models.Edit, len(edits))
+	copy(sorted, edits)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Offset != sorted[j].Offset {
+			return sorted[i].Offset < sorted[j].Offset
+		}
+		if sorted[i].Length != sorted[j].Length {
+			return sorted[i].Length > sorted[j].Length
+		}
+		return sorted[i].RuleType < sorted[j].RuleType
+	})
+
+	for _, edit := range sorted {
+		if len(applied) > 0 {
+			last := applied[len(applied)-1]
+			if last.Offset == edit.Offset && last.Length == edit.Length && last.Replacement == edit.Replacement {
+				continue
+			}
+			if last.Overlaps(edit) {
+				conflicts = append(conflicts, edit)
+				continue
+			}
+		}
+		applied = append(applied, edit)
+	}
+
+	return applied, conflicts
+}
+
+// Apply 将互不重叠的修改应用到文本
+// edits 必须按 Offset 升序排列且互不重叠（ResolveConflicts 的输出满足此要求）
+func Apply(text string, edits []models.Edit) string {
+	var sb strings.Builder
+	sb.Grow(len(text))
+
+	cursor := 0
+	for _, edit := range edits {
+		sb.WriteString(text[cursor:edit.Offset])
+		sb.WriteString(edit.Replacement)
+		cursor = edit.End()
+	}
+	sb.WriteString(text[cursor:])
+
+	return sb.String()
+}
@@ -0,0 +1,248 @@
+package fixer
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+func TestFixer_Fix(t *testing.T) {
+	cfg := config.DefaultConfig
+	f := NewFixer(&cfg)
+
+	tests := []struct {
+		name        string
+		text        string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:        "移除UTM参数",
+			text:        "See https://example.com/page?utm_source=chatgpt.com for details.",
+			contains:    []string{"https://example.com/page for details."},
+			notContains: []string{"utm_source"},
+		},
+		{
+			name:        "保留其他查询参数",
+			text:        "Link: https://example.com/?utm_source=chatgpt.com&id=42 here.",
+			contains:    []string{"https://example.com/?id=42"},
+			notContains: []string{"utm_source"},
+		},
+		{
+			name:        "移除幽灵标记",
+			text:        "The market grew 5% contentReference[oaicite:0]{index=0}.",
+			contains:    []string{"The market grew 5%."},
+			notContains: []string{"oaicite"},
+		},
+		{
+			name:        "删除协作式结束语",
+			text:        "The report is finished.\n\nI hope this helps! Let me know if you need more.",
+			contains:    []string{"The report is finished."},
+			notContains: []string{"I hope this helps", "Let me know"},
+		},
+		{
+			name:     "正文中的协作式短语不删除",
+			text:     "Feel free to skip this section. The rest of the report follows.",
+			contains: []string{"Feel free to skip this section."},
+		},
+		{
+			name:        "替换高频词",
+			text:        "This is crucial. Crucial steps follow. A crucial point.",
+			contains:    []string{"This is important.", "Important steps"},
+			notContains: []string{"rucial"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := f.Fix(tt.text)
+			for _, s := range tt.contains {
+				if !strings.Contains(result.Fixed, s) {
+					t.Errorf("Fixed = %q, want to contain %q", result.Fixed, s)
+				}
+			}
+			for _, s := range tt.notContains {
+				if strings.Contains(result.Fixed, s) {
+					t.Errorf("Fixed = %q, want not to contain %q", result.Fixed, s)
+				}
+			}
+		})
+	}
+}
+
+func TestFixer_NoChanges(t *testing.T) {
+	cfg := config.DefaultConfig
+	f := NewFixer(&cfg)
+
+	text := "I wrote this myself last weekend. It was fun."
+	result := f.Fix(text)
+	if result.Changed() {
+		t.Errorf("Changed() = true, want false (applied: %+v)", result.Applied)
+	}
+	if result.Fixed != text {
+		t.Errorf("Fixed = %q, want unchanged", result.Fixed)
+	}
+}
+
+func TestResolveConflicts(t *testing.T) {
+	edits := []models.Edit{
+		{Offset: 10, Length: 3, Replacement: "b"},
+		{Offset: 0, Length: 5, Replacement: "a"},
+		{Offset: 2, Length: 2, Replacement: "x"},  // 与 [0,5) 重叠
+		{Offset: 10, Length: 5, Replacement: "c"}, // 同一位置，范围更大
+		{Offset: 20, Length: 2, Replacement: "d"},
+		{Offset: 20, Length: 2, Replacement: "d"}, // 重复
+	}
+
+	applied, conflicts := ResolveConflicts(edits)
+
+	if len(applied) != 3 {
+		t.Fatalf("len(applied) = %d, want 3: %+v", len(applied), applied)
+	}
+	want := []string{"a", "c", "d"}
+	for i, w := range want {
+		if applied[i].Replacement != w {
+			t.Errorf("applied[%d].Replacement = %q, want %q", i, applied[i].Replacement, w)
+		}
+	}
+	if len(conflicts) != 2 {
+		t.Errorf("len(conflicts) = %d, want 2", len(conflicts))
+	}
+}
+
+func TestApply(t *testing.T) {
+	text := "hello world, hello go"
+	edits := []models.Edit{
+		{Offset: 0, Length: 5, Replacement: "hi"},
+		{Offset: 13, Length: 5, Replacement: "bye"},
+	}
+
+	got := Apply(text, edits)
+	want := "hi world, bye go"
+	if got != want {
+		t.Errorf("Apply() = %q, want %q", got, want)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	oldText := "line 1\nline 2\nline 3\nline 4\nline 5\nline 6\nline 7\nline 8\nline 9\nline 10\n"
+	newText := "line 1\nline two\nline 3\nline 4\nline 5\nline 6\nline 7\nline 8\nline 9\nline ten\n"
+
+	diff := UnifiedDiff("a/x.txt", "b/x.txt", oldText, newText)
+
+	expected := []string{
+		"--- a/x.txt\n",
+		"+++ b/x.txt\n",
+		"@@ -1,5 +1,5 @@\n",
+		"-line 2\n",
+		"+line two\n",
+		"@@ -7,4 +7,4 @@\n",
+		"-line 10\n",
+		"+line ten\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(diff, e) {
+			t.Errorf("diff missing %q:\n%s", e, diff)
+		}
+	}
+
+	if UnifiedDiff("a", "b", oldText, oldText) != "" {
+		t.Error("UnifiedDiff() of identical texts should be empty")
+	}
+}
+
+func TestChanges(t *testing.T) {
+	oldText := "first\nsecond\nthird\nfourth\n"
+	newText := "first\n2nd\nthird\nfourth\nfifth\n"
+
+	got := Changes(oldText, newText)
+	want := []Change{
+		{OldOffset: 6, OldLine: 2, Removed: "second\n", NewOffset: 6, NewLine: 2, Added: "2nd\n"},
+		{OldOffset: 26, OldLine: 5, Removed: "", NewOffset: 23, NewLine: 5, Added: "fifth\n"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Changes() = %+v, want %+v", got, want)
+	}
+
+	if Changes(oldText, oldText) != nil {
+		t.Error("Changes() of identical texts should be nil")
+	}
+}
+
+func TestDiffLines_Minimal(t *testing.T) {
+	// 伪随机生成取值范围较小的行序列，与动态规划求得的最长公共子序列比较编辑次数
+	seed := uint32(1)
+	random := func(n int) []string {
+		lines := make([]string, n)
+		for i := range lines {
+			seed = seed*1664525 + 1013904223
+			lines[i] = string(rune('a'+seed>>28%4)) + "\n"
+		}
+		return lines
+	}
+
+	for i := 0; i < 300; i++ {
+		a, b := random(i%23), random(i%17)
+		ops := diffLines(a, b)
+
+		var gotA, gotB []string
+		edits := 0
+		for _, op := range ops {
+			if op.kind != opInsert {
+				gotA = append(gotA, op.line)
+			}
+			if op.kind != opDelete {
+				gotB = append(gotB, op.line)
+			}
+			if op.kind != opEqual {
+				edits++
+			}
+		}
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("diffLines(%q, %q) = %+v does not reproduce both inputs", a, b, ops)
+		}
+
+		lcs := make([][]int, len(a)+1)
+		for x := range lcs {
+			lcs[x] = make([]int, len(b)+1)
+		}
+		for x := len(a) - 1; x >= 0; x-- {
+			for y := len(b) - 1; y >= 0; y-- {
+				if a[x] == b[y] {
+					lcs[x][y] = lcs[x+1][y+1] + 1
+				} else {
+					lcs[x][y] = max(lcs[x+1][y], lcs[x][y+1])
+				}
+			}
+		}
+		if want := len(a) + len(b) - 2*lcs[0][0]; edits != want {
+			t.Fatalf("diffLines(%q, %q) uses %d edits, want %d", a, b, edits, want)
+		}
+	}
+}
+
+func TestDiffLines_LargeRevision(t *testing.T) {
+	// 两份大幅修改的长文档：每行都不同时编辑距离为 n+m，内存占用应与行数成线性关系
+	const n = 3000
+	a, b := make([]string, n), make([]string, n)
+	for i := range a {
+		a[i] = "old line " + strings.Repeat("x", i%7) + "\n"
+		b[i] = "new line " + strings.Repeat("y", i%5) + "\n"
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	ops := diffLines(a, b)
+	runtime.ReadMemStats(&after)
+
+	if len(ops) != 2*n {
+		t.Errorf("len(ops) = %d, want %d", len(ops), 2*n)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 4<<20 {
+		t.Errorf("diffLines() allocated %d bytes, want linear in the number of lines", allocated)
+	}
+}
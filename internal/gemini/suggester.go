@@ -80,7 +80,7 @@ func (s *Suggester) GenerateSuggestions(ctx context.Context, text string, issues
 - %s
 
 请提供3-5条具体的改进建议，每条建议包括：
-1. 问题所在的具体文本片段
+1. 问题所在的具体文本片段（必须与原文完全一致）
 2. 建议的修改方式
 3. 修改后的示例
 4. 为什么这样修改可以让文本更自然
@@ -88,7 +88,7 @@ func (s *Suggester) GenerateSuggestions(ctx context.Context, text string, issues
 请以JSON数组格式返回：
 [
   {
-    "type": "<问题类型>",
+    "type": "<问题类型，优先使用规则类型: high_frequency_words, sentence_starters, false_range, citation_anomaly, em_dash_density, markdown_residue, emoji_anomaly, knowledge_cutoff, collaborative_tone, perfectionism, hidden_characters, parallel_structure>",
     "priority": <1-5>,
     "title": "<建议标题>",
     "description": "<详细描述>",
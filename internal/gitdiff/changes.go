@@ -0,0 +1,210 @@
+package gitdiff
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/leoobai/aigc-check/internal/extract"
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// Block 新文件中包含新增行的段落：新增行参与检测，同一段落中未修改的行只作为上下文展示
+type Block struct {
+	File      string         `json:"file"`
+	Format    extract.Format `json:"format"`     // 按文件扩展名判断的格式
+	StartLine int            `json:"start_line"` // 段落在新文件中的起始行号
+	EndLine   int            `json:"end_line"`   // 段落在新文件中的结束行号
+	Section   string         `json:"section,omitempty"`
+	Lines     []Line         `json:"lines"` // 段落中的上下文行和新增行，按新文件中的顺序排列
+}
+
+// Added 段落中的新增行数
+func (b *Block) Added() int {
+	n := 0
+	for _, line := range b.Lines {
+		if line.Kind == LineAdded {
+			n++
+		}
+	}
+	return n
+}
+
+// span 检测文本中来自同一段落新增行的部分
+type span struct {
+	offset int               // 在检测文本中的字节偏移量
+	length int               // 在检测文本中的长度
+	doc    *extract.Document // 按文件格式提取正文时的文档，按原样检测时为 nil
+	source string            // 段落中的新增行以换行连接的原文
+	lines  []int             // source 中各行在新文件中的行号
+	file   string
+}
+
+// Changes 从统一 diff 中重建的新增文本
+type Changes struct {
+	Text   string  // 待检测文本：只包含新增行中的正文，各段落之间以空行分隔
+	Files  int     // 包含待检测新增行的文件数
+	Lines  int     // 参与检测的新增行数
+	Blocks []Block // 参与检测的段落及其上下文
+	spans  []span
+}
+
+// Build 重建 diff 中的新增文本：按新文件中的空行把各变更块的上下文行和新增行切分为段落，
+// 只保留包含新增行的段落，并只取其中的新增行。Markdown、LaTeX 去除格式标记，源代码只保留注释和文档字符串，
+// 其他文件按原样检测；删除的文件和二进制文件被跳过，未修改的内容不进入待检测文本
+func Build(files []File) *Changes {
+	c := &Changes{}
+	var sb strings.Builder
+	for _, file := range files {
+		if file.Binary || file.NewName == "" {
+			continue
+		}
+		format, err := extract.Detect(file.NewName, nil)
+		if err != nil {
+			continue
+		}
+		added := false
+		for _, hunk := range file.Hunks {
+			for _, block := range paragraphs(file.NewName, hunk) {
+				var source []string
+				var lines []int
+				for _, line := range block.Lines {
+					if line.Kind == LineAdded {
+						source = append(source, line.Text)
+						lines = append(lines, line.NewLine)
+					}
+				}
+				if len(source) == 0 {
+					continue
+				}
+				s := span{source: strings.Join(source, "\n"), lines: lines, file: file.NewName}
+				text := s.source
+				if proseFormat(format) {
+					s.doc, err = extract.ExtractAs(format, []byte(s.source), extract.Options{})
+					if errors.Is(err, extract.ErrNoText) {
+						continue
+					}
+					if err != nil {
+						s.doc = nil
+					} else {
+						text = s.doc.Text
+					}
+				}
+
+				if sb.Len() > 0 {
+					sb.WriteString("\n\n")
+				}
+				s.offset, s.length = sb.Len(), len(text)
+				sb.WriteString(text)
+				c.spans = append(c.spans, s)
+				c.Lines += len(lines)
+				block.Format = format
+				c.Blocks = append(c.Blocks, block)
+				added = true
+			}
+		}
+		if added {
+			c.Files++
+		}
+	}
+	c.Text = sb.String()
+	return c
+}
+
+// proseFormat 是否需要从新增行中提取正文（去除格式标记或只保留注释）
+func proseFormat(format extract.Format) bool {
+	return format == extract.FormatMarkdown || format == extract.FormatLaTeX || extract.IsSourceCode(format)
+}
+
+// Format 返回参与检测的段落共同的格式，来自多种格式时返回空字符串
+func (c *Changes) Format() extract.Format {
+	var format extract.Format
+	for i, block := range c.Blocks {
+		if i > 0 && block.Format != format {
+			return ""
+		}
+		format = block.Format
+	}
+	return format
+}
+
+// paragraphs 按空行把变更块在新文件中的内容切分为段落，删除行不属于新文件，被跳过
+func paragraphs(name string, hunk Hunk) []Block {
+	var blocks []Block
+	var current *Block
+	for _, line := range hunk.Lines {
+		if line.Kind == LineRemoved {
+			continue
+		}
+		if strings.TrimSpace(line.Text) == "" {
+			current = nil
+			continue
+		}
+		if current == nil {
+			blocks = append(blocks, Block{File: name, StartLine: line.NewLine, Section: hunk.Section})
+			current = &blocks[len(blocks)-1]
+		}
+		current.Lines = append(current.Lines, line)
+		current.EndLine = line.NewLine
+	}
+	return blocks
+}
+
+// Locate 返回检测文本中的偏移量所在的文件、新文件中的行号和列号（列号按字符计，从 1 开始），
+// 偏移量落在段落分隔处时取下一段的开头
+func (c *Changes) Locate(offset int) (string, int, int, bool) {
+	i := sort.Search(len(c.spans), func(i int) bool {
+		return c.spans[i].offset+c.spans[i].length > offset
+	})
+	if i == len(c.spans) {
+		return "", 0, 0, false
+	}
+	s := c.spans[i]
+	local := max(offset-s.offset, 0)
+	if s.doc != nil {
+		local = s.doc.SourceOffset(local)
+	}
+	local = min(local, len(s.source))
+
+	before := s.source[:local]
+	lineStart := strings.LastIndexByte(before, '\n') + 1
+	column := utf8.RuneCountInString(before[lineStart:]) + 1
+	return s.file, s.lines[strings.Count(before, "\n")], column, true
+}
+
+// MapResult 将针对新增文本的检测结果映射回新文件：匹配项和建议锚点标注所在文件和 "文件:行号" 位置，
+// 行号和列号改为新文件中的位置，偏移量仍指向检测文本（result.text）
+func (c *Changes) MapResult(result *models.DetectionResult) {
+	if result == nil {
+		return
+	}
+	for i := range result.RuleResults {
+		for j := range result.RuleResults[i].Matches {
+			match := &result.RuleResults[i].Matches[j]
+			match.File, match.Location = c.mapPosition(&match.Position)
+		}
+	}
+	for i := range result.Suggestions {
+		for j := range result.Suggestions[i].Anchors {
+			anchor := &result.Suggestions[i].Anchors[j]
+			anchor.File, anchor.Location = c.mapPosition(&anchor.Position)
+		}
+	}
+}
+
+// mapPosition 将位置的行号和列号改为新文件中的位置，返回文件路径和位置描述
+func (c *Changes) mapPosition(pos *models.Position) (string, string) {
+	// 跳过开头的空白，使匹配从换行处开始时定位到实际内容所在的行
+	offset := pos.Offset
+	for offset < len(c.Text) && (c.Text[offset] == '\n' || c.Text[offset] == ' ') {
+		offset++
+	}
+	file, line, column, ok := c.Locate(offset)
+	if !ok {
+		return "", ""
+	}
+	pos.Line, pos.Column = line, column
+	return file, fmt.Sprintf("%s:%d", file, line)
+}
@@ -0,0 +1,139 @@
+package gitdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+const sampleDiff = `diff --git a/docs/intro.md b/docs/intro.md
+--- a/docs/intro.md
++++ b/docs/intro.md
+@@ -3,8 +3,9 @@ # Intro
+ Legacy paragraph that nobody touched.
+ It delves into many things.
+
+ The second paragraph starts here.
+-It was short.
++It is now longer, and I hope this helps.
++Let me know if anything is unclear.
+ It ends here.
+
+ Another untouched paragraph.
+diff --git a/docs/old.md b/docs/old.md
+deleted file mode 100644
+--- a/docs/old.md
++++ /dev/null
+@@ -1 +0,0 @@
+-Removed text delves deep.
+`
+
+func TestBuild(t *testing.T) {
+	files, err := Parse(strings.NewReader(sampleDiff))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	changes := Build(files)
+
+	want := "It is now longer, and I hope this helps.\nLet me know if anything is unclear."
+	if changes.Text != want {
+		t.Errorf("Text = %q, want %q", changes.Text, want)
+	}
+	if strings.Contains(changes.Text, "delves") {
+		t.Error("unchanged and removed lines must not be analyzed")
+	}
+	if changes.Files != 1 || changes.Lines != 2 {
+		t.Errorf("Files, Lines = %d, %d, want 1, 2", changes.Files, changes.Lines)
+	}
+
+	if len(changes.Blocks) != 1 {
+		t.Fatalf("Blocks = %+v, want one paragraph", changes.Blocks)
+	}
+	block := changes.Blocks[0]
+	if block.File != "docs/intro.md" || block.StartLine != 6 || block.EndLine != 9 || block.Section != "# Intro" {
+		t.Errorf("block = %s:%d-%d (%q), want docs/intro.md:6-9 (# Intro)", block.File, block.StartLine, block.EndLine, block.Section)
+	}
+	if len(block.Lines) != 4 || block.Added() != 2 {
+		t.Errorf("block has %d lines with %d added, want 4 with 2 added", len(block.Lines), block.Added())
+	}
+}
+
+func TestChanges_MapResult(t *testing.T) {
+	files, err := Parse(strings.NewReader(sampleDiff))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	changes := Build(files)
+
+	position := func(text string) models.Position {
+		offset := strings.Index(changes.Text, text)
+		return models.Position{Line: 1, Offset: offset, Length: len(text)}
+	}
+	result := &models.DetectionResult{
+		Text: changes.Text,
+		RuleResults: []models.RuleResult{{Matches: []models.Match{
+			{Text: "I hope this helps", Position: position("I hope this helps")},
+			{Text: "Let me know if", Position: position("Let me know if")},
+		}}},
+		// 句子锚点从连接两行的换行开始
+		Suggestions: []models.Suggestion{{Anchors: []models.Anchor{{Position: position("\nLet me know")}}}},
+	}
+	changes.MapResult(result)
+
+	tests := []struct {
+		file     string
+		location string
+		line     int
+		column   int
+		got      models.Position
+		gotFile  string
+		gotLoc   string
+	}{
+		{"docs/intro.md", "docs/intro.md:7", 7, 23, result.RuleResults[0].Matches[0].Position, result.RuleResults[0].Matches[0].File, result.RuleResults[0].Matches[0].Location},
+		{"docs/intro.md", "docs/intro.md:8", 8, 1, result.RuleResults[0].Matches[1].Position, result.RuleResults[0].Matches[1].File, result.RuleResults[0].Matches[1].Location},
+		{"docs/intro.md", "docs/intro.md:8", 8, 1, result.Suggestions[0].Anchors[0].Position, result.Suggestions[0].Anchors[0].File, result.Suggestions[0].Anchors[0].Location},
+	}
+	for i, tt := range tests {
+		if tt.gotFile != tt.file || tt.gotLoc != tt.location || tt.got.Line != tt.line || tt.got.Column != tt.column {
+			t.Errorf("finding %d = %s (%s) at %d:%d, want %s (%s) at %d:%d",
+				i, tt.gotLoc, tt.gotFile, tt.got.Line, tt.got.Column, tt.location, tt.file, tt.line, tt.column)
+		}
+	}
+}
+
+func TestBuild_Formats(t *testing.T) {
+	diff := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1,3 +1,6 @@\n package main\n \n" +
+		"+// run starts the server.\n+// Feel free to adjust as needed.\n+func run() { println(\"I hope this helps\") }\n" +
+		" func main() {}\n" +
+		"diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n@@ -1 +1,2 @@\n # Title\n+This is **crucial** for the [setup guide](docs/setup.md).\n"
+	files, err := Parse(strings.NewReader(diff))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	changes := Build(files)
+
+	want := "run starts the server. Feel free to adjust as needed.\n\nThis is crucial for the setup guide."
+	if changes.Text != want {
+		t.Errorf("Text = %q, want %q", changes.Text, want)
+	}
+	if changes.Format() != "" {
+		t.Errorf("Format() = %q, want empty for mixed formats", changes.Format())
+	}
+
+	tests := []struct {
+		text   string
+		file   string
+		line   int
+		column int
+	}{
+		{"Feel free", "main.go", 4, 4},
+		{"crucial", "README.md", 2, 11},
+	}
+	for _, tt := range tests {
+		file, line, column, ok := changes.Locate(strings.Index(changes.Text, tt.text))
+		if !ok || file != tt.file || line != tt.line || column != tt.column {
+			t.Errorf("Locate(%q) = %s:%d:%d, want %s:%d:%d", tt.text, file, line, column, tt.file, tt.line, tt.column)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package gitdiff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LineKind 变更块中行的类型，取值为统一 diff 中的行首字符
+type LineKind byte
+
+const (
+	// LineContext 未修改的上下文行
+	LineContext LineKind = ' '
+	// LineAdded 新增行
+	LineAdded LineKind = '+'
+	// LineRemoved 删除行
+	LineRemoved LineKind = '-'
+)
+
+// MarshalText 以名称（context、added、removed）输出行类型
+func (k LineKind) MarshalText() ([]byte, error) {
+	switch k {
+	case LineAdded:
+		return []byte("added"), nil
+	case LineRemoved:
+		return []byte("removed"), nil
+	default:
+		return []byte("context"), nil
+	}
+}
+
+// Line 变更块中的一行
+type Line struct {
+	Kind    LineKind `json:"kind"`
+	Text    string   `json:"text"`               // 行内容，不含行首的 +、-、空格和换行符
+	OldLine int      `json:"old_line,omitempty"` // 在旧文件中的行号，新增行为 0
+	NewLine int      `json:"new_line,omitempty"` // 在新文件中的行号，删除行为 0
+}
+
+// Hunk 统一 diff 中的一个变更块
+type Hunk struct {
+	OldStart int    `json:"old_start"`
+	OldLines int    `json:"old_lines"`
+	NewStart int    `json:"new_start"`
+	NewLines int    `json:"new_lines"`
+	Section  string `json:"section,omitempty"` // @@ 行末尾的函数或章节标题
+	Lines    []Line `json:"lines"`
+}
+
+// File 统一 diff 中一个文件的变更
+type File struct {
+	OldName string `json:"old_name,omitempty"` // 旧文件路径，新建文件为空
+	NewName string `json:"new_name,omitempty"` // 新文件路径，删除文件为空
+	Binary  bool   `json:"binary,omitempty"`
+	Hunks   []Hunk `json:"hunks,omitempty"`
+}
+
+// Name 返回文件路径，删除的文件返回旧路径
+func (f *File) Name() string {
+	if f.NewName != "" {
+		return f.NewName
+	}
+	return f.OldName
+}
+
+// hunkHeader 变更块头，如 "@@ -12,7 +12,9 @@ func main() {"
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@ ?(.*)$`)
+
+// parser 统一 diff 解析状态
+type parser struct {
+	files  []File
+	file   *File
+	hunk   *Hunk
+	oldRem int // 当前变更块中尚未读到的旧文件行数
+	newRem int // 当前变更块中尚未读到的新文件行数
+	oldNo  int
+	newNo  int
+}
+
+// Parse 解析统一 diff（git diff、git show、diff -u 的输出），第一个文件之前的内容（如提交说明）被忽略
+func Parse(r io.Reader) ([]File, error) {
+	p := &parser{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if err := p.line(strings.TrimSuffix(scanner.Text(), "\r")); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read diff: %w", err)
+	}
+	if p.hunk != nil && (p.oldRem > 0 || p.newRem > 0) {
+		return nil, fmt.Errorf("line %d: hunk is missing %d old and %d new lines", lineNo, p.oldRem, p.newRem)
+	}
+	p.flush()
+	return p.files, nil
+}
+
+// line 处理一行输入
+func (p *parser) line(text string) error {
+	// 变更块内按行数读取，以 "-"、"+" 开头的内容行不会被误认为文件头
+	if p.hunk != nil && (p.oldRem > 0 || p.newRem > 0) {
+		return p.hunkLine(text)
+	}
+	if p.hunk != nil && strings.HasPrefix(text, `\`) {
+		return nil
+	}
+	p.hunk = nil
+
+	switch {
+	case strings.HasPrefix(text, "diff --git "):
+		p.flush()
+		p.file = &File{}
+		p.file.OldName, p.file.NewName = gitNames(strings.TrimPrefix(text, "diff --git "))
+	case strings.HasPrefix(text, "--- "):
+		// diff -u 的输出没有 diff --git 行，以 --- 开始一个文件
+		if p.file == nil || len(p.file.Hunks) > 0 {
+			p.flush()
+			p.file = &File{}
+		}
+		p.file.OldName = headerName(strings.TrimPrefix(text, "--- "))
+	case strings.HasPrefix(text, "+++ ") && p.file != nil:
+		p.file.NewName = headerName(strings.TrimPrefix(text, "+++ "))
+	case p.file == nil:
+		// 第一个文件之前的内容
+	case strings.HasPrefix(text, "@@ "):
+		return p.startHunk(text)
+	case strings.HasPrefix(text, "new file mode"):
+		p.file.OldName = ""
+	case strings.HasPrefix(text, "deleted file mode"):
+		p.file.NewName = ""
+	case strings.HasPrefix(text, "rename from "):
+		p.file.OldName = unquote(strings.TrimPrefix(text, "rename from "))
+	case strings.HasPrefix(text, "rename to "):
+		p.file.NewName = unquote(strings.TrimPrefix(text, "rename to "))
+	case strings.HasPrefix(text, "Binary files "), text == "GIT binary patch":
+		p.file.Binary = true
+	}
+	return nil
+}
+
+// startHunk 解析变更块头
+func (p *parser) startHunk(text string) error {
+	m := hunkHeader.FindStringSubmatch(text)
+	if m == nil {
+		return fmt.Errorf("invalid hunk header %q", text)
+	}
+	h := Hunk{
+		OldStart: atoi(m[1], 0),
+		OldLines: atoi(m[2], 1),
+		NewStart: atoi(m[3], 0),
+		NewLines: atoi(m[4], 1),
+		Section:  m[5],
+	}
+	p.file.Hunks = append(p.file.Hunks, h)
+	p.hunk = &p.file.Hunks[len(p.file.Hunks)-1]
+	p.oldRem, p.newRem = h.OldLines, h.NewLines
+	p.oldNo, p.newNo = h.OldStart, h.NewStart
+	return nil
+}
+
+// hunkLine 解析变更块中的一行
+func (p *parser) hunkLine(text string) error {
+	if strings.HasPrefix(text, `\`) {
+		return nil
+	}
+	// 部分工具会去掉空上下文行的行首空格
+	kind, content := LineContext, ""
+	if text != "" {
+		kind, content = LineKind(text[0]), text[1:]
+	}
+
+	switch kind {
+	case LineContext:
+		if p.oldRem == 0 || p.newRem == 0 {
+			return fmt.Errorf("unexpected context line in hunk %q", text)
+		}
+		p.hunk.Lines = append(p.hunk.Lines, Line{Kind: kind, Text: content, OldLine: p.oldNo, NewLine: p.newNo})
+		p.oldNo++
+		p.newNo++
+		p.oldRem--
+		p.newRem--
+	case LineRemoved:
+		if p.oldRem == 0 {
+			return fmt.Errorf("unexpected removed line in hunk %q", text)
+		}
+		p.hunk.Lines = append(p.hunk.Lines, Line{Kind: kind, Text: content, OldLine: p.oldNo})
+		p.oldNo++
+		p.oldRem--
+	case LineAdded:
+		if p.newRem == 0 {
+			return fmt.Errorf("unexpected added line in hunk %q", text)
+		}
+		p.hunk.Lines = append(p.hunk.Lines, Line{Kind: kind, Text: content, NewLine: p.newNo})
+		p.newNo++
+		p.newRem--
+	default:
+		return fmt.Errorf("invalid hunk line %q", text)
+	}
+	return nil
+}
+
+// flush 结束当前文件
+func (p *parser) flush() {
+	if p.file != nil {
+		p.files = append(p.files, *p.file)
+	}
+	p.file, p.hunk = nil, nil
+}
+
+// gitNames 从 "diff --git a/x b/x" 中取出新旧文件路径，仅在没有 ---、+++ 行（如纯改名、二进制文件）时使用
+func gitNames(s string) (string, string) {
+	if strings.HasPrefix(s, `"`) {
+		if old, rest, ok := cutQuoted(s); ok {
+			return stripPrefix(old), stripPrefix(unquote(strings.TrimSpace(rest)))
+		}
+	}
+	// 路径中没有空格时两个路径以空格分隔；有空格时新旧路径通常相同，按对半切分
+	if i := strings.Index(s, " b/"); i >= 0 && strings.Count(s, " b/") == 1 {
+		return stripPrefix(s[:i]), stripPrefix(s[i+1:])
+	}
+	half := len(s) / 2
+	return stripPrefix(s[:half]), stripPrefix(strings.TrimSpace(s[half:]))
+}
+
+// cutQuoted 切出开头的带引号路径
+func cutQuoted(s string) (string, string, bool) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			name, err := strconv.Unquote(s[:i+1])
+			return name, s[i+1:], err == nil
+		}
+	}
+	return "", "", false
+}
+
+// headerName 解析 ---、+++ 行中的文件路径，/dev/null 返回空字符串
+func headerName(s string) string {
+	// diff -u 在路径后以制表符分隔时间戳
+	if i := strings.IndexByte(s, '\t'); i >= 0 {
+		s = s[:i]
+	}
+	s = unquote(strings.TrimSpace(s))
+	if s == "/dev/null" {
+		return ""
+	}
+	return stripPrefix(s)
+}
+
+// stripPrefix 去掉 git 添加的 a/、b/ 前缀
+func stripPrefix(s string) string {
+	s = unquote(s)
+	if strings.HasPrefix(s, "a/") || strings.HasPrefix(s, "b/") {
+		return s[2:]
+	}
+	return s
+}
+
+// unquote 解析 git 对含特殊字符的路径加的引号
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if name, err := strconv.Unquote(s); err == nil {
+			return name
+		}
+	}
+	return s
+}
+
+// atoi 解析变更块头中的数字，省略时返回默认值
+func atoi(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,106 @@
+package gitdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		diff  string
+		files []File
+		added []int // 各文件新增行在新文件中的行号
+	}{
+		{
+			name: "git show with commit message",
+			diff: "commit 1234abcd\nAuthor: someone\n\n    Update docs\n\n" +
+				"diff --git a/docs/guide.md b/docs/guide.md\nindex 83db48f..bf269f4 100644\n--- a/docs/guide.md\n+++ b/docs/guide.md\n" +
+				"@@ -10,4 +10,5 @@ ## Setup\n First line.\n-Old line.\n+--- not a header\n+New line.\n \n" +
+				"\\ No newline at end of file\n Last line.\n",
+			files: []File{{OldName: "docs/guide.md", NewName: "docs/guide.md"}},
+			added: []int{11, 12},
+		},
+		{
+			name: "new, deleted, renamed and binary files",
+			diff: "diff --git a/new.txt b/new.txt\nnew file mode 100644\nindex 0000000..e69de29\n--- /dev/null\n+++ b/new.txt\n@@ -0,0 +1 @@\n+hello\n" +
+				"diff --git a/old.txt b/old.txt\ndeleted file mode 100644\n--- a/old.txt\n+++ /dev/null\n@@ -1 +0,0 @@\n-bye\n" +
+				"diff --git a/a.txt b/b.txt\nsimilarity index 100%\nrename from a.txt\nrename to b.txt\n" +
+				"diff --git a/logo.png b/logo.png\nBinary files a/logo.png and b/logo.png differ\n",
+			files: []File{
+				{NewName: "new.txt"},
+				{OldName: "old.txt"},
+				{OldName: "a.txt", NewName: "b.txt"},
+				{OldName: "logo.png", NewName: "logo.png", Binary: true},
+			},
+			added: []int{1},
+		},
+		{
+			name: "diff -u without git headers",
+			diff: "--- notes.txt\t2024-01-01 10:00:00\n+++ notes.txt\t2024-01-02 10:00:00\n@@ -1,2 +1,2 @@\n-a\n+b\n c\n" +
+				"--- \"with space.txt\"\n+++ \"with space.txt\"\n@@ -3 +3,2 @@\n x\n+y\n",
+			files: []File{
+				{OldName: "notes.txt", NewName: "notes.txt"},
+				{OldName: "with space.txt", NewName: "with space.txt"},
+			},
+			added: []int{1, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := Parse(strings.NewReader(tt.diff))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(files) != len(tt.files) {
+				t.Fatalf("got %d files, want %d", len(files), len(tt.files))
+			}
+			var added []int
+			for i, f := range files {
+				want := tt.files[i]
+				if f.OldName != want.OldName || f.NewName != want.NewName || f.Binary != want.Binary {
+					t.Errorf("files[%d] = %q → %q (binary %v), want %q → %q (binary %v)",
+						i, f.OldName, f.NewName, f.Binary, want.OldName, want.NewName, want.Binary)
+				}
+				for _, h := range f.Hunks {
+					for _, line := range h.Lines {
+						if line.Kind == LineAdded {
+							added = append(added, line.NewLine)
+						}
+					}
+				}
+			}
+			if len(added) != len(tt.added) {
+				t.Fatalf("added lines = %v, want %v", added, tt.added)
+			}
+			for i := range added {
+				if added[i] != tt.added[i] {
+					t.Errorf("added lines = %v, want %v", added, tt.added)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want string
+	}{
+		{"bad hunk header", "--- a/x\n+++ b/x\n@@ -1 +1 @\n", "line 3: invalid hunk header"},
+		{"truncated hunk", "--- a/x\n+++ b/x\n@@ -1,2 +1,2 @@\n a\n", "hunk is missing 1 old and 1 new lines"},
+		{"unknown line", "--- a/x\n+++ b/x\n@@ -1 +1 @@\n?a\n", "line 4: invalid hunk line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.diff))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -7,6 +7,9 @@ type DetectionRequest struct {
 	Text     string            `json:"text"`      // 待检测文本
 	Options  DetectionOptions  `json:"options"`   // 检测选项
 	Metadata map[string]string `json:"metadata"`  // 元数据
+
+	// OnLayer 每个检测层完成时调用，用于异步任务上报阶段性结果，可为 nil
+	OnLayer func(LayerResult) `json:"-"`
 }
 
 // DetectionOptions 检测选项
@@ -14,8 +17,15 @@ type DetectionOptions struct {
 	EnabledRules []string `json:"enabled_rules"` // 启用的规则列表，空表示全部启用
 	Language     string   `json:"language"`      // 语言，默认"zh"
 	OutputFormat string   `json:"output_format"` // 输出格式：text, json
+	InputFormat  string   `json:"input_format"`  // 输入格式：text, markdown，默认 text
 }
 
+// 输入格式
+const (
+	InputFormatText     = "text"
+	InputFormatMarkdown = "markdown"
+)
+
 // DetectionResult 表示检测结果
 type DetectionResult struct {
 	RequestID    string          `json:"request_id"`     // 请求ID
@@ -26,6 +36,21 @@ type DetectionResult struct {
 	RiskLevel    RiskLevel       `json:"risk_level"`     // 风险等级
 	ProcessTime  time.Duration   `json:"process_time"`   // 处理时间
 	DetectedAt   time.Time       `json:"detected_at"`    // 检测时间
+
+	Multimodal        *MultimodalResult `json:"multimodal,omitempty"`         // 多模态检测详情（仅多模态模式）
+	AnalyzerVersion   string            `json:"analyzer_version,omitempty"`   // 分析器版本
+	ConfigFingerprint string            `json:"config_fingerprint,omitempty"` // 检测所用配置的指纹
+	RevisionAuthors   []RevisionAuthor  `json:"revision_authors,omitempty"`   // 修订作者汇总（仅带修订记录的文档）
+	Segments          []SegmentScore    `json:"segments,omitempty"`           // 各片段的评分（字幕中的句子、笔记本的单元格、EPUB 的章节）
+}
+
+// RevisionAuthor 文档中某位作者的修订汇总
+type RevisionAuthor struct {
+	Author        string `json:"author"`         // 修订作者
+	Insertions    int    `json:"insertions"`     // 插入次数
+	Deletions     int    `json:"deletions"`      // 删除次数
+	InsertedChars int    `json:"inserted_chars"` // 插入的字符数
+	DeletedChars  int    `json:"deleted_chars"`  // 删除的字符数
 }
 
 // RiskLevel 风险等级
@@ -0,0 +1,32 @@
+package models
+
+// Edit 文本修改操作（基于字节偏移）
+type Edit struct {
+	RuleType    RuleType `json:"rule_type"`   // 产生修改的规则
+	Offset      int      `json:"offset"`      // 起始字节偏移量 (从0开始)
+	Length      int      `json:"length"`      // 被替换的字节长度
+	Original    string   `json:"original"`    // 被替换的原文
+	Replacement string   `json:"replacement"` // 替换文本
+	Reason      string   `json:"reason"`      // 修改原因
+}
+
+// End 返回修改范围的结束偏移量（不含）
+func (e Edit) End() int {
+	return e.Offset + e.Length
+}
+
+// Overlaps 判断两个修改的范围是否重叠
+// 两个在同一位置的纯插入也视为重叠
+func (e Edit) Overlaps(other Edit) bool {
+	if e.Offset == other.Offset {
+		return true
+	}
+	return e.Offset < other.End() && other.Offset < e.End()
+}
+
+// Fixer 可自动修复的规则接口
+// 规则可选实现此接口，基于检测结果中的 Match 位置返回精确的修改操作
+type Fixer interface {
+	// Fix 根据规则检测结果生成修改操作
+	Fix(text string, result RuleResult) []Edit
+}
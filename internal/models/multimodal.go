@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // MultimodalResult 多模态检测结果
 type MultimodalResult struct {
 	// Layer 1: 规则检测分数 (0-100)
@@ -72,6 +74,20 @@ const (
 	DetectionModeMultimodal DetectionMode = "multimodal"
 )
 
+// 检测层名称
+const (
+	LayerRule       = "rule"
+	LayerStatistics = "statistics"
+	LayerSemantic   = "semantic"
+)
+
+// LayerResult 单个检测层完成后的阶段性结果
+type LayerResult struct {
+	Layer       string    `json:"layer"`
+	Score       float64   `json:"score"` // 该层的人类写作分数 (0-100)
+	CompletedAt time.Time `json:"completed_at"`
+}
+
 // RuleLayerDetails 规则层详细结果
 type RuleLayerDetails struct {
 	// 检测到的规则数量
@@ -33,6 +33,12 @@ const (
 
 	// Signal 10: 完美主义陷阱
 	RuleTypePerfectionism RuleType = "perfectionism"
+
+	// Signal 11: 隐形字符与同形字母
+	RuleTypeHiddenChars RuleType = "hidden_characters"
+
+	// Signal 12: 三段式与排比结构
+	RuleTypeParallelStructure RuleType = "parallel_structure"
 )
 
 // RuleResult 规则检测结果
@@ -66,6 +72,9 @@ type Match struct {
 	Position Position `json:"position"`  // 位置信息
 	Context  string   `json:"context"`   // 上下文
 	Reason   string   `json:"reason"`    // 匹配原因
+	Location string   `json:"location,omitempty"` // 在原文档中的段落位置描述（如 "第 3.2 节第 14 段"），仅无行号的文档格式设置
+	Time     *TimeRange `json:"time,omitempty"`   // 在音视频中的时间范围，仅字幕格式设置
+	File     string   `json:"file,omitempty"`     // 所在文件，仅检测 diff 时设置
 }
 
 // Position 位置信息
@@ -112,6 +121,8 @@ func GetRuleTypeName(ruleType RuleType) string {
 		RuleTypeKnowledgeCutoff:   "知识截止日期",
 		RuleTypeCollaborative:     "协作式语气",
 		RuleTypePerfectionism:     "完美主义陷阱",
+		RuleTypeHiddenChars:       "隐形字符与同形字母",
+		RuleTypeParallelStructure: "三段式与排比结构",
 	}
 	if name, ok := names[ruleType]; ok {
 		return name
@@ -132,5 +143,7 @@ func GetAllRuleTypes() []RuleType {
 		RuleTypeKnowledgeCutoff,
 		RuleTypeCollaborative,
 		RuleTypePerfectionism,
+		RuleTypeHiddenChars,
+		RuleTypeParallelStructure,
 	}
 }
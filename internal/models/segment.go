@@ -0,0 +1,35 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeRange 音视频时间轴上的时间范围
+type TimeRange struct {
+	Start time.Duration `json:"start"` // 开始时间
+	End   time.Duration `json:"end"`   // 结束时间
+}
+
+// String 返回 "00:03:12–00:03:18" 形式的时间范围
+func (r TimeRange) String() string {
+	return formatTimestamp(r.Start) + "–" + formatTimestamp(r.End)
+}
+
+// formatTimestamp 将时间格式化为 时:分:秒
+func formatTimestamp(d time.Duration) string {
+	seconds := int(d / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+// SegmentScore 文档中一个片段的评分，如字幕中由若干条字幕连成的一句话、Jupyter 笔记本的单元格、EPUB 的章节
+type SegmentScore struct {
+	Index   int        `json:"index"`           // 片段序号：字幕为提取文本中的段落序号，单元格和章节为部分序号
+	Text    string     `json:"text,omitempty"`  // 片段文本（只有字幕片段才会设置）
+	Part    string     `json:"part,omitempty"`  // 单元格或章节的名称，如 "单元格 3"、"OEBPS/chapter02.xhtml"
+	Title   string     `json:"title,omitempty"` // 单元格或章节中的第一个标题
+	Time    *TimeRange `json:"time,omitempty"`  // 在音视频中的时间范围
+	Score   float64    `json:"score"`           // 片段得分（0-100），各规则的扣分按匹配项所在的片段分摊
+	Matches int        `json:"matches"`         // 片段中的匹配项个数
+	Rules   []string   `json:"rules,omitempty"` // 在片段中有匹配项的规则名称
+}
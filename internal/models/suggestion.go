@@ -2,12 +2,32 @@ package models
 
 // Suggestion 改进建议
 type Suggestion struct {
-	Category    SuggestionCategory `json:"category"`     // 建议类别
-	Priority    Priority           `json:"priority"`     // 优先级
-	Title       string             `json:"title"`        // 标题
-	Description string             `json:"description"`  // 详细描述
-	Examples    []Example          `json:"examples"`     // 示例
-	RelatedRule RuleType           `json:"related_rule"` // 相关规则
+	Category    SuggestionCategory `json:"category"`          // 建议类别
+	Priority    Priority           `json:"priority"`          // 优先级
+	Title       string             `json:"title"`             // 标题
+	Description string             `json:"description"`       // 详细描述
+	Examples    []Example          `json:"examples"`          // 示例
+	RelatedRule RuleType           `json:"related_rule"`      // 相关规则
+	Source      SuggestionSource   `json:"source"`            // 建议来源
+	Anchors     []Anchor           `json:"anchors,omitempty"` // 建议针对的具体文本位置
+}
+
+// SuggestionSource 建议来源
+type SuggestionSource string
+
+const (
+	SourceRule SuggestionSource = "rule" // 规则检测
+	SourceLLM  SuggestionSource = "llm"  // 大模型语义分析
+)
+
+// Anchor 建议锚定的文本片段
+type Anchor struct {
+	Text         string   `json:"text"`                   // 原文片段
+	Position     Position `json:"position"`               // 位置信息
+	Replacements []string `json:"replacements,omitempty"` // 可直接应用的替换候选，空字符串表示删除
+	Location     string   `json:"location,omitempty"`     // 在原文档中的段落位置描述，仅无行号的文档格式设置
+	Time         *TimeRange `json:"time,omitempty"`       // 在音视频中的时间范围，仅字幕格式设置
+	File         string   `json:"file,omitempty"`         // 所在文件，仅检测 diff 时设置
 }
 
 // SuggestionCategory 建议类别
@@ -47,6 +67,7 @@ func NewSuggestion(category SuggestionCategory, priority Priority, title, descri
 		Description: description,
 		Examples:    []Example{},
 		RelatedRule: relatedRule,
+		Source:      SourceRule,
 	}
 }
 
@@ -59,6 +80,111 @@ func (s *Suggestion) AddExample(before, after, reason string) {
 	})
 }
 
+// AddAnchor 添加锚定位置
+func (s *Suggestion) AddAnchor(text string, position Position, replacements ...string) {
+	s.Anchors = append(s.Anchors, Anchor{
+		Text:         text,
+		Position:     position,
+		Replacements: replacements,
+	})
+}
+
+// Overlaps 判断两个锚定位置的范围是否重叠
+func (a Anchor) Overlaps(other Anchor) bool {
+	aEnd := a.Position.Offset + a.Position.Length
+	bEnd := other.Position.Offset + other.Position.Length
+	return a.Position.Offset < bEnd && other.Position.Offset < aEnd
+}
+
+// DeduplicateSuggestions 合并规则与大模型来源的重复建议
+// 大模型建议的锚点与规则建议的锚点重叠时，将其替换候选合并到规则锚点中；
+// 所有锚点都被合并的大模型建议会被移除，标题和关联规则相同的建议只保留第一条
+func DeduplicateSuggestions(suggestions []Suggestion) []Suggestion {
+	type key struct {
+		rule  RuleType
+		title string
+	}
+	seen := make(map[key]bool)
+
+	// 先收集规则建议，大模型建议的锚点合并到其中
+	var ruleSuggestions, llmSuggestions []Suggestion
+	for _, s := range suggestions {
+		if s.Source == SourceLLM {
+			llmSuggestions = append(llmSuggestions, s)
+		} else {
+			ruleSuggestions = append(ruleSuggestions, s)
+		}
+	}
+
+	var result []Suggestion
+	for _, s := range ruleSuggestions {
+		k := key{s.RelatedRule, s.Title}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		result = append(result, s)
+	}
+
+	for _, s := range llmSuggestions {
+		k := key{s.RelatedRule, s.Title}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+
+		var remaining []Anchor
+		for _, anchor := range s.Anchors {
+			if !mergeAnchor(result, anchor) {
+				remaining = append(remaining, anchor)
+			}
+		}
+		if len(s.Anchors) > 0 && len(remaining) == 0 {
+			continue
+		}
+		s.Anchors = remaining
+		result = append(result, s)
+	}
+
+	return result
+}
+
+// mergeAnchor 将锚点合并到第一个与之重叠的规则锚点中
+// 仅当两者范围完全相同时合并替换候选，部分重叠的锚点视为重复直接丢弃
+func mergeAnchor(suggestions []Suggestion, anchor Anchor) bool {
+	for i := range suggestions {
+		if suggestions[i].Source == SourceLLM {
+			continue
+		}
+		for j := range suggestions[i].Anchors {
+			target := &suggestions[i].Anchors[j]
+			if !target.Overlaps(anchor) {
+				continue
+			}
+			if target.Position.Offset != anchor.Position.Offset || target.Position.Length != anchor.Position.Length {
+				return true
+			}
+			for _, candidate := range anchor.Replacements {
+				if !containsString(target.Replacements, candidate) {
+					target.Replacements = append(target.Replacements, candidate)
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// containsString 判断字符串切片是否包含指定值
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // GetCategoryName 获取类别名称
 func GetCategoryName(category SuggestionCategory) string {
 	names := map[SuggestionCategory]string{
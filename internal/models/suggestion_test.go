@@ -113,3 +113,80 @@ func TestGetCategoryName_Unknown(t *testing.T) {
 		t.Errorf("GetCategoryName(unknown) = %s, want 'unknown'", got)
 	}
 }
+
+func TestSuggestion_AddAnchor(t *testing.T) {
+	s := NewSuggestion(CategoryVocabulary, PriorityHigh, "Title", "Desc", RuleTypeHighFreqWords)
+	if s.Source != SourceRule {
+		t.Errorf("Source = %s, want %s", s.Source, SourceRule)
+	}
+
+	s.AddAnchor("crucial", Position{Line: 1, Column: 5, Offset: 4, Length: 7}, "important", "key")
+
+	if len(s.Anchors) != 1 {
+		t.Fatalf("len(Anchors) = %d, want 1", len(s.Anchors))
+	}
+	anchor := s.Anchors[0]
+	if anchor.Text != "crucial" || anchor.Position.Offset != 4 || anchor.Position.Length != 7 {
+		t.Errorf("Anchor = %+v, unexpected span", anchor)
+	}
+	if len(anchor.Replacements) != 2 || anchor.Replacements[0] != "important" {
+		t.Errorf("Anchor.Replacements = %v, want [important key]", anchor.Replacements)
+	}
+}
+
+func TestAnchor_Overlaps(t *testing.T) {
+	base := Anchor{Position: Position{Offset: 10, Length: 5}}
+	tests := []struct {
+		name   string
+		offset int
+		length int
+		want   bool
+	}{
+		{"same span", 10, 5, true},
+		{"inside", 11, 2, true},
+		{"overlap left", 8, 3, true},
+		{"adjacent left", 5, 5, false},
+		{"adjacent right", 15, 3, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := Anchor{Position: Position{Offset: tt.offset, Length: tt.length}}
+			if got := base.Overlaps(other); got != tt.want {
+				t.Errorf("Overlaps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeduplicateSuggestions(t *testing.T) {
+	rule := NewSuggestion(CategoryVocabulary, PriorityHigh, "减少高频词汇", "desc", RuleTypeHighFreqWords)
+	rule.AddAnchor("crucial", Position{Offset: 4, Length: 7}, "important")
+
+	// 与规则锚点完全重叠，替换候选应合并
+	llmSame := Suggestion{Source: SourceLLM, Title: "换词", RelatedRule: RuleTypeHighFreqWords}
+	llmSame.AddAnchor("crucial", Position{Offset: 4, Length: 7}, "key", "important")
+
+	// 不重叠的锚点，建议应保留
+	llmOther := Suggestion{Source: SourceLLM, Title: "增加个人经历", RelatedRule: RuleTypeHighFreqWords}
+	llmOther.AddAnchor("In conclusion", Position{Offset: 40, Length: 13}, "总之")
+
+	// 与规则建议标题相同，应被去重
+	llmDup := Suggestion{Source: SourceLLM, Title: "减少高频词汇", RelatedRule: RuleTypeHighFreqWords}
+
+	got := DeduplicateSuggestions([]Suggestion{llmSame, rule, llmOther, llmDup})
+
+	if len(got) != 2 {
+		t.Fatalf("len(result) = %d, want 2: %+v", len(got), got)
+	}
+	if got[0].Source != SourceRule {
+		t.Errorf("result[0].Source = %s, want rule suggestion first", got[0].Source)
+	}
+	replacements := got[0].Anchors[0].Replacements
+	if len(replacements) != 2 || replacements[0] != "important" || replacements[1] != "key" {
+		t.Errorf("merged Replacements = %v, want [important key]", replacements)
+	}
+	if got[1].Title != "增加个人经历" || len(got[1].Anchors) != 1 {
+		t.Errorf("result[1] = %+v, want LLM suggestion with its own anchor", got[1])
+	}
+}
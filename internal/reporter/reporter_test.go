@@ -127,6 +127,118 @@ func TestTextReporter_Generate_NoIssues(t *testing.T) {
 	}
 }
 
+func TestTextReporter_Generate_DocumentLocations(t *testing.T) {
+	reporter := NewTextReporter(false)
+
+	result := &models.DetectionResult{
+		Score:     models.Score{Total: 70},
+		RiskLevel: models.RiskLevelMedium,
+		RuleResults: []models.RuleResult{
+			{
+				RuleName: "AI高频词汇",
+				Detected: true,
+				Severity: models.SeverityHigh,
+				Matches: []models.Match{
+					{Text: "crucial", Position: models.Position{Line: 11}, Location: "第 3.2 节第 14 段"},
+					{Text: "delve", Position: models.Position{Line: 4}},
+				},
+			},
+		},
+		RevisionAuthors: []models.RevisionAuthor{
+			{Author: "张三", Insertions: 2, InsertedChars: 15, Deletions: 1, DeletedChars: 4},
+			{Insertions: 1, InsertedChars: 3},
+		},
+		DetectedAt: time.Now(),
+	}
+
+	output, err := reporter.Generate(result)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"- 第 3.2 节第 14 段: crucial",
+		"- 行4: delve",
+		"【修订作者】",
+		"张三: 插入 2 处（15 字），删除 1 处（4 字）",
+		"（未知作者）: 插入 1 处（3 字），删除 0 处（0 字）",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Generate() output missing %q", want)
+		}
+	}
+}
+
+func TestTextReporter_Generate_Timeline(t *testing.T) {
+	reporter := NewTextReporter(false)
+
+	span := &models.TimeRange{Start: 3*time.Minute + 12*time.Second, End: 3*time.Minute + 18*time.Second}
+	result := &models.DetectionResult{
+		Score:     models.Score{Total: 70},
+		RiskLevel: models.RiskLevelMedium,
+		RuleResults: []models.RuleResult{
+			{
+				RuleName: "协作式语气检测",
+				Detected: true,
+				Severity: models.SeverityHigh,
+				Matches:  []models.Match{{Text: "I hope this helps", Location: span.String(), Time: span}},
+			},
+		},
+		Segments: []models.SegmentScore{
+			{Index: 0, Text: "Thanks for watching.", Time: &models.TimeRange{End: 2 * time.Second}, Score: 100},
+			{Index: 1, Text: "I hope this helps.", Time: span, Score: 80, Matches: 1, Rules: []string{"协作式语气检测"}},
+		},
+		DetectedAt: time.Now(),
+	}
+
+	output, err := reporter.Generate(result)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"- 00:03:12–00:03:18: I hope this helps",
+		"【时间轴】",
+		"00:03:12–00:03:18: 协作式语气检测（片段得分 80.0）\n   I hope this helps.",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Generate() output missing %q", want)
+		}
+	}
+	if strings.Contains(output, "Thanks for watching") {
+		t.Error("Generate() should only list segments with matches")
+	}
+}
+
+func TestTextReporter_Generate_Parts(t *testing.T) {
+	reporter := NewTextReporter(false)
+
+	result := &models.DetectionResult{
+		Score:     models.Score{Total: 70},
+		RiskLevel: models.RiskLevelMedium,
+		Segments: []models.SegmentScore{
+			{Index: 0, Part: "OEBPS/ch01.xhtml", Title: "Chapter One", Score: 100},
+			{Index: 1, Part: "OEBPS/ch02.xhtml", Title: "Chapter Two", Score: 62.5, Matches: 3, Rules: []string{"协作式语气检测", "高频词检测"}},
+		},
+		DetectedAt: time.Now(),
+	}
+
+	output, err := reporter.Generate(result)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.Contains(output, "【分部评分】") ||
+		!strings.Contains(output, "OEBPS/ch02.xhtml（Chapter Two）: 片段得分 62.5，3 处匹配\n   协作式语气检测、高频词检测") {
+		t.Errorf("Generate() output missing part scores:\n%s", output)
+	}
+	if strings.Contains(output, "ch01.xhtml") {
+		t.Error("Generate() should only list parts with matches")
+	}
+	if strings.Contains(output, "【时间轴】") {
+		t.Error("Generate() should not list parts in the timeline")
+	}
+}
+
 func TestTextReporter_CreateScoreBar(t *testing.T) {
 	reporter := NewTextReporter(false)
 
@@ -43,6 +43,15 @@ func (r *TextReporter) Generate(result *models.DetectionResult) (string, error)
 	// 改进建议
 	r.writeSuggestions(&sb, result)
 
+	// 修订作者
+	r.writeRevisionAuthors(&sb, result)
+
+	// 时间轴
+	r.writeTimeline(&sb, result)
+
+	// 单元格和章节
+	r.writeParts(&sb, result)
+
 	// 处理时间
 	sb.WriteString(fmt.Sprintf("\n处理时间: %v\n", result.ProcessTime))
 	sb.WriteString(fmt.Sprintf("检测时间: %s\n", result.DetectedAt.Format("2006-01-02 15:04:05")))
@@ -164,7 +173,7 @@ func (r *TextReporter) writeDetectedIssues(sb *strings.Builder, result *models.D
 			}
 			for i := 0; i < maxShow; i++ {
 				match := ruleResult.Matches[i]
-				sb.WriteString(fmt.Sprintf("     - 行%d: %s\n", match.Position.Line, match.Text))
+				sb.WriteString(fmt.Sprintf("     - %s: %s\n", formatLocation(match.Location, match.Position), match.Text))
 			}
 			if len(ruleResult.Matches) > maxShow {
 				sb.WriteString(fmt.Sprintf("     ... 还有 %d 个匹配项\n", len(ruleResult.Matches)-maxShow))
@@ -193,6 +202,8 @@ func (r *TextReporter) writeSuggestions(sb *strings.Builder, result *models.Dete
 		))
 		sb.WriteString(fmt.Sprintf("   %s\n", suggestion.Description))
 
+		r.writeAnchors(sb, suggestion.Anchors)
+
 		if len(suggestion.Examples) > 0 {
 			sb.WriteString("   示例:\n")
 			for _, example := range suggestion.Examples {
@@ -207,6 +218,118 @@ func (r *TextReporter) writeSuggestions(sb *strings.Builder, result *models.Dete
 	}
 }
 
+// maxAnchorsShown 每条建议显示的最大锚点数
+const maxAnchorsShown = 5
+
+// writeAnchors 写入建议锚定的具体位置
+func (r *TextReporter) writeAnchors(sb *strings.Builder, anchors []models.Anchor) {
+	if len(anchors) == 0 {
+		return
+	}
+
+	sb.WriteString("   位置:\n")
+	shown := len(anchors)
+	if shown > maxAnchorsShown {
+		shown = maxAnchorsShown
+	}
+	for _, anchor := range anchors[:shown] {
+		sb.WriteString(fmt.Sprintf("     - %s: %s\n", formatLocation(anchor.Location, anchor.Position), formatAnchorAction(anchor)))
+	}
+	if len(anchors) > shown {
+		sb.WriteString(fmt.Sprintf("     ... 还有 %d 处\n", len(anchors)-shown))
+	}
+}
+
+// formatLocation 格式化匹配位置，有段落位置描述时优先使用
+func formatLocation(location string, pos models.Position) string {
+	if location != "" {
+		return location
+	}
+	return fmt.Sprintf("行%d", pos.Line)
+}
+
+// writeRevisionAuthors 写入文档修订记录的作者汇总
+func (r *TextReporter) writeRevisionAuthors(sb *strings.Builder, result *models.DetectionResult) {
+	if len(result.RevisionAuthors) == 0 {
+		return
+	}
+
+	sb.WriteString("【修订作者】\n")
+	sb.WriteString(strings.Repeat("─", 60) + "\n")
+	for _, author := range result.RevisionAuthors {
+		name := author.Author
+		if name == "" {
+			name = "（未知作者）"
+		}
+		sb.WriteString(fmt.Sprintf("%s: 插入 %d 处（%d 字），删除 %d 处（%d 字）\n",
+			name, author.Insertions, author.InsertedChars, author.Deletions, author.DeletedChars))
+	}
+	sb.WriteString("\n")
+}
+
+// writeTimeline 写入带时间范围的片段（如字幕中的句子）中检测到的问题，按时间顺序排列
+func (r *TextReporter) writeTimeline(sb *strings.Builder, result *models.DetectionResult) {
+	var segments []models.SegmentScore
+	for _, segment := range result.Segments {
+		if segment.Time != nil && segment.Matches > 0 {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return
+	}
+
+	sb.WriteString("【时间轴】\n")
+	sb.WriteString(strings.Repeat("─", 60) + "\n")
+	for _, segment := range segments {
+		sb.WriteString(fmt.Sprintf("%s: %s（片段得分 %.1f）\n", segment.Time, strings.Join(segment.Rules, "、"), segment.Score))
+		sb.WriteString(fmt.Sprintf("   %s\n", segment.Text))
+	}
+	sb.WriteString("\n")
+}
+
+// writeParts 写入笔记本单元格、EPUB 章节等部分的片段得分，只列出有匹配项的部分，按在文档中的顺序排列
+func (r *TextReporter) writeParts(sb *strings.Builder, result *models.DetectionResult) {
+	var segments []models.SegmentScore
+	for _, segment := range result.Segments {
+		if segment.Part != "" && segment.Matches > 0 {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return
+	}
+
+	sb.WriteString("【分部评分】\n")
+	sb.WriteString(strings.Repeat("─", 60) + "\n")
+	for _, segment := range segments {
+		name := segment.Part
+		if segment.Title != "" {
+			name = fmt.Sprintf("%s（%s）", segment.Part, segment.Title)
+		}
+		sb.WriteString(fmt.Sprintf("%s: 片段得分 %.1f，%d 处匹配\n", name, segment.Score, segment.Matches))
+		sb.WriteString(fmt.Sprintf("   %s\n", strings.Join(segment.Rules, "、")))
+	}
+	sb.WriteString("\n")
+}
+
+// formatAnchorAction 格式化锚点的修改动作
+func formatAnchorAction(anchor models.Anchor) string {
+	original := strings.TrimSpace(anchor.Text)
+	if len(anchor.Replacements) == 0 {
+		return fmt.Sprintf("'%s'", original)
+	}
+	if len(anchor.Replacements) == 1 && strings.TrimSpace(anchor.Replacements[0]) == "" {
+		return fmt.Sprintf("删除 '%s'", original)
+	}
+
+	candidates := make([]string, 0, len(anchor.Replacements))
+	for _, replacement := range anchor.Replacements {
+		candidates = append(candidates, fmt.Sprintf("'%s'", strings.TrimSpace(replacement)))
+	}
+	return fmt.Sprintf("替换 '%s' → %s", original, strings.Join(candidates, " / "))
+}
+
 // createScoreBar 创建评分条
 func (r *TextReporter) createScoreBar(score float64) string {
 	barLength := 50
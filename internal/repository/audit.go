@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 审计操作类型
+const (
+	AuditActionPurgeText       = "retention.purge_text"
+	AuditActionPurgeTextDryRun = "retention.purge_text_dry_run"
+)
+
+// AuditLog 审计日志
+type AuditLog struct {
+	ID        uint      `gorm:"primaryKey"`
+	Action    string    `gorm:"size:64;not null;index"`
+	Actor     string    `gorm:"size:128"` // 触发者，如 scheduler、cli
+	Details   string    // JSON
+	CreatedAt time.Time `gorm:"index"`
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// AuditRepository 审计日志仓储接口
+type AuditRepository interface {
+	Create(entry *AuditLog) error
+	List(action string, limit int) ([]*AuditLog, error)
+}
+
+// auditRepository 审计日志仓储实现
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository 创建审计日志仓储
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// Create 写入审计日志
+func (r *auditRepository) Create(entry *AuditLog) error {
+	if err := r.db.Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create audit log: %w", err)
+	}
+	return nil
+}
+
+// List 按时间倒序获取审计日志，action 为空时返回所有类型
+func (r *auditRepository) List(action string, limit int) ([]*AuditLog, error) {
+	var entries []*AuditLog
+	query := r.db.Order("created_at DESC").Order("id DESC").Limit(limit)
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	return entries, nil
+}
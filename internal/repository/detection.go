@@ -1,17 +1,28 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // DetectionRepository 检测记录仓储接口
 type DetectionRepository interface {
 	Create(record *DetectionRecord) error
 	GetByID(id string) (*DetectionRecord, error)
+	GetByIDs(ids []string) ([]*DetectionRecord, error)
 	GetByRequestID(requestID string) (*DetectionRecord, error)
-	List(page, pageSize int, sortBy, order string) ([]*DetectionRecord, int64, error)
+	List(page, pageSize int, sortBy, order string, filter ListFilter) ([]*DetectionRecord, int64, error)
+	FindByContentHash(hash string) ([]*DetectionRecord, error)
+	CountDuplicates(ids []string) (map[string]int64, error)
+	RecordResubmission(id string, tags string) error
+	CountExpiredText(before time.Time) (int64, error)
+	FindExpiredText(before time.Time, limit int) ([]*DetectionRecord, error)
+	PurgeText(record *DetectionRecord) error
 	Delete(id string) error
 	DeleteAll() error
 }
@@ -38,7 +49,7 @@ func (r *detectionRepository) Create(record *DetectionRecord) error {
 func (r *detectionRepository) GetByID(id string) (*DetectionRecord, error) {
 	var record DetectionRecord
 	if err := r.db.Where("id = ?", id).First(&record).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("detection record not found: %s", id)
 		}
 		return nil, fmt.Errorf("failed to get detection record: %w", err)
@@ -46,11 +57,23 @@ func (r *detectionRepository) GetByID(id string) (*DetectionRecord, error) {
 	return &record, nil
 }
 
+// GetByIDs 批量获取检测记录，不存在的 ID 被忽略
+func (r *detectionRepository) GetByIDs(ids []string) ([]*DetectionRecord, error) {
+	var records []*DetectionRecord
+	if len(ids) == 0 {
+		return records, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to get detection records: %w", err)
+	}
+	return records, nil
+}
+
 // GetByRequestID 根据 RequestID 获取检测记录
 func (r *detectionRepository) GetByRequestID(requestID string) (*DetectionRecord, error) {
 	var record DetectionRecord
 	if err := r.db.Where("request_id = ?", requestID).First(&record).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("detection record not found: %s", requestID)
 		}
 		return nil, fmt.Errorf("failed to get detection record: %w", err)
@@ -58,8 +81,8 @@ func (r *detectionRepository) GetByRequestID(requestID string) (*DetectionRecord
 	return &record, nil
 }
 
-// List 获取检测记录列表（分页）
-func (r *detectionRepository) List(page, pageSize int, sortBy, order string) ([]*DetectionRecord, int64, error) {
+// List 获取检测记录列表（分页、过滤和全文检索）
+func (r *detectionRepository) List(page, pageSize int, sortBy, order string, filter ListFilter) ([]*DetectionRecord, int64, error) {
 	var records []*DetectionRecord
 	var total int64
 
@@ -67,22 +90,119 @@ func (r *detectionRepository) List(page, pageSize int, sortBy, order string) ([]
 	offset := (page - 1) * pageSize
 
 	// 构建查询
-	query := r.db.Model(&DetectionRecord{})
+	query := r.applyFilter(r.db.Model(&DetectionRecord{}), filter)
 
 	// 获取总数
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count records: %w", err)
 	}
 
-	// 排序和分页
-	orderClause := fmt.Sprintf("%s %s", sortBy, order)
-	if err := query.Order(orderClause).Offset(offset).Limit(pageSize).Find(&records).Error; err != nil {
+	// 排序和分页（列名由数据库方言负责加引号）
+	orderBy := clause.OrderByColumn{
+		Column: clause.Column{Name: sortBy},
+		Desc:   strings.EqualFold(order, "desc"),
+	}
+	if err := query.Order(orderBy).Offset(offset).Limit(pageSize).Find(&records).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to list records: %w", err)
 	}
 
 	return records, total, nil
 }
 
+// FindByContentHash 查找内容哈希相同的检测记录，按创建时间升序排列
+func (r *detectionRepository) FindByContentHash(hash string) ([]*DetectionRecord, error) {
+	var records []*DetectionRecord
+	if err := r.db.Where("content_hash = ?", hash).Order("created_at").Order("id").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to find records by content hash: %w", err)
+	}
+	return records, nil
+}
+
+// CountDuplicates 统计关联到各记录的重复提交记录数
+func (r *detectionRepository) CountDuplicates(ids []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(ids))
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		DuplicateOf string
+		Count       int64
+	}
+	if err := r.db.Model(&DetectionRecord{}).
+		Select("duplicate_of, COUNT(*) AS count").
+		Where("duplicate_of IN ?", ids).
+		Group("duplicate_of").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count duplicates: %w", err)
+	}
+	for _, row := range rows {
+		counts[row.DuplicateOf] = row.Count
+	}
+	return counts, nil
+}
+
+// RecordResubmission 记录一次复用已有结果的提交：提交次数加一并更新标签
+func (r *detectionRepository) RecordResubmission(id string, tags string) error {
+	result := r.db.Model(&DetectionRecord{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"submit_count": gorm.Expr("submit_count + ?", 1),
+		"tags":         tags,
+		"updated_at":   time.Now(),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to record resubmission: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("detection record not found: %s", id)
+	}
+	return nil
+}
+
+// expiredText 创建时间早于 before 且原文尚未清除的记录
+func (r *detectionRepository) expiredText(before time.Time) *gorm.DB {
+	return r.db.Model(&DetectionRecord{}).
+		Where("created_at < ? AND text_purged_at IS NULL", before.Local())
+}
+
+// CountExpiredText 统计超过保留期限、原文尚未清除的记录数
+func (r *detectionRepository) CountExpiredText(before time.Time) (int64, error) {
+	var count int64
+	if err := r.expiredText(before).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count expired records: %w", err)
+	}
+	return count, nil
+}
+
+// FindExpiredText 按创建时间升序获取超过保留期限、原文尚未清除的记录
+func (r *detectionRepository) FindExpiredText(before time.Time, limit int) ([]*DetectionRecord, error) {
+	var records []*DetectionRecord
+	if err := r.expiredText(before).Order("created_at").Order("id").Limit(limit).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to find expired records: %w", err)
+	}
+	return records, nil
+}
+
+// PurgeText 清除记录的原文及含原文片段的字段，只保留分数和元数据
+// record 中的文本相关字段应已由调用方替换为清除后的内容
+func (r *detectionRepository) PurgeText(record *DetectionRecord) error {
+	result := r.db.Model(&DetectionRecord{}).
+		Where("id = ? AND text_purged_at IS NULL", record.ID).
+		Updates(map[string]interface{}{
+			"text":              record.Text,
+			"text_preview":      record.TextPreview,
+			"rule_results":      record.RuleResults,
+			"suggestions":       record.Suggestions,
+			"multimodal_result": record.MultimodalResult,
+			"content_hash":      record.ContentHash,
+			"text_purged_at":    record.TextPurgedAt,
+			"updated_at":        time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to purge detection record: %w", result.Error)
+	}
+	return nil
+}
+
 // Delete 删除检测记录
 func (r *detectionRepository) Delete(id string) error {
 	result := r.db.Where("id = ?", id).Delete(&DetectionRecord{})
@@ -97,7 +217,8 @@ func (r *detectionRepository) Delete(id string) error {
 
 // DeleteAll 删除所有检测记录
 func (r *detectionRepository) DeleteAll() error {
-	if err := r.db.Exec("DELETE FROM detection_records").Error; err != nil {
+	// 使用 GORM 构建删除语句，表名与引号由数据库方言处理
+	if err := r.db.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&DetectionRecord{}).Error; err != nil {
 		return fmt.Errorf("failed to delete all records: %w", err)
 	}
 	return nil
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Document 文档：同一篇稿件多次修订的检测记录集合
+type Document struct {
+	ID        string `gorm:"primaryKey;size:64"`
+	Title     string `gorm:"size:255"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName 指定表名
+func (Document) TableName() string {
+	return "documents"
+}
+
+// DocumentRevision 文档修订版本，关联一条检测记录
+type DocumentRevision struct {
+	ID         uint   `gorm:"primaryKey"`
+	DocumentID string `gorm:"size:64;not null;uniqueIndex:idx_document_revisions_document_revision"`
+	Revision   int    `gorm:"not null;uniqueIndex:idx_document_revisions_document_revision"` // 修订号，从 1 开始
+	RecordID   string `gorm:"size:64;not null;index"`
+	CreatedAt  time.Time
+}
+
+// TableName 指定表名
+func (DocumentRevision) TableName() string {
+	return "document_revisions"
+}
+
+// DocumentRepository 文档仓储接口
+type DocumentRepository interface {
+	Create(document *Document) error
+	GetByID(id string) (*Document, error)
+	AddRevision(documentID, recordID string) (*DocumentRevision, error)
+	GetRevision(documentID string, revision int) (*DocumentRevision, error)
+	ListRevisions(documentID string) ([]*DocumentRevision, error)
+}
+
+// documentRepository 文档仓储实现
+type documentRepository struct {
+	db *gorm.DB
+}
+
+// NewDocumentRepository 创建文档仓储
+func NewDocumentRepository(db *gorm.DB) DocumentRepository {
+	return &documentRepository{db: db}
+}
+
+// Create 创建文档
+func (r *documentRepository) Create(document *Document) error {
+	if err := r.db.Create(document).Error; err != nil {
+		return fmt.Errorf("failed to create document: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据 ID 获取文档
+func (r *documentRepository) GetByID(id string) (*Document, error) {
+	var document Document
+	if err := r.db.Where("id = ?", id).First(&document).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("document not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	return &document, nil
+}
+
+// AddRevision 为文档追加修订版本，修订号在事务中按当前最大值递增
+// 并发追加时由 (document_id, revision) 唯一索引保证修订号不重复
+func (r *documentRepository) AddRevision(documentID, recordID string) (*DocumentRevision, error) {
+	var revision DocumentRevision
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Document{}).Where("id = ?", documentID).Update("updated_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		var latest int
+		if err := tx.Model(&DocumentRevision{}).
+			Where("document_id = ?", documentID).
+			Select("COALESCE(MAX(revision), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		revision = DocumentRevision{
+			DocumentID: documentID,
+			Revision:   latest + 1,
+			RecordID:   recordID,
+		}
+		return tx.Create(&revision).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add document revision: %w", err)
+	}
+	return &revision, nil
+}
+
+// GetRevision 获取文档的指定修订版本
+func (r *documentRepository) GetRevision(documentID string, revision int) (*DocumentRevision, error) {
+	var record DocumentRevision
+	if err := r.db.Where("document_id = ? AND revision = ?", documentID, revision).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("document revision not found: %s#%d", documentID, revision)
+		}
+		return nil, fmt.Errorf("failed to get document revision: %w", err)
+	}
+	return &record, nil
+}
+
+// ListRevisions 按修订号升序获取文档的所有修订版本
+func (r *documentRepository) ListRevisions(documentID string) ([]*DocumentRevision, error) {
+	var revisions []*DocumentRevision
+	if err := r.db.Where("document_id = ?", documentID).Order("revision").Find(&revisions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list document revisions: %w", err)
+	}
+	return revisions, nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// FTSTable SQLite FTS5 全文索引表名（需要以 sqlite_fts5 构建标签编译）
+const FTSTable = "detection_records_fts"
+
+// ListFilter 历史记录过滤条件，零值字段表示不过滤
+type ListFilter struct {
+	MinScore   *float64  // 最低分数（含）
+	MaxScore   *float64  // 最高分数（含）
+	RiskLevels []string  // 风险等级，满足任一即可
+	From       time.Time // 创建时间下限（含）
+	To         time.Time // 创建时间上限（不含）
+	RuleTypes  []string  // 触发的规则类型，必须全部触发
+	Tags       []string  // 元数据标签，必须全部包含
+	Query      string    // 全文检索关键词，空白分隔的多个词必须全部出现
+
+	DuplicateOf   string // 只返回关联到该记录的重复提交
+	OriginalsOnly bool   // 只返回首次提交的记录，用于按文档分组展示
+}
+
+// JoinList 将字符串列表编码为两端带分隔符的形式（",a,b,"），便于用 LIKE 匹配单个元素
+func JoinList(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return "," + strings.Join(values, ",") + ","
+}
+
+// SplitList 解码 JoinList 编码的字符串列表
+func SplitList(value string) []string {
+	value = strings.Trim(value, ",")
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// SearchTerms 将检索关键词拆分为词项
+func SearchTerms(query string) []string {
+	return strings.Fields(query)
+}
+
+// applyFilter 将过滤条件应用到查询
+func (r *detectionRepository) applyFilter(query *gorm.DB, filter ListFilter) *gorm.DB {
+	if filter.MinScore != nil {
+		query = query.Where("score >= ?", *filter.MinScore)
+	}
+	if filter.MaxScore != nil {
+		query = query.Where("score <= ?", *filter.MaxScore)
+	}
+	if len(filter.RiskLevels) > 0 {
+		query = query.Where("risk_level IN ?", filter.RiskLevels)
+	}
+	// SQLite 以带时区偏移的字符串保存时间并按字符串比较，统一转换为记录写入时使用的本地时区
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From.Local())
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To.Local())
+	}
+	for _, ruleType := range filter.RuleTypes {
+		query = query.Where("detected_rules LIKE ? ESCAPE '!'", "%"+escapeLike(JoinList([]string{ruleType}))+"%")
+	}
+	for _, tag := range filter.Tags {
+		query = query.Where("tags LIKE ? ESCAPE '!'", "%"+escapeLike(JoinList([]string{tag}))+"%")
+	}
+	if filter.DuplicateOf != "" {
+		query = query.Where("duplicate_of = ?", filter.DuplicateOf)
+	}
+	if filter.OriginalsOnly {
+		query = query.Where("duplicate_of IS NULL OR duplicate_of = ?", "")
+	}
+	for _, term := range SearchTerms(filter.Query) {
+		query = r.applySearchTerm(query, term)
+	}
+	return query
+}
+
+// applySearchTerm 按数据库类型选择全文检索方式
+// SQLite 使用 FTS5 trigram 索引（不可用或词项少于 3 个字符时退回 LIKE），
+// PostgreSQL 对非中日韩词项使用 tsvector，其余情况使用子串匹配
+func (r *detectionRepository) applySearchTerm(query *gorm.DB, term string) *gorm.DB {
+	switch r.db.Dialector.Name() {
+	case "sqlite":
+		if utf8.RuneCountInString(term) >= 3 && r.db.Migrator().HasTable(FTSTable) {
+			return query.Where("id IN (SELECT id FROM "+FTSTable+" WHERE "+FTSTable+" MATCH ?)", ftsPhrase(term))
+		}
+	case "postgres":
+		if !containsCJK(term) {
+			return query.Where("to_tsvector('simple', text) @@ plainto_tsquery('simple', ?)", term)
+		}
+		return query.Where("strpos(lower(text), lower(?)) > 0", term)
+	}
+	return query.Where("text LIKE ? ESCAPE '!'", "%"+escapeLike(term)+"%")
+}
+
+// ftsPhrase 将词项转换为 FTS5 短语查询
+func ftsPhrase(term string) string {
+	return `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+}
+
+// escapeLike 转义 LIKE 模式中的通配符
+// 使用 '!' 作为转义字符，反斜杠在 MySQL 字符串字面量中有特殊含义，无法跨数据库使用
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`!`, `!!`, `%`, `!%`, `_`, `!_`)
+	return replacer.Replace(value)
+}
+
+// containsCJK 判断字符串是否包含中日韩文字
+func containsCJK(value string) bool {
+	for _, r := range value {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return true
+		}
+	}
+	return false
+}
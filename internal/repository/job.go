@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 异步任务状态
+const (
+	JobStatusQueued  = "queued"
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	JobStatusFailed  = "failed"
+)
+
+// 回调投递状态
+const (
+	WebhookStatusPending   = "pending"
+	WebhookStatusDelivered = "delivered"
+	WebhookStatusFailed    = "failed"
+)
+
+// Job 异步检测任务
+type Job struct {
+	ID      string `gorm:"primaryKey;size:64"`
+	Status  string `gorm:"size:16;not null;index"`
+	Text    string // 待检测文本，任务结束后清除，结果保存在检测记录中；匿名任务不保存
+	Options string // JSON 格式的检测选项
+	Layers  string // JSON 格式的各检测层阶段性结果
+
+	ResultID string `gorm:"size:64"` // 完成后对应的检测记录 ID
+	Error    string
+
+	WebhookURL      string `gorm:"size:2048"`
+	WebhookStatus   string `gorm:"size:16"`
+	WebhookAttempts int    `gorm:"not null;default:0"`
+	WebhookError    string
+
+	Owner       string     `gorm:"size:64"` // 执行任务的服务实例 ID；匿名任务的原文只保存在提交实例的内存中，排队时即归属该实例
+	HeartbeatAt *time.Time // 所属实例最近一次续租的时间，超过租约时长未续租的任务视为被中断
+
+	CreatedAt  time.Time `gorm:"index"`
+	UpdatedAt  time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// TableName 指定表名
+func (Job) TableName() string {
+	return "jobs"
+}
+
+// JobRepository 异步任务仓储接口
+type JobRepository interface {
+	Create(job *Job) error
+	GetByID(id string) (*Job, error)
+	Claim(id, owner string) (bool, error)
+	Heartbeat(owner string) error
+	UpdateLayers(id, owner, layers string) (bool, error)
+	Finish(id, owner, status, resultID, message string) (bool, error)
+	UpdateWebhook(id, owner, status string, attempts int, message string) error
+	MarkFailed(id, message string) error
+	RequeueExpired(before time.Time) (int64, error)
+	ListQueued() ([]*Job, error)
+}
+
+// jobRepository 异步任务仓储实现
+type jobRepository struct {
+	db *gorm.DB
+}
+
+// NewJobRepository 创建异步任务仓储
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+// Create 创建任务
+func (r *jobRepository) Create(job *Job) error {
+	if err := r.db.Create(job).Error; err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据 ID 获取任务
+func (r *jobRepository) GetByID(id string) (*Job, error) {
+	var job Job
+	if err := r.db.Where("id = ?", id).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("job not found: %s: %w", id, err)
+		}
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	return &job, nil
+}
+
+// Claim 将排队中的任务标记为由 owner 执行
+// 任务已被其他 worker 领取、不处于排队状态或归属其他实例时返回 false
+func (r *jobRepository) Claim(id, owner string) (bool, error) {
+	now := time.Now()
+	result := r.db.Model(&Job{}).
+		Where("id = ? AND status = ? AND (owner = '' OR owner IS NULL OR owner = ?)", id, JobStatusQueued, owner).
+		Updates(map[string]interface{}{
+			"status":       JobStatusRunning,
+			"owner":        owner,
+			"started_at":   now,
+			"heartbeat_at": now,
+			"updated_at":   now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim job: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Heartbeat 为 owner 排队中和执行中的全部任务续租
+func (r *jobRepository) Heartbeat(owner string) error {
+	err := r.db.Model(&Job{}).
+		Where("owner = ? AND status IN ?", owner, []string{JobStatusQueued, JobStatusRunning}).
+		Update("heartbeat_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to renew job lease: %w", err)
+	}
+	return nil
+}
+
+// UpdateLayers 保存 owner 执行中任务的阶段性结果
+// 只更新结果列，不影响续租时间；任务因租约过期被其他实例接管时返回 false
+func (r *jobRepository) UpdateLayers(id, owner, layers string) (bool, error) {
+	result := r.db.Model(&Job{}).
+		Where("id = ? AND owner = ? AND status = ?", id, owner, JobStatusRunning).
+		Updates(map[string]interface{}{
+			"layers":     layers,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update job layers: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Finish 结束 owner 执行中的任务并清除原文
+// 任务因租约过期被其他实例接管时不做修改并返回 false
+func (r *jobRepository) Finish(id, owner, status, resultID, message string) (bool, error) {
+	now := time.Now()
+	result := r.db.Model(&Job{}).
+		Where("id = ? AND owner = ? AND status = ?", id, owner, JobStatusRunning).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"result_id":   resultID,
+			"error":       message,
+			"text":        "",
+			"finished_at": now,
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to finish job: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateWebhook 记录 owner 执行完成的任务的回调投递结果
+func (r *jobRepository) UpdateWebhook(id, owner, status string, attempts int, message string) error {
+	err := r.db.Model(&Job{}).
+		Where("id = ? AND owner = ?", id, owner).
+		Updates(map[string]interface{}{
+			"webhook_status":   status,
+			"webhook_attempts": attempts,
+			"webhook_error":    message,
+			"updated_at":       time.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update job webhook status: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed 将任务标记为失败并清除原文，用于无法加载完整任务记录或无法放入队列的情况
+func (r *jobRepository) MarkFailed(id, message string) error {
+	now := time.Now()
+	err := r.db.Model(&Job{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":      JobStatusFailed,
+			"error":       message,
+			"text":        "",
+			"finished_at": now,
+			"updated_at":  now,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark job failed: %w", err)
+	}
+	return nil
+}
+
+// RequeueExpired 将租约在 before 之前过期的任务重新放回队列并解除归属，返回受影响的任务数
+// 用于恢复所属实例崩溃或重启后被中断的任务，其他实例仍在续租的任务不受影响；阶段性结果从头开始记录
+func (r *jobRepository) RequeueExpired(before time.Time) (int64, error) {
+	result := r.db.Model(&Job{}).
+		Where("(status = ? AND (heartbeat_at IS NULL OR heartbeat_at < ?)) OR (status = ? AND owner <> '' AND heartbeat_at < ?)",
+			JobStatusRunning, before, JobStatusQueued, before).
+		Updates(map[string]interface{}{
+			"status":       JobStatusQueued,
+			"layers":       "[]",
+			"owner":        "",
+			"started_at":   nil,
+			"heartbeat_at": nil,
+			"updated_at":   time.Now(),
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to requeue expired jobs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// ListQueued 按提交顺序列出排队中的任务
+func (r *jobRepository) ListQueued() ([]*Job, error) {
+	var jobs []*Job
+	err := r.db.Where("status = ?", JobStatusQueued).
+		Order("created_at ASC").Order("id ASC").
+		Find(&jobs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queued jobs: %w", err)
+	}
+	return jobs, nil
+}
@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -8,18 +11,41 @@ import (
 
 // DetectionRecord 检测记录数据库模型
 type DetectionRecord struct {
-	ID               string    `gorm:"primaryKey;type:text"`
-	RequestID        string    `gorm:"uniqueIndex;type:text;not null"`
-	Text             string    `gorm:"type:text;not null"`
-	TextPreview      string    `gorm:"type:text"`
-	Score            float64   `gorm:"not null"`
-	RiskLevel        string    `gorm:"type:text;not null;index"`
-	RuleResults      string    `gorm:"type:text"` // JSON
-	Suggestions      string    `gorm:"type:text"` // JSON
-	MultimodalResult string    `gorm:"type:text"` // JSON
-	ProcessTime      string    `gorm:"type:text"`
-	CreatedAt        time.Time `gorm:"index"`
-	UpdatedAt        time.Time
+	ID               string  `gorm:"primaryKey;size:64"`
+	RequestID        string  `gorm:"uniqueIndex;size:64;not null"`
+	Text             string  `gorm:"not null"` // 长文本，各数据库映射为 text/longtext
+	TextPreview      string  `gorm:"size:512"`
+	Score            float64 `gorm:"not null"`
+	RiskLevel        string  `gorm:"size:32;not null;index"`
+	RuleResults      string  // JSON
+	Suggestions      string  // JSON
+	MultimodalResult string  // JSON
+	ScoreDetail      string  // JSON，完整的 models.Score
+	ProcessTime      string  `gorm:"size:64"`
+	ContentHash      string  `gorm:"size:64;index:idx_detection_records_content_hash"` // 归一化文本的 SHA-256
+
+	// 维度评分，迁移前的旧记录为 NULL
+	VocabularyDiversityScore   *float64
+	SentenceComplexityScore    *float64
+	PersonalizationScore       *float64
+	LogicalCoherenceScore      *float64
+	EmotionalAuthenticityScore *float64
+
+	DetectedRules     string `gorm:"size:1024"` // 触发的规则类型，JoinList 编码
+	Tags              string `gorm:"size:1024"` // 元数据标签，JoinList 编码
+	RiskDescription   string `gorm:"size:128"`
+	AnalyzerVersion   string `gorm:"size:32"`
+	ConfigFingerprint string `gorm:"size:64"` // 检测所用配置的指纹
+	InputFormat       string `gorm:"size:16"` // 检测时的输入格式（text、markdown），迁移前的旧记录为空
+	Language          string `gorm:"size:16"` // 检测时指定的语言
+
+	DuplicateOf string `gorm:"size:64;index:idx_detection_records_duplicate_of"` // 同一内容最早的记录 ID，首次提交为空
+	SubmitCount int    `gorm:"not null;default:1"`                               // 复用该结果的提交次数
+
+	TextPurgedAt *time.Time // 原文及匹配内容被清除的时间，未清除为 NULL
+
+	CreatedAt time.Time `gorm:"index"`
+	UpdatedAt time.Time
 }
 
 // TableName 指定表名
@@ -43,3 +69,12 @@ func (r *DetectionRecord) BeforeUpdate(tx *gorm.DB) error {
 	r.UpdatedAt = time.Now()
 	return nil
 }
+
+// ContentHash 计算文本内容哈希
+// 统一换行符并去除首尾空白后计算 SHA-256，用于识别重复提交的文本
+func ContentHash(text string) string {
+	normalized := strings.ReplaceAll(text, "\r\n", "\n")
+	normalized = strings.TrimSpace(normalized)
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
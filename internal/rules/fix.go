@@ -0,0 +1,88 @@
+package rules
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// newEdit 基于字节范围创建修改操作
+func newEdit(ruleType models.RuleType, text string, start, end int, replacement, reason string) models.Edit {
+	return models.Edit{
+		RuleType:    ruleType,
+		Offset:      start,
+		Length:      end - start,
+		Original:    text[start:end],
+		Replacement: replacement,
+		Reason:      reason,
+	}
+}
+
+// matchCase 使替换词的大小写与原词保持一致
+func matchCase(original, replacement string) string {
+	if original == "" || replacement == "" {
+		return replacement
+	}
+	if strings.ToUpper(original) == original && len([]rune(original)) > 1 {
+		return strings.ToUpper(replacement)
+	}
+	first, _ := utf8.DecodeRuneInString(original)
+	if unicode.IsUpper(first) {
+		r, size := utf8.DecodeRuneInString(replacement)
+		return string(unicode.ToUpper(r)) + replacement[size:]
+	}
+	return replacement
+}
+
+// sentenceBounds 返回包含指定偏移量的句子字节范围
+// 句子以中英文句末标点或换行分隔，返回范围包含句末标点和其后的空格
+func sentenceBounds(text string, offset int) (int, int) {
+	start := 0
+	for i := offset; i > 0; {
+		r, size := utf8.DecodeLastRuneInString(text[:i])
+		if r == '\n' || isSentencePunct(r) {
+			start = i
+			break
+		}
+		i -= size
+	}
+	for start < offset && text[start] == ' ' {
+		start++
+	}
+
+	end := len(text)
+	for i := offset; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r == '\n' {
+			end = i
+			break
+		}
+		if isSentencePunct(r) {
+			end = i + size
+			for end < len(text) && isSentencePunctAt(text, end) {
+				_, s := utf8.DecodeRuneInString(text[end:])
+				end += s
+			}
+			break
+		}
+		i += size
+	}
+	return start, end
+}
+
+// isSentencePunct 判断是否为句末标点
+func isSentencePunct(r rune) bool {
+	switch r {
+	case '.', '!', '?', '。', '！', '？':
+		return true
+	}
+	return false
+}
+
+// isSentencePunctAt 判断指定字节位置是否为句末标点
+func isSentencePunctAt(text string, offset int) bool {
+	r, _ := utf8.DecodeRuneInString(text[offset:])
+	return isSentencePunct(r)
+}
@@ -0,0 +1,24 @@
+package rules
+
+import (
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// All 创建所有内置规则
+func All(cfg *config.Config) []models.Rule {
+	return []models.Rule{
+		NewHighFreqWordsRule(cfg),
+		NewSentenceStartersRule(cfg),
+		NewFalseRangeRule(cfg),
+		NewCitationAnomalyRule(cfg),
+		NewEmDashRule(cfg),
+		NewMarkdownRule(cfg),
+		NewEmojiRule(cfg),
+		NewKnowledgeCutoffRule(cfg),
+		NewCollaborativeRule(cfg),
+		NewPerfectionismRule(cfg),
+		NewHiddenCharsRule(cfg),
+		NewParallelStructureRule(cfg),
+	}
+}
@@ -139,3 +139,28 @@ func (r *HighFreqWordsRule) getContext(text string, offset, length int) string {
 	context := string(runes[start:end])
 	return strings.TrimSpace(context)
 }
+
+// Fix 生成高频词汇的修复操作
+// 使用配置的同义词替换表替换超过阈值的高频词，并保持原词的大小写
+func (r *HighFreqWordsRule) Fix(text string, result models.RuleResult) []models.Edit {
+	synonyms := r.config.Thresholds.HighFrequencyWords.Synonyms
+	if len(synonyms) == 0 {
+		return nil
+	}
+
+	var edits []models.Edit
+	for _, match := range result.Matches {
+		replacement, ok := synonyms[strings.ToLower(match.Text)]
+		if !ok {
+			continue
+		}
+
+		start := match.Position.Offset
+		end := start + match.Position.Length
+		original := text[start:end]
+		edits = append(edits, newEdit(models.RuleTypeHighFreqWords, text, start, end, matchCase(original, replacement),
+			fmt.Sprintf("使用 '%s' 替换高频词 '%s'", replacement, original)))
+	}
+
+	return edits
+}
@@ -150,3 +150,123 @@ func (r *CitationAnomalyRule) getContext(text string, offset, length int) string
 	context := string(runes[start:end])
 	return strings.TrimSpace(context)
 }
+
+// Fix 生成引用异常的修复操作
+// 移除AI生成的UTM参数和幽灵标记，占位符日期需要人工补充，不自动修复
+func (r *CitationAnomalyRule) Fix(text string, result models.RuleResult) []models.Edit {
+	var edits []models.Edit
+
+	for _, match := range result.Matches {
+		start := match.Position.Offset
+		end := start + match.Position.Length
+
+		if r.isUTMPattern(match.Text) {
+			start, end, ok := utmParamBounds(text, start, end)
+			if !ok {
+				continue
+			}
+			edits = append(edits, newEdit(models.RuleTypeCitationAnomaly, text, start, end, "",
+				fmt.Sprintf("移除AI生成的UTM参数: %s", match.Text)))
+			continue
+		}
+
+		if end, ok := ghostMarkerEnd(text, start, end); ok {
+			// 同时移除标记前的空格
+			if start > 0 && text[start-1] == ' ' {
+				start--
+			}
+			edits = append(edits, newEdit(models.RuleTypeCitationAnomaly, text, start, end, "",
+				fmt.Sprintf("移除AI生成的幽灵标记: %s", match.Text)))
+		}
+	}
+
+	return edits
+}
+
+// isUTMPattern 判断匹配文本是否来自UTM参数模式
+func (r *CitationAnomalyRule) isUTMPattern(matchText string) bool {
+	for _, pattern := range r.config.Thresholds.CitationAnomaly.UTMPatterns {
+		if pattern == matchText {
+			return true
+		}
+	}
+	return false
+}
+
+// utmParamBounds 将UTM匹配扩展为完整的URL查询参数
+// 仅处理位于URL查询串中（以 ? 或 & 开头）的参数
+func utmParamBounds(text string, start, end int) (int, int, bool) {
+	if start == 0 {
+		return 0, 0, false
+	}
+
+	// 扩展到参数值结尾
+	for end < len(text) && !strings.ContainsRune("&# \t\r\n)]\"'<>", rune(text[end])) {
+		end++
+	}
+
+	switch text[start-1] {
+	case '?':
+		if end < len(text) && text[end] == '&' {
+			// 保留 ?，移除参数及其后的 &
+			end++
+		} else {
+			start--
+		}
+	case '&':
+		start--
+	default:
+		return 0, 0, false
+	}
+
+	return start, end, true
+}
+
+// ghostMarkerEnd 计算幽灵标记的完整结束位置
+// 仅处理结构可确定的OpenAI引用标记，其他标记返回 false
+func ghostMarkerEnd(text string, start, end int) (int, bool) {
+	marker := text[start:end]
+
+	switch {
+	case strings.HasPrefix(marker, "contentReference[oaicite:"), strings.HasPrefix(marker, "[oai_citation:"):
+		// contentReference[oaicite:0]{index=0} / [oai_citation:1‡source](url)
+		closing := strings.IndexByte(text[end:], ']')
+		if closing == -1 {
+			return 0, false
+		}
+		end += closing + 1
+		if end < len(text) && (text[end] == '{' || text[end] == '(') {
+			pair := byte('}')
+			if text[end] == '(' {
+				pair = ')'
+			}
+			if idx := strings.IndexByte(text[end:], pair); idx != -1 {
+				end += idx + 1
+			}
+		}
+		return end, true
+
+	case strings.HasPrefix(marker, "【oaicite:"):
+		closing := strings.Index(text[end:], "】")
+		if closing == -1 {
+			return 0, false
+		}
+		return end + closing + len("】"), true
+
+	case strings.HasPrefix(strings.ToLower(marker), "turn0search"):
+		for end < len(text) && isASCIIAlnum(text[end]) {
+			end++
+		}
+		return end, true
+
+	case strings.HasPrefix(marker, "[^") && strings.HasSuffix(marker, "^]"):
+		return end, true
+	}
+
+	return 0, false
+}
+
+// isASCIIAlnum 判断是否为ASCII字母或数字
+func isASCIIAlnum(b byte) bool {
+	return (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -3,6 +3,8 @@ package rules
 import (
 	"fmt"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/leoobai/aigc-check/internal/config"
 	"github.com/leoobai/aigc-check/internal/models"
@@ -136,3 +138,49 @@ func (r *EmDashRule) getContext(text string, offset, length int) string {
 	context := string(runes[start:end])
 	return strings.TrimSpace(context)
 }
+
+// Fix 生成破折号的修复操作
+// 仅在密度超过阈值时修复：中文语境替换为逗号"，"，英文语境替换为", "
+func (r *EmDashRule) Fix(text string, result models.RuleResult) []models.Edit {
+	if !result.Detected {
+		return nil
+	}
+
+	var edits []models.Edit
+	prevEnd := -1
+	for _, match := range result.Matches {
+		start := match.Position.Offset
+		end := start + match.Position.Length
+
+		// 已被上一处修改覆盖（如"——"的第二个破折号）
+		if start < prevEnd {
+			continue
+		}
+
+		// 中文双破折号"——"作为一个整体处理
+		for strings.HasPrefix(text[end:], "—") {
+			end += len("—")
+		}
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+
+		replacement := ", "
+		if unicode.Is(unicode.Han, before) || unicode.Is(unicode.Han, after) {
+			replacement = "，"
+		} else {
+			// 英文语境中吸收破折号两侧的空格
+			for start > 0 && text[start-1] == ' ' {
+				start--
+			}
+			for end < len(text) && text[end] == ' ' {
+				end++
+			}
+		}
+
+		edits = append(edits, newEdit(models.RuleTypeEmDash, text, start, end, replacement, "使用逗号替代破折号"))
+		prevEnd = end
+	}
+
+	return edits
+}
@@ -93,3 +93,34 @@ func generateWords(count int) string {
 	}
 	return result
 }
+
+func TestEmDashRule_Fix(t *testing.T) {
+	cfg := &config.Config{Thresholds: config.DefaultThresholds}
+	rule := NewEmDashRule(cfg)
+
+	t.Run("英文语境", func(t *testing.T) {
+		text := "It works — mostly — fine."
+		edits := rule.Fix(text, rule.Check(text))
+		if len(edits) != 2 {
+			t.Fatalf("len(edits) = %d, want 2", len(edits))
+		}
+		if edits[0].Original != " — " || edits[0].Replacement != ", " {
+			t.Errorf("edit = %q → %q, want \" — \" → \", \"", edits[0].Original, edits[0].Replacement)
+		}
+	})
+
+	t.Run("中文双破折号", func(t *testing.T) {
+		text := "这是测试——真的。"
+		edits := rule.Fix(text, rule.Check(text))
+		if len(edits) != 1 || edits[0].Original != "——" || edits[0].Replacement != "，" {
+			t.Errorf("edits = %+v, want a single —— → ，", edits)
+		}
+	})
+
+	t.Run("低密度不修复", func(t *testing.T) {
+		text := generateWords(300) + "— end"
+		if edits := rule.Fix(text, rule.Check(text)); len(edits) != 0 {
+			t.Errorf("len(edits) = %d, want 0", len(edits))
+		}
+	})
+}
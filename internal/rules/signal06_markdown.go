@@ -2,6 +2,7 @@ package rules
 
 import (
 	"fmt"
+	"html"
 	"regexp"
 	"strings"
 
@@ -165,3 +166,121 @@ func (r *MarkdownRule) getContext(text string, offset, length int) string {
 	context := string(runes[start:end])
 	return strings.TrimSpace(context)
 }
+
+// markdownLinkRe 匹配Markdown链接和图片
+var markdownLinkRe = regexp.MustCompile(`^!?\[([^\]]*)\]\([^)]*\)$`)
+
+// markdownEmphasisDelimiters 强调类格式的成对定界符
+var markdownEmphasisDelimiters = []string{"**", "__", "~~", "==", "*", "_", "`"}
+
+// Fix 生成Markdown残留的修复操作
+// 去除强调、链接、标题、列表等格式标记并保留文本内容，表格、LaTeX和模板语法需要人工处理
+func (r *MarkdownRule) Fix(text string, result models.RuleResult) []models.Edit {
+	var edits []models.Edit
+
+	for _, match := range result.Matches {
+		start := match.Position.Offset
+		end := start + match.Position.Length
+		if end > len(text) {
+			continue
+		}
+
+		replacement, end, ok := markdownReplacement(text, start, end)
+		if !ok {
+			continue
+		}
+		edits = append(edits, newEdit(models.RuleTypeMarkdown, text, start, end, replacement,
+			fmt.Sprintf("清理Markdown格式: %s", text[start:end])))
+	}
+
+	return edits
+}
+
+// markdownReplacement 计算单个Markdown残留的替换文本和结束位置
+func markdownReplacement(text string, start, end int) (string, int, bool) {
+	matched := text[start:end]
+
+	// 链接与图片：保留链接文本
+	if m := markdownLinkRe.FindStringSubmatch(matched); m != nil {
+		return m[1], end, true
+	}
+
+	// 代码块围栏：连同语言标记一起移除
+	if matched == "```" {
+		for end < len(text) && text[end] != '\n' {
+			end++
+		}
+		return "", end, true
+	}
+
+	// 标题标记：连同其后的空格一起移除
+	if strings.Trim(matched, "#") == "" {
+		for end < len(text) && text[end] == ' ' {
+			end++
+		}
+		return "", end, true
+	}
+
+	// 行首的列表和引用标记
+	trimmed := strings.TrimSpace(matched)
+	if (start == 0 || text[start-1] == '\n') && isListOrQuoteMarker(trimmed) {
+		return "", end, true
+	}
+
+	// HTML实体
+	if strings.HasPrefix(matched, "&") && strings.HasSuffix(matched, ";") {
+		unescaped := html.UnescapeString(matched)
+		// 不换行空格替换为普通空格
+		unescaped = strings.ReplaceAll(unescaped, "\u00a0", " ")
+		return unescaped, end, unescaped != matched
+	}
+
+	// HTML标签：移除整个标签，换行标签替换为换行符
+	if strings.HasPrefix(matched, "<") {
+		closing := strings.IndexByte(text[start:], '>')
+		if closing == -1 {
+			return "", 0, false
+		}
+		end = start + closing + 1
+		if strings.HasPrefix(matched, "<br") {
+			return "\n", end, true
+		}
+		return "", end, true
+	}
+
+	// 单独的强调定界符（字面匹配 "**" 等）直接移除，分隔线等其他纯符号残留不处理
+	switch matched {
+	case "**", "__", "~~":
+		return "", end, true
+	}
+	if strings.Trim(matched, "*_~=`-") == "" {
+		return "", 0, false
+	}
+
+	// 成对的强调标记：保留内部文本
+	for _, delim := range markdownEmphasisDelimiters {
+		if len(matched) <= 2*len(delim) || !strings.HasPrefix(matched, delim) || !strings.HasSuffix(matched, delim) {
+			continue
+		}
+		// 单字符定界符位于单词内部时（如 snake_case）不是格式标记
+		if len(delim) == 1 && ((start > 0 && isASCIIAlnum(text[start-1])) || (end < len(text) && isASCIIAlnum(text[end]))) {
+			return "", 0, false
+		}
+		return matched[len(delim) : len(matched)-len(delim)], end, true
+	}
+
+	return "", 0, false
+}
+
+// isListOrQuoteMarker 判断是否为列表或引用标记
+func isListOrQuoteMarker(marker string) bool {
+	switch marker {
+	case "-", "*", "+", ">", ">>":
+		return true
+	}
+	if strings.HasSuffix(marker, ".") {
+		digits := strings.TrimSuffix(marker, ".")
+		return digits != "" && strings.Trim(digits, "0123456789") == ""
+	}
+	return false
+}
@@ -78,3 +78,42 @@ func TestMarkdownRule_GetType(t *testing.T) {
 		t.Errorf("GetType() = %s, want %s", rule.GetType(), models.RuleTypeMarkdown)
 	}
 }
+
+func TestMarkdownRule_Fix(t *testing.T) {
+	cfg := &config.Config{Thresholds: config.DefaultThresholds}
+	cfg.Thresholds.MarkdownResidue.Patterns = []string{
+		"##+",
+		"\\*\\*[^\\*]+\\*\\*",
+		"\\[.+\\]\\(.+\\)",
+		"&nbsp;",
+		"<br",
+	}
+	rule := NewMarkdownRule(cfg)
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"标题标记", "## Summary", "Summary"},
+		{"加粗", "This is **bold** text.", "This is bold text."},
+		{"链接保留文本", "See [the docs](https://example.com) now.", "See the docs now."},
+		{"HTML实体", "a&nbsp;b", "a b"},
+		{"换行标签", "line one<br/>line two", "line one\nline two"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := rule.Check(tt.text)
+			edits := rule.Fix(tt.text, result)
+			if len(edits) != 1 {
+				t.Fatalf("len(edits) = %d, want 1: %+v", len(edits), edits)
+			}
+			e := edits[0]
+			got := tt.text[:e.Offset] + e.Replacement + tt.text[e.End():]
+			if got != tt.want {
+				t.Errorf("fixed = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@ package rules
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/leoobai/aigc-check/internal/config"
 	"github.com/leoobai/aigc-check/internal/models"
@@ -109,3 +112,49 @@ func (r *CollaborativeRule) getContext(text string, offset, length int) string {
 	context := string(runes[start:end])
 	return strings.TrimSpace(context)
 }
+
+// Fix 生成协作式语气的修复操作
+// 仅删除位于文末的协作式结束语（如"I hope this helps!"），正文中的表达需要人工改写
+func (r *CollaborativeRule) Fix(text string, result models.RuleResult) []models.Edit {
+	type span struct {
+		start, end int
+		phrase     string
+	}
+
+	// 收集包含协作式短语的句子（按起始位置去重）
+	seen := make(map[int]bool)
+	var spans []span
+	for _, match := range result.Matches {
+		start, end := sentenceBounds(text, match.Position.Offset)
+		if seen[start] {
+			continue
+		}
+		seen[start] = true
+		spans = append(spans, span{start: start, end: end, phrase: match.Text})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start > spans[j].start })
+
+	// 从文末向前，连续的协作式句子视为结束语
+	var edits []models.Edit
+	tail := len(strings.TrimRightFunc(text, unicode.IsSpace))
+	for _, s := range spans {
+		if s.end < tail && strings.TrimSpace(text[s.end:tail]) != "" {
+			break
+		}
+		tail = s.start
+
+		// 按字符回退，避免把多字节字符的后续字节当作空白
+		start := s.start
+		for start > 0 {
+			ch, size := utf8.DecodeLastRuneInString(text[:start])
+			if !unicode.IsSpace(ch) {
+				break
+			}
+			start -= size
+		}
+		edits = append(edits, newEdit(models.RuleTypeCollaborative, text, start, s.end, "",
+			fmt.Sprintf("删除协作式结束语: %s", s.phrase)))
+	}
+
+	return edits
+}
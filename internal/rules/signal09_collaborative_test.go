@@ -2,6 +2,7 @@ package rules
 
 import (
 	"testing"
+	"unicode/utf8"
 
 	"github.com/leoobai/aigc-check/internal/config"
 	"github.com/leoobai/aigc-check/internal/models"
@@ -63,3 +64,36 @@ func TestCollaborativeRule_GetType(t *testing.T) {
 		t.Errorf("GetType() = %s, want %s", rule.GetType(), models.RuleTypeCollaborative)
 	}
 }
+
+func TestCollaborativeRule_Fix(t *testing.T) {
+	cfg := &config.Config{Thresholds: config.DefaultThresholds}
+	rule := NewCollaborativeRule(cfg)
+
+	tests := []struct {
+		name         string
+		text         string
+		wantOriginal string
+		wantFixed    string
+	}{
+		{"英文结束语", "The answer is 42. I hope this helps!", " I hope this helps!", "The answer is 42."},
+		// "久" 的 UTF-8 编码以 0x85 结尾，不能被当作空白
+		{"中文结束语前的多字节字符", "这是永久\n希望这能帮到你", "\n希望这能帮到你", "这是永久"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edits := rule.Fix(tt.text, rule.Check(tt.text))
+			if len(edits) != 1 {
+				t.Fatalf("len(edits) = %d, want 1: %+v", len(edits), edits)
+			}
+			e := edits[0]
+			if e.Original != tt.wantOriginal {
+				t.Errorf("Original = %q, want %q", e.Original, tt.wantOriginal)
+			}
+			fixed := tt.text[:e.Offset] + e.Replacement + tt.text[e.End():]
+			if fixed != tt.wantFixed || !utf8.ValidString(fixed) {
+				t.Errorf("fixed = %q, want %q", fixed, tt.wantFixed)
+			}
+		})
+	}
+}
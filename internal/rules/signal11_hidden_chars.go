@@ -0,0 +1,386 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+// HiddenCharsRule Signal 11: 隐形字符与同形字母检测
+// 生成工具和"人性化"改写工具会插入零宽字符、连接符、窄不换行空格等隐形字符作为水印，
+// 或把拉丁字母替换为外形相同的西里尔、希腊字母以规避检测。
+// 规则直接扫描原始文本，不经过 TextProcessor 的标准化（标准化会去除行首尾的空白类字符）
+type HiddenCharsRule struct {
+	config  *config.Config
+	allowed map[rune]bool
+}
+
+// hiddenChars 隐形字符及其中文名称
+var hiddenChars = map[rune]string{
+	0x00AD: "软连字符",
+	0x061C: "阿拉伯字母标记",
+	0x180E: "蒙古文元音分隔符",
+	0x200B: "零宽空格",
+	0x200C: "零宽非连接符",
+	0x200D: "零宽连接符",
+	0x200E: "从左到右标记",
+	0x200F: "从右到左标记",
+	0x202A: "从左到右嵌入",
+	0x202B: "从右到左嵌入",
+	0x202C: "方向格式结束",
+	0x202D: "从左到右覆盖",
+	0x202E: "从右到左覆盖",
+	0x202F: "窄不换行空格",
+	0x2060: "词连接符",
+	0x2061: "函数应用",
+	0x2062: "隐形乘号",
+	0x2063: "隐形分隔符",
+	0x2064: "隐形加号",
+	0x2066: "从左到右隔离",
+	0x2067: "从右到左隔离",
+	0x2068: "首强隔离",
+	0x2069: "方向隔离结束",
+	0x3164: "韩文填充符",
+	0xFEFF: "零宽不换行空格",
+	0xFFA0: "半角韩文填充符",
+}
+
+// homoglyphs 外形与拉丁字母相同的西里尔和希腊字母
+var homoglyphs = map[rune]rune{
+	// 西里尔字母
+	'а': 'a', 'е': 'e', 'о': 'o', 'р': 'p', 'с': 'c', 'у': 'y', 'х': 'x',
+	'ѕ': 's', 'і': 'i', 'ј': 'j', 'ԁ': 'd', 'һ': 'h', 'ӏ': 'l', 'ԛ': 'q', 'ԝ': 'w',
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O', 'Р': 'P',
+	'С': 'C', 'Т': 'T', 'Х': 'X', 'Ѕ': 'S', 'І': 'I', 'Ј': 'J', 'Ү': 'Y', 'Ԛ': 'Q', 'Ԝ': 'W',
+	// 希腊字母
+	'ο': 'o', 'ν': 'v', 'ι': 'i',
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K', 'Μ': 'M',
+	'Ν': 'N', 'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+}
+
+// 匹配分类
+const (
+	hiddenCharsWatermark = "疑似水印"
+	hiddenCharsEvasion   = "疑似规避检测"
+)
+
+// NewHiddenCharsRule 创建隐形字符与同形字母检测规则
+func NewHiddenCharsRule(cfg *config.Config) *HiddenCharsRule {
+	allowed := make(map[rune]bool)
+	for _, code := range cfg.Thresholds.HiddenCharacters.Allowed {
+		if r, ok := parseCodepoint(code); ok {
+			allowed[r] = true
+		}
+	}
+	return &HiddenCharsRule{
+		config:  cfg,
+		allowed: allowed,
+	}
+}
+
+// Check 执行规则检测
+// 连续的隐形字符合并为一个匹配项，位于单词内部的视为规避检测（拆开关键词），其余视为水印；
+// 同形字母只在与拉丁字母混写的单词中检测，每个字母一个匹配项，均视为规避检测
+func (rule *HiddenCharsRule) Check(text string) models.RuleResult {
+	result := models.RuleResult{
+		RuleType:    models.RuleTypeHiddenChars,
+		RuleName:    rule.GetName(),
+		Description: rule.GetDescription(),
+		Detected:    false,
+		Score:       100.0,
+		Severity:    models.SeverityCritical,
+		Matches:     []models.Match{},
+		Count:       0,
+		Threshold:   rule.config.Thresholds.HiddenCharacters.Threshold,
+	}
+
+	runes := []rune(text)
+	offsets := make([]int, len(runes)+1)
+	lines := make([]int, len(runes))
+	columns := make([]int, len(runes))
+	line, column, offset := 1, 1, 0
+	rtl := false
+	for i, r := range runes {
+		offsets[i], lines[i], columns[i] = offset, line, column
+		offset += utf8.RuneLen(r)
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+		if unicode.In(r, unicode.Hebrew, unicode.Arabic, unicode.Syriac, unicode.Thaana, unicode.Nko) {
+			rtl = true
+		}
+	}
+	offsets[len(runes)] = offset
+
+	match := func(start, end int, reason string) models.Match {
+		return models.Match{
+			Text: string(runes[start:end]),
+			Position: models.Position{
+				Line:   lines[start],
+				Column: columns[start],
+				Offset: offsets[start],
+				Length: offsets[end] - offsets[start],
+			},
+			Context: rule.getContext(runes, start, end),
+			Reason:  reason,
+		}
+	}
+
+	var hidden, glyphs, watermark, evasion int
+	for i := 0; i < len(runes); {
+		if !rule.isHidden(runes, i, rtl) {
+			i++
+			continue
+		}
+		end := i + 1
+		for end < len(runes) && rule.isHidden(runes, end, rtl) {
+			end++
+		}
+
+		// 窄不换行空格等空白字符代替的是单词间的空格，不算拆开单词
+		category := hiddenCharsWatermark
+		if i > 0 && end < len(runes) && isWordRune(runes[i-1]) && isWordRune(runes[end]) &&
+			!strings.ContainsFunc(string(runes[i:end]), unicode.IsSpace) {
+			category = hiddenCharsEvasion
+			evasion++
+		} else {
+			watermark++
+		}
+		hidden += end - i
+		result.Matches = append(result.Matches, match(i, end,
+			fmt.Sprintf("%s：%s", category, describeHidden(runes[i:end]))))
+		i = end
+	}
+
+	for start := 0; start < len(runes); {
+		if !unicode.IsLetter(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		latin := false
+		for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsMark(runes[end])) {
+			latin = latin || unicode.Is(unicode.Latin, runes[end])
+			end++
+		}
+		if latin {
+			word := string(runes[start:end])
+			for i := start; i < end; i++ {
+				if target, ok := homoglyphs[runes[i]]; ok && !rule.allowed[runes[i]] {
+					glyphs++
+					evasion++
+					result.Matches = append(result.Matches, match(i, i+1,
+						fmt.Sprintf("%s：U+%04X %s %c 冒充拉丁字母 %c（单词 %s）",
+							hiddenCharsEvasion, runes[i], scriptName(runes[i]), runes[i], target, word)))
+				}
+			}
+		}
+		start = end
+	}
+
+	result.Count = hidden + glyphs
+	if result.Count > 0 && result.Count >= result.Threshold {
+		result.Detected = true
+
+		// 计算评分：每个字符扣20分
+		result.Score = 100.0 - float64(result.Count)*20.0
+		if result.Score < 0 {
+			result.Score = 0
+		}
+
+		result.Message = fmt.Sprintf("检测到 %d 个隐形字符和 %d 个同形字母（%s %d 处，%s %d 处）",
+			hidden, glyphs, hiddenCharsWatermark, watermark, hiddenCharsEvasion, evasion)
+	} else {
+		result.Message = fmt.Sprintf("检测到 %d 个隐形字符和 %d 个同形字母，正常", hidden, glyphs)
+	}
+
+	return result
+}
+
+// Fix 生成隐形字符与同形字母的修复操作
+// 删除隐形字符（窄不换行空格两侧没有空白时替换为普通空格），同形字母替换回对应的拉丁字母
+func (rule *HiddenCharsRule) Fix(text string, result models.RuleResult) []models.Edit {
+	var edits []models.Edit
+
+	for _, match := range result.Matches {
+		start := match.Position.Offset
+		end := start + match.Position.Length
+		if end > len(text) || text[start:end] != match.Text {
+			continue
+		}
+
+		r, _ := utf8.DecodeRuneInString(match.Text)
+		if target, ok := homoglyphs[r]; ok && len(match.Text) == utf8.RuneLen(r) {
+			edits = append(edits, newEdit(models.RuleTypeHiddenChars, text, start, end, string(target),
+				fmt.Sprintf("将同形字母 U+%04X 替换为拉丁字母 %c", r, target)))
+			continue
+		}
+
+		replacement := ""
+		if strings.ContainsRune(match.Text, 0x202F) {
+			before, _ := utf8.DecodeLastRuneInString(text[:start])
+			after, _ := utf8.DecodeRuneInString(text[end:])
+			if start > 0 && end < len(text) && !unicode.IsSpace(before) && !unicode.IsSpace(after) {
+				replacement = " "
+			}
+		}
+		edits = append(edits, newEdit(models.RuleTypeHiddenChars, text, start, end, replacement,
+			fmt.Sprintf("移除隐形字符: %s", describeHidden([]rune(match.Text)))))
+	}
+
+	return edits
+}
+
+// GetType 获取规则类型
+func (rule *HiddenCharsRule) GetType() models.RuleType {
+	return models.RuleTypeHiddenChars
+}
+
+// GetName 获取规则名称
+func (rule *HiddenCharsRule) GetName() string {
+	return "隐形字符与同形字母检测"
+}
+
+// GetDescription 获取规则描述
+func (rule *HiddenCharsRule) GetDescription() string {
+	return "检测零宽字符、窄不换行空格等隐形字符和冒充拉丁字母的西里尔、希腊字母，它们常是生成工具的水印或规避检测的痕迹"
+}
+
+// isHidden 判断 runes[i] 是否为需要报告的隐形字符，排除正常文字中的合法用法：
+// 文本开头的字节顺序标记、表情符号序列和复杂文字中的零宽连接符、含从右到左文字时的方向控制符、
+// 法语标点前后和数字分组中的窄不换行空格、表情旗帜序列中的标签字符
+func (rule *HiddenCharsRule) isHidden(runes []rune, i int, rtl bool) bool {
+	r := runes[i]
+	if rule.allowed[r] {
+		return false
+	}
+	var prev, next rune
+	if i > 0 {
+		prev = runes[i-1]
+	}
+	if i+1 < len(runes) {
+		next = runes[i+1]
+	}
+
+	if r >= 0xE0000 && r <= 0xE007F {
+		// 标签字符：表情旗帜（🏴 加标签序列）之外用于隐藏 ASCII 文本
+		j := i
+		for j > 0 && runes[j-1] >= 0xE0000 && runes[j-1] <= 0xE007F {
+			j--
+		}
+		return j == 0 || runes[j-1] != 0x1F3F4
+	}
+
+	if _, ok := hiddenChars[r]; !ok {
+		return false
+	}
+	switch r {
+	case 0xFEFF:
+		return i > 0
+	case 0x200D:
+		return !isEmojiRune(prev) && !isEmojiRune(next) && !(isJoiningScript(prev) && isJoiningScript(next))
+	case 0x200C:
+		return !(isJoiningScript(prev) && isJoiningScript(next))
+	case 0x200E, 0x200F, 0x061C, 0x202A, 0x202B, 0x202C, 0x202D, 0x202E, 0x2066, 0x2067, 0x2068, 0x2069:
+		return !rtl
+	case 0x202F:
+		if strings.ContainsRune(";:!?»›%", next) || strings.ContainsRune("«‹", prev) ||
+			unicode.IsDigit(prev) && unicode.IsDigit(next) ||
+			unicode.Is(unicode.Mongolian, prev) || unicode.Is(unicode.Mongolian, next) {
+			return false
+		}
+	}
+	return true
+}
+
+// isEmojiRune 判断是否为表情符号或可出现在表情序列中的符号
+func isEmojiRune(r rune) bool {
+	return isEmoji(r) || unicode.Is(unicode.So, r) || (r >= 0x1F3FB && r <= 0x1F3FF)
+}
+
+// isJoiningScript 判断是否为需要零宽（非）连接符控制连写的文字，如阿拉伯文、波斯文和印度诸文字
+func isJoiningScript(r rune) bool {
+	if !unicode.IsLetter(r) && !unicode.IsMark(r) {
+		return false
+	}
+	return !unicode.In(r, unicode.Latin, unicode.Greek, unicode.Cyrillic, unicode.Han,
+		unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Common)
+}
+
+// isWordRune 判断是否为单词内部的字符
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// scriptName 同形字母所属文字的名称
+func scriptName(r rune) string {
+	if unicode.Is(unicode.Greek, r) {
+		return "希腊字母"
+	}
+	return "西里尔字母"
+}
+
+// describeHidden 描述一段隐形字符的码位和名称，重复出现的字符合并计数
+func describeHidden(runes []rune) string {
+	var order []rune
+	counts := make(map[rune]int)
+	for _, r := range runes {
+		if counts[r] == 0 {
+			order = append(order, r)
+		}
+		counts[r]++
+	}
+
+	parts := make([]string, 0, len(order))
+	for _, r := range order {
+		name, ok := hiddenChars[r]
+		if !ok {
+			name = "标签字符"
+		}
+		part := fmt.Sprintf("U+%04X（%s）", r, name)
+		if counts[r] > 1 {
+			part += fmt.Sprintf(" ×%d", counts[r])
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "、")
+}
+
+// parseCodepoint 解析 "U+200B" 形式的码位
+func parseCodepoint(code string) (rune, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) < 3 || !strings.EqualFold(code[:2], "U+") {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(code[2:], 16, 32)
+	if err != nil || n > unicode.MaxRune {
+		return 0, false
+	}
+	return rune(n), true
+}
+
+// getContext 获取匹配项的上下文，隐形字符显示为码位标记
+func (rule *HiddenCharsRule) getContext(runes []rune, start, end int) string {
+	const contextSize = 30
+
+	from := max(start-contextSize, 0)
+	to := min(end+contextSize, len(runes))
+
+	var b strings.Builder
+	for _, r := range runes[from:to] {
+		if _, ok := hiddenChars[r]; ok || (r >= 0xE0000 && r <= 0xE007F) {
+			fmt.Fprintf(&b, "[U+%04X]", r)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return strings.TrimSpace(b.String())
+}
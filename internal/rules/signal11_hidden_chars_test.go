@@ -0,0 +1,119 @@
+package rules
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+func TestHiddenCharsRule_Check(t *testing.T) {
+	cfg := &config.Config{Thresholds: config.DefaultThresholds}
+	rule := NewHiddenCharsRule(cfg)
+
+	tests := []struct {
+		name           string
+		text           string
+		expectDetected bool
+		expectCount    int
+		expectReason   string
+	}{
+		{"普通文本", "This is a normal text written by a person.", false, 0, ""},
+		{"开头的字节顺序标记", "\ufeffPlain text with a BOM.", false, 0, ""},
+		{"表情序列中的零宽连接符", "Our family 👨\u200d👩\u200d👧 went hiking.", false, 0, ""},
+		{"波斯文中的零宽非连接符", "می\u200cخواهم بروم", false, 0, ""},
+		{"法语标点前的窄不换行空格", "Bonjour\u202f! Comment ça va\u202f?", false, 0, ""},
+		{"表情旗帜中的标签字符", "Go 🏴\U000E0067\U000E0062\U000E0073\U000E0063\U000E0074\U000E007F team", false, 0, ""},
+		{"单词之间的零宽空格", "The results are\u200b clear and\u200b\u200c robust.", true, 3, "疑似水印：U+200B（零宽空格）"},
+		{"拆开单词的零宽空格", "This is cru\u200bcial.", true, 1, "疑似规避检测：U+200B（零宽空格）"},
+		{"单词之间的窄不换行空格", "The data\u202fshows growth.", true, 1, "疑似水印：U+202F（窄不换行空格）"},
+		{"无从右到左文字时的方向控制符", "Total \u202eexe.txt", true, 1, "U+202E（从右到左覆盖）"},
+		{"隐藏文本的标签字符", "Hello\U000E0068\U000E0069 world", true, 2, "U+E0068（标签字符）"},
+		{"西里尔同形字母", "The pаper shows clеar results.", true, 2, "疑似规避检测：U+0430 西里尔字母 а 冒充拉丁字母 a（单词 pаper）"},
+		{"希腊同形字母", "Thе mοdel wοrks.", true, 3, "U+03BF 希腊字母 ο 冒充拉丁字母 o"},
+		{"纯俄文不是同形字母", "Это обычный русский текст.", false, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := rule.Check(tt.text)
+
+			if result.Detected != tt.expectDetected {
+				t.Errorf("Detected = %v, want %v (matches: %+v)", result.Detected, tt.expectDetected, result.Matches)
+			}
+			if result.Count != tt.expectCount {
+				t.Errorf("Count = %d, want %d", result.Count, tt.expectCount)
+			}
+			if result.RuleType != models.RuleTypeHiddenChars {
+				t.Errorf("RuleType = %s, want %s", result.RuleType, models.RuleTypeHiddenChars)
+			}
+			if tt.expectReason == "" {
+				return
+			}
+			found := false
+			for _, m := range result.Matches {
+				if strings.Contains(m.Reason, tt.expectReason) {
+					found = true
+				}
+				if got := tt.text[m.Position.Offset : m.Position.Offset+m.Position.Length]; got != m.Text {
+					t.Errorf("match text = %q, position covers %q", m.Text, got)
+				}
+			}
+			if !found {
+				t.Errorf("no match with reason %q: %+v", tt.expectReason, result.Matches)
+			}
+		})
+	}
+}
+
+func TestHiddenCharsRule_Position(t *testing.T) {
+	rule := NewHiddenCharsRule(&config.Config{Thresholds: config.DefaultThresholds})
+
+	result := rule.Check("第一行\nIt is\u200b\u200b done.")
+	if len(result.Matches) != 1 {
+		t.Fatalf("Matches = %+v, want 1 run", result.Matches)
+	}
+	m := result.Matches[0]
+	want := models.Position{Line: 2, Column: 6, Offset: len("第一行\nIt is"), Length: 6}
+	if m.Position != want {
+		t.Errorf("Position = %+v, want %+v", m.Position, want)
+	}
+	if !strings.Contains(m.Reason, "U+200B（零宽空格） ×2") {
+		t.Errorf("Reason = %q", m.Reason)
+	}
+	if !strings.Contains(m.Context, "is[U+200B][U+200B] done") {
+		t.Errorf("Context = %q", m.Context)
+	}
+}
+
+func TestHiddenCharsRule_Allowed(t *testing.T) {
+	cfg := &config.Config{Thresholds: config.DefaultThresholds}
+	cfg.Thresholds.HiddenCharacters.Allowed = []string{"U+00AD", "u+0430", "bogus"}
+	rule := NewHiddenCharsRule(cfg)
+
+	result := rule.Check("Donau\u00addampf\u00adschiff and a pаper with a\u200bgap.")
+	if result.Count != 1 || !strings.Contains(result.Matches[0].Reason, "U+200B") {
+		t.Errorf("Count = %d, matches = %+v, want only the zero width space", result.Count, result.Matches)
+	}
+}
+
+func TestHiddenCharsRule_Fix(t *testing.T) {
+	rule := NewHiddenCharsRule(&config.Config{Thresholds: config.DefaultThresholds})
+
+	text := "The p\u0430per is cru\u200bcial.\u200b The data\u202fshows growth."
+	edits := rule.Fix(text, rule.Check(text))
+	if len(edits) != 4 {
+		t.Fatalf("len(edits) = %d, want 4: %+v", len(edits), edits)
+	}
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].Offset > edits[j].Offset })
+	got := text
+	for _, e := range edits {
+		got = got[:e.Offset] + e.Replacement + got[e.End():]
+	}
+	if want := "The paper is crucial. The data shows growth."; got != want {
+		t.Errorf("fixed = %q, want %q", got, want)
+	}
+}
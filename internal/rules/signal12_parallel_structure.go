@@ -0,0 +1,332 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/models"
+	"github.com/leoobai/aigc-check/internal/text"
+)
+
+// ParallelStructureRule Signal 12: 三段式与排比结构检测
+// AI生成内容偏爱恰好三项的并列列举（"fast, reliable, and scalable"、"既…又…还…"）
+// 和连续使用相同句式的排比分句，按每千字的出现次数衡量
+type ParallelStructureRule struct {
+	config    *config.Config
+	processor *text.TextProcessor
+}
+
+var (
+	// tripletPattern 英文三项并列：X, Y, and Z（每项一到三个单词，牛津逗号可省略）
+	tripletPattern = regexp.MustCompile(`(?i)\b([a-z][a-z'-]*(?: [a-z][a-z'-]*){0,2}), ([a-z][a-z'-]*(?: [a-z][a-z'-]*){0,2}),? (?:and|or) ([a-z][a-z'-]*(?: [a-z][a-z'-]*){0,2})\b`)
+
+	// tripletPatternZH 中文三项并列：甲、乙和丙
+	tripletPatternZH = regexp.MustCompile(`(\p{Han}{1,8})、(\p{Han}{1,8})(?:、|和|及|以及|与)(\p{Han}{1,8})`)
+
+	// jiYouHaiPattern 中文三项递进：既…又…还（也、更）…
+	jiYouHaiPattern = regexp.MustCompile(`既[^，。！？；,.!?;\n]{1,15}[，,]?\s*又[^，。！？；,.!?;\n]{1,15}[，,]?\s*(?:还|也|更)[^，。！？；,.!?;\n]{1,15}`)
+)
+
+// tripletPronouns 以代词开头的并列项多是分句而非列举，不计为三项并列
+var tripletPronouns = map[string]bool{
+	"i": true, "we": true, "you": true, "he": true, "she": true, "it": true, "they": true,
+	"this": true, "that": true, "there": true,
+}
+
+// templateWords 构成句式模板的英文虚词，其余单词视为实词
+var templateWords = map[string]bool{
+	"a": true, "an": true, "the": true, "this": true, "that": true, "these": true, "those": true,
+	"i": true, "we": true, "you": true, "he": true, "she": true, "it": true, "they": true,
+	"our": true, "your": true, "their": true, "its": true, "my": true,
+	"is": true, "are": true, "was": true, "were": true, "be": true, "can": true, "will": true,
+	"should": true, "must": true, "may": true, "do": true, "does": true, "not": true, "no": true,
+	"to": true, "of": true, "in": true, "on": true, "for": true, "with": true, "by": true,
+	"from": true, "at": true, "into": true, "and": true, "or": true, "but": true,
+	"more": true, "less": true, "most": true, "every": true, "each": true, "all": true,
+}
+
+// templateRunesZH 构成句式模板的中文虚字，其余汉字视为实词
+const templateRunesZH = "的地得了着过是在有和与及或而并更最很太越也都就才还又再不没把被让使对为从向以于要会能将每各"
+
+// NewParallelStructureRule 创建三段式与排比结构检测规则
+func NewParallelStructureRule(cfg *config.Config) *ParallelStructureRule {
+	return &ParallelStructureRule{
+		config:    cfg,
+		processor: text.NewTextProcessor(),
+	}
+}
+
+// Check 执行规则检测
+func (r *ParallelStructureRule) Check(text string) models.RuleResult {
+	thresholds := r.config.Thresholds.ParallelStructure
+	result := models.RuleResult{
+		RuleType:    models.RuleTypeParallelStructure,
+		RuleName:    r.GetName(),
+		Description: r.GetDescription(),
+		Detected:    false,
+		Score:       100.0,
+		Severity:    models.SeverityMedium,
+		Matches:     []models.Match{},
+		Count:       0,
+		Threshold:   thresholds.Threshold,
+	}
+
+	charCount := utf8.RuneCountInString(text)
+	if charCount == 0 {
+		result.Message = "文本为空"
+		return result
+	}
+
+	var triplets, parallels int
+	for _, span := range r.findTriplets(text) {
+		triplets++
+		result.Matches = append(result.Matches, r.newMatch(text, span[0], span[1], "三项并列列举"))
+	}
+	for _, run := range r.findParallelRuns(text, thresholds.MinRepeats) {
+		parallels++
+		result.Matches = append(result.Matches, r.newMatch(text, run.start, run.end,
+			fmt.Sprintf("排比句式：连续 %d 个分句使用相同句式 %s", run.count, run.template)))
+	}
+	result.Count = len(result.Matches)
+
+	// 计算密度（每千字）
+	density := float64(result.Count) * 1000.0 / float64(charCount)
+
+	if result.Count >= result.Threshold && density >= thresholds.Density {
+		result.Detected = true
+
+		// 计算评分
+		deduction := (density - thresholds.Density) * 5.0
+		result.Score = 100.0 - deduction
+		if result.Score < 0 {
+			result.Score = 0
+		}
+
+		result.Message = fmt.Sprintf("检测到 %d 处三项并列和 %d 处排比句式，密度 %.2f/千字，超过阈值 %.2f/千字",
+			triplets, parallels, density, thresholds.Density)
+	} else {
+		result.Message = fmt.Sprintf("检测到 %d 处三项并列和 %d 处排比句式，密度 %.2f/千字，正常",
+			triplets, parallels, density)
+	}
+
+	return result
+}
+
+// GetType 获取规则类型
+func (r *ParallelStructureRule) GetType() models.RuleType {
+	return models.RuleTypeParallelStructure
+}
+
+// GetName 获取规则名称
+func (r *ParallelStructureRule) GetName() string {
+	return "三段式与排比检测"
+}
+
+// GetDescription 获取规则描述
+func (r *ParallelStructureRule) GetDescription() string {
+	return "检测恰好三项的并列列举和连续使用相同句式的排比分句，AI生成内容倾向于过度使用这类工整结构"
+}
+
+// findTriplets 查找恰好三项的并列列举，返回字节范围
+// 首尾两项的正则匹配可能吞入前后的单词，按中间一项的长度截短；
+// 前面紧接逗号或顿号的是四项以上列举的末尾，不计入
+func (r *ParallelStructureRule) findTriplets(text string) [][2]int {
+	var spans [][2]int
+
+	for _, m := range tripletPattern.FindAllStringSubmatchIndex(text, -1) {
+		if strings.HasSuffix(strings.TrimRight(text[:m[0]], " "), ",") {
+			continue
+		}
+		words := len(strings.Fields(text[m[4]:m[5]]))
+		first := strings.Fields(text[m[2]:m[3]])
+		first = first[max(len(first)-words, 0):]
+		last := strings.Fields(text[m[6]:m[7]])
+		last = last[:min(len(last), words+1)]
+		if tripletPronouns[strings.ToLower(first[0])] || tripletPronouns[strings.ToLower(last[0])] ||
+			tripletPronouns[strings.ToLower(strings.Fields(text[m[4]:m[5]])[0])] {
+			continue
+		}
+		start := m[3] - len(strings.Join(first, " "))
+		end := m[6] + len(strings.Join(last, " "))
+		spans = append(spans, [2]int{start, end})
+	}
+
+	for _, m := range tripletPatternZH.FindAllStringSubmatchIndex(text, -1) {
+		if strings.HasSuffix(text[:m[0]], "、") || strings.HasPrefix(text[m[1]:], "、") ||
+			strings.ContainsAny(text[m[6]:m[7]], "和及与") {
+			continue
+		}
+		runes := max(utf8.RuneCountInString(text[m[4]:m[5]]), 2)
+		start, end := m[3], m[6]
+		for n := 0; n < runes && start > m[2]; n++ {
+			_, size := utf8.DecodeLastRuneInString(text[:start])
+			start -= size
+		}
+		for n := 0; n < runes && end < m[7]; n++ {
+			_, size := utf8.DecodeRuneInString(text[end:])
+			end += size
+		}
+		spans = append(spans, [2]int{start, end})
+	}
+
+	for _, m := range jiYouHaiPattern.FindAllStringIndex(text, -1) {
+		spans = append(spans, [2]int{m[0], m[1]})
+	}
+
+	return spans
+}
+
+// parallelRun 连续使用相同句式的分句
+type parallelRun struct {
+	start, end int
+	count      int
+	template   string
+}
+
+// clause 分句的字节范围和句式模板，模板为空的分句会打断排比
+type clause struct {
+	start, end int
+	template   string
+}
+
+// findParallelRuns 查找连续至少 minRepeats 个句式模板相同的分句
+// 分句以句末标点、分号、中文逗号和换行分隔，空行打断排比
+func (r *ParallelStructureRule) findParallelRuns(text string, minRepeats int) []parallelRun {
+	clauses := splitClauses(text)
+
+	var runs []parallelRun
+	for i := 0; i < len(clauses); {
+		j := i + 1
+		for j < len(clauses) && clauses[i].template != "" && clauses[j].template == clauses[i].template {
+			j++
+		}
+		if clauses[i].template != "" && j-i >= minRepeats {
+			runs = append(runs, parallelRun{
+				start:    clauses[i].start,
+				end:      clauses[j-1].end,
+				count:    j - i,
+				template: clauses[i].template,
+			})
+		}
+		i = j
+	}
+	return runs
+}
+
+// splitClauses 将文本拆分为分句并计算句式模板
+func splitClauses(text string) []clause {
+	var clauses []clause
+	start := 0
+	flush := func(end int, newline bool) {
+		segment := text[start:end]
+		trimmed := strings.TrimSpace(segment)
+		if trimmed == "" {
+			// 空行
+			if newline && start > 0 && text[start-1] == '\n' {
+				clauses = append(clauses, clause{start: start, end: end})
+			}
+			return
+		}
+		s := start + strings.Index(segment, trimmed)
+		clauses = append(clauses, clause{start: s, end: s + len(trimmed), template: clauseTemplate(trimmed)})
+	}
+
+	for i, ch := range text {
+		switch {
+		case strings.ContainsRune("。！？!?；;，\n", ch):
+			flush(i, ch == '\n')
+			start = i + utf8.RuneLen(ch)
+		case ch == '.':
+			if next, _ := utf8.DecodeRuneInString(text[i+1:]); i+1 == len(text) || unicode.IsSpace(next) {
+				flush(i, false)
+				start = i + 1
+			}
+		}
+	}
+	flush(len(text), false)
+	return clauses
+}
+
+// clauseTemplate 计算分句的句式模板：保留虚词，连续的实词合并为 "…"
+// 少于三个成分或不含虚词的分句没有可比较的句式，返回空字符串
+func clauseTemplate(s string) string {
+	var tokens []string
+	functionWords := 0
+	add := func(token string, function bool) {
+		if function {
+			functionWords++
+		} else if len(tokens) > 0 && tokens[len(tokens)-1] == "…" {
+			return
+		}
+		tokens = append(tokens, token)
+	}
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		ch := runes[i]
+		switch {
+		case unicode.Is(unicode.Han, ch):
+			if strings.ContainsRune(templateRunesZH, ch) {
+				add(string(ch), true)
+			} else {
+				add("…", false)
+			}
+			i++
+		case unicode.IsLetter(ch):
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || runes[j] == '\'' || runes[j] == '-') {
+				j++
+			}
+			word := strings.ToLower(string(runes[i:j]))
+			if templateWords[word] {
+				add(word, true)
+			} else {
+				add("…", false)
+			}
+			i = j
+		case unicode.IsDigit(ch):
+			add("…", false)
+			i++
+		default:
+			i++
+		}
+	}
+
+	if len(tokens) < 3 || functionWords == 0 {
+		return ""
+	}
+	return strings.Join(tokens, " ")
+}
+
+// newMatch 根据字节范围创建匹配项
+func (r *ParallelStructureRule) newMatch(text string, start, end int, reason string) models.Match {
+	line, column := r.processor.GetLineColumn(text, start)
+	return models.Match{
+		Text: text[start:end],
+		Position: models.Position{
+			Line:   line,
+			Column: column,
+			Offset: start,
+			Length: end - start,
+		},
+		Context: r.getContext(text, start, end-start),
+		Reason:  reason,
+	}
+}
+
+// getContext 获取匹配项的上下文
+func (r *ParallelStructureRule) getContext(text string, offset, length int) string {
+	const contextSize = 30
+
+	runes := []rune(text)
+	runeOffset := utf8.RuneCountInString(text[:offset])
+	runeLength := utf8.RuneCountInString(text[offset : offset+length])
+	start := max(runeOffset-contextSize, 0)
+	end := min(runeOffset+runeLength+contextSize, len(runes))
+
+	return strings.TrimSpace(string(runes[start:end]))
+}
@@ -0,0 +1,133 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/models"
+)
+
+func TestParallelStructureRule_Check(t *testing.T) {
+	cfg := &config.Config{Thresholds: config.DefaultThresholds}
+	rule := NewParallelStructureRule(cfg)
+
+	tests := []struct {
+		name           string
+		text           string
+		expectDetected bool
+		expectMatches  []string
+	}{
+		{
+			name:           "普通文本",
+			text:           "I went to the store this morning. The bread was gone, so I bought rolls instead. Then I walked home in the rain.",
+			expectDetected: false,
+		},
+		{
+			name:           "四项列举不是三项并列",
+			text:           "I bought bread, milk, eggs, and butter.",
+			expectDetected: false,
+		},
+		{
+			name:           "以代词开头的分句不是列举",
+			text:           "The store was closed, it was raining, and we went home.",
+			expectDetected: false,
+		},
+		{
+			name:           "英文三项并列",
+			text:           "Our platform is fast, reliable, and scalable. It empowers teams, streamlines workflows, and drives growth. We deliver speed, quality and value.",
+			expectDetected: true,
+			expectMatches:  []string{"fast, reliable, and scalable", "empowers teams, streamlines workflows, and drives growth", "speed, quality and value"},
+		},
+		{
+			name:           "中文三项并列和排比",
+			text:           "这个方案既提高了效率，又降低了成本，还改善了体验。我们关注速度、质量和成本。更快的速度，更低的成本，更好的体验。",
+			expectDetected: true,
+			expectMatches:  []string{"既提高了效率，又降低了成本，还改善了体验", "速度、质量和成本", "更快的速度，更低的成本，更好的体验"},
+		},
+		{
+			name:           "少于最少匹配数量",
+			text:           "It is fast, cheap, and simple.",
+			expectDetected: false,
+			expectMatches:  []string{"fast, cheap, and simple"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := rule.Check(tt.text)
+
+			if result.Detected != tt.expectDetected {
+				t.Errorf("Detected = %v, want %v (%s)", result.Detected, tt.expectDetected, result.Message)
+			}
+			if result.RuleType != models.RuleTypeParallelStructure {
+				t.Errorf("RuleType = %s, want %s", result.RuleType, models.RuleTypeParallelStructure)
+			}
+
+			var got []string
+			for _, m := range result.Matches {
+				got = append(got, m.Text)
+				if tt.text[m.Position.Offset:m.Position.Offset+m.Position.Length] != m.Text {
+					t.Errorf("match %q does not match its position %+v", m.Text, m.Position)
+				}
+			}
+			if len(got) != len(tt.expectMatches) {
+				t.Fatalf("Matches = %q, want %q", got, tt.expectMatches)
+			}
+			for _, want := range tt.expectMatches {
+				found := false
+				for _, g := range got {
+					found = found || g == want
+				}
+				if !found {
+					t.Errorf("Matches = %q, missing %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParallelStructureRule_ParallelRuns(t *testing.T) {
+	cfg := &config.Config{Thresholds: config.DefaultThresholds}
+	rule := NewParallelStructureRule(cfg)
+
+	tests := []struct {
+		name   string
+		text   string
+		expect int
+	}{
+		{"连续三句相同句式", "It is fast.\nIt is cheap. It is simple.", 3},
+		{"空行打断排比", "It is fast. It is cheap.\n\nIt is simple.", 0},
+		{"句式不同", "It is fast. We ship on time. The team is small.", 0},
+		{"中文排比", "我们要创新；我们要合作；我们要共赢；我们要发展。", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := rule.findParallelRuns(tt.text, cfg.Thresholds.ParallelStructure.MinRepeats)
+			got := 0
+			if len(runs) > 0 {
+				got = runs[0].count
+			}
+			if got != tt.expect {
+				t.Errorf("parallel run = %+v, want %d clauses", runs, tt.expect)
+			}
+		})
+	}
+}
+
+func TestParallelStructureRule_Density(t *testing.T) {
+	cfg := &config.Config{Thresholds: config.DefaultThresholds}
+	rule := NewParallelStructureRule(cfg)
+
+	dense := "Our tools are fast, reliable, and secure. Teams plan, build, and ship. We value speed, quality, and trust."
+	if result := rule.Check(dense); !result.Detected || result.Score >= 100 {
+		t.Errorf("dense text: Detected = %v, Score = %.1f, want detected", result.Detected, result.Score)
+	}
+
+	// 同样三处并列分散在长文本中，密度低于阈值
+	long := dense + strings.Repeat(" The morning was quiet and nothing much happened at the office.", 20)
+	if result := rule.Check(long); result.Detected {
+		t.Errorf("long text: Detected = true (%s), want density below threshold", result.Message)
+	}
+}
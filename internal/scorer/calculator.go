@@ -103,6 +103,8 @@ var redFlagPenalties = map[models.RuleType]RedFlagPenalty{
 	models.RuleTypeMarkdown: {Factor: 0.80, MaxDeductionRate: 0.20},
 	// Signal 7: 表情符号异常 - 轻微问题，最大扣分10%
 	models.RuleTypeEmoji: {Factor: 0.90, MaxDeductionRate: 0.10},
+	// Signal 11: 隐形字符与同形字母 - 严重问题，扣分50%
+	models.RuleTypeHiddenChars: {Factor: 0.50, MaxDeductionRate: 0.50},
 }
 
 // 最低保底分数：即使检测到严重问题，也保留最低分数以区分程度
@@ -153,6 +155,14 @@ func (c *Calculator) CalculateTotalWithRedFlags(dimensions models.DimensionScore
 		total *= factor
 	}
 
+	// Signal 11: 隐形字符与同形字母（水印或规避检测，严重问题）
+	hiddenCharsResult := c.findRuleResult(results, models.RuleTypeHiddenChars)
+	if hiddenCharsResult != nil && hiddenCharsResult.Detected {
+		penalty := redFlagPenalties[models.RuleTypeHiddenChars]
+		adjustedFactor := c.adjustFactorBySeverity(penalty.Factor, hiddenCharsResult.Score)
+		total *= adjustedFactor
+	}
+
 	// 应用最低保底分数
 	// 即使检测到所有红旗，也保留最低分数以区分严重程度
 	if total < MinimumScore {
@@ -251,6 +261,16 @@ func (c *Calculator) calculateSentenceComplexity(results []models.RuleResult, ma
 		}
 	}
 
+	// Signal 12: 三段式与排比结构
+	parallelResult := c.findRuleResult(results, models.RuleTypeParallelStructure)
+	if parallelResult != nil {
+		if parallelResult.Detected {
+			deduction := (100.0 - parallelResult.Score) / 100.0 * maxScore * 0.5
+			totalScore -= deduction
+			issues = append(issues, "三项并列和排比句式过多，结构过于工整")
+		}
+	}
+
 	if totalScore < 0 {
 		totalScore = 0
 	}
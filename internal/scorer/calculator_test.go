@@ -147,6 +147,33 @@ func TestCalculator_CalculateTotalWithRedFlags(t *testing.T) {
 		}
 	})
 
+	// 测试隐形字符与同形字母红旗
+	t.Run("隐形字符与同形字母红旗", func(t *testing.T) {
+		dimensions := models.DimensionScores{
+			VocabularyDiversity:   models.DimensionScore{Score: 20},
+			SentenceComplexity:    models.DimensionScore{Score: 15},
+			Personalization:       models.DimensionScore{Score: 25},
+			LogicalCoherence:      models.DimensionScore{Score: 20},
+			EmotionalAuthenticity: models.DimensionScore{Score: 20},
+		}
+
+		// 单个隐形字符：评分80，轻微扣分
+		single := calc.CalculateTotalWithRedFlags(dimensions, []models.RuleResult{
+			{RuleType: models.RuleTypeHiddenChars, Detected: true, Score: 80.0},
+		})
+		if single < 85 || single > 95 {
+			t.Errorf("Total = %.1f, expected light deduction for a single hidden character", single)
+		}
+
+		// 大量隐形字符：评分0，扣分50%
+		total := calc.CalculateTotalWithRedFlags(dimensions, []models.RuleResult{
+			{RuleType: models.RuleTypeHiddenChars, Detected: true, Score: 0.0},
+		})
+		if total > 55 {
+			t.Errorf("Total = %.1f, expected significant deduction for hidden characters", total)
+		}
+	})
+
 	// 测试Markdown残留
 	t.Run("Markdown残留", func(t *testing.T) {
 		dimensions := models.DimensionScores{
@@ -359,6 +386,23 @@ func TestCalculator_CalculateDimensions(t *testing.T) {
 			t.Errorf("Personalization = %.1f, expected lower score for detected issues", dimensions.Personalization.Score)
 		}
 	})
+
+	// 测试三段式与排比结构计入句式复杂度
+	t.Run("三段式与排比结构", func(t *testing.T) {
+		results := []models.RuleResult{
+			{RuleType: models.RuleTypeParallelStructure, Detected: true, Score: 0},
+		}
+
+		dimensions := calc.CalculateDimensions(results)
+
+		maxScore := dimensions.SentenceComplexity.MaxScore
+		if got := dimensions.SentenceComplexity.Score; got > maxScore*0.5+0.01 {
+			t.Errorf("SentenceComplexity = %.1f, expected half deduction of %.1f", got, maxScore)
+		}
+		if len(dimensions.SentenceComplexity.Issues) != 1 {
+			t.Errorf("SentenceComplexity.Issues = %v, want 1 issue", dimensions.SentenceComplexity.Issues)
+		}
+	})
 }
 
 func TestCalculator_ScoreBounds(t *testing.T) {
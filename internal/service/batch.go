@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/leoobai/aigc-check/internal/config"
+)
+
+// BatchService 批量检测服务接口
+type BatchService interface {
+	Detect(ctx context.Context, items []BatchItem, options DetectionOptions, emit func(*BatchItemResult)) *BatchSummary
+}
+
+// BatchItem 批量检测条目
+type BatchItem struct {
+	ID       string            `json:"id"`
+	Text     string            `json:"text"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// BatchItemResult 单个条目的检测结果，失败时只包含错误信息
+type BatchItemResult struct {
+	Index    int               `json:"index"` // 条目在请求中的下标
+	ID       string            `json:"id,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Result   *DetectionResult  `json:"result,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// BatchSummary 批量检测汇总
+type BatchSummary struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+// errEmptyText 条目文本为空
+var errEmptyText = errors.New("text is empty")
+
+// batchService 批量检测服务实现
+type batchService struct {
+	detection   DetectionService
+	concurrency int
+}
+
+// NewBatchService 创建批量检测服务
+func NewBatchService(cfg config.BatchConfig, detection DetectionService) BatchService {
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = config.DefaultBatchConfig.Concurrency
+	}
+	return &batchService{
+		detection:   detection,
+		concurrency: concurrency,
+	}
+}
+
+// Detect 以有限并发检测所有条目，每个条目完成时按完成顺序调用 emit（调用是串行的）
+// 单个条目失败不影响其他条目；ctx 取消后不再开始新的条目，未执行的条目不会回调
+func (s *batchService) Detect(ctx context.Context, items []BatchItem, options DetectionOptions, emit func(*BatchItemResult)) *BatchSummary {
+	summary := &BatchSummary{Total: len(items)}
+
+	indexes := make(chan int)
+	go func() {
+		defer close(indexes)
+		for i := range items {
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	workers := s.concurrency
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result := s.detectItem(i, items[i], options)
+
+				mu.Lock()
+				if result.Error == "" {
+					summary.Succeeded++
+				} else {
+					summary.Failed++
+				}
+				emit(result)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return summary
+}
+
+// detectItem 检测单个条目，panic 同样作为该条目的错误返回
+func (s *batchService) detectItem(index int, item BatchItem, options DetectionOptions) (result *BatchItemResult) {
+	result = &BatchItemResult{
+		Index:    index,
+		ID:       item.ID,
+		Metadata: item.Metadata,
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			result.Result = nil
+			result.Error = fmt.Sprintf("detection panicked: %v", r)
+		}
+	}()
+
+	if strings.TrimSpace(item.Text) == "" {
+		result.Error = errEmptyText.Error()
+		return result
+	}
+
+	detection, err := s.detection.Detect(item.Text, options)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Result = detection
+	return result
+}
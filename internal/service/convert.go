@@ -0,0 +1,203 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/leoobai/aigc-check/internal/models"
+	"github.com/leoobai/aigc-check/internal/repository"
+)
+
+// recordToResult 将数据库记录还原为检测结果
+func recordToResult(record *repository.DetectionRecord) (*DetectionResult, error) {
+	// 反序列化 JSON 字段
+	var ruleResults []*models.RuleResult
+	if err := json.Unmarshal([]byte(record.RuleResults), &ruleResults); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rule results: %w", err)
+	}
+
+	var suggestions []*models.Suggestion
+	if err := json.Unmarshal([]byte(record.Suggestions), &suggestions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal suggestions: %w", err)
+	}
+
+	var multimodalResult *models.MultimodalResult
+	if record.MultimodalResult != "" {
+		if err := json.Unmarshal([]byte(record.MultimodalResult), &multimodalResult); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal multimodal result: %w", err)
+		}
+	}
+
+	score, err := recordScore(record)
+	if err != nil {
+		return nil, err
+	}
+
+	riskDescription := record.RiskDescription
+	if riskDescription == "" {
+		riskDescription = models.RiskLevel(record.RiskLevel).Description()
+	}
+
+	return &DetectionResult{
+		ID:                record.ID,
+		RequestID:         record.RequestID,
+		Text:              record.Text,
+		Score:             score,
+		RiskLevel:         record.RiskLevel,
+		RuleResults:       ruleResults,
+		Suggestions:       suggestions,
+		MultimodalResult:  multimodalResult,
+		ProcessTime:       record.ProcessTime,
+		DetectedAt:        record.CreatedAt.Local(),
+		Tags:              repository.SplitList(record.Tags),
+		RiskDescription:   riskDescription,
+		AnalyzerVersion:   record.AnalyzerVersion,
+		ConfigFingerprint: record.ConfigFingerprint,
+		InputFormat:       record.InputFormat,
+		Language:          record.Language,
+		DuplicateOf:       record.DuplicateOf,
+		SubmitCount:       record.SubmitCount,
+		TextPurgedAt:      record.TextPurgedAt,
+	}, nil
+}
+
+// resultToRecord 将检测结果转换为数据库记录
+func resultToRecord(result *DetectionResult) (*repository.DetectionRecord, error) {
+	// 序列化 JSON 字段
+	ruleResultsJSON, err := json.Marshal(result.RuleResults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rule results: %w", err)
+	}
+
+	suggestionsJSON, err := json.Marshal(result.Suggestions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal suggestions: %w", err)
+	}
+
+	scoreJSON, err := json.Marshal(result.Score)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal score: %w", err)
+	}
+
+	var multimodalJSON []byte
+	if result.MultimodalResult != nil {
+		multimodalJSON, err = json.Marshal(result.MultimodalResult)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal multimodal result: %w", err)
+		}
+	}
+
+	// 创建文本预览（前100字）
+	// 按字符截断，避免切断多字节字符导致 PostgreSQL/MySQL 拒绝非法 UTF-8
+	textPreview := result.Text
+	if runes := []rune(textPreview); len(runes) > 100 {
+		textPreview = string(runes[:100]) + "..."
+	}
+
+	// 创建数据库记录
+	record := &repository.DetectionRecord{
+		ID:                result.ID,
+		RequestID:         result.RequestID,
+		Text:              result.Text,
+		TextPreview:       textPreview,
+		Score:             result.Score.Total,
+		RiskLevel:         result.RiskLevel,
+		RuleResults:       string(ruleResultsJSON),
+		Suggestions:       string(suggestionsJSON),
+		MultimodalResult:  string(multimodalJSON),
+		ScoreDetail:       string(scoreJSON),
+		ProcessTime:       result.ProcessTime,
+		ContentHash:       repository.ContentHash(result.Text),
+		DetectedRules:     repository.JoinList(detectedRuleTypes(result.RuleResults)),
+		Tags:              repository.JoinList(result.Tags),
+		RiskDescription:   result.RiskDescription,
+		AnalyzerVersion:   result.AnalyzerVersion,
+		ConfigFingerprint: result.ConfigFingerprint,
+		InputFormat:       result.InputFormat,
+		Language:          result.Language,
+		DuplicateOf:       result.DuplicateOf,
+		SubmitCount:       result.SubmitCount,
+		TextPurgedAt:      result.TextPurgedAt,
+		CreatedAt:         result.DetectedAt,
+	}
+	// 原文已清除的记录不保留内容哈希，避免通过哈希反查提交过的文本
+	if result.TextPurgedAt != nil {
+		record.ContentHash = ""
+	}
+	if result.Score != nil {
+		dims := result.Score.Dimensions
+		record.VocabularyDiversityScore = &dims.VocabularyDiversity.Score
+		record.SentenceComplexityScore = &dims.SentenceComplexity.Score
+		record.PersonalizationScore = &dims.Personalization.Score
+		record.LogicalCoherenceScore = &dims.LogicalCoherence.Score
+		record.EmotionalAuthenticityScore = &dims.EmotionalAuthenticity.Score
+	}
+
+	return record, nil
+}
+
+// recordScore 还原完整评分
+// 早期记录没有保存完整评分，使用总分和维度评分列尽量还原
+func recordScore(record *repository.DetectionRecord) (*models.Score, error) {
+	if record.ScoreDetail != "" {
+		var score models.Score
+		if err := json.Unmarshal([]byte(record.ScoreDetail), &score); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal score: %w", err)
+		}
+		return &score, nil
+	}
+
+	score := &models.Score{Total: record.Score}
+	weights := models.DefaultDimensionWeights
+	dimensions := []struct {
+		value    *float64
+		maxScore float64
+		target   *models.DimensionScore
+	}{
+		{record.VocabularyDiversityScore, weights.VocabularyDiversity, &score.Dimensions.VocabularyDiversity},
+		{record.SentenceComplexityScore, weights.SentenceComplexity, &score.Dimensions.SentenceComplexity},
+		{record.PersonalizationScore, weights.Personalization, &score.Dimensions.Personalization},
+		{record.LogicalCoherenceScore, weights.LogicalCoherence, &score.Dimensions.LogicalCoherence},
+		{record.EmotionalAuthenticityScore, weights.EmotionalAuthenticity, &score.Dimensions.EmotionalAuthenticity},
+	}
+	for _, d := range dimensions {
+		if d.value != nil {
+			*d.target = models.NewDimensionScore(*d.value, d.maxScore, nil, "")
+		}
+	}
+	return score, nil
+}
+
+// ToModel 转换为分析器的检测结果，用于通过任意报告生成器重新渲染历史报告
+func (r *DetectionResult) ToModel() *models.DetectionResult {
+	result := &models.DetectionResult{
+		RequestID:         r.RequestID,
+		Text:              r.Text,
+		RiskLevel:         models.RiskLevel(r.RiskLevel),
+		DetectedAt:        r.DetectedAt,
+		Multimodal:        r.MultimodalResult,
+		AnalyzerVersion:   r.AnalyzerVersion,
+		ConfigFingerprint: r.ConfigFingerprint,
+	}
+	if r.Score != nil {
+		result.Score = *r.Score
+	}
+	// Duration.String 的输出可以被 ParseDuration 精确还原
+	if d, err := time.ParseDuration(r.ProcessTime); err == nil {
+		result.ProcessTime = d
+	}
+	if r.RuleResults != nil {
+		result.RuleResults = make([]models.RuleResult, len(r.RuleResults))
+		for i, ruleResult := range r.RuleResults {
+			result.RuleResults[i] = *ruleResult
+		}
+	}
+	if r.Suggestions != nil {
+		result.Suggestions = make([]models.Suggestion, len(r.Suggestions))
+		for i, suggestion := range r.Suggestions {
+			result.Suggestions[i] = *suggestion
+		}
+	}
+	return result
+}
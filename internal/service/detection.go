@@ -1,8 +1,8 @@
 package service
 
 import (
-	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -24,6 +24,14 @@ type DetectionOptions struct {
 	EnableStatistics bool
 	EnableSemantic   bool
 	Language         string
+	Tags             []string // 元数据标签，可用于历史记录过滤
+	Force            bool     // 忽略内容相同的已有结果，强制重新检测
+	Anonymous        bool     // 匿名模式：不保存原文，也不与已有记录比对或关联
+	InputFormat      string   // 输入格式：text, markdown，markdown 时文本应为已去除格式标记的正文
+	Profile          string   // 规则配置档（如代码注释使用的 code），为空时使用全局配置
+
+	// OnLayer 每个检测层完成时调用，复用已有结果时不会调用
+	OnLayer func(models.LayerResult) `json:"-"`
 }
 
 // DetectionResult 检测结果
@@ -38,34 +46,101 @@ type DetectionResult struct {
 	MultimodalResult *models.MultimodalResult `json:"multimodal,omitempty"`
 	ProcessTime      string                  `json:"process_time"`
 	DetectedAt       time.Time               `json:"detected_at"`
+
+	Tags              []string `json:"tags,omitempty"`
+	RiskDescription   string `json:"risk_description"`
+	AnalyzerVersion   string `json:"analyzer_version,omitempty"`
+	ConfigFingerprint string `json:"config_fingerprint,omitempty"`
+	InputFormat       string `json:"input_format,omitempty"` // 检测时的输入格式，与分析器版本和配置指纹一起决定结果能否复用
+	Language          string `json:"language,omitempty"`
+
+	Cached      bool   `json:"cached"`                 // 是否复用了内容相同的已有结果
+	DuplicateOf string `json:"duplicate_of,omitempty"` // 同一内容最早的记录 ID
+	SubmitCount int    `json:"submit_count"`           // 该结果被提交的次数
+
+	TextPurgedAt *time.Time `json:"text_purged_at,omitempty"` // 原文被清除的时间，清除后只保留分数和元数据
 }
 
 // detectionService 检测服务实现
 type detectionService struct {
+	config     *config.Config
 	analyzer   *analyzer.Analyzer
 	repository repository.DetectionRepository
+
+	mu       sync.Mutex
+	profiles map[string]*analyzer.Analyzer // 各规则配置档的分析器，首次使用时创建
 }
 
 // NewDetectionService 创建检测服务
 func NewDetectionService(cfg *config.Config, repo repository.DetectionRepository) DetectionService {
 	return &detectionService{
+		config:     cfg,
 		analyzer:   analyzer.NewAnalyzer(cfg),
 		repository: repo,
+		profiles:   make(map[string]*analyzer.Analyzer),
 	}
 }
 
+// analyzerFor 返回使用指定规则配置档的分析器，profile 为空时返回全局配置的分析器
+func (s *detectionService) analyzerFor(profile string) (*analyzer.Analyzer, error) {
+	if profile == "" {
+		return s.analyzer, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a, ok := s.profiles[profile]; ok {
+		return a, nil
+	}
+	cfg, err := s.config.WithProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	a := analyzer.NewAnalyzer(cfg)
+	s.profiles[profile] = a
+	return a, nil
+}
+
 // Detect 执行文本检测
 func (s *detectionService) Detect(text string, options DetectionOptions) (*DetectionResult, error) {
+	// 规则配置档改变了检测配置，其结果的配置指纹也随之不同
+	a, err := s.analyzerFor(options.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// 查找内容相同的已有记录（匿名提交不做比对，避免暴露同一文本被提交过）
+	var previous []*repository.DetectionRecord
+	if !options.Anonymous {
+		previous, err = s.repository.FindByContentHash(repository.ContentHash(text))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 分析器版本、配置和影响结果的检测选项都一致时直接复用已有结果，避免重复调用各检测层
+	inputFormat := options.InputFormat
+	if inputFormat == "" {
+		inputFormat = models.InputFormatText
+	}
+	if !options.Force {
+		if record := s.findReusable(previous, a.Fingerprint(), inputFormat, options.Language); record != nil {
+			return s.reuse(record, options.Tags)
+		}
+	}
+
 	// 构建检测请求
 	request := models.DetectionRequest{
 		Text: text,
 		Options: models.DetectionOptions{
-			Language: options.Language,
+			Language:    options.Language,
+			InputFormat: options.InputFormat,
 		},
+		OnLayer: options.OnLayer,
 	}
 
 	// 执行分析
-	result, err := s.analyzer.Analyze(request)
+	result, err := a.Analyze(request)
 	if err != nil {
 		return nil, fmt.Errorf("analysis failed: %w", err)
 	}
@@ -74,14 +149,26 @@ func (s *detectionService) Detect(text string, options DetectionOptions) (*Detec
 	id := uuid.New().String()
 
 	// 转换结果
+	// 检测时间截断到毫秒，与各数据库的时间精度一致，保证从历史记录读取的结果与首次返回相同
 	detectionResult := &DetectionResult{
-		ID:          id,
-		RequestID:   result.RequestID,
-		Text:        result.Text,
-		Score:       &result.Score,
-		RiskLevel:   string(result.RiskLevel),
-		ProcessTime: result.ProcessTime.String(),
-		DetectedAt:  result.DetectedAt,
+		ID:                id,
+		RequestID:         result.RequestID,
+		Text:              result.Text,
+		Score:             &result.Score,
+		RiskLevel:         string(result.RiskLevel),
+		MultimodalResult:  result.Multimodal,
+		ProcessTime:       result.ProcessTime.String(),
+		DetectedAt:        result.DetectedAt.Truncate(time.Millisecond),
+		Tags:              options.Tags,
+		RiskDescription:   result.RiskLevel.Description(),
+		AnalyzerVersion:   result.AnalyzerVersion,
+		ConfigFingerprint: result.ConfigFingerprint,
+		InputFormat:       inputFormat,
+		Language:          options.Language,
+		SubmitCount:       1,
+	}
+	if len(previous) > 0 {
+		detectionResult.DuplicateOf = groupID(previous[0])
 	}
 
 	// 转换 RuleResults
@@ -97,7 +184,7 @@ func (s *detectionService) Detect(text string, options DetectionOptions) (*Detec
 	}
 
 	// 保存到数据库
-	if err := s.saveToRepository(detectionResult); err != nil {
+	if err := s.saveToRepository(detectionResult, options.Anonymous); err != nil {
 		return nil, fmt.Errorf("failed to save result: %w", err)
 	}
 
@@ -105,47 +192,51 @@ func (s *detectionService) Detect(text string, options DetectionOptions) (*Detec
 }
 
 // saveToRepository 保存检测结果到数据库
-func (s *detectionService) saveToRepository(result *DetectionResult) error {
-	// 序列化 JSON 字段
-	ruleResultsJSON, err := json.Marshal(result.RuleResults)
-	if err != nil {
-		return fmt.Errorf("failed to marshal rule results: %w", err)
+// anonymous 为 true 时只保存分数和元数据，原文及含原文片段的字段不落库
+func (s *detectionService) saveToRepository(result *DetectionResult, anonymous bool) error {
+	stored := result
+	if anonymous {
+		stored = redactResult(result, result.DetectedAt)
 	}
 
-	suggestionsJSON, err := json.Marshal(result.Suggestions)
+	record, err := resultToRecord(stored)
 	if err != nil {
-		return fmt.Errorf("failed to marshal suggestions: %w", err)
+		return err
 	}
+	return s.repository.Create(record)
+}
 
-	var multimodalJSON []byte
-	if result.MultimodalResult != nil {
-		multimodalJSON, err = json.Marshal(result.MultimodalResult)
-		if err != nil {
-			return fmt.Errorf("failed to marshal multimodal result: %w", err)
+// findReusable 在内容相同的记录中查找分析器版本、配置指纹、输入格式和语言都一致的最新记录
+// 早期记录没有保存输入格式，不会被复用
+func (s *detectionService) findReusable(records []*repository.DetectionRecord, fingerprint, inputFormat, language string) *repository.DetectionRecord {
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		if record.TextPurgedAt != nil {
+			continue
+		}
+		if record.AnalyzerVersion == analyzer.Version && record.ConfigFingerprint == fingerprint &&
+			record.InputFormat == inputFormat && record.Language == language {
+			return record
 		}
 	}
+	return nil
+}
 
-	// 创建文本预览（前100字）
-	textPreview := result.Text
-	if len(textPreview) > 100 {
-		textPreview = textPreview[:100] + "..."
+// reuse 复用已有记录的结果，合并本次提交的标签并累加提交次数
+func (s *detectionService) reuse(record *repository.DetectionRecord, tags []string) (*DetectionResult, error) {
+	merged := mergeTags(repository.SplitList(record.Tags), tags)
+	if err := s.repository.RecordResubmission(record.ID, repository.JoinList(merged)); err != nil {
+		return nil, err
 	}
+	record.Tags = repository.JoinList(merged)
+	record.SubmitCount++
 
-	// 创建数据库记录
-	record := &repository.DetectionRecord{
-		ID:               result.ID,
-		RequestID:        result.RequestID,
-		Text:             result.Text,
-		TextPreview:      textPreview,
-		Score:            result.Score.Total,
-		RiskLevel:        result.RiskLevel,
-		RuleResults:      string(ruleResultsJSON),
-		Suggestions:      string(suggestionsJSON),
-		MultimodalResult: string(multimodalJSON),
-		ProcessTime:      result.ProcessTime,
+	result, err := recordToResult(record)
+	if err != nil {
+		return nil, err
 	}
-
-	return s.repository.Create(record)
+	result.Cached = true
+	return result, nil
 }
 
 // GetResult 根据 ID 获取检测结果
@@ -154,35 +245,37 @@ func (s *detectionService) GetResult(id string) (*DetectionResult, error) {
 	if err != nil {
 		return nil, err
 	}
+	return recordToResult(record)
+}
 
-	// 反序列化 JSON 字段
-	var ruleResults []*models.RuleResult
-	if err := json.Unmarshal([]byte(record.RuleResults), &ruleResults); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal rule results: %w", err)
+// detectedRuleTypes 提取触发的规则类型
+func detectedRuleTypes(results []*models.RuleResult) []string {
+	var ruleTypes []string
+	for _, result := range results {
+		if result.Detected {
+			ruleTypes = append(ruleTypes, string(result.RuleType))
+		}
 	}
+	return ruleTypes
+}
 
-	var suggestions []*models.Suggestion
-	if err := json.Unmarshal([]byte(record.Suggestions), &suggestions); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal suggestions: %w", err)
+// groupID 返回记录所属重复提交分组的 ID（即同一内容最早的记录 ID）
+func groupID(record *repository.DetectionRecord) string {
+	if record.DuplicateOf != "" {
+		return record.DuplicateOf
 	}
+	return record.ID
+}
 
-	var multimodalResult *models.MultimodalResult
-	if record.MultimodalResult != "" {
-		if err := json.Unmarshal([]byte(record.MultimodalResult), &multimodalResult); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal multimodal result: %w", err)
+// mergeTags 合并标签并去重，保持原有顺序
+func mergeTags(existing, added []string) []string {
+	seen := make(map[string]bool, len(existing)+len(added))
+	var merged []string
+	for _, tag := range append(append([]string{}, existing...), added...) {
+		if !seen[tag] {
+			seen[tag] = true
+			merged = append(merged, tag)
 		}
 	}
-
-	return &DetectionResult{
-		ID:               record.ID,
-		RequestID:        record.RequestID,
-		Text:             record.Text,
-		Score:            &models.Score{Total: record.Score},
-		RiskLevel:        record.RiskLevel,
-		RuleResults:      ruleResults,
-		Suggestions:      suggestions,
-		MultimodalResult: multimodalResult,
-		ProcessTime:      record.ProcessTime,
-		DetectedAt:       record.CreatedAt,
-	}, nil
+	return merged
 }
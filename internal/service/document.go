@@ -0,0 +1,213 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leoobai/aigc-check/internal/compare"
+	"github.com/leoobai/aigc-check/internal/repository"
+)
+
+// DocumentService 文档修订服务接口
+type DocumentService interface {
+	Create(title string) (*Document, error)
+	Get(id string) (*Document, error)
+	AddRevision(documentID, text string, options DetectionOptions) (*RevisionResult, error)
+	Compare(documentID string, from, to int) (*DocumentComparison, error)
+}
+
+// Document 文档及其修订版本的分数走势
+type Document struct {
+	ID        string             `json:"id"`
+	Title     string             `json:"title"`
+	Revisions []*RevisionSummary `json:"revisions"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// RevisionSummary 修订版本摘要
+type RevisionSummary struct {
+	Revision   int       `json:"revision"`
+	RecordID   string    `json:"record_id"`
+	Score      float64   `json:"score"`
+	RiskLevel  string    `json:"risk_level"`
+	ScoreDelta float64   `json:"score_delta"` // 相对上一修订版本的分数变化
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// RevisionResult 追加修订版本的结果
+type RevisionResult struct {
+	DocumentID string           `json:"document_id"`
+	Revision   int              `json:"revision"`
+	Result     *DetectionResult `json:"result"`
+}
+
+// DocumentComparison 文档两个修订版本之间的比较
+type DocumentComparison struct {
+	DocumentID   string `json:"document_id"`
+	FromRevision int    `json:"from_revision"`
+	ToRevision   int    `json:"to_revision"`
+	*compare.Comparison
+}
+
+// documentService 文档修订服务实现
+type documentService struct {
+	detectionService DetectionService
+	documents        repository.DocumentRepository
+	records          repository.DetectionRepository
+}
+
+// NewDocumentService 创建文档修订服务
+func NewDocumentService(
+	detectionService DetectionService,
+	documents repository.DocumentRepository,
+	records repository.DetectionRepository,
+) DocumentService {
+	return &documentService{
+		detectionService: detectionService,
+		documents:        documents,
+		records:          records,
+	}
+}
+
+// Create 创建文档
+func (s *documentService) Create(title string) (*Document, error) {
+	document := &repository.Document{
+		ID:    uuid.New().String(),
+		Title: title,
+	}
+	if err := s.documents.Create(document); err != nil {
+		return nil, err
+	}
+	return &Document{
+		ID:        document.ID,
+		Title:     document.Title,
+		Revisions: []*RevisionSummary{},
+		CreatedAt: document.CreatedAt,
+		UpdatedAt: document.UpdatedAt,
+	}, nil
+}
+
+// Get 获取文档及各修订版本的分数走势
+func (s *documentService) Get(id string) (*Document, error) {
+	document, err := s.documents.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.documents.ListRevisions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(revisions))
+	for i, revision := range revisions {
+		ids[i] = revision.RecordID
+	}
+	records, err := s.records.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*repository.DetectionRecord, len(records))
+	for _, record := range records {
+		byID[record.ID] = record
+	}
+
+	result := &Document{
+		ID:        document.ID,
+		Title:     document.Title,
+		Revisions: make([]*RevisionSummary, 0, len(revisions)),
+		CreatedAt: document.CreatedAt,
+		UpdatedAt: document.UpdatedAt,
+	}
+	var previous *RevisionSummary
+	for _, revision := range revisions {
+		summary := &RevisionSummary{
+			Revision:  revision.Revision,
+			RecordID:  revision.RecordID,
+			CreatedAt: revision.CreatedAt,
+		}
+		// 关联的检测记录可能已被删除，此时只保留修订号
+		if record, ok := byID[revision.RecordID]; ok {
+			summary.Score = record.Score
+			summary.RiskLevel = record.RiskLevel
+			if previous != nil && previous.RiskLevel != "" {
+				summary.ScoreDelta = summary.Score - previous.Score
+			}
+		}
+		result.Revisions = append(result.Revisions, summary)
+		previous = summary
+	}
+
+	return result, nil
+}
+
+// AddRevision 检测新版本文本并追加为文档的修订版本
+func (s *documentService) AddRevision(documentID, text string, options DetectionOptions) (*RevisionResult, error) {
+	if _, err := s.documents.GetByID(documentID); err != nil {
+		return nil, err
+	}
+
+	result, err := s.detectionService.Detect(text, options)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.documents.AddRevision(documentID, result.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RevisionResult{
+		DocumentID: documentID,
+		Revision:   revision.Revision,
+		Result:     result,
+	}, nil
+}
+
+// Compare 比较文档的两个修订版本
+// to 为 0 时使用最新修订版本，from 为 0 时使用 to 的上一个修订版本
+func (s *documentService) Compare(documentID string, from, to int) (*DocumentComparison, error) {
+	if to == 0 {
+		revisions, err := s.documents.ListRevisions(documentID)
+		if err != nil {
+			return nil, err
+		}
+		if len(revisions) == 0 {
+			return nil, fmt.Errorf("document has no revisions: %s", documentID)
+		}
+		to = revisions[len(revisions)-1].Revision
+	}
+	if from == 0 {
+		from = to - 1
+	}
+	if from < 1 || from == to {
+		return nil, fmt.Errorf("document %s needs two different revisions to compare (from %d, to %d)", documentID, from, to)
+	}
+
+	fromResult, err := s.revisionResult(documentID, from)
+	if err != nil {
+		return nil, err
+	}
+	toResult, err := s.revisionResult(documentID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DocumentComparison{
+		DocumentID:   documentID,
+		FromRevision: from,
+		ToRevision:   to,
+		Comparison:   compare.Compare(fromResult.ToModel(), toResult.ToModel()),
+	}, nil
+}
+
+// revisionResult 获取修订版本关联的检测结果
+func (s *documentService) revisionResult(documentID string, number int) (*DetectionResult, error) {
+	revision, err := s.documents.GetRevision(documentID, number)
+	if err != nil {
+		return nil, err
+	}
+	return s.detectionService.GetResult(revision.RecordID)
+}
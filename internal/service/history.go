@@ -1,16 +1,15 @@
 package service
 
 import (
-	"encoding/json"
 	"fmt"
+	"time"
 
-	"github.com/leoobai/aigc-check/internal/models"
 	"github.com/leoobai/aigc-check/internal/repository"
 )
 
 // HistoryService 历史服务接口
 type HistoryService interface {
-	List(page, pageSize int, sortBy, order string) (*HistoryListResult, error)
+	List(page, pageSize int, sortBy, order string, filter HistoryFilter) (*HistoryListResult, error)
 	GetByID(id string) (*DetectionResult, error)
 	Delete(id string) error
 	DeleteAll() error
@@ -26,12 +25,34 @@ type HistoryListResult struct {
 
 // HistoryListItem 历史列表项
 type HistoryListItem struct {
-	ID          string `json:"id"`
-	RequestID   string `json:"request_id"`
-	TextPreview string `json:"text_preview"`
-	Score       float64 `json:"score"`
-	RiskLevel   string `json:"risk_level"`
-	CreatedAt   string `json:"created_at"`
+	ID            string   `json:"id"`
+	RequestID     string   `json:"request_id"`
+	TextPreview   string   `json:"text_preview"`
+	Score         float64  `json:"score"`
+	RiskLevel     string   `json:"risk_level"`
+	DetectedRules []string `json:"detected_rules"`
+	Tags          []string `json:"tags"`
+	Snippet       string   `json:"snippet,omitempty"` // 全文检索命中片段，命中词以 <mark> 标记
+	DuplicateOf   string   `json:"duplicate_of,omitempty"`
+	SubmitCount   int      `json:"submit_count"`
+	Duplicates    int64    `json:"duplicates"`  // 关联到该记录的重新检测次数
+	TextPurged    bool     `json:"text_purged"` // 原文已按保留策略清除或提交时未保存
+	CreatedAt     string   `json:"created_at"`
+}
+
+// HistoryFilter 历史记录过滤条件，零值字段表示不过滤
+type HistoryFilter struct {
+	MinScore   *float64
+	MaxScore   *float64
+	RiskLevels []string
+	From       time.Time
+	To         time.Time
+	RuleTypes  []string
+	Tags       []string
+	Query      string
+
+	DuplicateOf   string
+	OriginalsOnly bool
 }
 
 // historyService 历史服务实现
@@ -47,8 +68,17 @@ func NewHistoryService(repo repository.DetectionRepository) HistoryService {
 }
 
 // List 获取历史记录列表
-func (s *historyService) List(page, pageSize int, sortBy, order string) (*HistoryListResult, error) {
-	records, total, err := s.repository.List(page, pageSize, sortBy, order)
+func (s *historyService) List(page, pageSize int, sortBy, order string, filter HistoryFilter) (*HistoryListResult, error) {
+	records, total, err := s.repository.List(page, pageSize, sortBy, order, repository.ListFilter(filter))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list history: %w", err)
+	}
+
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+	duplicates, err := s.repository.CountDuplicates(ids)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list history: %w", err)
 	}
@@ -56,12 +86,21 @@ func (s *historyService) List(page, pageSize int, sortBy, order string) (*Histor
 	items := make([]*HistoryListItem, len(records))
 	for i, record := range records {
 		items[i] = &HistoryListItem{
-			ID:          record.ID,
-			RequestID:   record.RequestID,
-			TextPreview: record.TextPreview,
-			Score:       record.Score,
-			RiskLevel:   record.RiskLevel,
-			CreatedAt:   record.CreatedAt.Format("2006-01-02 15:04:05"),
+			ID:            record.ID,
+			RequestID:     record.RequestID,
+			TextPreview:   record.TextPreview,
+			Score:         record.Score,
+			RiskLevel:     record.RiskLevel,
+			DetectedRules: repository.SplitList(record.DetectedRules),
+			Tags:          repository.SplitList(record.Tags),
+			DuplicateOf:   record.DuplicateOf,
+			SubmitCount:   record.SubmitCount,
+			Duplicates:    duplicates[record.ID],
+			TextPurged:    record.TextPurgedAt != nil,
+			CreatedAt:     record.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+		if filter.Query != "" {
+			items[i].Snippet = highlightSnippet(record.Text, repository.SearchTerms(filter.Query))
 		}
 	}
 
@@ -79,29 +118,7 @@ func (s *historyService) GetByID(id string) (*DetectionResult, error) {
 	if err != nil {
 		return nil, err
 	}
-
-	// 反序列化 JSON 字段
-	var ruleResults []*models.RuleResult
-	if err := json.Unmarshal([]byte(record.RuleResults), &ruleResults); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal rule results: %w", err)
-	}
-
-	var suggestions []*models.Suggestion
-	if err := json.Unmarshal([]byte(record.Suggestions), &suggestions); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal suggestions: %w", err)
-	}
-
-	return &DetectionResult{
-		ID:          record.ID,
-		RequestID:   record.RequestID,
-		Text:        record.Text,
-		Score:       &models.Score{Total: record.Score},
-		RiskLevel:   record.RiskLevel,
-		RuleResults: ruleResults,
-		Suggestions: suggestions,
-		ProcessTime: record.ProcessTime,
-		DetectedAt:  record.CreatedAt,
-	}, nil
+	return recordToResult(record)
 }
 
 // Delete 删除历史记录
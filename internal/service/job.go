@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/models"
+	"github.com/leoobai/aigc-check/internal/repository"
+)
+
+// ErrQueueFull 等待队列已满
+var ErrQueueFull = errors.New("job queue is full")
+
+// webhookResolveTimeout 提交任务时解析回调域名的超时时间
+const webhookResolveTimeout = 5 * time.Second
+
+// errAnonymousTextLost 匿名任务的原文只保存在提交实例的内存中，实例重启后无法恢复
+var errAnonymousTextLost = errors.New("anonymous job text is kept in memory only and was lost when its server stopped")
+
+// JobService 异步检测任务服务接口
+type JobService interface {
+	Submit(text string, options DetectionOptions, webhookURL string) (*JobStatus, error)
+	Get(id string) (*JobStatus, error)
+	Run(ctx context.Context) error
+}
+
+// JobStatus 异步任务状态
+type JobStatus struct {
+	ID         string               `json:"id"`
+	Status     string               `json:"status"` // queued, running, done, failed
+	Layers     []models.LayerResult `json:"layers"` // 已完成检测层的阶段性结果
+	ResultID   string               `json:"result_id,omitempty"`
+	Result     *DetectionResult     `json:"result,omitempty"` // 任务完成后的检测结果
+	Error      string               `json:"error,omitempty"`
+	Webhook    *WebhookStatus       `json:"webhook,omitempty"`
+	CreatedAt  time.Time            `json:"created_at"`
+	StartedAt  *time.Time           `json:"started_at,omitempty"`
+	FinishedAt *time.Time           `json:"finished_at,omitempty"`
+}
+
+// WebhookStatus 回调投递状态
+type WebhookStatus struct {
+	URL      string `json:"url"`
+	Status   string `json:"status"` // pending, delivered, failed
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+}
+
+// webhookPayload 回调请求体
+type webhookPayload struct {
+	Event string     `json:"event"`
+	Job   *JobStatus `json:"job"`
+}
+
+// jobService 异步检测任务服务实现
+// 任务持久化在数据库中，由固定数量的 worker 从有界队列中取出执行；
+// 多个实例可以共享同一个数据库，执行中的任务由所属实例定期续租，租约过期的任务由任一实例重新执行
+type jobService struct {
+	detection DetectionService
+	jobs      repository.JobRepository
+	webhook   *webhookSender
+	workers   int
+	queue     chan string
+	owner     string        // 当前实例 ID
+	lease     time.Duration // 租约时长
+
+	mu    sync.Mutex
+	texts map[string]string // 匿名任务的原文，不写入数据库
+}
+
+// NewJobService 创建异步检测任务服务
+func NewJobService(cfg config.JobsConfig, detection DetectionService, jobs repository.JobRepository) JobService {
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = config.DefaultJobsConfig.Workers
+	}
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = config.DefaultJobsConfig.QueueSize
+	}
+	leaseTimeout := cfg.LeaseTimeout
+	if leaseTimeout <= 0 {
+		leaseTimeout = config.DefaultJobsConfig.LeaseTimeout
+	}
+
+	return &jobService{
+		detection: detection,
+		jobs:      jobs,
+		webhook:   newWebhookSender(cfg.WebhookSecret, cfg.WebhookAllowPrivate, time.Duration(cfg.WebhookTimeout)*time.Second, cfg.WebhookRetries),
+		workers:   workers,
+		queue:     make(chan string, queueSize),
+		owner:     uuid.New().String(),
+		lease:     time.Duration(leaseTimeout) * time.Second,
+		texts:     make(map[string]string),
+	}
+}
+
+// Submit 提交异步检测任务，队列已满时返回 ErrQueueFull
+// 匿名任务的原文不写入数据库，任务归属当前实例，只能由当前实例执行
+func (s *jobService) Submit(text string, options DetectionOptions, webhookURL string) (*JobStatus, error) {
+	if webhookURL != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookResolveTimeout)
+		err := s.webhook.validate(ctx, webhookURL)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	encodedOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job options: %w", err)
+	}
+
+	job := &repository.Job{
+		ID:         uuid.New().String(),
+		Status:     repository.JobStatusQueued,
+		Text:       text,
+		Options:    string(encodedOptions),
+		Layers:     "[]",
+		WebhookURL: webhookURL,
+	}
+	if webhookURL != "" {
+		job.WebhookStatus = repository.WebhookStatusPending
+	}
+	if options.Anonymous {
+		now := time.Now()
+		job.Text = ""
+		job.Owner = s.owner
+		job.HeartbeatAt = &now
+		s.mu.Lock()
+		s.texts[job.ID] = text
+		s.mu.Unlock()
+	}
+	if err := s.jobs.Create(job); err != nil {
+		s.takeText(job.ID)
+		return nil, err
+	}
+
+	select {
+	case s.queue <- job.ID:
+	default:
+		s.takeText(job.ID)
+		// 被拒绝的任务标记为失败，不再留在队列中等待恢复
+		if err := s.jobs.MarkFailed(job.ID, ErrQueueFull.Error()); err != nil {
+			return nil, err
+		}
+		return nil, ErrQueueFull
+	}
+
+	return jobToStatus(job, nil)
+}
+
+// Get 获取任务状态，任务完成时附带检测结果
+func (s *jobService) Get(id string) (*JobStatus, error) {
+	job, err := s.jobs.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *DetectionResult
+	if job.Status == repository.JobStatusDone && job.ResultID != "" {
+		result, err = s.detection.GetResult(job.ResultID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job result: %w", err)
+		}
+	}
+	return jobToStatus(job, result)
+}
+
+// Run 启动 worker 执行任务，直到 ctx 被取消；返回前等待执行中的任务完成
+// 运行期间定期为当前实例的任务续租，并将租约过期（所属实例已停止）的任务重新放回队列
+func (s *jobService) Run(ctx context.Context) error {
+	requeued, err := s.recoverJobs(ctx, true)
+	if err != nil {
+		return err
+	}
+	if requeued > 0 {
+		log.Printf("Recovered %d queued jobs", requeued)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case id := <-s.queue:
+					s.process(id)
+				}
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(s.lease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return nil
+		case <-ticker.C:
+			if err := s.jobs.Heartbeat(s.owner); err != nil {
+				log.Printf("Failed to renew job lease: %v", err)
+			}
+			if requeued, err := s.recoverJobs(ctx, false); err != nil {
+				log.Printf("Failed to recover expired jobs: %v", err)
+			} else if requeued > 0 {
+				log.Printf("Recovered %d expired jobs", requeued)
+			}
+		}
+	}
+}
+
+// recoverJobs 将租约过期的任务放回队列，并把数据库中排队的任务放入内存队列
+// 启动时（all 为 true）放入全部排队中的任务，之后只在有任务过期时放入，返回放入的任务数
+func (s *jobService) recoverJobs(ctx context.Context, all bool) (int, error) {
+	expired, err := s.jobs.RequeueExpired(time.Now().Add(-s.lease))
+	if err != nil {
+		return 0, err
+	}
+	if !all && expired == 0 {
+		return 0, nil
+	}
+	queued, err := s.jobs.ListQueued()
+	if err != nil {
+		return 0, err
+	}
+
+	// 恢复的任务可能超过队列容量，在后台逐个放入队列
+	// 已在队列中的任务会被重复放入，由 Claim 保证只执行一次
+	go func() {
+		for _, job := range queued {
+			select {
+			case s.queue <- job.ID:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return len(queued), nil
+}
+
+// process 执行单个任务
+func (s *jobService) process(id string) {
+	claimed, err := s.jobs.Claim(id, s.owner)
+	if err != nil {
+		log.Printf("Failed to claim job %s: %v", id, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+	text, anonymous := s.takeText(id)
+
+	job, err := s.jobs.GetByID(id)
+	if err != nil {
+		log.Printf("Failed to load job %s: %v", id, err)
+		// 已领取的任务不能停留在执行中状态
+		if err := s.jobs.MarkFailed(id, err.Error()); err != nil {
+			log.Printf("Failed to save job %s: %v", id, err)
+		}
+		return
+	}
+	if !anonymous {
+		text = job.Text
+	}
+
+	result, err := s.execute(job, text)
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Text = ""
+	if err != nil {
+		job.Status = repository.JobStatusFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = repository.JobStatusDone
+		job.ResultID = result.ID
+	}
+	// 只更新结束相关的列，租约已被其他实例接管时不覆盖其执行状态
+	finished, err := s.jobs.Finish(id, s.owner, job.Status, job.ResultID, job.Error)
+	if err != nil {
+		log.Printf("Failed to save job %s: %v", id, err)
+		return
+	}
+	if !finished {
+		log.Printf("Job %s was taken over by another instance after its lease expired", id)
+		return
+	}
+
+	if job.WebhookURL != "" {
+		s.deliver(job, result)
+	}
+}
+
+// execute 执行检测，并在每个检测层完成时保存阶段性结果
+func (s *jobService) execute(job *repository.Job, text string) (result *DetectionResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("detection panicked: %v", r)
+		}
+	}()
+
+	var options DetectionOptions
+	if err := json.Unmarshal([]byte(job.Options), &options); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job options: %w", err)
+	}
+	if options.Anonymous && text == "" {
+		return nil, errAnonymousTextLost
+	}
+
+	var layers []models.LayerResult
+	options.OnLayer = func(layer models.LayerResult) {
+		layers = append(layers, layer)
+		encoded, err := json.Marshal(layers)
+		if err != nil {
+			return
+		}
+		job.Layers = string(encoded)
+		if _, err := s.jobs.UpdateLayers(job.ID, s.owner, job.Layers); err != nil {
+			log.Printf("Failed to save progress of job %s: %v", job.ID, err)
+		}
+	}
+
+	return s.detection.Detect(text, options)
+}
+
+// takeText 取出并删除匿名任务保存在内存中的原文
+func (s *jobService) takeText(id string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	text, ok := s.texts[id]
+	delete(s.texts, id)
+	return text, ok
+}
+
+// deliver 投递任务结束回调并记录投递结果
+func (s *jobService) deliver(job *repository.Job, result *DetectionResult) {
+	status, err := jobToStatus(job, result)
+	if err != nil {
+		log.Printf("Failed to build webhook payload of job %s: %v", job.ID, err)
+		return
+	}
+	status.Webhook = nil
+
+	body, err := json.Marshal(webhookPayload{Event: WebhookEventJobFinished, Job: status})
+	if err != nil {
+		log.Printf("Failed to marshal webhook payload of job %s: %v", job.ID, err)
+		return
+	}
+
+	attempts, err := s.webhook.send(job.WebhookURL, WebhookEventJobFinished, body)
+	job.WebhookAttempts = attempts
+	if err != nil {
+		job.WebhookStatus = repository.WebhookStatusFailed
+		job.WebhookError = err.Error()
+	} else {
+		job.WebhookStatus = repository.WebhookStatusDelivered
+		job.WebhookError = ""
+	}
+	if err := s.jobs.UpdateWebhook(job.ID, s.owner, job.WebhookStatus, job.WebhookAttempts, job.WebhookError); err != nil {
+		log.Printf("Failed to save webhook status of job %s: %v", job.ID, err)
+	}
+}
+
+// jobToStatus 将任务记录转换为任务状态
+func jobToStatus(job *repository.Job, result *DetectionResult) (*JobStatus, error) {
+	layers := []models.LayerResult{}
+	if job.Layers != "" {
+		if err := json.Unmarshal([]byte(job.Layers), &layers); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal job layers: %w", err)
+		}
+	}
+
+	status := &JobStatus{
+		ID:         job.ID,
+		Status:     job.Status,
+		Layers:     layers,
+		ResultID:   job.ResultID,
+		Result:     result,
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+	}
+	if job.WebhookURL != "" {
+		status.Webhook = &WebhookStatus{
+			URL:      job.WebhookURL,
+			Status:   job.WebhookStatus,
+			Attempts: job.WebhookAttempts,
+			Error:    job.WebhookError,
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/models"
+	"github.com/leoobai/aigc-check/internal/repository"
+)
+
+const (
+	// purgeBatchSize 每批清除的记录数
+	purgeBatchSize = 100
+
+	// maxReportIDs 清理报告中列出的记录 ID 上限
+	maxReportIDs = 100
+)
+
+// RetentionService 数据保留服务接口
+type RetentionService interface {
+	Purge(options PurgeOptions) (*PurgeReport, error)
+	Run(ctx context.Context)
+}
+
+// PurgeOptions 清理选项
+type PurgeOptions struct {
+	DryRun bool   // 只生成报告，不修改数据
+	Actor  string // 触发者，记录在审计日志中
+}
+
+// PurgeReport 原文清理报告
+type PurgeReport struct {
+	DryRun     bool      `json:"dry_run"`
+	TextDays   int       `json:"text_days"`
+	Cutoff     time.Time `json:"cutoff"`     // 早于该时间创建的记录超过保留期限
+	Matched    int64     `json:"matched"`    // 超过保留期限、原文尚未清除的记录数
+	Purged     int64     `json:"purged"`     // 实际清除的记录数，dry-run 时为 0
+	RecordIDs  []string  `json:"record_ids"` // 受影响的记录 ID，最多列出 maxReportIDs 条
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// retentionService 数据保留服务实现
+type retentionService struct {
+	config  config.RetentionConfig
+	records repository.DetectionRepository
+	audit   repository.AuditRepository
+}
+
+// NewRetentionService 创建数据保留服务
+func NewRetentionService(cfg config.RetentionConfig, records repository.DetectionRepository, audit repository.AuditRepository) RetentionService {
+	return &retentionService{
+		config:  cfg,
+		records: records,
+		audit:   audit,
+	}
+}
+
+// Purge 清除超过保留期限的原文，只保留分数和元数据，并写入审计日志
+func (s *retentionService) Purge(options PurgeOptions) (*PurgeReport, error) {
+	if s.config.TextDays <= 0 {
+		return nil, fmt.Errorf("text retention is not configured (database.retention.text_days)")
+	}
+
+	startedAt := time.Now()
+	report := &PurgeReport{
+		DryRun:    options.DryRun,
+		TextDays:  s.config.TextDays,
+		Cutoff:    startedAt.AddDate(0, 0, -s.config.TextDays),
+		RecordIDs: []string{},
+		StartedAt: startedAt,
+	}
+
+	matched, err := s.records.CountExpiredText(report.Cutoff)
+	if err != nil {
+		return nil, err
+	}
+	report.Matched = matched
+
+	if options.DryRun {
+		records, err := s.records.FindExpiredText(report.Cutoff, maxReportIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			report.RecordIDs = append(report.RecordIDs, record.ID)
+		}
+	} else {
+		err = s.purgeExpired(report)
+	}
+	report.FinishedAt = time.Now()
+
+	// 部分清除失败时同样记录审计日志，保证已清除的记录可追溯
+	if report.Matched > 0 {
+		if auditErr := s.writeAudit(report, options.Actor); auditErr != nil && err == nil {
+			err = auditErr
+		}
+	}
+	if err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// purgeExpired 分批清除超过保留期限的原文
+func (s *retentionService) purgeExpired(report *PurgeReport) error {
+	for {
+		records, err := s.records.FindExpiredText(report.Cutoff, purgeBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		for _, record := range records {
+			if err := s.purgeRecord(record, report.StartedAt); err != nil {
+				return fmt.Errorf("failed to purge record %s: %w", record.ID, err)
+			}
+			report.Purged++
+			if len(report.RecordIDs) < maxReportIDs {
+				report.RecordIDs = append(report.RecordIDs, record.ID)
+			}
+		}
+	}
+}
+
+// purgeRecord 清除单条记录的原文及含原文片段的字段
+func (s *retentionService) purgeRecord(record *repository.DetectionRecord, purgedAt time.Time) error {
+	result, err := recordToResult(record)
+	if err != nil {
+		return err
+	}
+
+	purged, err := resultToRecord(redactResult(result, purgedAt))
+	if err != nil {
+		return err
+	}
+	return s.records.PurgeText(purged)
+}
+
+// writeAudit 将清理报告写入审计日志
+func (s *retentionService) writeAudit(report *PurgeReport, actor string) error {
+	details, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal purge report: %w", err)
+	}
+
+	action := repository.AuditActionPurgeText
+	if report.DryRun {
+		action = repository.AuditActionPurgeTextDryRun
+	}
+	return s.audit.Create(&repository.AuditLog{
+		Action:    action,
+		Actor:     actor,
+		Details:   string(details),
+		CreatedAt: report.FinishedAt,
+	})
+}
+
+// Run 按配置的间隔在后台定期清理，直到 ctx 被取消；未配置保留期限时直接返回
+func (s *retentionService) Run(ctx context.Context) {
+	if s.config.TextDays <= 0 {
+		return
+	}
+
+	interval := time.Duration(s.config.PurgeInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Duration(config.DefaultDatabaseConfig.Retention.PurgeInterval) * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := s.Purge(PurgeOptions{DryRun: s.config.DryRun, Actor: "scheduler"})
+		if err != nil {
+			log.Printf("Retention purge failed: %v", err)
+		} else if report.Matched > 0 {
+			log.Printf("Retention purge completed (dry_run: %v, matched: %d, purged: %d)", report.DryRun, report.Matched, report.Purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// redactResult 返回只保留分数和元数据的检测结果副本
+// 清除原文、规则匹配项（含原文片段）、改进建议（含原文锚点）以及语义分析中可能引用原文的说明
+func redactResult(result *DetectionResult, purgedAt time.Time) *DetectionResult {
+	redacted := *result
+	redacted.Text = ""
+	redacted.Suggestions = []*models.Suggestion{}
+	redacted.TextPurgedAt = &purgedAt
+
+	redacted.RuleResults = make([]*models.RuleResult, len(result.RuleResults))
+	for i, ruleResult := range result.RuleResults {
+		copied := *ruleResult
+		copied.Matches = []models.Match{}
+		redacted.RuleResults[i] = &copied
+	}
+
+	if result.MultimodalResult != nil && result.MultimodalResult.SemanticLayerDetails != nil {
+		multimodal := *result.MultimodalResult
+		semantic := *multimodal.SemanticLayerDetails
+		semantic.DetectedFeatures = nil
+		semantic.Explanation = ""
+		multimodal.SemanticLayerDetails = &semantic
+		redacted.MultimodalResult = &multimodal
+	}
+
+	return &redacted
+}
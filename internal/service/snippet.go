@@ -0,0 +1,107 @@
+package service
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// snippetContextRunes 命中片段中关键词前后保留的字符数
+const snippetContextRunes = 40
+
+// highlightSnippet 截取文本中第一个命中关键词附近的片段，并用 <mark> 标记所有命中词
+// 片段中的其他内容做 HTML 转义，可以直接渲染；未命中时返回空字符串
+func highlightSnippet(text string, terms []string) string {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// 长词优先，避免短词截断长词的标记
+	var needles [][]rune
+	for _, term := range terms {
+		if needle := []rune(strings.ToLower(term)); len(needle) > 0 {
+			needles = append(needles, needle)
+		}
+	}
+	sort.SliceStable(needles, func(i, j int) bool {
+		return len(needles[i]) > len(needles[j])
+	})
+
+	first := -1
+	for _, needle := range needles {
+		if i := indexRunes(lower, needle, 0); i >= 0 && (first == -1 || i < first) {
+			first = i
+		}
+	}
+	if first == -1 {
+		return ""
+	}
+
+	start := first - snippetContextRunes
+	if start < 0 {
+		start = 0
+	}
+	end := first + snippetContextRunes
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	for i := start; i < end; {
+		matched := 0
+		for _, needle := range needles {
+			if hasRunesAt(lower, needle, i) {
+				matched = len(needle)
+				break
+			}
+		}
+		if matched > 0 {
+			sb.WriteString("<mark>")
+			sb.WriteString(html.EscapeString(string(runes[i : i+matched])))
+			sb.WriteString("</mark>")
+			i += matched
+			continue
+		}
+
+		r := runes[i]
+		if r == '\n' || r == '\r' || r == '\t' {
+			r = ' '
+		}
+		sb.WriteString(html.EscapeString(string(r)))
+		i++
+	}
+	if end < len(runes) {
+		sb.WriteString("…")
+	}
+
+	return sb.String()
+}
+
+// indexRunes 查找子序列首次出现的位置
+func indexRunes(haystack, needle []rune, from int) int {
+	for i := from; i+len(needle) <= len(haystack); i++ {
+		if hasRunesAt(haystack, needle, i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasRunesAt 判断子序列是否出现在指定位置
+func hasRunesAt(haystack, needle []rune, at int) bool {
+	if at+len(needle) > len(haystack) {
+		return false
+	}
+	for j, r := range needle {
+		if haystack[at+j] != r {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// 回调请求头
+const (
+	WebhookSignatureHeader = "X-AIGC-Signature" // sha256=<HMAC-SHA256(timestamp + "." + body) 的十六进制>
+	WebhookTimestampHeader = "X-AIGC-Timestamp" // 签名时的 Unix 时间戳（秒）
+	WebhookEventHeader     = "X-AIGC-Event"
+)
+
+// WebhookEventJobFinished 任务结束（完成或失败）事件
+const WebhookEventJobFinished = "job.finished"
+
+// ErrInvalidWebhookURL 回调地址无效
+var ErrInvalidWebhookURL = errors.New("invalid webhook url")
+
+// ErrWebhookDisabled 未配置签名密钥时不接受回调地址，避免投递无签名的回调
+var ErrWebhookDisabled = errors.New("webhook is disabled: jobs.webhook_secret is not configured")
+
+// maxWebhookRedirects 回调允许跟随的最大重定向次数
+const maxWebhookRedirects = 5
+
+// SignWebhook 计算回调签名，接收方使用相同的密钥、时间戳和请求体校验
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// isBlockedWebhookIP 判断是否为禁止回调的地址：回环、私有网络、链路本地（含云元数据地址 169.254.169.254）、未指定和组播地址
+func isBlockedWebhookIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
+
+// webhookSender 回调发送器，失败时按指数退避重试
+// 默认只允许回调公网地址：提交时解析域名校验，连接时和跟随重定向时再次校验实际地址
+type webhookSender struct {
+	client       *http.Client
+	secret       string
+	allowPrivate bool
+	retries      int
+	backoff      time.Duration // 首次重试前的等待时间
+}
+
+// newWebhookSender 创建回调发送器
+func newWebhookSender(secret string, allowPrivate bool, timeout time.Duration, retries int) *webhookSender {
+	w := &webhookSender{
+		secret:       secret,
+		allowPrivate: allowPrivate,
+		retries:      retries,
+		backoff:      time.Second,
+	}
+
+	dialer := &net.Dialer{Timeout: timeout, Control: w.checkDial}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// 不经过代理，保证连接时校验的是回调目标本身的地址
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	w.client = &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxWebhookRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxWebhookRedirects)
+			}
+			return w.validate(req.Context(), req.URL.String())
+		},
+	}
+	return w
+}
+
+// validate 校验回调地址：只允许 http 和 https，且域名解析出的地址都不在内网
+func (w *webhookSender) validate(ctx context.Context, raw string) error {
+	if w.secret == "" {
+		return ErrWebhookDisabled
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhookURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("%w: must be an absolute http or https url", ErrInvalidWebhookURL)
+	}
+	if w.allowPrivate {
+		return nil
+	}
+
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("%w: failed to resolve host: %v", ErrInvalidWebhookURL, err)
+	}
+	for _, ip := range ips {
+		if isBlockedWebhookIP(ip) {
+			return fmt.Errorf("%w: %s resolves to non-public address %s", ErrInvalidWebhookURL, u.Hostname(), ip)
+		}
+	}
+	return nil
+}
+
+// checkDial 在建立连接前校验实际连接的地址，防止提交后域名被重新解析到内网
+func (w *webhookSender) checkDial(network, address string, _ syscall.RawConn) error {
+	if w.allowPrivate {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || isBlockedWebhookIP(ip) {
+		return fmt.Errorf("%w: refusing to connect to non-public address %s", ErrInvalidWebhookURL, host)
+	}
+	return nil
+}
+
+// send 投递回调，返回尝试次数和最后一次失败的原因
+func (w *webhookSender) send(target, event string, body []byte) (int, error) {
+	var lastErr error
+	backoff := w.backoff
+	for attempt := 1; attempt <= w.retries+1; attempt++ {
+		if attempt > 1 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		lastErr = w.post(target, event, body)
+		if lastErr == nil {
+			return attempt, nil
+		}
+	}
+	return w.retries + 1, lastErr
+}
+
+// post 发送一次回调请求，非 2xx 响应视为失败
+func (w *webhookSender) post(target, event string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	// 密钥在提交后被移除时不再投递无签名的回调
+	if w.secret == "" {
+		return ErrWebhookDisabled
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, event)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(w.secret, timestamp, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package text
 import (
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/leoobai/aigc-check/internal/models"
 )
@@ -84,14 +85,16 @@ func (p *TextProcessor) normalize(text string) string {
 func (p *TextProcessor) splitSentences(text string) []Sentence {
 	var sentences []Sentence
 	var currentSentence strings.Builder
-	var startOffset int
+	var startOffset, startByte int
 	var startLine, startColumn int = 1, 1
 
 	runes := []rune(text)
 	line, column := 1, 1
+	byteOffset := 0
 
 	for i, r := range runes {
 		currentSentence.WriteRune(r)
+		byteOffset += utf8.RuneLen(r)
 
 		// 更新位置
 		if r == '\n' {
@@ -113,7 +116,7 @@ func (p *TextProcessor) splitSentences(text string) []Sentence {
 					Position: models.Position{
 						Line:   startLine,
 						Column: startColumn,
-						Offset: startOffset,
+						Offset: startByte,
 						Length: len(sentenceText),
 					},
 					Words: words,
@@ -123,6 +126,7 @@ func (p *TextProcessor) splitSentences(text string) []Sentence {
 			// 重置
 			currentSentence.Reset()
 			startOffset = i + 1
+			startByte = byteOffset
 			startLine = line
 			startColumn = column
 		}
@@ -138,7 +142,7 @@ func (p *TextProcessor) splitSentences(text string) []Sentence {
 				Position: models.Position{
 					Line:   startLine,
 					Column: startColumn,
-					Offset: startOffset,
+					Offset: startByte,
 					Length: len(sentenceText),
 				},
 				Words: words,
@@ -205,7 +209,7 @@ func (p *TextProcessor) extractWordsFromRange(text string, start, end int) []Tok
 			} else {
 				column++
 			}
-			offset++
+			offset += utf8.RuneLen(r)
 			continue
 		}
 		if i >= end {
@@ -245,7 +249,7 @@ func (p *TextProcessor) extractWordsFromRange(text string, start, end int) []Tok
 		} else {
 			column++
 		}
-		offset++
+		offset += utf8.RuneLen(r)
 	}
 
 	// 处理最后一个词汇
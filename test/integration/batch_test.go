@@ -0,0 +1,167 @@
+package integration
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoobai/aigc-check/internal/api/handlers"
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/database"
+	"github.com/leoobai/aigc-check/internal/repository"
+	"github.com/leoobai/aigc-check/internal/service"
+)
+
+// countingDetection 记录最大并发数的检测服务
+type countingDetection struct {
+	service.DetectionService
+	running, peak int32
+}
+
+func (d *countingDetection) Detect(text string, options service.DetectionOptions) (*service.DetectionResult, error) {
+	n := atomic.AddInt32(&d.running, 1)
+	defer atomic.AddInt32(&d.running, -1)
+	for {
+		peak := atomic.LoadInt32(&d.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&d.peak, peak, n) {
+			break
+		}
+	}
+	return d.DetectionService.Detect(text, options)
+}
+
+func newBatchItems(n int) []service.BatchItem {
+	items := make([]service.BatchItem, n)
+	for i := range items {
+		items[i] = service.BatchItem{
+			ID:       fmt.Sprintf("sku-%d", i),
+			Text:     fmt.Sprintf("Product %d is a crucial addition to your kitchen. Additionally, it is durable.", i),
+			Metadata: map[string]string{"row": fmt.Sprint(i)},
+		}
+	}
+	// 空文本只影响该条目
+	items[3].Text = "   "
+	return items
+}
+
+func TestBatch_Detect(t *testing.T) {
+	cfg := config.DefaultConfig
+	db := openTestDB(t, database.TypeSQLite)
+	detection := &countingDetection{DetectionService: service.NewDetectionService(&cfg, repository.NewDetectionRepository(db))}
+	batchService := service.NewBatchService(config.BatchConfig{Concurrency: 3}, detection)
+
+	items := newBatchItems(20)
+	results := make(map[int]*service.BatchItemResult)
+	summary := batchService.Detect(context.Background(), items, service.DetectionOptions{}, func(result *service.BatchItemResult) {
+		results[result.Index] = result
+	})
+
+	if summary.Total != 20 || summary.Succeeded != 19 || summary.Failed != 1 {
+		t.Errorf("summary = %+v, want 19 succeeded and 1 failed", summary)
+	}
+	if len(results) != 20 {
+		t.Fatalf("got %d results, want 20", len(results))
+	}
+	if results[3].Error == "" || results[3].Result != nil {
+		t.Errorf("results[3] = %+v, want per-item error", results[3])
+	}
+	for i, result := range results {
+		if i == 3 {
+			continue
+		}
+		if result.Error != "" || result.Result == nil || result.ID != items[i].ID || result.Metadata["row"] != fmt.Sprint(i) {
+			t.Errorf("results[%d] = %+v, want result for %s", i, result, items[i].ID)
+		}
+	}
+	if peak := atomic.LoadInt32(&detection.peak); peak > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", peak)
+	}
+}
+
+func TestBatch_Handler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.DefaultConfig
+	db := openTestDB(t, database.TypeSQLite)
+	detectionService := service.NewDetectionService(&cfg, repository.NewDetectionRepository(db))
+	handler := handlers.NewBatchHandler(service.NewBatchService(config.BatchConfig{Concurrency: 4}, detectionService), 10)
+
+	router := gin.New()
+	router.POST("/api/v1/detect/batch", handler.Detect)
+
+	post := func(body interface{}, accept string) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/detect/batch", strings.NewReader(string(data)))
+		req.Header.Set("Content-Type", "application/json")
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// 超过条目上限
+	if w := post(map[string]interface{}{"items": newBatchItems(11)}, ""); w.Code != http.StatusBadRequest {
+		t.Errorf("oversized batch status = %d, want 400", w.Code)
+	}
+
+	// JSON 输出按请求顺序排列
+	w := post(map[string]interface{}{"items": newBatchItems(5)}, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data handlers.BatchDetectResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if response.Data.Summary.Succeeded != 4 || response.Data.Summary.Failed != 1 {
+		t.Errorf("summary = %+v, want 4 succeeded and 1 failed", response.Data.Summary)
+	}
+	for i, item := range response.Data.Items {
+		if item.Index != i {
+			t.Errorf("Items[%d].Index = %d, want request order", i, item.Index)
+		}
+	}
+
+	// NDJSON 输出每行一个条目，最后一行为汇总
+	w = post(map[string]interface{}{"items": newBatchItems(5)}, "application/x-ndjson")
+	if got := w.Header().Get("Content-Type"); got != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q, want application/x-ndjson", got)
+	}
+	var lines []string
+	scanner := bufio.NewScanner(w.Body)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 6 {
+		t.Fatalf("got %d NDJSON lines, want 5 items and a summary", len(lines))
+	}
+	seen := make(map[int]bool)
+	for _, line := range lines[:5] {
+		var item service.BatchItemResult
+		if err := json.Unmarshal([]byte(line), &item); err != nil {
+			t.Fatalf("decode line %q: %v", line, err)
+		}
+		seen[item.Index] = true
+	}
+	if len(seen) != 5 {
+		t.Errorf("NDJSON items cover indexes %v, want 0-4", seen)
+	}
+	var last struct {
+		Summary service.BatchSummary `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(lines[5]), &last); err != nil || last.Summary.Total != 5 {
+		t.Errorf("last line = %s, want summary of 5 items", lines[5])
+	}
+}
@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/database"
+	"github.com/leoobai/aigc-check/internal/repository"
+	"github.com/leoobai/aigc-check/internal/service"
+)
+
+func TestDocument_Revisions(t *testing.T) {
+	drafts := []string{
+		"Additionally, it is crucial to understand the pivotal role of AI.\n\nWe delve into the groundbreaking advancements — and they are profound.\n\nI hope this helps! Let me know if you have any questions.\n",
+		"Additionally, it is crucial to understand the pivotal role of AI.\n\nWe delve into the groundbreaking advancements — and they are profound.\n\nHonestly, I was surprised by how much I learned.\n",
+		"AI plays a real part in my work.\n\nWe delve into the groundbreaking advancements — and they are profound.\n\nHonestly, I was surprised by how much I learned.\n",
+	}
+
+	cfg := config.DefaultConfig
+	db := openTestDB(t, database.TypeSQLite)
+	records := repository.NewDetectionRepository(db)
+	detectionService := service.NewDetectionService(&cfg, records)
+	documentService := service.NewDocumentService(detectionService, repository.NewDocumentRepository(db), records)
+
+	document, err := documentService.Create("季度报告")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if _, err := documentService.Compare(document.ID, 0, 0); err == nil {
+		t.Error("Compare() without revisions should fail")
+	}
+
+	var results []*service.DetectionResult
+	for i, draft := range drafts {
+		revision, err := documentService.AddRevision(document.ID, draft, service.DetectionOptions{})
+		if err != nil {
+			t.Fatalf("AddRevision() error = %v", err)
+		}
+		if revision.Revision != i+1 {
+			t.Errorf("AddRevision() revision = %d, want %d", revision.Revision, i+1)
+		}
+		results = append(results, revision.Result)
+	}
+
+	if _, err := documentService.AddRevision("missing", drafts[0], service.DetectionOptions{}); err == nil {
+		t.Error("AddRevision() on unknown document should fail")
+	}
+
+	got, err := documentService.Get(document.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(got.Revisions) != len(drafts) {
+		t.Fatalf("Get() returned %d revisions, want %d", len(got.Revisions), len(drafts))
+	}
+	for i, revision := range got.Revisions {
+		if revision.RecordID != results[i].ID || revision.Score != results[i].Score.Total {
+			t.Errorf("revision %d = %+v, want record %s with score %v", i+1, revision, results[i].ID, results[i].Score.Total)
+		}
+		if i > 0 && revision.ScoreDelta != results[i].Score.Total-results[i-1].Score.Total {
+			t.Errorf("revision %d ScoreDelta = %v, want %v", i+1, revision.ScoreDelta, results[i].Score.Total-results[i-1].Score.Total)
+		}
+	}
+
+	// 默认比较最新版本与上一个版本
+	latest, err := documentService.Compare(document.ID, 0, 0)
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	if latest.FromRevision != 2 || latest.ToRevision != 3 {
+		t.Errorf("Compare() revisions = %d -> %d, want 2 -> 3", latest.FromRevision, latest.ToRevision)
+	}
+
+	comparison, err := documentService.Compare(document.ID, 1, 2)
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	if comparison.ScoreDelta != results[1].Score.Total-results[0].Score.Total {
+		t.Errorf("ScoreDelta = %v, want %v", comparison.ScoreDelta, results[1].Score.Total-results[0].Score.Total)
+	}
+	if len(comparison.Edits) != 1 || comparison.Edits[0].OldLine != 5 {
+		t.Fatalf("Edits = %+v, want a single edit on line 5", comparison.Edits)
+	}
+	if !comparison.Edits[0].MovedScore() {
+		t.Error("removing the collaborative closing should be marked as moving the score")
+	}
+	for _, resolved := range comparison.Resolved {
+		if resolved.Position.Line != 5 {
+			t.Errorf("match %q on unchanged line %d reported as resolved", resolved.Text, resolved.Position.Line)
+		}
+	}
+
+	if _, err := documentService.Compare(document.ID, 1, 9); err == nil {
+		t.Error("Compare() with unknown revision should fail")
+	}
+}
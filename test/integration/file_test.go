@@ -0,0 +1,248 @@
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoobai/aigc-check/internal/api/handlers"
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/database"
+	"github.com/leoobai/aigc-check/internal/extract"
+	"github.com/leoobai/aigc-check/internal/models"
+	"github.com/leoobai/aigc-check/internal/repository"
+	"github.com/leoobai/aigc-check/internal/service"
+)
+
+// minimalDOCX 构造只包含正文的 docx 文件
+func minimalDOCX(t *testing.T, paragraphs ...string) []byte {
+	t.Helper()
+	var body strings.Builder
+	for _, p := range paragraphs {
+		body.WriteString(`<w:p><w:r><w:t>` + p + `</w:t></w:r></w:p>`)
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("word/document.xml")
+	if err != nil {
+		t.Fatalf("create zip entry: %v", err)
+	}
+	w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>` +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		body.String() + `</w:body></w:document>`))
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestFile_Detect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.DefaultConfig
+	db := openTestDB(t, database.TypeSQLite)
+	detectionService := service.NewDetectionService(&cfg, repository.NewDetectionRepository(db))
+	handler := handlers.NewFileHandler(detectionService, config.UploadConfig{
+		MaxSizeMB:    1,
+		AllowedTypes: []string{"txt", "md", "html", "docx"},
+	})
+
+	router := gin.New()
+	router.POST("/api/v1/detect/file", handler.Detect)
+
+	post := func(filename string, data []byte, fields map[string]string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		part, _ := mw.CreateFormFile("file", filename)
+		part.Write(data)
+		for key, value := range fields {
+			mw.WriteField(key, value)
+		}
+		mw.Close()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/detect/file", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name       string
+		filename   string
+		data       []byte
+		wantFormat string
+		wantText   string
+	}{
+		{
+			name:       "text",
+			filename:   "report.txt",
+			data:       []byte("第一段内容。\r\n\r\nAdditionally, it is crucial to delve into the details."),
+			wantFormat: "txt",
+			wantText:   "第一段内容。\n\nAdditionally, it is crucial to delve into the details.",
+		},
+		{
+			name:       "html",
+			filename:   "page.html",
+			data:       []byte(`<html><head><script>var x = 1;</script></head><body><h1>标题</h1><p>Additionally, it is crucial &amp; important.</p></body></html>`),
+			wantFormat: "html",
+			wantText:   "标题\n\nAdditionally, it is crucial & important.",
+		},
+		{
+			name:       "docx",
+			filename:   "paper.docx",
+			data:       minimalDOCX(t, "第一段。", "Additionally, it is crucial to delve into the details."),
+			wantFormat: "docx",
+			wantText:   "第一段。\n\nAdditionally, it is crucial to delve into the details.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := post(tt.filename, tt.data, map[string]string{"tags": "upload,thesis"})
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			var response struct {
+				Data struct {
+					File       handlers.FileInfo       `json:"file"`
+					Paragraphs []extract.Paragraph     `json:"paragraphs"`
+					Result     service.DetectionResult `json:"result"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			data := response.Data
+			if data.File.Name != tt.filename || data.File.Format != tt.wantFormat || data.File.Size != int64(len(tt.data)) {
+				t.Errorf("File = %+v, want %s (%s, %d bytes)", data.File, tt.filename, tt.wantFormat, len(tt.data))
+			}
+			if data.Result.Text != tt.wantText {
+				t.Errorf("Result.Text = %q, want %q", data.Result.Text, tt.wantText)
+			}
+			if len(data.Result.Tags) != 2 {
+				t.Errorf("Result.Tags = %v, want tags from form", data.Result.Tags)
+			}
+
+			// 段落映射和规则匹配的偏移量都指向提取出的文本
+			if len(data.Paragraphs) != 2 {
+				t.Fatalf("got %d paragraphs, want 2", len(data.Paragraphs))
+			}
+			for _, p := range data.Paragraphs {
+				if p.Offset+p.Length > len(data.Result.Text) {
+					t.Errorf("paragraph %+v out of range", p)
+				}
+			}
+			for _, rule := range data.Result.RuleResults {
+				for _, match := range rule.Matches {
+					pos := match.Position
+					if pos.Offset+pos.Length > len(data.Result.Text) ||
+						!strings.HasPrefix(strings.ToLower(data.Result.Text[pos.Offset:]), strings.ToLower(match.Text)) {
+						t.Errorf("%s match %q at %+v does not index into extracted text", rule.RuleType, match.Text, pos)
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("docx comments and revisions", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for name, content := range map[string]string{
+			"word/document.xml": `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+				`<w:p><w:r><w:t>第一段。</w:t></w:r></w:p>` +
+				`<w:p><w:ins w:author="张三"><w:r><w:t>Additionally, it is crucial to delve into the details.</w:t></w:r></w:ins></w:p>` +
+				`</w:body></w:document>`,
+			"word/comments.xml": `<w:comments xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+				`<w:comment w:id="0" w:author="李四"><w:p><w:r><w:t>请核实。</w:t></w:r></w:p></w:comment></w:comments>`,
+		} {
+			w, _ := zw.Create(name)
+			w.Write([]byte(content))
+		}
+		zw.Close()
+
+		w := post("paper.docx", buf.Bytes(), map[string]string{"comments": "true"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data struct {
+				Paragraphs      []extract.Paragraph     `json:"paragraphs"`
+				RevisionAuthors []models.RevisionAuthor `json:"revision_authors"`
+				Result          service.DetectionResult `json:"result"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		data := response.Data
+		if n := len(data.Paragraphs); n != 3 || data.Paragraphs[2].Kind != extract.KindComment || data.Paragraphs[2].Author != "李四" {
+			t.Errorf("Paragraphs = %+v, want body and one comment", data.Paragraphs)
+		}
+		if len(data.RevisionAuthors) != 1 || data.RevisionAuthors[0].Author != "张三" || data.RevisionAuthors[0].Insertions != 1 {
+			t.Errorf("RevisionAuthors = %+v, want one insertion by 张三", data.RevisionAuthors)
+		}
+		located := false
+		for _, rule := range data.Result.RuleResults {
+			for _, match := range rule.Matches {
+				located = located || match.Location == "第 2 段"
+			}
+		}
+		if !located {
+			t.Errorf("no match located in paragraph 2: %s", w.Body.String())
+		}
+
+		if w := post("paper.docx", buf.Bytes(), map[string]string{"footnotes": "maybe"}); w.Code != http.StatusBadRequest {
+			t.Errorf("invalid footnotes status = %d, want 400", w.Code)
+		}
+	})
+
+	t.Run("format override", func(t *testing.T) {
+		w := post("notes", []byte("# 标题\n\n正文内容。"), map[string]string{"format": "md"})
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"format":"md"`) {
+			t.Errorf("status = %d, body %s, want markdown format", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("type not allowed", func(t *testing.T) {
+		w := post("paper.pdf", []byte("%PDF-1.4\n%%EOF"), nil)
+		if w.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("status = %d, want 415", w.Code)
+		}
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		w := post("image.bin", []byte{0xff, 0xfe, 0x00, 0x01, 0x80, 0x81}, nil)
+		if w.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("status = %d, want 415", w.Code)
+		}
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		w := post("big.txt", bytes.Repeat([]byte("a"), 2<<20), nil)
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("status = %d, want 413", w.Code)
+		}
+	})
+
+	t.Run("no text", func(t *testing.T) {
+		w := post("empty.html", []byte("<html><body><script>x()</script></body></html>"), nil)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("status = %d, want 400", w.Code)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/detect/file", strings.NewReader(""))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("status = %d, want 400", w.Code)
+		}
+	})
+}
@@ -0,0 +1,209 @@
+package integration
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/database"
+	"github.com/leoobai/aigc-check/internal/models"
+	"github.com/leoobai/aigc-check/internal/reporter"
+	"github.com/leoobai/aigc-check/internal/repository"
+	"github.com/leoobai/aigc-check/internal/service"
+)
+
+func TestHistory_RoundTrip(t *testing.T) {
+	text := `Additionally, it is crucial to understand the pivotal role of AI. Furthermore, the groundbreaking advancements are profound.
+
+## Key Points
+
+**Efficiency**: AI processes data quickly — and accurately — at scale.
+
+I hope this helps! Let me know if you have any questions.`
+
+	for _, multimodal := range []bool{false, true} {
+		name := "single"
+		if multimodal {
+			name = "multimodal"
+		}
+		t.Run(name, func(t *testing.T) {
+			cfg := config.DefaultConfig
+			cfg.Multimodal.Enabled = multimodal
+
+			repo := repository.NewDetectionRepository(openTestDB(t, database.TypeSQLite))
+			detectionService := service.NewDetectionService(&cfg, repo)
+			historyService := service.NewHistoryService(repo)
+
+			original, err := detectionService.Detect(text, service.DetectionOptions{})
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if original.AnalyzerVersion == "" || original.ConfigFingerprint != cfg.Fingerprint() {
+				t.Errorf("AnalyzerVersion/ConfigFingerprint = %q/%q, want version and config fingerprint",
+					original.AnalyzerVersion, original.ConfigFingerprint)
+			}
+			if multimodal && original.MultimodalResult == nil {
+				t.Fatal("MultimodalResult = nil, want multimodal details")
+			}
+
+			fromResult, err := detectionService.GetResult(original.ID)
+			if err != nil {
+				t.Fatalf("GetResult() error = %v", err)
+			}
+			fromHistory, err := historyService.GetByID(original.ID)
+			if err != nil {
+				t.Fatalf("GetByID() error = %v", err)
+			}
+
+			for label, got := range map[string]*service.DetectionResult{"GetResult": fromResult, "GetByID": fromHistory} {
+				if !reflect.DeepEqual(got.Score, original.Score) {
+					t.Errorf("%s Score = %+v, want %+v", label, got.Score, original.Score)
+				}
+				if !reflect.DeepEqual(got.MultimodalResult, original.MultimodalResult) {
+					t.Errorf("%s MultimodalResult differs from original", label)
+				}
+				if got.RiskDescription != original.RiskDescription {
+					t.Errorf("%s RiskDescription = %q, want %q", label, got.RiskDescription, original.RiskDescription)
+				}
+
+				// 历史报告必须与首次检测的报告完全一致
+				reporters := []reporter.Reporter{reporter.NewJSONReporter(true), reporter.NewTextReporter(false)}
+				for _, rep := range reporters {
+					want, err := rep.Generate(original.ToModel())
+					if err != nil {
+						t.Fatalf("Generate() error = %v", err)
+					}
+					gotReport, err := rep.Generate(got.ToModel())
+					if err != nil {
+						t.Fatalf("Generate() error = %v", err)
+					}
+					if gotReport != want {
+						t.Errorf("%s %s report differs from original:\n got: %s\nwant: %s", label, rep.Format(), gotReport, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDetection_ReuseDuplicates(t *testing.T) {
+	text := "Additionally, it is crucial to understand the pivotal role of AI."
+
+	cfg := config.DefaultConfig
+	repo := repository.NewDetectionRepository(openTestDB(t, database.TypeSQLite))
+	detectionService := service.NewDetectionService(&cfg, repo)
+	historyService := service.NewHistoryService(repo)
+
+	first, err := detectionService.Detect(text, service.DetectionOptions{Tags: []string{"draft"}})
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if first.Cached || first.DuplicateOf != "" || first.SubmitCount != 1 {
+		t.Errorf("first submission Cached/DuplicateOf/SubmitCount = %v/%q/%d, want false/\"\"/1",
+			first.Cached, first.DuplicateOf, first.SubmitCount)
+	}
+
+	// 仅换行符和首尾空白不同的文本视为同一内容
+	second, err := detectionService.Detect("\r\n"+text+"\r\n", service.DetectionOptions{Tags: []string{"final"}})
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if !second.Cached || second.ID != first.ID {
+		t.Fatalf("resubmission Cached/ID = %v/%q, want cached result %q", second.Cached, second.ID, first.ID)
+	}
+	if second.SubmitCount != 2 {
+		t.Errorf("resubmission SubmitCount = %d, want 2", second.SubmitCount)
+	}
+	if !reflect.DeepEqual(second.Tags, []string{"draft", "final"}) {
+		t.Errorf("resubmission Tags = %v, want [draft final]", second.Tags)
+	}
+	if !reflect.DeepEqual(second.Score, first.Score) {
+		t.Errorf("resubmission Score = %+v, want %+v", second.Score, first.Score)
+	}
+
+	forced, err := detectionService.Detect(text, service.DetectionOptions{Force: true})
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if forced.Cached || forced.ID == first.ID || forced.DuplicateOf != first.ID {
+		t.Errorf("forced Cached/ID/DuplicateOf = %v/%q/%q, want new record linked to %q",
+			forced.Cached, forced.ID, forced.DuplicateOf, first.ID)
+	}
+
+	// 配置变化后重新检测，并关联到同一文档
+	changedCfg := config.DefaultConfig
+	changedCfg.Scoring.Weights.VocabularyDiversity += 1
+	changed, err := service.NewDetectionService(&changedCfg, repo).Detect(text, service.DetectionOptions{})
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if changed.Cached || changed.DuplicateOf != first.ID {
+		t.Errorf("changed config Cached/DuplicateOf = %v/%q, want fresh result linked to %q",
+			changed.Cached, changed.DuplicateOf, first.ID)
+	}
+
+	grouped, err := historyService.List(1, 20, "created_at", "desc", service.HistoryFilter{OriginalsOnly: true})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if grouped.Total != 1 || grouped.Items[0].ID != first.ID {
+		t.Fatalf("grouped List() = %d items, want only the original record", grouped.Total)
+	}
+	if grouped.Items[0].Duplicates != 2 || grouped.Items[0].SubmitCount != 2 {
+		t.Errorf("grouped item Duplicates/SubmitCount = %d/%d, want 2/2",
+			grouped.Items[0].Duplicates, grouped.Items[0].SubmitCount)
+	}
+
+	members, err := historyService.List(1, 20, "created_at", "asc", service.HistoryFilter{DuplicateOf: first.ID})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if members.Total != 2 || members.Items[0].ID != forced.ID || members.Items[1].ID != changed.ID {
+		t.Errorf("duplicate_of List() = %+v, want forced and changed-config records", members.Items)
+	}
+}
+
+func TestDetection_ReuseMatchesOptions(t *testing.T) {
+	text := "Additionally, it is crucial to understand the pivotal role of AI."
+
+	cfg := config.DefaultConfig
+	detectionService := service.NewDetectionService(&cfg, repository.NewDetectionRepository(openTestDB(t, database.TypeSQLite)))
+
+	plain, err := detectionService.Detect(text, service.DetectionOptions{})
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if plain.InputFormat != models.InputFormatText {
+		t.Errorf("InputFormat = %q, want %q", plain.InputFormat, models.InputFormatText)
+	}
+
+	tests := []struct {
+		name    string
+		options service.DetectionOptions
+		cached  bool
+	}{
+		{"显式指定纯文本格式", service.DetectionOptions{InputFormat: models.InputFormatText}, true},
+		{"Markdown 格式", service.DetectionOptions{InputFormat: models.InputFormatMarkdown}, false},
+		{"不同语言", service.DetectionOptions{Language: "en"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := detectionService.Detect(text, tt.options)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if result.Cached != tt.cached || (result.ID == plain.ID) != tt.cached {
+				t.Errorf("Cached/ID = %v/%q, want cached %v (plain result %q)", result.Cached, result.ID, tt.cached, plain.ID)
+			}
+		})
+	}
+
+	// 以 Markdown 格式检测过的文本，再以同样格式提交时复用该结果
+	markdown, err := detectionService.Detect(text, service.DetectionOptions{InputFormat: models.InputFormatMarkdown})
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if !markdown.Cached || markdown.InputFormat != models.InputFormatMarkdown {
+		t.Errorf("markdown resubmission Cached/InputFormat = %v/%q, want cached markdown result", markdown.Cached, markdown.InputFormat)
+	}
+}
@@ -0,0 +1,409 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoobai/aigc-check/internal/api/handlers"
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/database"
+	"github.com/leoobai/aigc-check/internal/models"
+	"github.com/leoobai/aigc-check/internal/repository"
+	"github.com/leoobai/aigc-check/internal/service"
+)
+
+// waitForJob 轮询任务直到结束
+func waitForJob(t *testing.T, jobService service.JobService, id string) *service.JobStatus {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		status, err := jobService.Get(id)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if status.Status == repository.JobStatusDone || status.Status == repository.JobStatusFailed {
+			return status
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return nil
+}
+
+// runJobs 在后台运行任务服务，测试结束时停止
+func runJobs(t *testing.T, jobService service.JobService) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- jobService.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	})
+}
+
+func TestJobs_SubmitAndWebhook(t *testing.T) {
+	text := "Additionally, it is crucial to understand the pivotal role of AI. I hope this helps!"
+
+	var (
+		mu       sync.Mutex
+		payloads [][]byte
+		calls    int
+	)
+	secret := "test-secret"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(service.WebhookTimestampHeader), 10, 64)
+		if r.Header.Get(service.WebhookSignatureHeader) != service.SignWebhook(secret, timestamp, body) {
+			t.Errorf("webhook signature mismatch")
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		// 第一次回调失败，验证重试
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		payloads = append(payloads, body)
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig
+	cfg.Multimodal.Enabled = true
+	db := openTestDB(t, database.TypeSQLite)
+	detectionService := service.NewDetectionService(&cfg, repository.NewDetectionRepository(db))
+	jobs := repository.NewJobRepository(db)
+	jobService := service.NewJobService(config.JobsConfig{
+		Workers:        2,
+		QueueSize:      10,
+		WebhookSecret:  secret,
+		WebhookTimeout: 5,
+		WebhookRetries: 1,
+		// 测试服务器监听在回环地址上
+		WebhookAllowPrivate: true,
+	}, detectionService, jobs)
+
+	if _, err := jobService.Submit(text, service.DetectionOptions{}, "ftp://example.com"); err == nil {
+		t.Error("Submit() with ftp webhook error = nil, want error")
+	}
+
+	submitted, err := jobService.Submit(text, service.DetectionOptions{Tags: []string{"async"}}, server.URL)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if submitted.Status != repository.JobStatusQueued {
+		t.Errorf("Submit() status = %s, want queued", submitted.Status)
+	}
+
+	runJobs(t, jobService)
+	status := waitForJob(t, jobService, submitted.ID)
+
+	if status.Status != repository.JobStatusDone || status.Result == nil || status.Result.ID != status.ResultID {
+		t.Fatalf("job = %+v, want done with result", status)
+	}
+	// 阶段性结果与最终结果中实际执行的检测层一致
+	wantLayers := []string{models.LayerRule}
+	if status.Result.MultimodalResult.StatisticsLayerDetails != nil {
+		wantLayers = append(wantLayers, models.LayerStatistics)
+	}
+	if len(status.Layers) != len(wantLayers) {
+		t.Fatalf("Layers = %+v, want %v", status.Layers, wantLayers)
+	}
+	for i, layer := range status.Layers {
+		if layer.Layer != wantLayers[i] {
+			t.Errorf("Layers[%d] = %s, want %s", i, layer.Layer, wantLayers[i])
+		}
+	}
+	if status.Layers[0].Score != status.Result.MultimodalResult.RuleLayerScore {
+		t.Errorf("rule layer score = %v, want %v", status.Layers[0].Score, status.Result.MultimodalResult.RuleLayerScore)
+	}
+	if status.Result.Tags[0] != "async" {
+		t.Errorf("Result.Tags = %v, want [async]", status.Result.Tags)
+	}
+
+	// 回调在任务状态保存之后投递，等待投递结果落库
+	deadline := time.Now().Add(10 * time.Second)
+	for status.Webhook.Status == repository.WebhookStatusPending && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		status, _ = jobService.Get(submitted.ID)
+	}
+	if status.Webhook.Status != repository.WebhookStatusDelivered || status.Webhook.Attempts != 2 {
+		t.Errorf("Webhook = %+v, want delivered after 2 attempts", status.Webhook)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(payloads) != 1 {
+		t.Fatalf("received %d webhook payloads, want 1", len(payloads))
+	}
+	var payload struct {
+		Event string            `json:"event"`
+		Job   service.JobStatus `json:"job"`
+	}
+	if err := json.Unmarshal(payloads[0], &payload); err != nil {
+		t.Fatalf("webhook payload: %v", err)
+	}
+	if payload.Event != service.WebhookEventJobFinished || payload.Job.ID != submitted.ID ||
+		payload.Job.Result == nil || payload.Job.Result.Score.Total != status.Result.Score.Total {
+		t.Errorf("webhook payload = %+v, want finished job with result", payload)
+	}
+
+	// 任务结束后不再保留原文副本
+	job, err := jobs.GetByID(submitted.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if job.Text != "" {
+		t.Error("finished job still stores the submitted text")
+	}
+}
+
+func TestJobs_WebhookTargets(t *testing.T) {
+	cfg := config.DefaultConfig
+	db := openTestDB(t, database.TypeSQLite)
+	detectionService := service.NewDetectionService(&cfg, repository.NewDetectionRepository(db))
+	jobs := repository.NewJobRepository(db)
+
+	unsigned := service.NewJobService(config.JobsConfig{Workers: 1, QueueSize: 10}, detectionService, jobs)
+	if _, err := unsigned.Submit("Some text.", service.DetectionOptions{}, "https://example.com/hook"); !errors.Is(err, service.ErrWebhookDisabled) {
+		t.Errorf("Submit() without webhook secret error = %v, want ErrWebhookDisabled", err)
+	}
+
+	jobService := service.NewJobService(config.JobsConfig{Workers: 1, QueueSize: 10, WebhookSecret: "test-secret"}, detectionService, jobs)
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"不支持的协议", "ftp://example.com"},
+		{"回环地址", "http://127.0.0.1:8080/hook"},
+		{"解析到回环地址的域名", "http://localhost/hook"},
+		{"IPv6 回环地址", "http://[::1]/hook"},
+		{"私有网络地址", "https://10.0.0.5/hook"},
+		{"云服务器元数据地址", "http://169.254.169.254/latest/meta-data/"},
+		{"未指定地址", "http://0.0.0.0/hook"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := jobService.Submit("Some text.", service.DetectionOptions{}, tt.url); !errors.Is(err, service.ErrInvalidWebhookURL) {
+				t.Errorf("Submit(%q) error = %v, want ErrInvalidWebhookURL", tt.url, err)
+			}
+		})
+	}
+}
+
+func TestJobs_RecoverAfterRestart(t *testing.T) {
+	cfg := config.DefaultConfig
+	db := openTestDB(t, database.TypeSQLite)
+	detectionService := service.NewDetectionService(&cfg, repository.NewDetectionRepository(db))
+	jobs := repository.NewJobRepository(db)
+
+	// 模拟上次运行时排队中和执行到一半被中断的任务
+	started := time.Now()
+	for _, job := range []*repository.Job{
+		{ID: "queued-job", Status: repository.JobStatusQueued, Text: "Queued text.", Options: "{}", Layers: "[]"},
+		{ID: "running-job", Status: repository.JobStatusRunning, Text: "Running text.", Options: "{}",
+			Layers: `[{"layer":"rule","score":50}]`, StartedAt: &started},
+	} {
+		if err := jobs.Create(job); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	jobService := service.NewJobService(config.JobsConfig{Workers: 1, QueueSize: 1}, detectionService, jobs)
+	runJobs(t, jobService)
+
+	for _, id := range []string{"queued-job", "running-job"} {
+		status := waitForJob(t, jobService, id)
+		if status.Status != repository.JobStatusDone || status.Result == nil {
+			t.Errorf("job %s = %+v, want done with result", id, status)
+		}
+		if len(status.Layers) != 1 || status.Layers[0].Layer != models.LayerRule {
+			t.Errorf("job %s Layers = %+v, want a single rule layer", id, status.Layers)
+		}
+	}
+}
+
+func TestJobs_Lease(t *testing.T) {
+	cfg := config.DefaultConfig
+	db := openTestDB(t, database.TypeSQLite)
+	detectionService := service.NewDetectionService(&cfg, repository.NewDetectionRepository(db))
+	jobs := repository.NewJobRepository(db)
+
+	// 其他实例仍在续租的任务、租约已过期的任务，以及已停止实例留下的匿名任务
+	now := time.Now()
+	stale := now.Add(-time.Hour)
+	for _, job := range []*repository.Job{
+		{ID: "leased-job", Status: repository.JobStatusRunning, Text: "Leased text.", Options: "{}", Layers: "[]",
+			Owner: "other-instance", StartedAt: &now, HeartbeatAt: &now},
+		{ID: "expired-job", Status: repository.JobStatusRunning, Text: "Expired text.", Options: "{}", Layers: "[]",
+			Owner: "stopped-instance", StartedAt: &stale, HeartbeatAt: &stale},
+		{ID: "anonymous-job", Status: repository.JobStatusQueued, Options: `{"Anonymous":true}`, Layers: "[]",
+			Owner: "stopped-instance", HeartbeatAt: &stale},
+	} {
+		if err := jobs.Create(job); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	jobService := service.NewJobService(config.JobsConfig{Workers: 1, QueueSize: 10}, detectionService, jobs)
+	runJobs(t, jobService)
+
+	if status := waitForJob(t, jobService, "expired-job"); status.Status != repository.JobStatusDone {
+		t.Errorf("expired job = %+v, want done", status)
+	}
+	if status := waitForJob(t, jobService, "anonymous-job"); status.Status != repository.JobStatusFailed {
+		t.Errorf("anonymous job of stopped instance = %+v, want failed", status)
+	}
+	if status, _ := jobService.Get("leased-job"); status.Status != repository.JobStatusRunning {
+		t.Errorf("job leased by another instance = %+v, want still running", status)
+	}
+}
+
+// slowDetection 每个检测层完成前等待一段时间，并统计检测次数
+type slowDetection struct {
+	service.DetectionService
+	delay time.Duration
+	calls atomic.Int32
+}
+
+func (d *slowDetection) Detect(text string, options service.DetectionOptions) (*service.DetectionResult, error) {
+	d.calls.Add(1)
+	for _, layer := range []string{models.LayerRule, models.LayerStatistics, models.LayerSemantic} {
+		time.Sleep(d.delay)
+		options.OnLayer(models.LayerResult{Layer: layer, Score: 50, CompletedAt: time.Now()})
+	}
+	return d.DetectionService.Detect(text, service.DetectionOptions{})
+}
+
+func TestJobs_LeaseKeptWhileLayersRun(t *testing.T) {
+	cfg := config.DefaultConfig
+	db := openTestDB(t, database.TypeSQLite)
+	detection := &slowDetection{
+		DetectionService: service.NewDetectionService(&cfg, repository.NewDetectionRepository(db)),
+		delay:            1500 * time.Millisecond,
+	}
+	jobs := repository.NewJobRepository(db)
+
+	// 两个共享数据库的实例，租约短于单个检测层的耗时
+	jobsConfig := config.JobsConfig{Workers: 1, QueueSize: 10, LeaseTimeout: 1}
+	first := service.NewJobService(jobsConfig, detection, jobs)
+	second := service.NewJobService(jobsConfig, detection, jobs)
+
+	submitted, err := first.Submit("Slow text.", service.DetectionOptions{}, "")
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	runJobs(t, first)
+	for detection.calls.Load() == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	runJobs(t, second)
+
+	deadline := time.Now().Add(20 * time.Second)
+	status, _ := first.Get(submitted.ID)
+	for status.Status != repository.JobStatusDone && status.Status != repository.JobStatusFailed && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+		status, _ = first.Get(submitted.ID)
+	}
+	if status.Status != repository.JobStatusDone || len(status.Layers) != 3 {
+		t.Errorf("job = %+v, want done with 3 layers", status)
+	}
+	if calls := detection.calls.Load(); calls != 1 {
+		t.Errorf("job ran %d times, want once", calls)
+	}
+}
+
+func TestJobs_AnonymousTextNotStored(t *testing.T) {
+	text := "Additionally, it is crucial to understand the pivotal role of AI."
+
+	cfg := config.DefaultConfig
+	db := openTestDB(t, database.TypeSQLite)
+	detectionService := service.NewDetectionService(&cfg, repository.NewDetectionRepository(db))
+	jobs := repository.NewJobRepository(db)
+	jobService := service.NewJobService(config.JobsConfig{Workers: 1, QueueSize: 10}, detectionService, jobs)
+
+	submitted, err := jobService.Submit(text, service.DetectionOptions{Anonymous: true}, "")
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	job, err := jobs.GetByID(submitted.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if job.Text != "" {
+		t.Error("queued anonymous job stores the submitted text")
+	}
+
+	runJobs(t, jobService)
+	status := waitForJob(t, jobService, submitted.ID)
+	if status.Status != repository.JobStatusDone || status.Result == nil || status.Result.Score == nil {
+		t.Fatalf("job = %+v, want done with result", status)
+	}
+}
+
+func TestJobs_HandlerGetByID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.DefaultConfig
+	db := openTestDB(t, database.TypeSQLite)
+	detectionService := service.NewDetectionService(&cfg, repository.NewDetectionRepository(db))
+	jobs := repository.NewJobRepository(db)
+	handler := handlers.NewJobHandler(service.NewJobService(config.JobsConfig{}, detectionService, jobs))
+
+	router := gin.New()
+	router.GET("/api/v1/jobs/:id", handler.GetByID)
+
+	for _, job := range []*repository.Job{
+		{ID: "queued-job", Status: repository.JobStatusQueued, Options: "{}", Layers: "[]"},
+		{ID: "corrupt-job", Status: repository.JobStatusRunning, Options: "{}", Layers: "not json"},
+	} {
+		if err := jobs.Create(job); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		id   string
+		want int
+	}{
+		{"queued-job", http.StatusOK},
+		{"missing-job", http.StatusNotFound},
+		{"corrupt-job", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/jobs/"+tt.id, nil))
+		if w.Code != tt.want {
+			t.Errorf("GET %s status = %d, want %d (%s)", tt.id, w.Code, tt.want, w.Body.String())
+		}
+	}
+}
+
+func TestJobs_QueueFull(t *testing.T) {
+	cfg := config.DefaultConfig
+	db := openTestDB(t, database.TypeSQLite)
+	detectionService := service.NewDetectionService(&cfg, repository.NewDetectionRepository(db))
+	jobService := service.NewJobService(config.JobsConfig{Workers: 1, QueueSize: 1}, detectionService, repository.NewJobRepository(db))
+
+	// 未启动 worker 时队列只能容纳一个任务
+	if _, err := jobService.Submit("First text.", service.DetectionOptions{}, ""); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if _, err := jobService.Submit("Second text.", service.DetectionOptions{}, ""); err != service.ErrQueueFull {
+		t.Errorf("Submit() error = %v, want ErrQueueFull", err)
+	}
+}
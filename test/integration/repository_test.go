@@ -0,0 +1,234 @@
+package integration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leoobai/aigc-check/internal/database"
+	"github.com/leoobai/aigc-check/internal/database/migrations"
+	"github.com/leoobai/aigc-check/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB 打开指定类型的测试数据库
+// PostgreSQL 与 MySQL 通过环境变量 AIGC_TEST_POSTGRES_DSN / AIGC_TEST_MYSQL_DSN 提供连接，
+// 未设置或无法连接时跳过测试
+func openTestDB(t *testing.T, dbType string) *gorm.DB {
+	t.Helper()
+
+	var dsn string
+	switch dbType {
+	case database.TypeSQLite:
+		dsn = filepath.Join(t.TempDir(), "aigc-check.db")
+	case database.TypePostgres:
+		dsn = os.Getenv("AIGC_TEST_POSTGRES_DSN")
+	case database.TypeMySQL:
+		dsn = os.Getenv("AIGC_TEST_MYSQL_DSN")
+	}
+	if dsn == "" {
+		t.Skipf("%s not configured, skipping", dbType)
+	}
+
+	db, err := database.Open(database.Config{
+		Type:         dbType,
+		DSN:          dsn,
+		LogLevel:     logger.Silent,
+		MaxOpenConns: 4,
+		MaxIdleConns: 2,
+	})
+	if err != nil {
+		if dbType == database.TypeSQLite {
+			t.Fatalf("Open() error = %v", err)
+		}
+		t.Skipf("%s unavailable: %v", dbType, err)
+	}
+	t.Cleanup(func() {
+		// PostgreSQL 与 MySQL 测试库由各测试共享，需回滚全部迁移并删除迁移记录表，
+		// 下一个测试才会重新执行迁移
+		if _, err := migrations.Rollback(db, len(migrations.All())); err != nil {
+			t.Errorf("Rollback() error = %v", err)
+		}
+		if err := db.Migrator().DropTable(&migrations.SchemaMigration{}); err != nil {
+			t.Errorf("DropTable(schema_migrations) error = %v", err)
+		}
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	if err := migrations.AutoMigrate(db); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	return db
+}
+
+func TestDetectionRepository_Backends(t *testing.T) {
+	for _, dbType := range []string{database.TypeSQLite, database.TypePostgres, database.TypeMySQL} {
+		t.Run(dbType, func(t *testing.T) {
+			repo := repository.NewDetectionRepository(openTestDB(t, dbType))
+			testDetectionRepository(t, repo)
+		})
+	}
+}
+
+func TestDetectionRepository_Filter(t *testing.T) {
+	for _, dbType := range []string{database.TypeSQLite, database.TypePostgres, database.TypeMySQL} {
+		t.Run(dbType, func(t *testing.T) {
+			repo := repository.NewDetectionRepository(openTestDB(t, dbType))
+			testDetectionRepositoryFilter(t, repo)
+		})
+	}
+}
+
+// testDetectionRepositoryFilter 测试过滤条件和全文检索
+func testDetectionRepositoryFilter(t *testing.T, repo repository.DetectionRepository) {
+	now := time.Now().Truncate(time.Second)
+	records := []*repository.DetectionRecord{
+		{
+			ID: "r1", RequestID: "q1", Score: 35, RiskLevel: "very_high",
+			Text:          "本季度的季度报告显示营收增长。As of my last knowledge update, revenue grew.",
+			DetectedRules: repository.JoinList([]string{"knowledge_cutoff", "markdown_residue"}),
+			Tags:          repository.JoinList([]string{"finance", "q3"}),
+			CreatedAt:     now.AddDate(0, 0, -2),
+		},
+		{
+			ID: "r2", RequestID: "q2", Score: 55, RiskLevel: "high",
+			Text:          "季度报告草稿，尚未审核。The quarterly numbers look fine.",
+			DetectedRules: repository.JoinList([]string{"knowledge_cutoff"}),
+			Tags:          repository.JoinList([]string{"finance"}),
+			CreatedAt:     now.AddDate(0, 0, -10),
+		},
+		{
+			ID: "r3", RequestID: "q3", Score: 85, RiskLevel: "low",
+			Text:          "A personal note about my weekend hiking trip.",
+			DetectedRules: "",
+			Tags:          repository.JoinList([]string{"personal"}),
+			CreatedAt:     now.AddDate(0, 0, -1),
+		},
+	}
+	for _, record := range records {
+		if err := repo.Create(record); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	minScore, maxScore := 30.0, 60.0
+	tests := []struct {
+		name   string
+		filter repository.ListFilter
+		want   []string
+	}{
+		{"no filter", repository.ListFilter{}, []string{"r1", "r2", "r3"}},
+		{"score range", repository.ListFilter{MinScore: &minScore, MaxScore: &maxScore}, []string{"r1", "r2"}},
+		{"risk level", repository.ListFilter{RiskLevels: []string{"high", "low"}}, []string{"r2", "r3"}},
+		{"date range", repository.ListFilter{From: now.AddDate(0, 0, -7)}, []string{"r1", "r3"}},
+		{"date upper bound", repository.ListFilter{To: now.AddDate(0, 0, -5)}, []string{"r2"}},
+		{"rule types all required", repository.ListFilter{RuleTypes: []string{"knowledge_cutoff", "markdown_residue"}}, []string{"r1"}},
+		{"tag", repository.ListFilter{Tags: []string{"finance"}}, []string{"r1", "r2"}},
+		{"tag is exact", repository.ListFilter{Tags: []string{"fin"}}, nil},
+		{"chinese query", repository.ListFilter{Query: "季度报告"}, []string{"r1", "r2"}},
+		{"english query", repository.ListFilter{Query: "hiking"}, []string{"r3"}},
+		{"all terms required", repository.ListFilter{Query: "季度报告 revenue"}, []string{"r1"}},
+		{"like wildcards escaped", repository.ListFilter{Query: "100%"}, nil},
+		{
+			"combined",
+			repository.ListFilter{
+				RiskLevels: []string{"very_high", "high"},
+				From:       now.AddDate(0, 0, -7),
+				RuleTypes:  []string{"knowledge_cutoff"},
+				Query:      "季度报告",
+			},
+			[]string{"r1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total, err := repo.List(1, 10, "score", "asc", tt.filter)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			var ids []string
+			for _, record := range got {
+				ids = append(ids, record.ID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.want) || int(total) != len(tt.want) {
+				t.Errorf("List() = %v (total %d), want %v", ids, total, tt.want)
+			}
+		})
+	}
+}
+
+// testDetectionRepository 对仓储执行与数据库无关的增删查测试
+func testDetectionRepository(t *testing.T, repo repository.DetectionRepository) {
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i := 0; i < 3; i++ {
+		record := &repository.DetectionRecord{
+			ID:          fmt.Sprintf("id-%d", i),
+			RequestID:   fmt.Sprintf("req-%d", i),
+			Text:        "这是一段用于测试的中文文本，包含多字节字符。",
+			TextPreview: "这是一段用于测试的中文文本",
+			Score:       float64(50 + i*10),
+			RiskLevel:   "medium",
+			RuleResults: "[]",
+			Suggestions: "[]",
+			ProcessTime: "10ms",
+			CreatedAt:   base.Add(time.Duration(i) * time.Minute),
+		}
+		if err := repo.Create(record); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	got, err := repo.GetByID("id-1")
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.RequestID != "req-1" || got.Text != "这是一段用于测试的中文文本，包含多字节字符。" {
+		t.Errorf("GetByID() = %+v, unexpected record", got)
+	}
+
+	if _, err := repo.GetByRequestID("req-2"); err != nil {
+		t.Errorf("GetByRequestID() error = %v", err)
+	}
+	if _, err := repo.GetByID("missing"); err == nil {
+		t.Error("GetByID(missing) error = nil, want not found")
+	}
+
+	records, total, err := repo.List(1, 2, "score", "desc", repository.ListFilter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if total != 3 || len(records) != 2 {
+		t.Fatalf("List() total = %d, len = %d, want 3, 2", total, len(records))
+	}
+	if records[0].Score != 70 || records[1].Score != 60 {
+		t.Errorf("List() scores = %v, %v, want 70, 60", records[0].Score, records[1].Score)
+	}
+
+	records, _, err = repo.List(2, 2, "created_at", "asc", repository.ListFilter{})
+	if err != nil {
+		t.Fatalf("List() page 2 error = %v", err)
+	}
+	if len(records) != 1 || records[0].ID != "id-2" {
+		t.Errorf("List() page 2 = %v, want [id-2]", records)
+	}
+
+	if err := repo.Delete("id-0"); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+	if err := repo.Delete("id-0"); err == nil {
+		t.Error("Delete() twice error = nil, want not found")
+	}
+
+	if err := repo.DeleteAll(); err != nil {
+		t.Fatalf("DeleteAll() error = %v", err)
+	}
+	if _, total, _ := repo.List(1, 10, "created_at", "desc", repository.ListFilter{}); total != 0 {
+		t.Errorf("List() after DeleteAll total = %d, want 0", total)
+	}
+}
@@ -0,0 +1,137 @@
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/leoobai/aigc-check/internal/config"
+	"github.com/leoobai/aigc-check/internal/database"
+	"github.com/leoobai/aigc-check/internal/repository"
+	"github.com/leoobai/aigc-check/internal/service"
+)
+
+func TestRetention_AnonymousAndPurge(t *testing.T) {
+	text := `Additionally, it is crucial to understand the pivotal role of AI.
+
+I hope this helps! Let me know if you have any questions.`
+
+	cfg := config.DefaultConfig
+	db := openTestDB(t, database.TypeSQLite)
+	records := repository.NewDetectionRepository(db)
+	audit := repository.NewAuditRepository(db)
+	detectionService := service.NewDetectionService(&cfg, records)
+
+	// 匿名提交只保存分数和元数据
+	anonymous, err := detectionService.Detect(text, service.DetectionOptions{Anonymous: true})
+	if err != nil {
+		t.Fatalf("Detect(anonymous) error = %v", err)
+	}
+	if anonymous.Text != text {
+		t.Error("anonymous Detect() response should still contain the submitted text")
+	}
+	stored, err := records.GetByID(anonymous.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if stored.Text != "" || stored.TextPreview != "" || stored.ContentHash != "" || stored.TextPurgedAt == nil {
+		t.Errorf("anonymous record = text %q, preview %q, hash %q, purged %v; want no text and purge timestamp",
+			stored.Text, stored.TextPreview, stored.ContentHash, stored.TextPurgedAt)
+	}
+	if stored.Score != anonymous.Score.Total {
+		t.Errorf("anonymous record Score = %v, want %v", stored.Score, anonymous.Score.Total)
+	}
+
+	old, err := detectionService.Detect(text, service.DetectionOptions{})
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if old.Cached {
+		t.Error("Detect() reused an anonymous record")
+	}
+	if err := db.Exec("UPDATE detection_records SET created_at = ? WHERE id = ?",
+		time.Now().AddDate(0, 0, -40), old.ID).Error; err != nil {
+		t.Fatalf("backdate record: %v", err)
+	}
+
+	retention := service.NewRetentionService(config.RetentionConfig{TextDays: 30}, records, audit)
+
+	// dry-run 只报告，不修改数据
+	report, err := retention.Purge(service.PurgeOptions{DryRun: true, Actor: "test"})
+	if err != nil {
+		t.Fatalf("Purge(dry-run) error = %v", err)
+	}
+	if report.Matched != 1 || report.Purged != 0 || len(report.RecordIDs) != 1 || report.RecordIDs[0] != old.ID {
+		t.Errorf("dry-run report = %+v, want 1 matched record %s and nothing purged", report, old.ID)
+	}
+	if record, _ := records.GetByID(old.ID); record.Text != text || record.TextPurgedAt != nil {
+		t.Error("dry-run modified the record")
+	}
+
+	report, err = retention.Purge(service.PurgeOptions{Actor: "test"})
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if report.Matched != 1 || report.Purged != 1 {
+		t.Errorf("Purge() report = %+v, want 1 purged record", report)
+	}
+
+	purged, err := detectionService.GetResult(old.ID)
+	if err != nil {
+		t.Fatalf("GetResult() error = %v", err)
+	}
+	if purged.Text != "" || purged.TextPurgedAt == nil || len(purged.Suggestions) != 0 {
+		t.Errorf("purged result = text %q, purged %v, %d suggestions; want text and suggestions removed",
+			purged.Text, purged.TextPurgedAt, len(purged.Suggestions))
+	}
+	if purged.Score.Total != old.Score.Total || len(purged.RuleResults) != len(old.RuleResults) {
+		t.Errorf("purged result score = %v with %d rules, want %v with %d rules",
+			purged.Score.Total, len(purged.RuleResults), old.Score.Total, len(old.RuleResults))
+	}
+	for i, ruleResult := range purged.RuleResults {
+		if len(ruleResult.Matches) != 0 || ruleResult.Count != old.RuleResults[i].Count {
+			t.Errorf("purged %s = %d matches, count %d; want no matches, count %d",
+				ruleResult.RuleType, len(ruleResult.Matches), ruleResult.Count, old.RuleResults[i].Count)
+		}
+	}
+
+	// 再次清理没有需要处理的记录
+	report, err = retention.Purge(service.PurgeOptions{Actor: "test"})
+	if err != nil || report.Matched != 0 {
+		t.Errorf("second Purge() = %+v, %v; want nothing matched", report, err)
+	}
+
+	logs, err := audit.List("", 10)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(logs) != 2 || logs[0].Action != repository.AuditActionPurgeText || logs[1].Action != repository.AuditActionPurgeTextDryRun {
+		t.Fatalf("audit logs = %+v, want purge and dry-run entries", logs)
+	}
+	var details service.PurgeReport
+	if err := json.Unmarshal([]byte(logs[0].Details), &details); err != nil {
+		t.Fatalf("audit details: %v", err)
+	}
+	if logs[0].Actor != "test" || details.Purged != 1 || details.RecordIDs[0] != old.ID {
+		t.Errorf("audit entry = %+v, want purge of %s by test", logs[0], old.ID)
+	}
+
+	// 原文已清除的记录不再被复用
+	fresh, err := detectionService.Detect(text, service.DetectionOptions{})
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if fresh.Cached || fresh.Text != text {
+		t.Errorf("Detect() after purge cached = %v, want a fresh result", fresh.Cached)
+	}
+}
+
+func TestRetention_NotConfigured(t *testing.T) {
+	db := openTestDB(t, database.TypeSQLite)
+	retention := service.NewRetentionService(config.RetentionConfig{},
+		repository.NewDetectionRepository(db), repository.NewAuditRepository(db))
+
+	if _, err := retention.Purge(service.PurgeOptions{}); err == nil {
+		t.Error("Purge() without text_days error = nil, want error")
+	}
+}